@@ -0,0 +1,105 @@
+package discount
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type breakerState int
+
+const (
+	closed breakerState = iota
+	open
+	halfOpen
+)
+
+// breaker stops calls to the discount service after it fails a number of times in a row. Once the
+// cooldown is over a single probe call is let through, its success closes the breaker again and its
+// failure opens it for another cooldown.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+	probing   bool
+}
+
+// newBreaker returns a breaker opening after threshold failures, no breaker when threshold is zero.
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &breaker{threshold: threshold, cooldown: cooldown}
+}
+
+// allow tells whether a call can be made, every allowed call has to be followed by done or abandon.
+func (b *breaker) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case open:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = halfOpen
+		b.probing = true
+		return true
+	case halfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	}
+	return true
+}
+
+// done records the outcome of an allowed call.
+func (b *breaker) done(failed bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	switch {
+	case !failed && b.state == halfOpen:
+		b.state = closed
+		b.failures = 0
+		log.Info().Str("method", "discount.breaker").Msg("discount service circuit closed")
+	case !failed:
+		b.failures = 0
+	case b.state == halfOpen:
+		b.trip()
+	case b.state == closed:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.trip()
+		}
+	}
+}
+
+// abandon lets go of an allowed call that ended without telling anything of the service, e.g. one
+// canceled by its caller.
+func (b *breaker) abandon() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *breaker) trip() {
+	b.state = open
+	b.openedAt = time.Now()
+	log.Warn().Str("method", "discount.breaker").Int("failures", b.failures).Dur("cooldown", b.cooldown).
+		Msg("discount service circuit opened")
+}
@@ -0,0 +1,73 @@
+package discount
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"wallet/db"
+	"wallet/internal/config"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	giftPrefix = ":GIFT:"
+	// dropTimeout bounds dropping a cached gift, which outlives the request that used the gift.
+	dropTimeout = 2 * time.Second
+)
+
+// CachedClient keeps the gifts looked up through a Client in redis for a while. Using or releasing a
+// gift drops it, so the next lookup sees how much of it is left.
+type CachedClient struct {
+	client Client
+	rdb    db.RedisClient
+	ttl    time.Duration
+}
+
+func NewCachedClient(client Client, rdb db.RedisClient, ttl time.Duration) *CachedClient {
+	return &CachedClient{client: client, rdb: rdb, ttl: ttl}
+}
+
+func (c *CachedClient) GetGiftByCode(ctx context.Context, code string) (*Gift, error) {
+	key := config.RDBPrefix() + giftPrefix + code
+	if s, err := c.rdb.Get(ctx, key); err == nil {
+		var gift Gift
+		if err = json.Unmarshal([]byte(s), &gift); err == nil {
+			observe("getGift", outcomeCacheHit, 0)
+			return &gift, nil
+		}
+	}
+	observe("getGift", outcomeCacheMiss, 0)
+	gift, err := c.client.GetGiftByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.rdb.Set(ctx, key, gift, c.ttl); err != nil {
+		log.Warn().Str("method", "discount.GetGiftByCode").Err(err).Msg("failed to cache gift")
+	}
+	return gift, nil
+}
+
+// UseGift drops the cached gift whether or not it was used, a failed call may still have used it.
+func (c *CachedClient) UseGift(ctx context.Context, code string, memberID int64, reference string) (*Gift, error) {
+	gift, err := c.client.UseGift(ctx, code, memberID, reference)
+	c.drop(ctx, code)
+	return gift, err
+}
+
+// ReleaseGift drops the cached gift like UseGift.
+func (c *CachedClient) ReleaseGift(ctx context.Context, code, reference string) (*Gift, error) {
+	gift, err := c.client.ReleaseGift(ctx, code, reference)
+	c.drop(ctx, code)
+	return gift, err
+}
+
+// drop removes the cached gift even when ctx is cancelled or timed out by the time the call returns,
+// otherwise the gift would be served with a stale used count until its ttl runs out.
+func (c *CachedClient) drop(ctx context.Context, code string) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), dropTimeout)
+	defer cancel()
+	if err := c.rdb.Del(ctx, config.RDBPrefix()+giftPrefix+code).Err(); err != nil {
+		log.Warn().Str("method", "discount.drop").Str("code", code).Err(err).Msg("failed to drop cached gift")
+	}
+}
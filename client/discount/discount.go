@@ -1,26 +1,82 @@
 package discount
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand"
 	"net/http"
+	"net/url"
 	"time"
-	"wallet/internal/serr"
 )
 
+// Client looks gifts up and uses them for members. Uses and releases carry the reference of the
+// redemption they are made for, a use or release repeated with the same reference is made once, so a
+// call whose outcome is not known can be made again.
 type Client interface {
-	GetGiftByCode(code string) (*Gift, error)
-	UseGift(code string) (*Gift, error)
+	GetGiftByCode(ctx context.Context, code string) (*Gift, error)
+	UseGift(ctx context.Context, code string, memberID int64, reference string) (*Gift, error)
+	// ReleaseGift gives back the use of a gift made with reference.
+	ReleaseGift(ctx context.Context, code, reference string) (*Gift, error)
 }
 
+const (
+	defaultTimeout         = 10 * time.Second
+	defaultRetries         = 2
+	defaultRetryDelay      = 100 * time.Millisecond
+	defaultBreakerFailures = 5
+	defaultBreakerCooldown = 30 * time.Second
+)
+
 type HTTPClient struct {
 	address    string
 	httpClient *http.Client
+	retries    int
+	retryDelay time.Duration
+	breaker    *breaker
+}
+
+// Option configures an HTTPClient.
+type Option func(*HTTPClient)
+
+// WithTimeout bounds every request to the discount service, 10s by default.
+func WithTimeout(d time.Duration) Option {
+	return func(c *HTTPClient) {
+		if d > 0 {
+			c.httpClient.Timeout = d
+		}
+	}
+}
+
+// WithRetries sets how many times a gift lookup failing on the service is made again, with a
+// jittered backoff starting at delay. It is 2 times from 100ms by default, using a gift is never retried.
+func WithRetries(n int, delay time.Duration) Option {
+	return func(c *HTTPClient) {
+		c.retries = n
+		c.retryDelay = delay
+	}
 }
 
-func NewHTTPClient(address string) *HTTPClient {
-	c := &HTTPClient{address: address}
-	c.httpClient = &http.Client{Timeout: time.Second * 10}
+// WithBreaker stops calling the discount service for cooldown once it failed failures times in a
+// row, 5 times and 30s by default. Zero failures never stops calling it.
+func WithBreaker(failures int, cooldown time.Duration) Option {
+	return func(c *HTTPClient) {
+		c.breaker = newBreaker(failures, cooldown)
+	}
+}
+
+func NewHTTPClient(address string, opts ...Option) *HTTPClient {
+	c := &HTTPClient{
+		address:    address,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+		retries:    defaultRetries,
+		retryDelay: defaultRetryDelay,
+		breaker:    newBreaker(defaultBreakerFailures, defaultBreakerCooldown),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
 	return c
 }
 
@@ -34,56 +90,100 @@ type Gift struct {
 	StartDateTime  string `json:"startDateTime"`
 	CreatedAt      string `json:"createdAt"`
 	UpdatedAt      string `json:"updatedAt"`
+	// PerMemberLimit is set by the local engine, which limits the uses of each member itself. The
+	// discount api leaves it zero and a member redeems its gifts once.
+	PerMemberLimit int64 `json:"perMemberLimit,omitempty"`
 }
 
-func (r *HTTPClient) GetGiftByCode(code string) (*Gift, error) {
-	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/gift/%s", r.address, code), nil)
-	if err != nil {
-		return nil, err
-	}
-	res, err := r.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
-	}
-	defer res.Body.Close()
-	if res.StatusCode != http.StatusOK {
-		var result map[string]interface{}
-		err = json.NewDecoder(res.Body).Decode(&result)
-		if err != nil {
-			return nil, err
+func (g *Gift) MarshalBinary() ([]byte, error) {
+	return json.Marshal(g)
+}
+
+// GetGiftByCode looks a gift up, lookups failing on the service are made again.
+func (r *HTTPClient) GetGiftByCode(ctx context.Context, code string) (*Gift, error) {
+	const op = "getGift"
+	address := fmt.Sprintf("%s/gift/%s", r.address, url.PathEscape(code))
+	for attempt := 0; ; attempt++ {
+		gift, err := r.call(ctx, op, http.MethodGet, address, "")
+		var e *Error
+		if err == nil || attempt >= r.retries || ctx.Err() != nil || !errors.As(err, &e) || !e.Temporary() {
+			return gift, serviceErr(ctx, err)
+		}
+		observe(op, outcomeRetry, 0)
+		delay := r.retryDelay<<attempt + time.Duration(rand.Int63n(int64(r.retryDelay)+1))
+		select {
+		case <-ctx.Done():
+			return nil, serviceErr(ctx, err)
+		case <-time.After(delay):
 		}
-		return nil, serr.ValidationErr("getGift", result["message"].(string), serr.ErrDiscountClient)
 	}
-	var gift Gift
-	err = json.NewDecoder(res.Body).Decode(&gift)
-	if err != nil {
-		return nil, err
+}
+
+// UseGift uses a gift up once, the reference is sent as the Idempotency-Key of the request. It is
+// made a single time, a failed call may have used the gift. The discount api keeps its own limits,
+// the member is not sent.
+func (r *HTTPClient) UseGift(ctx context.Context, code string, _ int64, reference string) (*Gift, error) {
+	address := fmt.Sprintf("%s/gift/use/%s", r.address, url.PathEscape(code))
+	gift, err := r.call(ctx, "useGift", http.MethodPost, address, reference)
+	return gift, serviceErr(ctx, err)
+}
+
+// ReleaseGift gives back the use made with reference, sent as the Idempotency-Key of the request.
+func (r *HTTPClient) ReleaseGift(ctx context.Context, code, reference string) (*Gift, error) {
+	address := fmt.Sprintf("%s/gift/release/%s", r.address, url.PathEscape(code))
+	gift, err := r.call(ctx, "releaseGift", http.MethodPost, address, reference)
+	return gift, serviceErr(ctx, err)
+}
+
+// call makes a request through the breaker and counts it in the metrics.
+func (r *HTTPClient) call(ctx context.Context, op, method, address, reference string) (*Gift, error) {
+	if !r.breaker.allow() {
+		observe(op, outcomeCircuitOpen, 0)
+		return nil, &Error{Op: op, Err: ErrCircuitOpen}
 	}
-	return &gift, nil
+	start := time.Now()
+	gift, err := r.do(ctx, op, method, address, reference)
+	var e *Error
+	switch {
+	case ctx.Err() != nil:
+		r.breaker.abandon()
+	case errors.As(err, &e):
+		r.breaker.done(e.Temporary())
+	default:
+		r.breaker.done(false)
+	}
+	observe(op, outcome(ctx, err), time.Since(start))
+	return gift, err
 }
 
-func (r *HTTPClient) UseGift(code string) (*Gift, error) {
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/gift/use/%s", r.address, code), nil)
+func (r *HTTPClient) do(ctx context.Context, op, method, address, reference string) (*Gift, error) {
+	req, err := http.NewRequestWithContext(ctx, method, address, nil)
 	if err != nil {
-		return nil, err
+		return nil, &Error{Op: op, Err: err}
+	}
+	if reference != "" {
+		req.Header.Set("Idempotency-Key", reference)
 	}
 	res, err := r.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, &Error{Op: op, Err: fmt.Errorf("failed to make request: %w", err)}
 	}
 	defer res.Body.Close()
 	if res.StatusCode != http.StatusOK {
-		var result map[string]interface{}
-		err = json.NewDecoder(res.Body).Decode(&result)
-		if err != nil {
-			return nil, err
+		e := &Error{Op: op, Status: res.StatusCode}
+		var result struct {
+			Message string `json:"message"`
+		}
+		// a body without a string message leaves the error without one
+		if json.NewDecoder(res.Body).Decode(&result) == nil {
+			e.Message = result.Message
 		}
-		return nil, serr.ValidationErr("useGift", result["message"].(string), serr.ErrDiscountClient)
+		return nil, e
 	}
 	var gift Gift
 	err = json.NewDecoder(res.Body).Decode(&gift)
 	if err != nil {
-		return nil, err
+		return nil, &Error{Op: op, Status: res.StatusCode, Err: fmt.Errorf("invalid response: %w", err)}
 	}
 	return &gift, nil
 }
@@ -0,0 +1,286 @@
+package discount_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	"wallet/client/discount"
+	"wallet/internal/serr"
+	dbMocks "wallet/mocks/repomocks/db"
+	discountMocks "wallet/mocks/repomocks/discount"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testGift = &discount.Gift{Id: 1, Code: "GIFT", GiftAmount: 1000, UsageLimit: 10}
+
+// giftServer answers the calls of the discount client with the statuses given, then with a gift.
+func giftServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			_, _ = w.Write([]byte(`{"message":"failed"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(testGift)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func serviceErr(t *testing.T, err error) *serr.ServiceError {
+	var e *serr.ServiceError
+	require.True(t, errors.As(err, &e), "not a service error: %v", err)
+	return e
+}
+
+func metric(name string) int64 {
+	v := expvar.Get("discount").(*expvar.Map).Get(name)
+	if v == nil {
+		return 0
+	}
+	return v.(*expvar.Int).Value()
+}
+
+func TestHTTPClient_ErrorResponses(t *testing.T) {
+	cases := []struct {
+		name    string
+		status  int
+		body    string
+		message string
+		code    serr.ErrorCode
+	}{
+		{"message", http.StatusBadRequest, `{"message":"gift not found"}`, "gift not found", serr.ErrDiscountClient},
+		{"message not a string", http.StatusNotFound, `{"message":404}`, "discount request rejected", serr.ErrDiscountClient},
+		{"no json", http.StatusBadRequest, `not found`, "discount request rejected", serr.ErrDiscountClient},
+		{"server error", http.StatusBadGateway, `{}`, "discount service is unavailable", serr.ErrDiscountUnavailable},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+			c := discount.NewHTTPClient(srv.URL, discount.WithRetries(0, 0))
+
+			_, err := c.UseGift(context.Background(), "GIFT", 1, "ref")
+			e := serviceErr(t, err)
+			assert.Equal(t, tc.code, e.ErrorCode)
+			assert.Equal(t, tc.message, e.Message)
+			var de *discount.Error
+			require.True(t, errors.As(err, &de))
+			assert.Equal(t, tc.status, de.Status)
+		})
+	}
+}
+
+func TestHTTPClient_Retries(t *testing.T) {
+	t.Run("gift lookups", func(t *testing.T) {
+		srv, calls := giftServer(t, http.StatusInternalServerError, http.StatusServiceUnavailable)
+		c := discount.NewHTTPClient(srv.URL, discount.WithRetries(2, time.Millisecond))
+		retries := metric("getGift.retry")
+
+		g, err := c.GetGiftByCode(context.Background(), "GIFT")
+		require.NoError(t, err)
+		assert.Equal(t, testGift, g)
+		assert.Equal(t, int32(3), calls.Load())
+		assert.Equal(t, retries+2, metric("getGift.retry"))
+	})
+
+	t.Run("up to the retries", func(t *testing.T) {
+		srv, calls := giftServer(t, 500, 500, 500, 500)
+		c := discount.NewHTTPClient(srv.URL, discount.WithRetries(2, time.Millisecond))
+
+		_, err := c.GetGiftByCode(context.Background(), "GIFT")
+		assert.Equal(t, serr.ErrDiscountUnavailable, serviceErr(t, err).ErrorCode)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("not rejected lookups", func(t *testing.T) {
+		srv, calls := giftServer(t, http.StatusNotFound)
+		c := discount.NewHTTPClient(srv.URL, discount.WithRetries(2, time.Millisecond))
+
+		_, err := c.GetGiftByCode(context.Background(), "GIFT")
+		assert.Equal(t, serr.ErrDiscountClient, serviceErr(t, err).ErrorCode)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("not gift uses", func(t *testing.T) {
+		srv, calls := giftServer(t, http.StatusInternalServerError)
+		c := discount.NewHTTPClient(srv.URL, discount.WithRetries(2, time.Millisecond))
+
+		_, err := c.UseGift(context.Background(), "GIFT", 1, "ref")
+		assert.Equal(t, serr.ErrDiscountUnavailable, serviceErr(t, err).ErrorCode)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("not past the context", func(t *testing.T) {
+		srv, calls := giftServer(t, 500, 500, 500)
+		c := discount.NewHTTPClient(srv.URL, discount.WithRetries(2, time.Hour))
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := c.GetGiftByCode(ctx, "GIFT")
+		assert.True(t, serr.IsTimeout(err))
+		assert.Equal(t, serr.ErrTimeout, serviceErr(t, err).ErrorCode)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
+func TestHTTPClient_References(t *testing.T) {
+	var paths, keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		_ = json.NewEncoder(w).Encode(testGift)
+	}))
+	defer srv.Close()
+	c := discount.NewHTTPClient(srv.URL)
+
+	_, err := c.UseGift(context.Background(), "GIFT", 1, "ref")
+	require.NoError(t, err)
+	g, err := c.ReleaseGift(context.Background(), "GIFT", "ref")
+	require.NoError(t, err)
+	assert.Equal(t, testGift, g)
+	assert.Equal(t, []string{"POST /gift/use/GIFT", "POST /gift/release/GIFT"}, paths)
+	assert.Equal(t, []string{"ref", "ref"}, keys)
+}
+
+func TestHTTPClient_Breaker(t *testing.T) {
+	srv, calls := giftServer(t, 500, 500, 500)
+	c := discount.NewHTTPClient(srv.URL, discount.WithRetries(0, 0), discount.WithBreaker(2, 50*time.Millisecond))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err := c.GetGiftByCode(ctx, "GIFT")
+		assert.Equal(t, serr.ErrDiscountUnavailable, serviceErr(t, err).ErrorCode)
+	}
+	// open, the service is not called
+	_, err := c.UseGift(ctx, "GIFT", 1, "ref")
+	assert.ErrorIs(t, err, discount.ErrCircuitOpen)
+	assert.Equal(t, serr.ErrDiscountUnavailable, serviceErr(t, err).ErrorCode)
+	assert.Equal(t, int32(2), calls.Load())
+
+	// a failed probe opens it again
+	time.Sleep(60 * time.Millisecond)
+	_, err = c.GetGiftByCode(ctx, "GIFT")
+	assert.NotErrorIs(t, err, discount.ErrCircuitOpen)
+	_, err = c.GetGiftByCode(ctx, "GIFT")
+	assert.ErrorIs(t, err, discount.ErrCircuitOpen)
+	assert.Equal(t, int32(3), calls.Load())
+
+	// a successful one closes it
+	time.Sleep(60 * time.Millisecond)
+	for i := 0; i < 2; i++ {
+		_, err = c.GetGiftByCode(ctx, "GIFT")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(5), calls.Load())
+}
+
+func TestHTTPClient_BreakerIgnoresRejections(t *testing.T) {
+	srv, calls := giftServer(t, 404, 404, 404)
+	c := discount.NewHTTPClient(srv.URL, discount.WithBreaker(2, time.Hour))
+
+	for i := 0; i < 3; i++ {
+		_, err := c.GetGiftByCode(context.Background(), "GIFT")
+		assert.Equal(t, serr.ErrDiscountClient, serviceErr(t, err).ErrorCode)
+	}
+	_, err := c.GetGiftByCode(context.Background(), "GIFT")
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestCachedClient(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("miss", func(t *testing.T) {
+		client := discountMocks.NewClient(t)
+		rdb := dbMocks.NewRedisClient(t)
+		c := discount.NewCachedClient(client, rdb, time.Minute)
+		rdb.On("Get", mock.Anything, mock.Anything).Return("", redis.Nil)
+		client.On("GetGiftByCode", mock.Anything, "GIFT").Return(testGift, nil)
+		rdb.On("Set", mock.Anything, mock.Anything, testGift, time.Minute).Return(nil)
+
+		g, err := c.GetGiftByCode(ctx, "GIFT")
+		require.NoError(t, err)
+		assert.Equal(t, testGift, g)
+	})
+
+	t.Run("hit", func(t *testing.T) {
+		client := discountMocks.NewClient(t)
+		rdb := dbMocks.NewRedisClient(t)
+		c := discount.NewCachedClient(client, rdb, time.Minute)
+		cached, _ := testGift.MarshalBinary()
+		rdb.On("Get", mock.Anything, mock.Anything).Return(string(cached), nil)
+
+		g, err := c.GetGiftByCode(ctx, "GIFT")
+		require.NoError(t, err)
+		assert.Equal(t, testGift, g)
+		client.AssertNotCalled(t, "GetGiftByCode", mock.Anything, mock.Anything)
+	})
+
+	t.Run("failed lookups are not cached", func(t *testing.T) {
+		client := discountMocks.NewClient(t)
+		rdb := dbMocks.NewRedisClient(t)
+		c := discount.NewCachedClient(client, rdb, time.Minute)
+		rdb.On("Get", mock.Anything, mock.Anything).Return("", redis.Nil)
+		client.On("GetGiftByCode", mock.Anything, "GIFT").Return(nil, errors.New("failed"))
+
+		_, err := c.GetGiftByCode(ctx, "GIFT")
+		require.Error(t, err)
+		rdb.AssertNotCalled(t, "Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("release drops the gift", func(t *testing.T) {
+		client := discountMocks.NewClient(t)
+		rdb := dbMocks.NewRedisClient(t)
+		c := discount.NewCachedClient(client, rdb, time.Minute)
+		client.On("ReleaseGift", mock.Anything, "GIFT", "ref").Return(testGift, nil)
+		rdb.On("Del", mock.Anything, mock.Anything).Return(redis.NewIntCmd(ctx))
+
+		_, err := c.ReleaseGift(ctx, "GIFT", "ref")
+		require.NoError(t, err)
+		rdb.AssertNumberOfCalls(t, "Del", 1)
+	})
+
+	t.Run("use drops the gift", func(t *testing.T) {
+		client := discountMocks.NewClient(t)
+		rdb := dbMocks.NewRedisClient(t)
+		c := discount.NewCachedClient(client, rdb, time.Minute)
+		client.On("UseGift", mock.Anything, "GIFT", int64(1), "ref").Return(nil, errors.New("failed"))
+		rdb.On("Del", mock.Anything, mock.Anything).Return(redis.NewIntCmd(ctx))
+
+		_, err := c.UseGift(ctx, "GIFT", 1, "ref")
+		require.Error(t, err)
+		rdb.AssertNumberOfCalls(t, "Del", 1)
+	})
+
+	t.Run("a cancelled request still drops the gift", func(t *testing.T) {
+		client := discountMocks.NewClient(t)
+		rdb := dbMocks.NewRedisClient(t)
+		c := discount.NewCachedClient(client, rdb, time.Minute)
+		cancelled, cancel := context.WithCancel(ctx)
+		client.On("UseGift", mock.Anything, "GIFT", int64(1), "ref").Return(testGift, nil).Run(func(mock.Arguments) { cancel() })
+		rdb.On("Del", mock.MatchedBy(func(ctx context.Context) bool {
+			_, ok := ctx.Deadline()
+			return ctx.Err() == nil && ok
+		}), mock.Anything).Return(redis.NewIntCmd(ctx))
+
+		_, err := c.UseGift(cancelled, "GIFT", 1, "ref")
+		require.NoError(t, err)
+		rdb.AssertNumberOfCalls(t, "Del", 1)
+	})
+}
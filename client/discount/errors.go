@@ -0,0 +1,73 @@
+package discount
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"wallet/internal/serr"
+)
+
+// ErrCircuitOpen fails calls made while the discount service is left alone after failing too often.
+var ErrCircuitOpen = errors.New("discount service circuit is open")
+
+// Error is a failed call to the discount service. Status is zero when no response came back, Message
+// is the message of an error response when it has one.
+type Error struct {
+	Op      string
+	Status  int
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("discount %s: %v", e.Op, e.Err)
+	}
+	return fmt.Sprintf("discount %s: status %d: %s", e.Op, e.Status, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Temporary tells whether the call may succeed when it is made again: the service could not be
+// reached, failed or asked to slow down.
+func (e *Error) Temporary() bool {
+	if errors.Is(e.Err, ErrCircuitOpen) {
+		return false
+	}
+	return e.Status == 0 || e.Status == http.StatusTooManyRequests || e.Status >= http.StatusInternalServerError
+}
+
+// rejected tells whether the service turned the request down, e.g. for an unknown or used up code.
+func (e *Error) rejected() bool {
+	return e.Status >= http.StatusBadRequest && e.Status < http.StatusInternalServerError &&
+		e.Status != http.StatusTooManyRequests
+}
+
+// serviceErr turns a failed call into the service error the api answers with, the Error stays its cause.
+func serviceErr(ctx context.Context, err error) error {
+	var e *Error
+	if !errors.As(err, &e) {
+		return err
+	}
+	switch {
+	case ctx.Err() != nil:
+		return serr.TimeoutErr(e.Op, errors.Join(ctx.Err(), err))
+	case e.rejected():
+		message := e.Message
+		if message == "" {
+			message = "discount request rejected"
+		}
+		return &serr.ServiceError{
+			Method:    e.Op,
+			Cause:     e,
+			Message:   message,
+			Code:      http.StatusBadRequest,
+			ErrorCode: serr.ErrDiscountClient,
+		}
+	default:
+		return serr.UnavailableErr(e.Op, "discount service is unavailable", serr.ErrDiscountUnavailable, e)
+	}
+}
@@ -0,0 +1,50 @@
+package discount
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Outcomes of calls counted in the metrics.
+const (
+	outcomeOK          = "ok"
+	outcomeRejected    = "rejected"
+	outcomeFailed      = "failed"
+	outcomeCanceled    = "canceled"
+	outcomeCircuitOpen = "circuit_open"
+	outcomeRetry       = "retry"
+	outcomeCacheHit    = "cache_hit"
+	outcomeCacheMiss   = "cache_miss"
+)
+
+// metrics counts the calls to the discount service by operation and outcome, e.g. getGift.ok, and
+// adds up the milliseconds they took under the operation, e.g. getGift.ms. They are the only expvar
+// variable published at /debug/vars.
+var metrics = expvar.NewMap("discount")
+
+func observe(op, outcome string, took time.Duration) {
+	metrics.Add(op+"."+outcome, 1)
+	if took > 0 {
+		metrics.Add(op+".ms", took.Milliseconds())
+	}
+	log.Debug().Str("method", "discount."+op).Str("outcome", outcome).Dur("took", took).Msg("discount call")
+}
+
+// outcome of a call that came back with err.
+func outcome(ctx context.Context, err error) string {
+	var e *Error
+	switch {
+	case err == nil:
+		return outcomeOK
+	case ctx.Err() != nil:
+		return outcomeCanceled
+	case errors.As(err, &e) && e.rejected():
+		return outcomeRejected
+	default:
+		return outcomeFailed
+	}
+}
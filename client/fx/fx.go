@@ -0,0 +1,92 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	"wallet/internal/currency"
+	"wallet/internal/serr"
+)
+
+type RateProvider interface {
+	GetRate(ctx context.Context, from, to string) (*Rate, error)
+}
+
+// Rate is the price of one major unit of From in To. Spread is the fraction kept from every conversion,
+// e.g. 0.005 for half a percent.
+type Rate struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Rate   float64 `json:"rate"`
+	Spread float64 `json:"spread"`
+}
+
+// Convert converts an amount in minor units of From to minor units of To after taking the spread,
+// rounding down so conversions never credit more than they debit.
+func (r *Rate) Convert(amount int64) int64 {
+	v := new(big.Rat).SetInt64(amount)
+	v.Mul(v, decimal(r.Rate))
+	v.Mul(v, new(big.Rat).Sub(big.NewRat(1, 1), decimal(r.Spread)))
+	v.Mul(v, pow10(currency.MinorUnits(r.To)-currency.MinorUnits(r.From)))
+	return new(big.Int).Quo(v.Num(), v.Denom()).Int64()
+}
+
+// decimal reads f as its shortest decimal representation, so 0.005 is exactly 5/1000.
+func decimal(f float64) *big.Rat {
+	v, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'f', -1, 64))
+	return v
+}
+
+func pow10(n int) *big.Rat {
+	if n < 0 {
+		return new(big.Rat).Inv(pow10(-n))
+	}
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil))
+}
+
+type HTTPClient struct {
+	address    string
+	httpClient *http.Client
+}
+
+func NewHTTPClient(address string) *HTTPClient {
+	c := &HTTPClient{address: address}
+	c.httpClient = &http.Client{Timeout: time.Second * 10}
+	return c
+}
+
+func (r *HTTPClient) GetRate(ctx context.Context, from, to string) (*Rate, error) {
+	query := url.Values{"from": {from}, "to": {to}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/rate?%s", r.address, query.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		var result map[string]interface{}
+		err = json.NewDecoder(res.Body).Decode(&result)
+		if err != nil {
+			return nil, err
+		}
+		message, _ := result["message"].(string)
+		return nil, serr.ValidationErr("getRate", message, serr.ErrFXClient)
+	}
+	var rate Rate
+	err = json.NewDecoder(res.Body).Decode(&rate)
+	if err != nil {
+		return nil, err
+	}
+	if rate.Rate <= 0 || rate.Spread < 0 || rate.Spread >= 1 {
+		return nil, serr.ValidationErr("getRate", "invalid exchange rate", serr.ErrFXClient)
+	}
+	return &rate, nil
+}
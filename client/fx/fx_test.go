@@ -0,0 +1,53 @@
+package fx_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"wallet/client/fx"
+	"wallet/internal/serr"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateConvert(t *testing.T) {
+	t.Run("same precision", func(t *testing.T) {
+		r := &fx.Rate{From: "USD", To: "EUR", Rate: 0.92, Spread: 0.005}
+		// 10.00 USD is 9.20 EUR, less the half percent spread
+		assert.Equal(t, int64(915), r.Convert(1000))
+	})
+
+	t.Run("to fewer minor units", func(t *testing.T) {
+		r := &fx.Rate{From: "USD", To: "IRR", Rate: 580000}
+		assert.Equal(t, int64(5800), r.Convert(1))
+	})
+
+	t.Run("to more minor units", func(t *testing.T) {
+		r := &fx.Rate{From: "IRR", To: "USD", Rate: 0.0000017}
+		assert.Equal(t, int64(17), r.Convert(100000))
+	})
+
+	t.Run("rounds down", func(t *testing.T) {
+		r := &fx.Rate{From: "EUR", To: "USD", Rate: 1.08, Spread: 0.002}
+		assert.Equal(t, int64(1), r.Convert(1))
+	})
+}
+
+func TestStaticProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[{"from":"usd","to":"eur","rate":0.92,"spread":0.002}]`), 0o600))
+	p, err := fx.NewStaticProvider(path)
+	require.NoError(t, err)
+
+	r, err := p.GetRate(context.Background(), "USD", "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, &fx.Rate{From: "USD", To: "EUR", Rate: 0.92, Spread: 0.002}, r)
+
+	_, err = p.GetRate(context.Background(), "EUR", "USD")
+	var e *serr.ServiceError
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrRateNotFound, e.ErrorCode)
+}
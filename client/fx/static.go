@@ -0,0 +1,45 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"wallet/internal/serr"
+)
+
+// StaticProvider serves fixed rates loaded from a JSON file holding a list of rates.
+type StaticProvider struct {
+	rates map[string]*Rate
+}
+
+func NewStaticProvider(path string) (*StaticProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rates []*Rate
+	if err = json.Unmarshal(data, &rates); err != nil {
+		return nil, fmt.Errorf("failed to parse rates file: %w", err)
+	}
+	p := &StaticProvider{rates: make(map[string]*Rate, len(rates))}
+	for _, r := range rates {
+		r.From, r.To = strings.ToUpper(r.From), strings.ToUpper(r.To)
+		p.rates[pair(r.From, r.To)] = r
+	}
+	return p, nil
+}
+
+func (p *StaticProvider) GetRate(_ context.Context, from, to string) (*Rate, error) {
+	r, ok := p.rates[pair(strings.ToUpper(from), strings.ToUpper(to))]
+	if !ok {
+		return nil, serr.ValidationErr("getRate", "exchange rate not found", serr.ErrRateNotFound)
+	}
+	rate := *r
+	return &rate, nil
+}
+
+func pair(from, to string) string {
+	return from + "/" + to
+}
@@ -3,10 +3,24 @@ package main
 import (
 	"database/sql"
 	"log"
+	"time"
 	"wallet/client/discount"
+	fxClient "wallet/client/fx"
 	"wallet/db"
+	"wallet/internal/auth"
 	"wallet/internal/config"
 	"wallet/server"
+	"wallet/service/apikey"
+	"wallet/service/gift"
+	"wallet/service/limit"
+	"wallet/service/outbox"
+	"wallet/service/statement"
+	"wallet/service/webhook"
+	apikeyStorage "wallet/storage/apikey"
+	limitStorage "wallet/storage/limit"
+	memberStorage "wallet/storage/member"
+	transStorage "wallet/storage/transaction"
+	walletStorage "wallet/storage/wallet"
 )
 
 func postgresDB() *sql.DB {
@@ -28,12 +42,80 @@ func redisDB() db.RedisClient {
 	return rdb
 }
 
-func externalClients() discount.Client {
-	giftRepo := discount.NewHTTPClient(config.APIDiscount())
-	return giftRepo
+// externalClients calls the discount api, or serves the gifts of the local engine when
+// api.discount.engine is local.
+func externalClients(rdb db.RedisClient, gifts gift.UseCase) discount.Client {
+	switch engine := config.APIDiscountEngine(); engine {
+	case "local":
+		return gifts
+	case "http", "":
+	default:
+		log.Fatalf("unknown discount engine %q", engine)
+	}
+	client := discount.NewHTTPClient(config.APIDiscount(),
+		discount.WithTimeout(config.APIDiscountTimeout()),
+		discount.WithRetries(config.APIDiscountRetries(), config.APIDiscountRetryDelay()),
+		discount.WithBreaker(config.APIDiscountBreakerFailures(), config.APIDiscountBreakerCooldown()),
+	)
+	if ttl := config.APIDiscountCacheTTL(); ttl > 0 {
+		return discount.NewCachedClient(client, rdb, ttl)
+	}
+	return client
+}
+
+func rateProvider() fxClient.RateProvider {
+	if path := config.FXRatesFile(); path != "" {
+		p, err := fxClient.NewStaticProvider(path)
+		if err != nil {
+			log.Fatalf("failed to load fx rates: %v", err)
+		}
+		return p
+	}
+	return fxClient.NewHTTPClient(config.APIFX())
+}
+
+func statementExporter() *statement.Exporter {
+	e, err := statement.NewExporter(config.StatementFont(), config.StatementBoldFont())
+	if err != nil {
+		log.Fatalf("failed to load statement fonts: %v", err)
+	}
+	return e
+}
+
+// eventPublisher publishes outbox events to the log, until a message broker is plugged in, and to
+// the webhooks subscribed to them.
+func eventPublisher(webhooks webhook.UseCase) outbox.Publisher {
+	return outbox.Publishers{outbox.LogPublisher{}, webhooks}
+}
+
+func authVerifier() *auth.Verifier {
+	v, err := auth.NewVerifier(config.JWTAlgorithm(), config.JWTSecret(), config.JWTPublicKeyFile(), config.JWTIssuer())
+	if err != nil {
+		log.Fatalf("failed to initialize auth: %v", err)
+	}
+	return v
+}
+
+func apiKeyService(repo apikeyStorage.Repository) *apikey.Service {
+	return apikey.New(repo, config.APIKeyRotationGrace())
+}
+
+// spendingLimits counts the daily and monthly debits of wallets in the limits.timezone zone.
+func spendingLimits(
+	limits limitStorage.Repository,
+	members memberStorage.Repository,
+	wallets walletStorage.Repository,
+	transactions transStorage.Repository,
+) *limit.Service {
+	location, err := time.LoadLocation(config.LimitTimezone())
+	if err != nil {
+		log.Fatalf("failed to load limits timezone: %v", err)
+	}
+	return limit.New(limits, members, wallets, transactions, location)
 }
 
-func setupServer(s *server.Server, psql *sql.DB) {
-	s.SetHealthFunc(healthFunc(psql)).
+func setupServer(s *server.Server, psql *sql.DB, apiKeys apikey.UseCase) {
+	s.WithMiddlewares(server.WithAPIKey(apiKeys)).
+		SetHealthFunc(healthFunc(psql)).
 		SetupRoutes()
 }
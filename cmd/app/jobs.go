@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"time"
+	"wallet/internal/config"
+	auditService "wallet/service/audit"
+	outboxService "wallet/service/outbox"
+	transService "wallet/service/transaction"
+	walletService "wallet/service/wallet"
+	webhookService "wallet/service/webhook"
+
+	"github.com/rs/zerolog/log"
+	"go.uber.org/fx"
+)
+
+// runHoldExpiry releases expired holds every jobs.holds.expiryInterval while the app runs.
+func runHoldExpiry(lc fx.Lifecycle, w walletService.UseCase) {
+	every(lc, config.HoldExpiryInterval(), func(ctx context.Context) {
+		n, err := w.ExpireHolds(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("failed to expire holds")
+		} else if n > 0 {
+			log.Info().Int("expired", n).Msg("expired holds")
+		}
+	})
+}
+
+// runAuditSealing chains the audit log entries written since every jobs.audit.sealInterval while the
+// app runs.
+func runAuditSealing(lc fx.Lifecycle, a auditService.UseCase) {
+	every(lc, config.AuditSealInterval(), func(ctx context.Context) {
+		if _, err := a.Seal(ctx); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("failed to seal audit log entries")
+		}
+	})
+}
+
+// runOutboxRelay publishes outbox events every jobs.outbox.relayInterval while the app runs.
+func runOutboxRelay(lc fx.Lifecycle, relay *outboxService.Relay) {
+	every(lc, config.OutboxRelayInterval(), func(ctx context.Context) {
+		if _, err := relay.Drain(ctx); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("failed to publish outbox events")
+		}
+	})
+}
+
+// runWebhookDelivery sends due webhook deliveries every jobs.webhooks.deliveryInterval while the app
+// runs.
+func runWebhookDelivery(lc fx.Lifecycle, w webhookService.UseCase) {
+	every(lc, config.WebhookDeliveryInterval(), func(ctx context.Context) {
+		if _, err := w.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("failed to send webhook deliveries")
+		}
+	})
+}
+
+// runArchival archives the transactions of wallets closed or deleted for jobs.archive.after, every
+// jobs.archive.interval while the app runs.
+func runArchival(lc fx.Lifecycle, t transService.UseCase) {
+	every(lc, config.ArchiveInterval(), func(ctx context.Context) {
+		n, err := t.Archive(ctx, time.Now().Add(-config.ArchiveAfter()))
+		if err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("failed to archive transactions")
+		} else if n > 0 {
+			log.Info().Int64("archived", n).Msg("archived transactions")
+		}
+	})
+}
+
+// runRedemptionRecovery settles the gift redemptions left pending or used for jobs.redemptions.after,
+// every jobs.redemptions.interval while the app runs.
+func runRedemptionRecovery(lc fx.Lifecycle, w walletService.UseCase) {
+	every(lc, config.RedemptionRecoveryInterval(), func(ctx context.Context) {
+		n, err := w.RecoverRedemptions(ctx, time.Now().Add(-config.RedemptionRecoveryAfter()))
+		if err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("failed to recover gift redemptions")
+		} else if n > 0 {
+			log.Info().Int("settled", n).Msg("recovered gift redemptions")
+		}
+	})
+}
+
+// every runs job on each tick of interval between the start and stop of the app, a non positive
+// interval disables it. The context of job is canceled when the app stops.
+func every(lc fx.Lifecycle, interval time.Duration, job func(ctx context.Context)) {
+	if interval <= 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+						job(ctx)
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			<-done
+			return nil
+		},
+	})
+}
@@ -8,12 +8,31 @@ import (
 	"wallet/internal/locale"
 	"wallet/internal/logger"
 	"wallet/server"
+	apikeyService "wallet/service/apikey"
+	auditService "wallet/service/audit"
+	giftService "wallet/service/gift"
+	limitService "wallet/service/limit"
 	memberService "wallet/service/member"
+	outboxService "wallet/service/outbox"
 	transService "wallet/service/transaction"
 	walletService "wallet/service/wallet"
+	webhookService "wallet/service/webhook"
+	apikeyStorage "wallet/storage/apikey"
+	auditStorage "wallet/storage/audit"
+	giftStorage "wallet/storage/gift"
+	holdStorage "wallet/storage/hold"
+	idempotencyStorage "wallet/storage/idempotency"
+	ledgerStorage "wallet/storage/ledger"
+	limitStorage "wallet/storage/limit"
 	memberStorage "wallet/storage/member"
+	outboxStorage "wallet/storage/outbox"
+	redemptionStorage "wallet/storage/redemption"
 	transStorage "wallet/storage/transaction"
 	walletStorage "wallet/storage/wallet"
+	webhookStorage "wallet/storage/webhook"
+
+	// the limits timezone is loaded on hosts without a zoneinfo database too
+	_ "time/tzdata"
 )
 
 func main() {
@@ -24,6 +43,10 @@ func main() {
 
 			// clients
 			externalClients,
+			rateProvider,
+			statementExporter,
+			eventPublisher,
+			authVerifier,
 
 			// storages
 			fx.Annotate(
@@ -38,6 +61,46 @@ func main() {
 				transStorage.NewStorage,
 				fx.As(new(transStorage.Repository)),
 			),
+			fx.Annotate(
+				idempotencyStorage.NewStorage,
+				fx.As(new(idempotencyStorage.Repository)),
+			),
+			fx.Annotate(
+				ledgerStorage.NewStorage,
+				fx.As(new(ledgerStorage.Repository)),
+			),
+			fx.Annotate(
+				holdStorage.NewStorage,
+				fx.As(new(holdStorage.Repository)),
+			),
+			fx.Annotate(
+				outboxStorage.NewStorage,
+				fx.As(new(outboxStorage.Repository)),
+			),
+			fx.Annotate(
+				webhookStorage.NewStorage,
+				fx.As(new(webhookStorage.Repository)),
+			),
+			fx.Annotate(
+				apikeyStorage.NewStorage,
+				fx.As(new(apikeyStorage.Repository)),
+			),
+			fx.Annotate(
+				limitStorage.NewStorage,
+				fx.As(new(limitStorage.Repository)),
+			),
+			fx.Annotate(
+				auditStorage.NewStorage,
+				fx.As(new(auditStorage.Repository)),
+			),
+			fx.Annotate(
+				redemptionStorage.NewStorage,
+				fx.As(new(redemptionStorage.Repository)),
+			),
+			fx.Annotate(
+				giftStorage.NewStorage,
+				fx.As(new(giftStorage.Repository)),
+			),
 
 			// services
 			fx.Annotate(
@@ -45,6 +108,11 @@ func main() {
 				fx.As(new(transService.UseCase)),
 			),
 
+			fx.Annotate(
+				spendingLimits,
+				fx.As(new(limitService.UseCase)),
+			),
+
 			fx.Annotate(
 				walletService.New,
 				fx.As(new(walletService.UseCase)),
@@ -55,9 +123,35 @@ func main() {
 				fx.As(new(memberService.UseCase)),
 			),
 
+			fx.Annotate(
+				webhookService.New,
+				fx.As(new(webhookService.UseCase)),
+			),
+
+			fx.Annotate(
+				apiKeyService,
+				fx.As(new(apikeyService.UseCase)),
+			),
+
+			fx.Annotate(
+				auditService.New,
+				fx.As(new(auditService.UseCase)),
+			),
+
+			fx.Annotate(
+				giftService.New,
+				fx.As(new(giftService.UseCase)),
+			),
+
+			outboxService.NewRelay,
+
 			// handlers
 			handler.NewMemberHandler,
 			handler.NewWalletHandler,
+			handler.NewWebhookHandler,
+			handler.NewAPIKeyHandler,
+			handler.NewAuditHandler,
+			handler.NewGiftHandler,
 
 			// server
 			server.NewServer,
@@ -71,7 +165,17 @@ func main() {
 			setupServer,
 			handler.SetupMemberRoutes,
 			handler.SetupWalletRoutes,
+			handler.SetupWebhookRoutes,
+			handler.SetupAPIKeyRoutes,
+			handler.SetupAuditRoutes,
+			handler.SetupGiftRoutes,
 			server.Run,
+			runHoldExpiry,
+			runOutboxRelay,
+			runWebhookDelivery,
+			runArchival,
+			runRedemptionRecovery,
+			runAuditSealing,
 		),
 	).Run()
 }
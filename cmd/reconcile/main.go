@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/rs/zerolog/log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	"wallet/db"
+	"wallet/internal/config"
+	"wallet/internal/logger"
+	reconService "wallet/service/reconciliation"
+	ledgerStorage "wallet/storage/ledger"
+	reconStorage "wallet/storage/reconciliation"
+	transStorage "wallet/storage/transaction"
+	walletStorage "wallet/storage/wallet"
+)
+
+// reconcile compares wallet balances with their transaction history once, or every interval until
+// it is stopped, and writes mismatches into reconciliation_report.
+func main() {
+	config.Init()
+	repair := flag.Bool("repair", config.ReconciliationRepair(), "correct mismatched wallets")
+	interval := flag.Duration("interval", config.ReconciliationInterval(), "run every interval instead of once")
+	flag.Parse()
+	if err := logger.SetupLogger(); err != nil {
+		log.Fatal().Err(err).Msg("failed to setup logger")
+	}
+	psql, err := db.NewPostgres(
+		config.DBName(), config.DBUser(), config.DBPassword(), config.DBHost(), config.DBPort(),
+		config.DBMaxOpenConn(), config.DBMaxIdleConn(),
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize db")
+	}
+	s := reconService.New(
+		walletStorage.NewStorage(psql),
+		transStorage.NewStorage(psql),
+		ledgerStorage.NewStorage(psql),
+		reconStorage.NewStorage(psql),
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	for {
+		summary, err := s.Run(ctx, *repair)
+		if err != nil {
+			log.Error().Err(err).Msg("reconciliation failed")
+		}
+		if summary != nil {
+			log.Info().Interface("summary", summary).Msg("reconciliation finished")
+		}
+		if *interval <= 0 {
+			if err != nil {
+				os.Exit(1)
+			}
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(*interval):
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Package dbtest connects tests to the postgres database given by the WALLET_TEST_DB_* environment
+// variables.
+package dbtest
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+	"wallet/db"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// Postgres connects to the test database migrated to the latest version and skips the test when
+// WALLET_TEST_DB_HOST is not set. Tests share the database, the rows they write have to be told
+// apart from the rows of other tests.
+func Postgres(t *testing.T) *sql.DB {
+	t.Helper()
+	host := os.Getenv("WALLET_TEST_DB_HOST")
+	if host == "" {
+		t.Skip("WALLET_TEST_DB_HOST is not set")
+	}
+	port := os.Getenv("WALLET_TEST_DB_PORT")
+	if port == "" {
+		port = "5432"
+	}
+	psql, err := db.NewPostgres(os.Getenv("WALLET_TEST_DB_NAME"), os.Getenv("WALLET_TEST_DB_USER"),
+		os.Getenv("WALLET_TEST_DB_PASSWORD"), host, port, 20, 5)
+	require.NoError(t, err)
+	_, file, _, _ := runtime.Caller(0)
+	viper.Set("db.postgres.migrationsPath", filepath.Join(filepath.Dir(file), "..", "migrations"))
+	require.NoError(t, db.Migrate(psql))
+	return psql
+}
+
+var phones atomic.Int64
+
+// Wallet creates a member with a wallet of balance in the default currency and returns the wallet id.
+func Wallet(t *testing.T, psql *sql.DB, balance int64) int64 {
+	t.Helper()
+	phone := fmt.Sprintf("+98%d", (time.Now().UnixNano()+phones.Add(1))%1e10)
+	var memberID, walletID int64
+	require.NoError(t, psql.QueryRow("INSERT INTO member (phone) VALUES ($1) RETURNING id", phone).Scan(&memberID))
+	require.NoError(t, psql.QueryRow("INSERT INTO wallet (member_id, balance) VALUES ($1, $2) RETURNING id",
+		memberID, balance).Scan(&walletID))
+	return walletID
+}
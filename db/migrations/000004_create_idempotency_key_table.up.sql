@@ -0,0 +1,10 @@
+CREATE TABLE "idempotency_key"
+(
+    key          VARCHAR(255) PRIMARY KEY,
+    request_hash VARCHAR(64) NOT NULL,
+    response     JSONB       NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+
+CREATE INDEX ON "idempotency_key" (created_at);
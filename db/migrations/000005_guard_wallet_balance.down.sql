@@ -0,0 +1,2 @@
+ALTER TABLE "wallet" DROP CONSTRAINT IF EXISTS wallet_balance_non_negative;
+ALTER TABLE "wallet" ALTER COLUMN balance DROP NOT NULL;
@@ -0,0 +1,6 @@
+UPDATE "wallet" SET balance = 0 WHERE balance IS NULL;
+
+ALTER TABLE "wallet" ALTER COLUMN balance SET NOT NULL;
+-- wallets that already went negative do not stop the deploy, new writes are checked right away and
+-- the existing rows are validated by 000027 once they are fixed
+ALTER TABLE "wallet" ADD CONSTRAINT wallet_balance_non_negative CHECK (balance >= 0) NOT VALID;
@@ -0,0 +1 @@
+ALTER TYPE "transaction_type" RENAME VALUE 'withdraw' TO 'withdrawal';
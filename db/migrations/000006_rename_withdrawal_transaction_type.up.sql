@@ -0,0 +1 @@
+ALTER TYPE "transaction_type" RENAME VALUE 'withdrawal' TO 'withdraw';
@@ -0,0 +1,6 @@
+ALTER TABLE "transaction" DROP COLUMN IF EXISTS journal_entry_id;
+DROP TABLE IF EXISTS "posting";
+DROP TABLE IF EXISTS "journal_entry";
+DROP FUNCTION IF EXISTS check_journal_entry_balanced();
+DROP FUNCTION IF EXISTS reject_posting_change();
+DROP TYPE IF EXISTS "posting_direction";
@@ -0,0 +1,85 @@
+CREATE TYPE "posting_direction" AS ENUM (
+    'debit',
+    'credit'
+    );
+
+CREATE TABLE "journal_entry"
+(
+    id          SERIAL PRIMARY KEY,
+    description VARCHAR(255),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- a posting belongs either to a wallet or to a system account such as cash_in or gift_funding
+CREATE TABLE "posting"
+(
+    id               SERIAL PRIMARY KEY,
+    journal_entry_id INT               NOT NULL REFERENCES "journal_entry" (id),
+    wallet_id        INT,
+    account          VARCHAR(50),
+    direction        posting_direction NOT NULL,
+    amount           DECIMAL(20, 0)    NOT NULL CHECK (amount > 0),
+    created_at       TIMESTAMPTZ       NOT NULL DEFAULT now(),
+    CHECK ((wallet_id IS NULL) <> (account IS NULL))
+);
+
+
+CREATE INDEX ON "posting" (journal_entry_id);
+CREATE INDEX ON "posting" (wallet_id);
+CREATE INDEX ON "posting" (account);
+
+CREATE FUNCTION check_journal_entry_balanced() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF (SELECT sum(CASE WHEN direction = 'debit' THEN amount ELSE -amount END)
+        FROM "posting"
+        WHERE journal_entry_id = NEW.journal_entry_id) <> 0 THEN
+        RAISE EXCEPTION 'journal entry % is not balanced', NEW.journal_entry_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- checked at commit so all postings of an entry can be inserted first
+CREATE CONSTRAINT TRIGGER posting_balanced
+    AFTER INSERT
+    ON "posting"
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW
+EXECUTE FUNCTION check_journal_entry_balanced();
+
+CREATE FUNCTION reject_posting_change() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'postings are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER posting_immutable
+    BEFORE UPDATE OR DELETE
+    ON "posting"
+    FOR EACH ROW
+EXECUTE FUNCTION reject_posting_change();
+
+ALTER TABLE "transaction"
+    ADD COLUMN journal_entry_id INT REFERENCES "journal_entry" (id);
+
+CREATE INDEX ON "transaction" (journal_entry_id);
+
+-- open the ledger with the balances wallets already have
+DO
+$$
+    DECLARE
+        w        RECORD;
+        entry_id INT;
+    BEGIN
+        FOR w IN SELECT id, balance FROM "wallet" WHERE balance > 0
+            LOOP
+                INSERT INTO "journal_entry" (description) VALUES ('opening balance') RETURNING id INTO entry_id;
+                INSERT INTO "posting" (journal_entry_id, account, direction, amount)
+                VALUES (entry_id, 'opening_balance', 'debit', w.balance);
+                INSERT INTO "posting" (journal_entry_id, wallet_id, direction, amount)
+                VALUES (entry_id, w.id, 'credit', w.balance);
+            END LOOP;
+    END
+$$;
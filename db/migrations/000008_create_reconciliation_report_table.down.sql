@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "reconciliation_report";
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TABLE "reconciliation_report"
+(
+    id                  SERIAL PRIMARY KEY,
+    run_id              VARCHAR(36)    NOT NULL,
+    wallet_id           INT            NOT NULL,
+    wallet_balance      DECIMAL(20, 0) NOT NULL,
+    transaction_balance DECIMAL(20, 0) NOT NULL,
+    ledger_balance      DECIMAL(20, 0) NOT NULL,
+    difference          DECIMAL(20, 0) NOT NULL,
+    repaired            BOOLEAN        NOT NULL DEFAULT false,
+    correction_entry_id INT REFERENCES "journal_entry" (id),
+    created_at          TIMESTAMPTZ    NOT NULL DEFAULT now()
+);
+
+
+CREATE INDEX ON "reconciliation_report" (run_id);
+CREATE INDEX ON "reconciliation_report" (wallet_id);
@@ -0,0 +1,15 @@
+CREATE OR REPLACE FUNCTION check_journal_entry_balanced() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF (SELECT sum(CASE WHEN direction = 'debit' THEN amount ELSE -amount END)
+        FROM "posting"
+        WHERE journal_entry_id = NEW.journal_entry_id) <> 0 THEN
+        RAISE EXCEPTION 'journal entry % is not balanced', NEW.journal_entry_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE "posting" DROP COLUMN IF EXISTS currency;
+ALTER TABLE "transaction" DROP COLUMN IF EXISTS currency;
+ALTER TABLE "wallet" DROP COLUMN IF EXISTS currency;
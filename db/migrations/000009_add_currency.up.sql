@@ -0,0 +1,23 @@
+ALTER TABLE "wallet"
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IRR';
+ALTER TABLE "transaction"
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IRR';
+ALTER TABLE "posting"
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'IRR';
+
+CREATE INDEX ON "wallet" (member_id, currency);
+
+-- an entry may move several currencies but each of them has to balance on its own
+CREATE OR REPLACE FUNCTION check_journal_entry_balanced() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF EXISTS(SELECT currency
+              FROM "posting"
+              WHERE journal_entry_id = NEW.journal_entry_id
+              GROUP BY currency
+              HAVING sum(CASE WHEN direction = 'debit' THEN amount ELSE -amount END) <> 0) THEN
+        RAISE EXCEPTION 'journal entry % is not balanced', NEW.journal_entry_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,5 @@
+ALTER TABLE "transaction"
+    DROP COLUMN fx_rate,
+    DROP COLUMN fx_spread,
+    DROP COLUMN counter_amount,
+    DROP COLUMN counter_currency;
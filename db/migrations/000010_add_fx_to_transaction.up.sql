@@ -0,0 +1,7 @@
+-- a converted transfer records the rate it used and the amount on the other side of the conversion
+ALTER TABLE "transaction"
+    ADD COLUMN fx_rate          NUMERIC(30, 12),
+    ADD COLUMN fx_spread        NUMERIC(10, 6),
+    ADD COLUMN counter_amount   BIGINT,
+    ADD COLUMN counter_currency CHAR(3),
+    ADD CHECK ((fx_rate IS NULL) = (counter_currency IS NULL));
@@ -0,0 +1,3 @@
+ALTER TABLE "transaction"
+    DROP COLUMN merchant_reference,
+    DROP COLUMN order_id;
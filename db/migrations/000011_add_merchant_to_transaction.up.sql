@@ -0,0 +1,6 @@
+-- payments record the merchant and its order so they can be looked up and refunded
+ALTER TABLE "transaction"
+    ADD COLUMN merchant_reference VARCHAR(255),
+    ADD COLUMN order_id           VARCHAR(255);
+
+CREATE INDEX ON "transaction" (merchant_reference, order_id);
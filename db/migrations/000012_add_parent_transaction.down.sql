@@ -0,0 +1,2 @@
+ALTER TABLE "transaction"
+    DROP COLUMN parent_transaction_id;
@@ -0,0 +1,5 @@
+-- refunds point at the withdraw or payment they give back
+ALTER TABLE "transaction"
+    ADD COLUMN parent_transaction_id BIGINT REFERENCES "transaction" (id);
+
+CREATE INDEX ON "transaction" (parent_transaction_id);
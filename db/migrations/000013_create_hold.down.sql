@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "hold";
+ALTER TABLE "wallet"
+    DROP COLUMN held_balance;
+DROP TYPE IF EXISTS "hold_status";
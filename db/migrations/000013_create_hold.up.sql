@@ -0,0 +1,28 @@
+CREATE TYPE "hold_status" AS ENUM (
+    'authorized',
+    'captured',
+    'voided',
+    'expired'
+    );
+
+-- authorized holds reserve part of the balance, held_balance is their sum. Wallets still negative
+-- from before 000005 are validated against the balance by 000027.
+ALTER TABLE "wallet"
+    ADD COLUMN held_balance BIGINT NOT NULL DEFAULT 0 CHECK (held_balance >= 0),
+    ADD CONSTRAINT wallet_held_within_balance CHECK (held_balance <= balance) NOT VALID;
+
+CREATE TABLE IF NOT EXISTS "hold"
+(
+    id              BIGSERIAL PRIMARY KEY,
+    wallet_id       BIGINT        NOT NULL REFERENCES "wallet" (id) ON DELETE CASCADE,
+    amount          BIGINT        NOT NULL CHECK (amount > 0),
+    captured_amount BIGINT        NOT NULL DEFAULT 0 CHECK (captured_amount >= 0 AND captured_amount <= amount),
+    currency        CHAR(3)       NOT NULL,
+    status          "hold_status" NOT NULL DEFAULT 'authorized',
+    expires_at      TIMESTAMPTZ   NOT NULL,
+    created_at      TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ   NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON "hold" (wallet_id);
+CREATE INDEX ON "hold" (expires_at) WHERE status = 'authorized';
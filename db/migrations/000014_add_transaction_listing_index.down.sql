@@ -0,0 +1 @@
+DROP INDEX IF EXISTS transaction_wallet_id_created_at_id_idx;
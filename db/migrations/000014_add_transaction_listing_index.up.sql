@@ -0,0 +1,2 @@
+-- wallet histories are listed newest first and paged by (created_at, id)
+CREATE INDEX IF NOT EXISTS transaction_wallet_id_created_at_id_idx ON "transaction" (wallet_id, created_at DESC, id DESC);
@@ -0,0 +1,14 @@
+-- domain events are written with the change they describe and published afterwards by the relay
+CREATE TABLE IF NOT EXISTS "outbox_event"
+(
+    id           BIGSERIAL PRIMARY KEY,
+    event_type   VARCHAR(64) NOT NULL,
+    wallet_id    BIGINT      NOT NULL,
+    payload      JSONB       NOT NULL,
+    attempts     INT         NOT NULL DEFAULT 0,
+    last_error   TEXT,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    published_at TIMESTAMPTZ
+);
+
+CREATE INDEX ON "outbox_event" (id) WHERE published_at IS NULL;
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS "webhook_subscription"
+(
+    id          BIGSERIAL PRIMARY KEY,
+    url         TEXT          NOT NULL,
+    event_types VARCHAR(64)[] NOT NULL,
+    secret      VARCHAR(64)   NOT NULL,
+    active      BOOLEAN       NOT NULL DEFAULT true,
+    created_at  TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ   NOT NULL DEFAULT now()
+);
+
+CREATE TYPE "webhook_delivery_status" AS ENUM (
+    'pending',
+    'succeeded',
+    'failed'
+    );
+
+-- a delivery of an outbox event to a subscription and the log of its attempts
+CREATE TABLE IF NOT EXISTS "webhook_delivery"
+(
+    id               BIGSERIAL PRIMARY KEY,
+    subscription_id  BIGINT                    NOT NULL REFERENCES "webhook_subscription" (id) ON DELETE CASCADE,
+    event_id         BIGINT                    NOT NULL,
+    event_type       VARCHAR(64)               NOT NULL,
+    payload          JSONB                     NOT NULL,
+    status           "webhook_delivery_status" NOT NULL DEFAULT 'pending',
+    attempts         INT                       NOT NULL DEFAULT 0,
+    last_status_code INT,
+    last_error       TEXT,
+    next_attempt_at  TIMESTAMPTZ               NOT NULL DEFAULT now(),
+    delivered_at     TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ               NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ               NOT NULL DEFAULT now(),
+    -- events are relayed at least once, a redelivered event must not be sent twice
+    UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX ON "webhook_delivery" (next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,14 @@
+-- keys of the services calling the api, only the sha256 of a key is kept
+CREATE TABLE IF NOT EXISTS "api_key"
+(
+    id         BIGSERIAL PRIMARY KEY,
+    name       VARCHAR(255)  NOT NULL,
+    prefix     VARCHAR(16)   NOT NULL,
+    key_hash   CHAR(64)      NOT NULL UNIQUE,
+    scopes     VARCHAR(32)[] NOT NULL,
+    -- set on the key a rotation replaced, it keeps working until then
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ   NOT NULL DEFAULT now()
+);
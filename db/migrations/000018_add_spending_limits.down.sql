@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "wallet_limit";
+ALTER TABLE "member" DROP COLUMN IF EXISTS tier;
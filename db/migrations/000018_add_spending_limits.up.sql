@@ -0,0 +1,14 @@
+ALTER TABLE "member" ADD COLUMN IF NOT EXISTS tier VARCHAR(32) NOT NULL DEFAULT 'standard';
+
+-- limits of a wallet overriding the limits of its member tier, a null limit keeps the tier one
+CREATE TABLE IF NOT EXISTS "wallet_limit"
+(
+    wallet_id             INT PRIMARY KEY REFERENCES "wallet" (id) ON DELETE CASCADE,
+    max_withdrawal        BIGINT,
+    daily_debit           BIGINT,
+    monthly_debit         BIGINT,
+    max_balance           BIGINT,
+    max_transfers_per_day INT,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT now()
+);
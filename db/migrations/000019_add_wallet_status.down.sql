@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "wallet_status_change";
+ALTER TABLE "wallet"
+    DROP COLUMN status;
+DROP TYPE IF EXISTS "wallet_status";
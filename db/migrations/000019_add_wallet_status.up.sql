@@ -0,0 +1,22 @@
+CREATE TYPE "wallet_status" AS ENUM (
+    'active',
+    'frozen',
+    'closed'
+    );
+
+ALTER TABLE "wallet"
+    ADD COLUMN status "wallet_status" NOT NULL DEFAULT 'active';
+
+-- every status change of a wallet with who made it and why
+CREATE TABLE IF NOT EXISTS "wallet_status_change"
+(
+    id          BIGSERIAL PRIMARY KEY,
+    wallet_id   BIGINT          NOT NULL REFERENCES "wallet" (id) ON DELETE CASCADE,
+    from_status "wallet_status" NOT NULL,
+    to_status   "wallet_status" NOT NULL,
+    reason      TEXT            NOT NULL,
+    actor       VARCHAR(64)     NOT NULL,
+    created_at  TIMESTAMPTZ     NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON "wallet_status_change" (wallet_id);
@@ -0,0 +1,15 @@
+DROP TRIGGER IF EXISTS transaction_immutable ON "transaction";
+DROP TABLE IF EXISTS "transaction_archive";
+DROP FUNCTION IF EXISTS reject_transaction_change();
+
+DROP INDEX IF EXISTS wallet_member_id_wallet_name_active_key;
+ALTER TABLE "wallet"
+    ADD UNIQUE (member_id, wallet_name);
+DROP INDEX IF EXISTS member_phone_active_key;
+ALTER TABLE "member"
+    ADD UNIQUE (phone);
+
+ALTER TABLE "wallet"
+    DROP COLUMN deleted_at;
+ALTER TABLE "member"
+    DROP COLUMN deleted_at;
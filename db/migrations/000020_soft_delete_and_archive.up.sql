@@ -0,0 +1,47 @@
+ALTER TABLE "member"
+    ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE "wallet"
+    ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- phones and wallet names of deleted rows can be taken again
+ALTER TABLE "member"
+    DROP CONSTRAINT IF EXISTS member_phone_key;
+CREATE UNIQUE INDEX member_phone_active_key ON "member" (phone) WHERE deleted_at IS NULL;
+ALTER TABLE "wallet"
+    DROP CONSTRAINT IF EXISTS wallet_member_id_wallet_name_key;
+CREATE UNIQUE INDEX wallet_member_id_wallet_name_active_key ON "wallet" (member_id, wallet_name) WHERE deleted_at IS NULL;
+
+-- transactions of closed and deleted wallets, moved out of "transaction" by the archival job
+CREATE TABLE IF NOT EXISTS "transaction_archive"
+(
+    LIKE "transaction" INCLUDING DEFAULTS,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX ON "transaction_archive" (wallet_id);
+
+-- transactions are never changed, they are only deleted when the archival job moves them, which it
+-- tells by setting wallet.archiving for its db transaction
+CREATE FUNCTION reject_transaction_change() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF TG_OP = 'DELETE' AND TG_TABLE_NAME = 'transaction'
+        AND coalesce(current_setting('wallet.archiving', true), '') = 'on' THEN
+        RETURN OLD;
+    END IF;
+    RAISE EXCEPTION 'transactions are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transaction_immutable
+    BEFORE UPDATE OR DELETE
+    ON "transaction"
+    FOR EACH ROW
+EXECUTE FUNCTION reject_transaction_change();
+
+CREATE TRIGGER transaction_archive_immutable
+    BEFORE UPDATE OR DELETE
+    ON "transaction_archive"
+    FOR EACH ROW
+EXECUTE FUNCTION reject_transaction_change();
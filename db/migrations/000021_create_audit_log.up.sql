@@ -0,0 +1,41 @@
+-- every administrative and financial action, written with the change it describes. Each entry
+-- carries the hash of the one before, so a changed or removed entry breaks the chain.
+CREATE TABLE IF NOT EXISTS "audit_log"
+(
+    id         BIGSERIAL PRIMARY KEY,
+    actor      VARCHAR(64)  NOT NULL,
+    action     VARCHAR(64)  NOT NULL,
+    entity     VARCHAR(32)  NOT NULL,
+    entity_id  BIGINT       NOT NULL,
+    -- JSON rather than JSONB keeps the documents byte for byte as they were hashed
+    before     JSON,
+    after      JSON,
+    trace_id   VARCHAR(64)  NOT NULL DEFAULT '',
+    client_ip  VARCHAR(64)  NOT NULL DEFAULT '',
+    prev_hash  VARCHAR(64)  NOT NULL,
+    hash       VARCHAR(64)  NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ  NOT NULL
+);
+
+CREATE INDEX ON "audit_log" (entity, entity_id);
+CREATE INDEX ON "audit_log" (actor);
+CREATE INDEX ON "audit_log" (created_at);
+
+CREATE FUNCTION reject_audit_log_change() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit log entries are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_immutable
+    BEFORE UPDATE OR DELETE
+    ON "audit_log"
+    FOR EACH ROW
+EXECUTE FUNCTION reject_audit_log_change();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE
+    ON "audit_log"
+    FOR EACH STATEMENT
+EXECUTE FUNCTION reject_audit_log_change();
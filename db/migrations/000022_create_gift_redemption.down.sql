@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "gift_redemption";
+DROP TYPE IF EXISTS "redemption_status";
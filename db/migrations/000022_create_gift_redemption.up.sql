@@ -0,0 +1,26 @@
+CREATE TYPE "redemption_status" AS ENUM (
+    'pending',
+    'used',
+    'completed',
+    'released',
+    'failed'
+    );
+
+-- a redemption is written before its gift is used on the discount service, so a use whose credit
+-- fails or is cut off can be finished or released afterwards
+CREATE TABLE IF NOT EXISTS "gift_redemption"
+(
+    id         BIGSERIAL PRIMARY KEY,
+    member_id  BIGINT              NOT NULL,
+    wallet_id  BIGINT              NOT NULL REFERENCES "wallet" (id) ON DELETE CASCADE,
+    gift_code  VARCHAR(255)        NOT NULL,
+    reference  UUID                NOT NULL UNIQUE,
+    amount     BIGINT              NOT NULL DEFAULT 0,
+    status     "redemption_status" NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMPTZ         NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ         NOT NULL DEFAULT now()
+);
+
+-- a member redeems a gift once, released and failed redemptions let it be redeemed again
+CREATE UNIQUE INDEX ON "gift_redemption" (member_id, gift_code) WHERE status IN ('pending', 'used', 'completed');
+CREATE INDEX ON "gift_redemption" (updated_at) WHERE status IN ('pending', 'used');
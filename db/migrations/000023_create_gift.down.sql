@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "gift_use";
+DROP TABLE IF EXISTS "gift";
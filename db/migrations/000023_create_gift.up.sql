@@ -0,0 +1,27 @@
+-- gifts of the local discount engine, used instead of the discount api when api.discount.engine is local
+CREATE TABLE IF NOT EXISTS "gift"
+(
+    id               BIGSERIAL PRIMARY KEY,
+    code             VARCHAR(255) NOT NULL UNIQUE,
+    amount           BIGINT       NOT NULL CHECK (amount > 0),
+    usage_limit      BIGINT       NOT NULL CHECK (usage_limit > 0),
+    used_count       BIGINT       NOT NULL DEFAULT 0 CHECK (used_count >= 0 AND used_count <= usage_limit),
+    per_member_limit BIGINT       NOT NULL DEFAULT 1 CHECK (per_member_limit > 0),
+    starts_at        TIMESTAMPTZ  NOT NULL,
+    expires_at       TIMESTAMPTZ  NOT NULL CHECK (expires_at > starts_at),
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+-- every use of a gift by the reference of the redemption it was made for, released uses no longer count
+CREATE TABLE IF NOT EXISTS "gift_use"
+(
+    id          BIGSERIAL PRIMARY KEY,
+    gift_id     BIGINT      NOT NULL REFERENCES "gift" (id) ON DELETE CASCADE,
+    member_id   BIGINT      NOT NULL,
+    reference   VARCHAR(64) NOT NULL UNIQUE,
+    released_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON "gift_use" (gift_id, member_id) WHERE released_at IS NULL;
@@ -0,0 +1,17 @@
+ALTER TABLE "reconciliation_report" DROP COLUMN IF EXISTS correction_transaction_id;
+
+-- enum values can not be dropped, the type is rebuilt without 'correction'
+ALTER TYPE "transaction_type" RENAME TO "transaction_type_old";
+CREATE TYPE "transaction_type" AS ENUM (
+    'recharge',
+    'gift',
+    'withdraw',
+    'payment',
+    'refund',
+    'transfer'
+    );
+ALTER TABLE "transaction"
+    ALTER COLUMN transaction_type TYPE "transaction_type" USING transaction_type::text::"transaction_type";
+ALTER TABLE "transaction_archive"
+    ALTER COLUMN transaction_type TYPE "transaction_type" USING transaction_type::text::"transaction_type";
+DROP TYPE "transaction_type_old";
@@ -0,0 +1,5 @@
+ALTER TYPE "transaction_type" ADD VALUE IF NOT EXISTS 'correction';
+
+-- not a foreign key, the archival job moves transactions of closed wallets out of "transaction"
+ALTER TABLE "reconciliation_report"
+    ADD COLUMN correction_transaction_id INT;
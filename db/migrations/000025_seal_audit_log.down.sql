@@ -0,0 +1,21 @@
+-- entries not sealed yet have no hash to keep, they are sealed before going back
+DO
+$$
+BEGIN
+    IF EXISTS(SELECT 1 FROM "audit_log" WHERE seq IS NULL) THEN
+        RAISE EXCEPTION 'audit log has entries the sealing job did not chain yet';
+    END IF;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION reject_audit_log_change() RETURNS TRIGGER AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit log entries are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE "audit_log"
+    DROP COLUMN seq,
+    ALTER COLUMN prev_hash SET NOT NULL,
+    ALTER COLUMN hash SET NOT NULL;
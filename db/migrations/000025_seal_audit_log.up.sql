@@ -0,0 +1,29 @@
+-- entries are written without their hashes and chained afterwards by the sealing job, in the order
+-- it sees them committed, so writers no longer wait on each other for the chain. seq is the position
+-- of an entry in the chain, entries written before keep the order of their ids.
+ALTER TABLE "audit_log"
+    ADD COLUMN seq BIGINT UNIQUE,
+    ALTER COLUMN prev_hash DROP NOT NULL,
+    ALTER COLUMN hash DROP NOT NULL;
+
+ALTER TABLE "audit_log" DISABLE TRIGGER audit_log_immutable;
+UPDATE "audit_log" SET seq = id;
+ALTER TABLE "audit_log" ENABLE TRIGGER audit_log_immutable;
+
+CREATE INDEX ON "audit_log" (id) WHERE seq IS NULL;
+
+-- the sealing job sets the chain fields of an entry once, nothing else about an entry ever changes
+CREATE OR REPLACE FUNCTION reject_audit_log_change() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD.seq IS NULL AND NEW.seq IS NOT NULL AND NEW.hash IS NOT NULL
+        AND (NEW.id, NEW.actor, NEW.action, NEW.entity, NEW.entity_id, NEW.before::text, NEW.after::text,
+             NEW.trace_id, NEW.client_ip, NEW.created_at)
+            IS NOT DISTINCT FROM
+            (OLD.id, OLD.actor, OLD.action, OLD.entity, OLD.entity_id, OLD.before::text, OLD.after::text,
+             OLD.trace_id, OLD.client_ip, OLD.created_at) THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'audit log entries are immutable';
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "gift_redemption_member_id_gift_code_idx";
+ALTER TABLE "gift_redemption"
+    DROP COLUMN once;
+
+CREATE UNIQUE INDEX ON "gift_redemption" (member_id, gift_code) WHERE status IN ('pending', 'used', 'completed');
@@ -0,0 +1,7 @@
+-- gifts of the local engine limit the uses of each member themselves and may be redeemed more than
+-- once, only redemptions of gifts redeemed once keep a member to one active redemption
+ALTER TABLE "gift_redemption"
+    ADD COLUMN once BOOLEAN NOT NULL DEFAULT true;
+
+DROP INDEX IF EXISTS "gift_redemption_member_id_gift_code_idx";
+CREATE UNIQUE INDEX ON "gift_redemption" (member_id, gift_code) WHERE once AND status IN ('pending', 'used', 'completed');
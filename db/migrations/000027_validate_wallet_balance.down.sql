@@ -0,0 +1,4 @@
+ALTER TABLE "wallet" DROP CONSTRAINT IF EXISTS wallet_held_within_balance;
+ALTER TABLE "wallet" ADD CONSTRAINT wallet_held_within_balance CHECK (held_balance <= balance) NOT VALID;
+ALTER TABLE "wallet" DROP CONSTRAINT IF EXISTS wallet_balance_non_negative;
+ALTER TABLE "wallet" ADD CONSTRAINT wallet_balance_non_negative CHECK (balance >= 0) NOT VALID;
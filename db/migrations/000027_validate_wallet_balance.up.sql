@@ -0,0 +1,17 @@
+-- wallets left negative from before 000005 are reported and keep the balance constraints
+-- unvalidated, go run ./cmd/reconcile -repair sets them to the ledger and the constraints are validated
+-- by hand afterwards
+DO
+$$
+DECLARE
+    negative TEXT;
+BEGIN
+    SELECT string_agg(id::TEXT, ', ' ORDER BY id) INTO negative FROM "wallet" WHERE balance < 0;
+    IF negative IS NULL THEN
+        ALTER TABLE "wallet" VALIDATE CONSTRAINT wallet_balance_non_negative;
+        ALTER TABLE "wallet" VALIDATE CONSTRAINT wallet_held_within_balance;
+    ELSE
+        RAISE WARNING 'wallets % have a negative balance, validate the constraints wallet_balance_non_negative and wallet_held_within_balance once they are fixed', negative;
+    END IF;
+END;
+$$;
@@ -8,8 +8,7 @@ import (
 )
 
 var (
-	ErrAlreadyInTX    = errors.New("storage already running in a tx")
-	ErrNoTXProvided   = errors.New("no tx provided")
+	ErrNotInTX        = errors.New("storage not running in a tx")
 	ErrDBNoTInitiated = errors.New("db not initiated")
 )
 
@@ -48,23 +47,7 @@ type Scanner interface {
 }
 
 type SQLExt interface {
-	QueryRow(query string, args ...any) *sql.Row
-	Query(query string, args ...any) (*sql.Rows, error)
-	Exec(query string, args ...any) (sql.Result, error)
-}
-
-func Transaction(ctx context.Context, fn func(tx *sql.Tx) error) error {
-	db, err := sqlDB()
-	if err != nil {
-		return err
-	}
-	tx, err := db.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	if err = fn(tx); err != nil {
-		_ = tx.Rollback()
-		return err
-	}
-	return tx.Commit()
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
 }
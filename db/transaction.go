@@ -0,0 +1,160 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// defaultTxAttempts is how many times a transaction failing on serialization is run
+	defaultTxAttempts = 3
+	// txRetryDelay is the base of the jittered delay before a transaction is run again
+	txRetryDelay = 20 * time.Millisecond
+	// serializationFailure is the error postgres fails a transaction with when it can not be
+	// serialized with the transactions running next to it
+	serializationFailure = "40001"
+)
+
+type txKey struct{}
+
+// unit is the db transaction carried by a context and its open savepoints.
+type unit struct {
+	tx         *sql.Tx
+	savepoints int
+}
+
+// TxOption configures a transaction started by Transaction.
+type TxOption func(*txOptions)
+
+type txOptions struct {
+	sql.TxOptions
+	attempts int
+}
+
+// Isolation runs the transaction at level, the default level of the db is used otherwise.
+func Isolation(level sql.IsolationLevel) TxOption {
+	return func(o *txOptions) {
+		o.Isolation = level
+	}
+}
+
+// ReadOnly runs a transaction that only reads.
+func ReadOnly() TxOption {
+	return func(o *txOptions) {
+		o.ReadOnly = true
+	}
+}
+
+// Attempts sets how many times a transaction failing on serialization is run, 3 by default.
+func Attempts(n int) TxOption {
+	return func(o *txOptions) {
+		o.attempts = n
+	}
+}
+
+// Transaction runs fn in a db transaction carried by the context fn gets, the storages given that
+// context run their queries in it. Called in a transaction it joins it instead, fn runs in a
+// savepoint rolled back on its own when fn fails and the options are left to the outer call.
+// A transaction failing on serialization is rolled back and run again from the start, so fn must
+// not have effects outside the db it can not repeat.
+func Transaction(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOption) error {
+	if u, ok := ctx.Value(txKey{}).(*unit); ok {
+		return u.savepoint(ctx, fn)
+	}
+	o := txOptions{attempts: defaultTxAttempts}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	db, err := sqlDB()
+	if err != nil {
+		return err
+	}
+	for attempt := 1; ; attempt++ {
+		err = run(ctx, db, &o.TxOptions, fn)
+		if err == nil || attempt >= o.attempts || !IsSerializationFailure(err) {
+			return err
+		}
+		delay := txRetryDelay*time.Duration(attempt) + time.Duration(rand.Int63n(int64(txRetryDelay)))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+func run(ctx context.Context, db *sql.DB, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	if err = fn(context.WithValue(ctx, txKey{}, &unit{tx: tx})); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// savepoint runs fn in a savepoint of the transaction. Savepoints nest like the calls opening them,
+// a transaction is used by one goroutine at a time.
+func (u *unit) savepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	u.savepoints++
+	defer func() { u.savepoints-- }()
+	name := fmt.Sprintf("sp_%d", u.savepoints)
+	if _, err := u.tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+	if err := fn(ctx); err != nil {
+		_, _ = u.tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+		return err
+	}
+	_, err := u.tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
+}
+
+// InTransaction tells whether ctx carries a db transaction.
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*unit)
+	return ok
+}
+
+// IsSerializationFailure tells whether err fails a transaction that could not be serialized with the
+// transactions running next to it, such a transaction succeeds when it is run again.
+func IsSerializationFailure(err error) bool {
+	var e *pq.Error
+	return errors.As(err, &e) && e.Code == serializationFailure
+}
+
+// Conn runs queries in the db transaction carried by their context, and on the db outside one.
+type Conn struct {
+	db *sql.DB
+}
+
+func NewConn(db *sql.DB) Conn {
+	return Conn{db: db}
+}
+
+func (c Conn) ext(ctx context.Context) SQLExt {
+	if u, ok := ctx.Value(txKey{}).(*unit); ok {
+		return u.tx
+	}
+	return c.db
+}
+
+func (c Conn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return c.ext(ctx).QueryRowContext(ctx, query, args...)
+}
+
+func (c Conn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return c.ext(ctx).QueryContext(ctx, query, args...)
+}
+
+func (c Conn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return c.ext(ctx).ExecContext(ctx, query, args...)
+}
@@ -0,0 +1,142 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"wallet/db"
+	"wallet/internal/serr"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTable creates an empty table for a test on the database at WALLET_TEST_DB_HOST, tests are
+// skipped without one.
+func testTable(t *testing.T, name string) *sql.DB {
+	host := os.Getenv("WALLET_TEST_DB_HOST")
+	if host == "" {
+		t.Skip("WALLET_TEST_DB_HOST is not set")
+	}
+	port := os.Getenv("WALLET_TEST_DB_PORT")
+	if port == "" {
+		port = "5432"
+	}
+	psql, err := db.NewPostgres(os.Getenv("WALLET_TEST_DB_NAME"), os.Getenv("WALLET_TEST_DB_USER"),
+		os.Getenv("WALLET_TEST_DB_PASSWORD"), host, port, 20, 5)
+	require.NoError(t, err)
+	_, err = psql.Exec("DROP TABLE IF EXISTS " + name + "; CREATE TABLE " + name + " (id INT PRIMARY KEY, seen INT)")
+	require.NoError(t, err)
+	t.Cleanup(func() { _, _ = psql.Exec("DROP TABLE " + name) })
+	return psql
+}
+
+func TestTransactionSavepoints(t *testing.T) {
+	psql := testTable(t, "tx_savepoint_test")
+	conn := db.NewConn(psql)
+	ctx := context.Background()
+	insert := func(ctx context.Context, id int) error {
+		_, err := conn.ExecContext(ctx, "INSERT INTO tx_savepoint_test (id) VALUES ($1)", id)
+		return err
+	}
+	errNested := errors.New("nested")
+
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		assert.True(t, db.InTransaction(ctx))
+		if err := insert(ctx, 1); err != nil {
+			return err
+		}
+		err := db.Transaction(ctx, func(ctx context.Context) error {
+			if err := insert(ctx, 2); err != nil {
+				return err
+			}
+			return errNested
+		})
+		require.ErrorIs(t, err, errNested)
+		// a failed statement is rolled back with its savepoint, the transaction goes on
+		require.Error(t, db.Transaction(ctx, func(ctx context.Context) error { return insert(ctx, 1) }))
+		return insert(ctx, 3)
+	})
+	require.NoError(t, err)
+
+	var ids []int
+	rows, err := psql.Query("SELECT id FROM tx_savepoint_test ORDER BY id")
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		require.NoError(t, rows.Scan(&id))
+		ids = append(ids, id)
+	}
+	assert.Equal(t, []int{1, 3}, ids)
+	assert.False(t, db.InTransaction(ctx))
+}
+
+func TestTransactionRetriesSerializationFailures(t *testing.T) {
+	psql := testTable(t, "tx_retry_test")
+	conn := db.NewConn(psql)
+
+	// both transactions read the table before either writes, so one of them can not be serialized
+	var read sync.WaitGroup
+	read.Add(2)
+	var attempts atomic.Int32
+	write := func(id int) error {
+		return db.Transaction(context.Background(), func(ctx context.Context) error {
+			first := attempts.Add(1) <= 2
+			var seen int
+			if err := conn.QueryRowContext(ctx, "SELECT count(*) FROM tx_retry_test").Scan(&seen); err != nil {
+				return err
+			}
+			if first {
+				read.Done()
+				read.Wait()
+			}
+			_, err := conn.ExecContext(ctx, "INSERT INTO tx_retry_test (id, seen) VALUES ($1, $2)", id, seen)
+			return err
+		}, db.Isolation(sql.LevelSerializable))
+	}
+
+	errs := make(chan error, 2)
+	for id := 1; id <= 2; id++ {
+		go func(id int) { errs <- write(id) }(id)
+	}
+	require.NoError(t, <-errs)
+	require.NoError(t, <-errs)
+	assert.Equal(t, int32(3), attempts.Load())
+
+	// the retried transaction saw the row of the one that won
+	var seen []int
+	rows, err := psql.Query("SELECT seen FROM tx_retry_test ORDER BY seen")
+	require.NoError(t, err)
+	defer rows.Close()
+	for rows.Next() {
+		var n int
+		require.NoError(t, rows.Scan(&n))
+		seen = append(seen, n)
+	}
+	assert.Equal(t, []int{0, 1}, seen)
+}
+
+func TestTransactionAttempts(t *testing.T) {
+	testTable(t, "tx_attempts_test")
+	calls := 0
+	err := db.Transaction(context.Background(), func(ctx context.Context) error {
+		calls++
+		return serr.DBError("Insert", "test", &pq.Error{Code: "40001"})
+	}, db.Attempts(2))
+	assert.True(t, db.IsSerializationFailure(err))
+	assert.Equal(t, 2, calls)
+}
+
+func TestIsSerializationFailure(t *testing.T) {
+	assert.True(t, db.IsSerializationFailure(&pq.Error{Code: "40001"}))
+	assert.True(t, db.IsSerializationFailure(serr.DBError("Insert", "test", &pq.Error{Code: "40001"})))
+	assert.False(t, db.IsSerializationFailure(&pq.Error{Code: "23505"}))
+	assert.False(t, db.IsSerializationFailure(errors.New("serialization failure")))
+	assert.False(t, db.IsSerializationFailure(nil))
+}
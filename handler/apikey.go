@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"wallet/internal/serr"
+	"wallet/server"
+	"wallet/service/apikey"
+)
+
+type APIKeyHandler struct {
+	apikey apikey.UseCase
+}
+
+func NewAPIKeyHandler(apikey apikey.UseCase) APIKeyHandler {
+	return APIKeyHandler{apikey: apikey}
+}
+
+func SetupAPIKeyRoutes(s *server.Server, h APIKeyHandler) {
+	g := s.Authenticated("/apikey", server.RequireAdmin())
+	g.POST("", h.CreateAPIKey)
+	g.GET("", h.GetAPIKeys)
+	g.GET("/:id", h.GetAPIKey)
+	g.POST("/:id/revoke", h.RevokeAPIKey)
+	g.POST("/:id/rotate", h.RotateAPIKey)
+}
+
+// CreateAPIKey godoc
+// @Summary      Create api key
+// @Description  Issue an api key for a service with the given scopes. The response carries the key, it is not shown
+// @Description  again. Services send it in the X-API-Key header.
+// @Tags         APIKeyDTO
+// @Accept       json
+// @Produce      json
+// @Param        body			body		apikey.CreateRequest		true	"API key create request"
+// @Success      200			{object}	apikey.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /apikey		[post]
+func (h APIKeyHandler) CreateAPIKey(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "createAPIKey")
+	defer cancel()
+	var req apikey.CreateRequest
+	if err := bindJSON(ctx, &req); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.apikey.Create(c, &req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetAPIKeys godoc
+// @Summary      List api keys
+// @Description  List the api keys, revoked ones included.
+// @Tags         APIKeyDTO
+// @Produce      json
+// @Param        page			query		int		false	"Page, 1 by default"
+// @Param        pageSize		query		int		false	"Page size, 10 by default"
+// @Success      200			{object}	[]apikey.DTO
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /apikey		[get]
+func (h APIKeyHandler) GetAPIKeys(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "getAPIKeys")
+	defer cancel()
+	page, pageSize := getPaginationParams(ctx)
+	result, err := h.apikey.List(c, pageSize, (page-1)*pageSize)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetAPIKey godoc
+// @Summary      Get api key
+// @Description  Get an api key by id.
+// @Tags         APIKeyDTO
+// @Produce      json
+// @Param        id		path		int64				true	"API key id"
+// @Success      200			{object}	apikey.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /apikey/{id}	[get]
+func (h APIKeyHandler) GetAPIKey(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "getAPIKey")
+	defer cancel()
+	id, err := getIDParam(ctx, "id", "invalid api key id", serr.ErrInvalidAPIKeyID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.apikey.GetByID(c, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// RevokeAPIKey godoc
+// @Summary      Revoke api key
+// @Description  Stop an api key from working at once.
+// @Tags         APIKeyDTO
+// @Produce      json
+// @Param        id		path		int64				true	"API key id"
+// @Success      200			{object}	apikey.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /apikey/{id}/revoke	[post]
+func (h APIKeyHandler) RevokeAPIKey(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "revokeAPIKey")
+	defer cancel()
+	id, err := getIDParam(ctx, "id", "invalid api key id", serr.ErrInvalidAPIKeyID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.apikey.Revoke(c, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// RotateAPIKey godoc
+// @Summary      Rotate api key
+// @Description  Issue a new key with the name and scopes of an api key. The old key keeps working for
+// @Description  auth.apiKeys.rotationGrace so its callers can move to the new one.
+// @Tags         APIKeyDTO
+// @Produce      json
+// @Param        id		path		int64				true	"API key id"
+// @Success      200			{object}	apikey.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      409  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /apikey/{id}/rotate	[post]
+func (h APIKeyHandler) RotateAPIKey(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "rotateAPIKey")
+	defer cancel()
+	id, err := getIDParam(ctx, "id", "invalid api key id", serr.ErrInvalidAPIKeyID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.apikey.Rotate(c, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
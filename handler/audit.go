@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+	"wallet/internal/serr"
+	"wallet/server"
+	"wallet/service/audit"
+)
+
+type AuditHandler struct {
+	audit audit.UseCase
+}
+
+func NewAuditHandler(audit audit.UseCase) AuditHandler {
+	return AuditHandler{audit: audit}
+}
+
+func SetupAuditRoutes(s *server.Server, h AuditHandler) {
+	g := s.Authenticated("/admin/audit", server.RequireAdmin())
+	g.GET("", h.GetAuditLog)
+	g.GET("/verify", h.VerifyAuditLog)
+}
+
+// GetAuditLog godoc
+// @Summary      List audit log
+// @Description  List the administrative and financial actions recorded in the audit log, newest first. Entries
+// @Description  carry the state of the entity before and after the action and who did it from where.
+// @Tags         AuditDTO
+// @Produce      json
+// @Param        actor			query		string		false	"Actor, such as admin:1, member:7 or apikey:3"
+// @Param        action			query		string		false	"Action, such as wallet.withdraw or member.delete"
+// @Param        entity			query		string		false	"member, wallet or hold"
+// @Param        entityId		query		int64		false	"Entity id"
+// @Param        traceId		query		string		false	"Trace id of the request"
+// @Param        from			query		string		false	"Created at or after, RFC 3339"
+// @Param        to				query		string		false	"Created before, RFC 3339"
+// @Param        page			query		int			false	"Page, 1 by default"
+// @Param        pageSize		query		int			false	"Page size, 10 by default and at most 100"
+// @Success      200			{object}	[]audit.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /admin/audit		[get]
+func (h AuditHandler) GetAuditLog(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "getAuditLog")
+	defer cancel()
+	req, err := getAuditListRequest(ctx)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.audit.List(c, req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// VerifyAuditLog godoc
+// @Summary      Verify audit log
+// @Description  Check the hash chain of the audit log from its first entry. brokenAt is the first entry that was
+// @Description  changed or follows a removed one. Keep lastHash elsewhere to tell later whether entries were cut off
+// @Description  the end of the log.
+// @Tags         AuditDTO
+// @Produce      json
+// @Success      200			{object}	audit.Verification
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /admin/audit/verify		[get]
+func (h AuditHandler) VerifyAuditLog(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "verifyAuditLog")
+	defer cancel()
+	result, err := h.audit.Verify(c)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// getAuditListRequest reads audit log filters from the query string.
+func getAuditListRequest(ctx *gin.Context) (*audit.ListRequest, error) {
+	page, pageSize := getPaginationParams(ctx)
+	req := &audit.ListRequest{
+		Actor:   ctx.Query("actor"),
+		Action:  audit.Action(ctx.Query("action")),
+		Entity:  audit.Entity(ctx.Query("entity")),
+		TraceID: ctx.Query("traceId"),
+		Limit:   pageSize,
+		Offset:  (page - 1) * pageSize,
+	}
+	entityID, err := queryInt64(ctx, "entityId")
+	if err != nil {
+		return nil, serr.ValidationErr("handler", "invalid entity id", serr.ErrInvalidFilter)
+	}
+	if entityID != nil {
+		req.EntityID = *entityID
+	}
+	if v := ctx.Query("from"); v != "" {
+		if req.CreatedFrom, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, serr.ValidationErr("handler", "invalid date range", serr.ErrInvalidFilter)
+		}
+	}
+	if v := ctx.Query("to"); v != "" {
+		if req.CreatedTo, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, serr.ValidationErr("handler", "invalid date range", serr.ErrInvalidFilter)
+		}
+	}
+	return req, nil
+}
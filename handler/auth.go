@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"wallet/internal/auth"
+	"wallet/internal/config"
+	"wallet/internal/serr"
+	"wallet/service/audit"
+)
+
+// privileged tells whether the caller acts for every member, as admins and services do.
+func privileged(ctx *gin.Context) bool {
+	id := auth.FromContext(ctx)
+	return id != nil && (id.IsAdmin() || id.IsService())
+}
+
+// authorizeMember lets the caller act on the data of a member if it is that member or privileged.
+func authorizeMember(ctx *gin.Context, memberID int64) error {
+	if id := auth.FromContext(ctx); id == nil || !id.CanActFor(memberID) {
+		return serr.PermissionErr("handler", "permission denied")
+	}
+	return nil
+}
+
+// authorizeAdmin lets through admins, and services whose scopes the route already checked.
+func authorizeAdmin(ctx *gin.Context) error {
+	if !privileged(ctx) {
+		return serr.PermissionErr("handler", "permission denied")
+	}
+	return nil
+}
+
+// authorizeWallet lets the caller act on a wallet of its own, privileged callers act on any wallet.
+func (h WalletHandler) authorizeWallet(ctx *gin.Context, walletID int64) error {
+	if privileged(ctx) {
+		return nil
+	}
+	w, err := h.wallet.GetByID(ctx.Request.Context(), walletID)
+	if err != nil {
+		return err
+	}
+	return authorizeMember(ctx, w.MemberID)
+}
+
+// authorizeHold lets the caller act on a hold on a wallet of its own.
+func (h WalletHandler) authorizeHold(ctx *gin.Context, holdID int64) error {
+	if privileged(ctx) {
+		return nil
+	}
+	hold, err := h.wallet.GetHold(ctx.Request.Context(), holdID)
+	if err != nil {
+		return err
+	}
+	return h.authorizeWallet(ctx, hold.WalletID)
+}
+
+// requestContext returns the context of a request to an operation, carrying its caller for the audit
+// log and cut off after the timeout of the operation. The request is bound to it as well, so the
+// helpers reading the request before the operation runs are cut off with it.
+func requestContext(ctx *gin.Context, operation string) (context.Context, context.CancelFunc) {
+	c := audit.WithMeta(ctx.Request.Context(), auditMeta(ctx))
+	var cancel context.CancelFunc
+	if timeout := config.RequestTimeout(operation); timeout > 0 {
+		c, cancel = context.WithTimeout(c, timeout)
+	} else {
+		c, cancel = context.WithCancel(c)
+	}
+	ctx.Request = ctx.Request.WithContext(c)
+	return c, cancel
+}
+
+// auditMeta returns the caller of a request as the audit log records it.
+func auditMeta(ctx *gin.Context) audit.Meta {
+	m := audit.Meta{TraceID: getTraceID(ctx), ClientIP: ctx.ClientIP()}
+	if id := auth.FromContext(ctx); id != nil {
+		m.Actor = id.String()
+	}
+	return m
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"wallet/internal/serr"
+	"wallet/server"
+	"wallet/service/gift"
+)
+
+type GiftHandler struct {
+	gift gift.UseCase
+}
+
+func NewGiftHandler(gift gift.UseCase) GiftHandler {
+	return GiftHandler{gift: gift}
+}
+
+func SetupGiftRoutes(s *server.Server, h GiftHandler) {
+	g := s.Authenticated("/admin/gift", server.RequireAdmin())
+	g.POST("", h.CreateGift)
+	g.GET("", h.GetGifts)
+	g.POST("/generate", h.GenerateGifts)
+	g.GET("/:id", h.GetGift)
+	g.PUT("/:id", h.UpdateGift)
+	g.DELETE("/:id", h.DeleteGift)
+}
+
+// CreateGift godoc
+// @Summary      Create gift
+// @Description  Create a gift code of the local discount engine, which the wallets redeem when api.discount.engine
+// @Description  is local.
+// @Tags         GiftDTO
+// @Accept       json
+// @Produce      json
+// @Param        body			body		gift.CreateRequest		true	"Gift create request"
+// @Success      200			{object}	gift.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      409  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /admin/gift		[post]
+func (h GiftHandler) CreateGift(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "createGift")
+	defer cancel()
+	var req gift.CreateRequest
+	if err := bindJSON(ctx, &req); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.gift.Create(c, &req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GenerateGifts godoc
+// @Summary      Generate gifts
+// @Description  Create gifts of the same terms with random codes, all of them or none.
+// @Tags         GiftDTO
+// @Accept       json
+// @Produce      json
+// @Param        body			body		gift.GenerateRequest		true	"Gift generate request"
+// @Success      200			{object}	[]gift.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      409  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /admin/gift/generate		[post]
+func (h GiftHandler) GenerateGifts(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "generateGifts")
+	defer cancel()
+	var req gift.GenerateRequest
+	if err := bindJSON(ctx, &req); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.gift.Generate(c, &req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetGifts godoc
+// @Summary      List gifts
+// @Description  List the gifts of the local discount engine newest first.
+// @Tags         GiftDTO
+// @Produce      json
+// @Param        page			query		int		false	"Page, 1 by default"
+// @Param        pageSize		query		int		false	"Page size, 10 by default"
+// @Success      200			{object}	[]gift.DTO
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /admin/gift		[get]
+func (h GiftHandler) GetGifts(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "getGifts")
+	defer cancel()
+	page, pageSize := getPaginationParams(ctx)
+	result, err := h.gift.List(c, pageSize, (page-1)*pageSize)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetGift godoc
+// @Summary      Get gift
+// @Description  Get a gift of the local discount engine by id.
+// @Tags         GiftDTO
+// @Produce      json
+// @Param        id		path		int64				true	"Gift id"
+// @Success      200			{object}	gift.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /admin/gift/{id}	[get]
+func (h GiftHandler) GetGift(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "getGift")
+	defer cancel()
+	id, err := getIDParam(ctx, "id", "invalid gift id", serr.ErrInvalidGiftID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.gift.GetByID(c, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// UpdateGift godoc
+// @Summary      Update gift
+// @Description  Replace the terms of a gift, its usage limit can not go below the uses already made.
+// @Tags         GiftDTO
+// @Accept       json
+// @Produce      json
+// @Param        id		path		int64				true	"Gift id"
+// @Param        body			body		gift.Terms		true	"Gift terms"
+// @Success      200			{object}	gift.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /admin/gift/{id}	[put]
+func (h GiftHandler) UpdateGift(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "updateGift")
+	defer cancel()
+	id, err := getIDParam(ctx, "id", "invalid gift id", serr.ErrInvalidGiftID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	var req gift.Terms
+	if err := bindJSON(ctx, &req); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.gift.Update(c, id, &req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// DeleteGift godoc
+// @Summary      Delete gift
+// @Description  Delete a gift no one has used, a used gift is ended by updating its expiry.
+// @Tags         GiftDTO
+// @Param        id		path		int64				true	"Gift id"
+// @Success      204
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      409  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /admin/gift/{id}	[delete]
+func (h GiftHandler) DeleteGift(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "deleteGift")
+	defer cancel()
+	id, err := getIDParam(ctx, "id", "invalid gift id", serr.ErrInvalidGiftID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = h.gift.Delete(c, id); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -4,12 +4,15 @@ import (
 	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
+	"io"
 	"net/http"
 	"strconv"
 	"wallet/internal/locale"
 	"wallet/internal/serr"
 )
 
+const idempotencyKeyHeader = "Idempotency-Key"
+
 func getPaginationParams(c *gin.Context) (page, pageSize int) {
 	if pageStr := c.Query("page"); pageStr != "" {
 		page, _ = strconv.Atoi(pageStr)
@@ -31,6 +34,42 @@ func getPaginationParams(c *gin.Context) (page, pageSize int) {
 	return
 }
 
+func getIDParam(ctx *gin.Context, key, message string, code serr.ErrorCode) (int64, error) {
+	id, err := strconv.ParseInt(ctx.Param(key), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, serr.ValidationErr("handler", message, code)
+	}
+	return id, nil
+}
+
+func bindJSON(ctx *gin.Context, req any) error {
+	if err := ctx.ShouldBindJSON(req); err != nil {
+		return serr.ValidationErr("handler", "invalid request body", serr.ErrInvalidRequest)
+	}
+	return nil
+}
+
+// queryInt64 returns the integer query parameter key, or nil when it is not given.
+func queryInt64(ctx *gin.Context, key string) (*int64, error) {
+	v := ctx.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// bindOptionalJSON is bindJSON for requests whose body may be left out.
+func bindOptionalJSON(ctx *gin.Context, req any) error {
+	if err := ctx.ShouldBindJSON(req); err != nil && !errors.Is(err, io.EOF) {
+		return serr.ValidationErr("handler", "invalid request body", serr.ErrInvalidRequest)
+	}
+	return nil
+}
+
 func getTraceID(ctx *gin.Context) string {
 	rID, exist := ctx.Get("trace_id")
 	if exist {
@@ -53,6 +92,10 @@ type Error struct {
 func handleError(ctx *gin.Context, err error) {
 	tID := getTraceID(ctx)
 	lang := getLanguage(ctx)
+	if serr.IsTimeout(err) || ctx.Request.Context().Err() != nil {
+		// the driver reports a query cut off by the request as canceled by the user
+		err = serr.TimeoutErr("handler", err)
+	}
 	switch err.(type) {
 	case *serr.ServiceError:
 		var e *serr.ServiceError
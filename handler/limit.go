@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"wallet/internal/serr"
+	"wallet/service/limit"
+)
+
+// GetLimits godoc
+// @Summary      Get wallet limits
+// @Description  Get the spending limits a wallet is held to, 0 is no limit.
+// @Tags         WalletDTO
+// @Accept       json
+// @Produce      json
+// @Param        walletId		path		int64				true	"Wallet id"
+// @Success      200			{object}	limit.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{walletId}/limits		[get]
+func (h WalletHandler) GetLimits(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "getLimits")
+	defer cancel()
+	walletId, err := getIDParam(ctx, "walletId", "invalid wallet id", serr.ErrInvalidWalletID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = h.authorizeWallet(ctx, walletId); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.limits.Get(c, walletId)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// SetLimits godoc
+// @Summary      Set wallet limits
+// @Description  Override the spending limits of a wallet, limits left out come from the member tier.
+// @Tags         WalletDTO
+// @Accept       json
+// @Produce      json
+// @Param        walletId		path		int64				true	"Wallet id"
+// @Param        body			body		limit.SetRequest	true	"Limits request"
+// @Success      200			{object}	limit.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{walletId}/limits		[put]
+func (h WalletHandler) SetLimits(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "setLimits")
+	defer cancel()
+	walletId, err := getIDParam(ctx, "walletId", "invalid wallet id", serr.ErrInvalidWalletID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = authorizeAdmin(ctx); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	var req limit.SetRequest
+	if err = ctx.ShouldBind(&req); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.limits.Set(c, walletId, &req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ResetLimits godoc
+// @Summary      Reset wallet limits
+// @Description  Drop the overrides of a wallet, its limits come from the member tier again.
+// @Tags         WalletDTO
+// @Accept       json
+// @Produce      json
+// @Param        walletId		path		int64				true	"Wallet id"
+// @Success      200			{object}	limit.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{walletId}/limits		[delete]
+func (h WalletHandler) ResetLimits(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "resetLimits")
+	defer cancel()
+	walletId, err := getIDParam(ctx, "walletId", "invalid wallet id", serr.ErrInvalidWalletID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = authorizeAdmin(ctx); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.limits.Reset(c, walletId)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
+	"wallet/internal/auth"
 	"wallet/server"
 	"wallet/service/member"
 )
@@ -17,10 +18,12 @@ func NewMemberHandler(member member.UseCase) MemberHandler {
 }
 
 func SetupMemberRoutes(s *server.Server, h MemberHandler) {
-	g := s.Engine.Group("/member")
+	g := s.Authenticated("/member", server.RequireScope(auth.MemberAdmin))
 	g.POST("", h.CreateMember)
 	g.GET("/:id", h.GetMember)
 	g.PUT("", h.UpdateMember)
+	g.PUT("/:id/tier", h.SetTier)
+	g.DELETE("/:id", h.DeleteMember)
 	g.GET("/gift/:giftCode", h.GetMembersByGiftCode)
 }
 
@@ -33,15 +36,25 @@ func SetupMemberRoutes(s *server.Server, h MemberHandler) {
 // @Param        body			body		member.CreateRequest		true	"Member create request"
 // @Success      200			{object}	member.DTO
 // @Failure      	400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
 // @Failure      	500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       	/member		[post]
 func (h MemberHandler) CreateMember(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "createMember")
+	defer cancel()
 	var req member.CreateRequest
 	if err := ctx.ShouldBind(&req); err != nil {
 		handleError(ctx, err)
 		return
 	}
-	result, err := h.member.Create(&req)
+	if err := authorizeAdmin(ctx); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.member.Create(c, &req)
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -58,15 +71,25 @@ func (h MemberHandler) CreateMember(ctx *gin.Context) {
 // @Param        id		path		int64				true	"Member id"
 // @Success      200			{object}	member.DTO
 // @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
 // @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /member/{id}	[get]
 func (h MemberHandler) GetMember(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "getMember")
+	defer cancel()
 	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
 	if err != nil {
 		handleError(ctx, err)
 		return
 	}
-	result, err := h.member.GetById(id)
+	if err = authorizeMember(ctx, id); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.member.GetById(c, id)
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -83,15 +106,25 @@ func (h MemberHandler) GetMember(ctx *gin.Context) {
 // @Param        body			body		member.DTO				true	"Member update request"
 // @Success      200			{object}	member.DTO
 // @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
 // @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /member	[put]
 func (h MemberHandler) UpdateMember(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "updateMember")
+	defer cancel()
 	var req member.DTO
 	if err := ctx.ShouldBind(&req); err != nil {
 		handleError(ctx, err)
 		return
 	}
-	result, err := h.member.Update(&req)
+	if err := authorizeMember(ctx, req.ID); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.member.Update(c, &req)
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -110,17 +143,104 @@ func (h MemberHandler) UpdateMember(ctx *gin.Context) {
 // @Param        offset		query		int					false	"Offset"
 // @Success      200			{object}	[]member.DTO
 // @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
 // @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /member/gift/{giftCode}		[get]
 func (h MemberHandler) GetMembersByGiftCode(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "getMembersByGiftCode")
+	defer cancel()
 	giftCode := ctx.Param("giftCode")
+	if err := authorizeAdmin(ctx); err != nil {
+		handleError(ctx, err)
+		return
+	}
 	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "10000"))
 	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
 
-	result, err := h.member.GetMembersByGiftCode(giftCode, limit, offset)
+	result, err := h.member.GetMembersByGiftCode(c, giftCode, limit, offset)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// SetTier godoc
+// @Summary      Set member tier
+// @Description  Move a member to the tier its spending limits come from.
+// @Tags         MemberDTO
+// @Accept       json
+// @Produce      json
+// @Param        id				path		int64					true	"Member id"
+// @Param        body			body		member.SetTierRequest	true	"Tier request"
+// @Success      200			{object}	member.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /member/{id}/tier	[put]
+func (h MemberHandler) SetTier(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "setTier")
+	defer cancel()
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = authorizeAdmin(ctx); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	var req member.SetTierRequest
+	if err = ctx.ShouldBind(&req); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.member.SetTier(c, id, req.Tier)
 	if err != nil {
 		handleError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, result)
 }
+
+// DeleteMember godoc
+// @Summary      Delete member
+// @Description  Delete a member and its wallets, which have to be empty. Their history is kept.
+// @Tags         MemberDTO
+// @Accept       json
+// @Produce      json
+// @Param        id				path		int64				true	"Member id"
+// @Success      204
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /member/{id}	[delete]
+func (h MemberHandler) DeleteMember(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "deleteMember")
+	defer cancel()
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = authorizeAdmin(ctx); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = h.member.Delete(c, id); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
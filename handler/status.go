@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"wallet/internal/auth"
+	"wallet/internal/serr"
+	"wallet/service/wallet"
+)
+
+// Freeze godoc
+// @Summary      Freeze wallet
+// @Description  Stop money from leaving a wallet, it can still be credited.
+// @Tags         WalletDTO
+// @Accept       json
+// @Produce      json
+// @Param        walletId		path		int64						true	"Wallet id"
+// @Param        body			body		wallet.StatusChangeRequest	true	"Status change request"
+// @Success      200			{object}	wallet.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{walletId}/freeze		[post]
+func (h WalletHandler) Freeze(ctx *gin.Context) {
+	h.changeStatus(ctx, "freeze", h.wallet.Freeze)
+}
+
+// Unfreeze godoc
+// @Summary      Unfreeze wallet
+// @Description  Make a frozen wallet active again.
+// @Tags         WalletDTO
+// @Accept       json
+// @Produce      json
+// @Param        walletId		path		int64						true	"Wallet id"
+// @Param        body			body		wallet.StatusChangeRequest	true	"Status change request"
+// @Success      200			{object}	wallet.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{walletId}/unfreeze		[post]
+func (h WalletHandler) Unfreeze(ctx *gin.Context) {
+	h.changeStatus(ctx, "unfreeze", h.wallet.Unfreeze)
+}
+
+// Close godoc
+// @Summary      Close wallet
+// @Description  Stop all money movement of an empty active wallet for good.
+// @Tags         WalletDTO
+// @Accept       json
+// @Produce      json
+// @Param        walletId		path		int64						true	"Wallet id"
+// @Param        body			body		wallet.StatusChangeRequest	true	"Status change request"
+// @Success      200			{object}	wallet.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{walletId}/close		[post]
+func (h WalletHandler) Close(ctx *gin.Context) {
+	h.changeStatus(ctx, "close", h.wallet.Close)
+}
+
+// GetStatusHistory godoc
+// @Summary      Get wallet status history
+// @Description  Get the status changes of a wallet with their reasons, oldest first.
+// @Tags         WalletDTO
+// @Accept       json
+// @Produce      json
+// @Param        walletId		path		int64				true	"Wallet id"
+// @Success      200			{object}	[]wallet.StatusChangeDTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{walletId}/status		[get]
+func (h WalletHandler) GetStatusHistory(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "getStatusHistory")
+	defer cancel()
+	walletId, err := getIDParam(ctx, "walletId", "invalid wallet id", serr.ErrInvalidWalletID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = h.authorizeWallet(ctx, walletId); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.wallet.StatusHistory(c, walletId)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// DeleteWallet godoc
+// @Summary      Delete wallet
+// @Description  Delete an empty wallet, its transactions are kept until they are archived.
+// @Tags         WalletDTO
+// @Accept       json
+// @Produce      json
+// @Param        walletId		path		int64				true	"Wallet id"
+// @Success      204
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{walletId}		[delete]
+func (h WalletHandler) DeleteWallet(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "deleteWallet")
+	defer cancel()
+	walletId, err := getIDParam(ctx, "walletId", "invalid wallet id", serr.ErrInvalidWalletID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = authorizeAdmin(ctx); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = h.wallet.Delete(c, walletId); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// changeStatus moves a wallet to another status with change, on behalf of the admin or service calling.
+func (h WalletHandler) changeStatus(
+	ctx *gin.Context, operation string, change func(c context.Context, id int64, reason, actor string) (*wallet.DTO, error),
+) {
+	c, cancel := requestContext(ctx, operation)
+	defer cancel()
+	walletId, err := getIDParam(ctx, "walletId", "invalid wallet id", serr.ErrInvalidWalletID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = authorizeAdmin(ctx); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	var req wallet.StatusChangeRequest
+	if err = ctx.ShouldBind(&req); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := change(c, walletId, req.Reason, auth.FromContext(ctx).String())
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
@@ -1,27 +1,67 @@
 package handler
 
 import (
+	"bytes"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
+	"wallet/internal/auth"
+	"wallet/internal/serr"
 	"wallet/server"
+	"wallet/service/limit"
+	"wallet/service/statement"
+	"wallet/service/transaction"
 	"wallet/service/wallet"
 )
 
 type WalletHandler struct {
-	wallet wallet.UseCase
+	wallet     wallet.UseCase
+	limits     limit.UseCase
+	statements *statement.Exporter
 }
 
-func NewWalletHandler(wallet wallet.UseCase) WalletHandler {
-	return WalletHandler{wallet: wallet}
+func NewWalletHandler(wallet wallet.UseCase, limits limit.UseCase, statements *statement.Exporter) WalletHandler {
+	return WalletHandler{wallet: wallet, limits: limits, statements: statements}
 }
 
 func SetupWalletRoutes(s *server.Server, h WalletHandler) {
-	g := s.Engine.Group("/wallet")
-	g.POST("", h.CreateWallet)
-	g.GET("/:walletId", h.GetWallet)
-	g.GET("/member/:userId", h.GetWallets)
-	g.POST("/gift", h.AddGift)
+	read := server.RequireScope(auth.WalletRead)
+	credit := server.RequireScope(auth.WalletCredit)
+	debit := server.RequireScope(auth.WalletDebit)
+	refund := server.RequireScope(auth.WalletRefund)
+	admin := server.RequireScope(auth.MemberAdmin)
+
+	g := s.Authenticated("/wallet")
+	g.POST("", credit, h.CreateWallet)
+	g.GET("/:walletId", read, h.GetWallet)
+	g.GET("/member/:userId", read, h.GetWallets)
+	g.POST("/gift", credit, h.AddGift)
+	g.POST("/transfer", debit, h.Transfer)
+	g.POST("/:walletId/recharge", credit, h.Recharge)
+	g.POST("/:walletId/withdraw", debit, h.Withdraw)
+	g.POST("/:walletId/pay", debit, h.Pay)
+	g.POST("/:walletId/hold", debit, h.Authorize)
+	g.GET("/:walletId/transactions", read, h.GetTransactions)
+	g.GET("/:walletId/statement", read, h.GetStatement)
+	g.GET("/:walletId/limits", read, h.GetLimits)
+	g.PUT("/:walletId/limits", admin, h.SetLimits)
+	g.DELETE("/:walletId/limits", admin, h.ResetLimits)
+	g.POST("/:walletId/freeze", admin, h.Freeze)
+	g.POST("/:walletId/unfreeze", admin, h.Unfreeze)
+	g.POST("/:walletId/close", admin, h.Close)
+	g.GET("/:walletId/status", read, h.GetStatusHistory)
+	g.DELETE("/:walletId", admin, h.DeleteWallet)
+
+	t := s.Authenticated("/transaction")
+	t.POST("/:id/refund", refund, h.Refund)
+
+	hg := s.Authenticated("/hold")
+	hg.GET("/:id", read, h.GetHold)
+	hg.POST("/:id/capture", debit, h.Capture)
+	hg.POST("/:id/void", debit, h.Void)
 }
 
 // CreateWallet godoc
@@ -33,15 +73,25 @@ func SetupWalletRoutes(s *server.Server, h WalletHandler) {
 // @Param        body			body		wallet.CreateRequest		true	"Wallet create request"
 // @Success      200			{object}	wallet.DTO
 // @Failure      	400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
 // @Failure      	500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       	/wallet		[post]
 func (h WalletHandler) CreateWallet(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "createWallet")
+	defer cancel()
 	var req wallet.CreateRequest
 	if err := ctx.ShouldBind(&req); err != nil {
 		handleError(ctx, err)
 		return
 	}
-	result, err := h.wallet.Create(&req)
+	if err := authorizeMember(ctx, req.MemberID); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.wallet.Create(c, &req)
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -58,16 +108,26 @@ func (h WalletHandler) CreateWallet(ctx *gin.Context) {
 // @Param        walletId		path		string				true	"Wallet id"
 // @Success      200			{object}	wallet.DTO
 // @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
 // @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /wallet/{walletId}	[get]
 func (h WalletHandler) GetWallet(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "getWallet")
+	defer cancel()
 	walletId, err := strconv.ParseInt(ctx.Param("walletId"), 10, 64)
 	if err != nil {
 		handleError(ctx, err)
 		return
 
 	}
-	result, err := h.wallet.GetByID(walletId)
+	if err = h.authorizeWallet(ctx, walletId); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.wallet.GetByID(c, walletId)
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -83,16 +143,26 @@ func (h WalletHandler) GetWallet(ctx *gin.Context) {
 // @Produce      json
 // @Success      200			{object}	[]wallet.DTO
 // @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
 // @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /wallets/{userId}		[get]
 func (h WalletHandler) GetWallets(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "getWallets")
+	defer cancel()
 	userId, err := strconv.ParseInt(ctx.Param("userId"), 10, 64)
 	if err != nil {
 		handleError(ctx, err)
 		return
 
 	}
-	result, err := h.wallet.GetByMemberID(userId)
+	if err = authorizeMember(ctx, userId); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.wallet.GetByMemberID(c, userId)
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -107,20 +177,552 @@ func (h WalletHandler) GetWallets(ctx *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        body			body		wallet.AddGiftRequest		true	"Add gift request"
+// @Param        Idempotency-Key	header		string				false	"Key making retries of this request safe"
 // @Success      200			{object}	wallet.DTO
 // @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
 // @Failure      500  			{object}  	Error
+// @Failure      503  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       	/wallet/gift		[post]
 func (h WalletHandler) AddGift(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "addGift")
+	defer cancel()
 	var req wallet.AddGiftRequest
 	if err := ctx.ShouldBind(&req); err != nil {
 		handleError(ctx, err)
 		return
 	}
-	result, err := h.wallet.AddGift(&req)
+	if err := authorizeMember(ctx, req.MemberID); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err := h.authorizeWallet(ctx, req.WalletID); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.wallet.AddGift(c, &req, ctx.GetHeader(idempotencyKeyHeader))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// Recharge godoc
+// @Summary      Recharge wallet
+// @Description  Add the given amount to a wallet balance. Only admins and services recharge wallets.
+// @Tags         WalletDTO
+// @Accept       json
+// @Produce      json
+// @Param        walletId		path		int64						true	"Wallet id"
+// @Param        body			body		wallet.RechargeRequest		true	"Recharge request"
+// @Param        Idempotency-Key	header		string				false	"Key making retries of this request safe"
+// @Success      200			{object}	wallet.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{walletId}/recharge		[post]
+func (h WalletHandler) Recharge(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "recharge")
+	defer cancel()
+	walletId, err := getIDParam(ctx, "walletId", "invalid wallet id", serr.ErrInvalidWalletID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = authorizeAdmin(ctx); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	var req wallet.RechargeRequest
+	if err := bindJSON(ctx, &req); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.wallet.Recharge(c, walletId, req.Amount, ctx.GetHeader(idempotencyKeyHeader))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// Withdraw godoc
+// @Summary      Withdraw from wallet
+// @Description  Subtract the given amount from a wallet balance.
+// @Tags         WalletDTO
+// @Accept       json
+// @Produce      json
+// @Param        walletId		path		int64						true	"Wallet id"
+// @Param        body			body		wallet.WithdrawRequest		true	"Withdraw request"
+// @Param        Idempotency-Key	header		string				false	"Key making retries of this request safe"
+// @Success      200			{object}	wallet.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{walletId}/withdraw		[post]
+func (h WalletHandler) Withdraw(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "withdraw")
+	defer cancel()
+	walletId, err := getIDParam(ctx, "walletId", "invalid wallet id", serr.ErrInvalidWalletID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = h.authorizeWallet(ctx, walletId); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	var req wallet.WithdrawRequest
+	if err := bindJSON(ctx, &req); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.wallet.Withdraw(c, walletId, req.Amount, ctx.GetHeader(idempotencyKeyHeader))
 	if err != nil {
 		handleError(ctx, err)
 		return
 	}
 	ctx.JSON(http.StatusOK, result)
 }
+
+// Transfer godoc
+// @Summary      Transfer between wallets
+// @Description  Move the given amount from one wallet to another and return the source wallet.
+// @Description  Wallets of different currencies need convert to be set, the amount is converted at the current rate.
+// @Tags         WalletDTO
+// @Accept       json
+// @Produce      json
+// @Param        body			body		wallet.TransferRequest		true	"Transfer request"
+// @Param        Idempotency-Key	header		string				false	"Key making retries of this request safe"
+// @Success      200			{object}	wallet.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/transfer		[post]
+func (h WalletHandler) Transfer(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "transfer")
+	defer cancel()
+	var req wallet.TransferRequest
+	if err := bindJSON(ctx, &req); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err := h.authorizeWallet(ctx, req.FromWalletID); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.wallet.Transfer(c, req.FromWalletID, req.ToWalletID, req.Amount, req.Convert, ctx.GetHeader(idempotencyKeyHeader))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// Pay godoc
+// @Summary      Pay merchant order
+// @Description  Pay a merchant order from a wallet balance.
+// @Tags         WalletDTO
+// @Accept       json
+// @Produce      json
+// @Param        walletId		path		int64						true	"Wallet id"
+// @Param        body			body		wallet.PayRequest			true	"Pay request"
+// @Param        Idempotency-Key	header		string				false	"Key making retries of this request safe"
+// @Success      200			{object}	wallet.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{walletId}/pay		[post]
+func (h WalletHandler) Pay(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "pay")
+	defer cancel()
+	walletId, err := getIDParam(ctx, "walletId", "invalid wallet id", serr.ErrInvalidWalletID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = h.authorizeWallet(ctx, walletId); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	var req wallet.PayRequest
+	if err := bindJSON(ctx, &req); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.wallet.Pay(c, walletId, &req, ctx.GetHeader(idempotencyKeyHeader))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// Refund godoc
+// @Summary      Refund transaction
+// @Description  Refund a withdraw or payment transaction back to its wallet, fully or in parts, other transactions answer TRANSACTION_NOT_REFUNDABLE. Services need the wallet:refund scope.
+// @Tags         WalletDTO
+// @Accept       json
+// @Produce      json
+// @Param        id		path		int64				true	"Transaction id"
+// @Param        body			body		wallet.RefundRequest		false	"Refund request, an empty body refunds the rest of the transaction"
+// @Param        Idempotency-Key	header		string				false	"Key making retries of this request safe"
+// @Success      200			{object}	wallet.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /transaction/{id}/refund		[post]
+func (h WalletHandler) Refund(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "refund")
+	defer cancel()
+	id, err := getIDParam(ctx, "id", "invalid transaction id", serr.ErrInvalidTransactionID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = authorizeAdmin(ctx); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	var req wallet.RefundRequest
+	if err := bindOptionalJSON(ctx, &req); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.wallet.Refund(c, id, req.Amount, ctx.GetHeader(idempotencyKeyHeader))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// Authorize godoc
+// @Summary      Authorize hold
+// @Description  Reserve part of a wallet balance until the hold is captured, voided or expires.
+// @Tags         WalletDTO
+// @Accept       json
+// @Produce      json
+// @Param        walletId		path		int64						true	"Wallet id"
+// @Param        body			body		wallet.AuthorizeRequest		true	"Authorize request"
+// @Param        Idempotency-Key	header		string				false	"Key making retries of this request safe"
+// @Success      200			{object}	wallet.HoldDTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{walletId}/hold		[post]
+func (h WalletHandler) Authorize(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "authorize")
+	defer cancel()
+	walletId, err := getIDParam(ctx, "walletId", "invalid wallet id", serr.ErrInvalidWalletID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = h.authorizeWallet(ctx, walletId); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	var req wallet.AuthorizeRequest
+	if err := bindJSON(ctx, &req); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ttl := time.Duration(req.TTLSeconds) * time.Second
+	result, err := h.wallet.Authorize(c, walletId, req.Amount, ttl, ctx.GetHeader(idempotencyKeyHeader))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetHold godoc
+// @Summary      Get hold
+// @Description  Get a hold by id.
+// @Tags         WalletDTO
+// @Produce      json
+// @Param        id		path		int64				true	"Hold id"
+// @Success      200			{object}	wallet.HoldDTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /hold/{id}		[get]
+func (h WalletHandler) GetHold(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "getHold")
+	defer cancel()
+	id, err := getIDParam(ctx, "id", "invalid hold id", serr.ErrInvalidHoldID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = h.authorizeHold(ctx, id); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.wallet.GetHold(c, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// Capture godoc
+// @Summary      Capture hold
+// @Description  Settle a hold as a payment and release whatever is not captured. Only admins and services capture holds.
+// @Tags         WalletDTO
+// @Accept       json
+// @Produce      json
+// @Param        id		path		int64				true	"Hold id"
+// @Param        body			body		wallet.CaptureRequest		false	"Capture request, an empty body captures the whole hold"
+// @Param        Idempotency-Key	header		string				false	"Key making retries of this request safe"
+// @Success      200			{object}	wallet.HoldDTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /hold/{id}/capture		[post]
+func (h WalletHandler) Capture(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "capture")
+	defer cancel()
+	id, err := getIDParam(ctx, "id", "invalid hold id", serr.ErrInvalidHoldID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = authorizeAdmin(ctx); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	var req wallet.CaptureRequest
+	if err := bindOptionalJSON(ctx, &req); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.wallet.Capture(c, id, req.Amount, ctx.GetHeader(idempotencyKeyHeader))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// Void godoc
+// @Summary      Void hold
+// @Description  Release a hold without moving any money. Only admins and services void holds.
+// @Tags         WalletDTO
+// @Produce      json
+// @Param        id		path		int64				true	"Hold id"
+// @Success      200			{object}	wallet.HoldDTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /hold/{id}/void		[post]
+func (h WalletHandler) Void(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "void")
+	defer cancel()
+	id, err := getIDParam(ctx, "id", "invalid hold id", serr.ErrInvalidHoldID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = authorizeAdmin(ctx); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.wallet.Void(c, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetTransactions godoc
+// @Summary      List wallet transactions
+// @Description  List the transactions of a wallet newest first. Pass the nextCursor of a page as cursor to get the next one.
+// @Tags         WalletDTO
+// @Produce      json
+// @Param        walletId		path		int64		true	"Wallet id"
+// @Param        type			query		[]string	false	"Transaction types" collectionFormat(multi)
+// @Param        from			query		string		false	"Created at or after, RFC 3339"
+// @Param        to				query		string		false	"Created before, RFC 3339"
+// @Param        minAmount		query		int64		false	"Minimum amount, withdrawals are negative"
+// @Param        maxAmount		query		int64		false	"Maximum amount, withdrawals are negative"
+// @Param        discountCode	query		string		false	"Discount code"
+// @Param        cursor			query		string		false	"Cursor of the next page"
+// @Param        limit			query		int			false	"Page size, 20 by default and at most 100"
+// @Success      200			{object}	transaction.Page
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{walletId}/transactions		[get]
+func (h WalletHandler) GetTransactions(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "getTransactions")
+	defer cancel()
+	walletId, err := getIDParam(ctx, "walletId", "invalid wallet id", serr.ErrInvalidWalletID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = h.authorizeWallet(ctx, walletId); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	req, err := getListRequest(ctx)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	req.WalletID = walletId
+	result, err := h.wallet.ListTransactions(c, req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetStatement godoc
+// @Summary      Get wallet statement
+// @Description  Export the statement of a wallet for a period with the opening balance, the running balance after
+// @Description  each transaction and the closing balance. It is written in Persian unless Accept-Language asks for English.
+// @Tags         WalletDTO
+// @Produce      application/pdf
+// @Produce      text/csv
+// @Param        walletId		path		string		true	"Wallet id"
+// @Param        from			query		string		true	"Start of the period, RFC 3339"
+// @Param        to				query		string		true	"End of the period, exclusive, RFC 3339"
+// @Param        format			query		string		false	"csv or pdf, pdf by default"
+// @Success      200			{file}		file
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{walletId}/statement		[get]
+func (h WalletHandler) GetStatement(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "getStatement")
+	defer cancel()
+	walletId, err := getIDParam(ctx, "walletId", "invalid wallet id", serr.ErrInvalidWalletID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = h.authorizeWallet(ctx, walletId); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	format, err := statement.ParseFormat(ctx.Query("format"))
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	req := &transaction.StatementRequest{WalletID: walletId}
+	req.From, err = time.Parse(time.RFC3339, ctx.Query("from"))
+	if err != nil {
+		handleError(ctx, serr.ValidationErr("handler", "invalid date range", serr.ErrInvalidFilter))
+		return
+	}
+	req.To, err = time.Parse(time.RFC3339, ctx.Query("to"))
+	if err != nil {
+		handleError(ctx, serr.ValidationErr("handler", "invalid date range", serr.ErrInvalidFilter))
+		return
+	}
+	result, err := h.wallet.Statement(c, req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	// the file is written out only once it is complete, so a failure can still be answered with an error
+	var buf bytes.Buffer
+	if err = h.statements.Export(&buf, result, format, getLanguage(ctx)); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	filename := fmt.Sprintf("statement-%d-%s.%s", walletId, req.From.Format("2006-01-02"), format)
+	ctx.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	ctx.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
+
+// getListRequest reads transaction listing filters from the query string.
+func getListRequest(ctx *gin.Context) (*transaction.ListRequest, error) {
+	req := &transaction.ListRequest{
+		DiscountCode: ctx.Query("discountCode"),
+		Cursor:       ctx.Query("cursor"),
+	}
+	for _, types := range ctx.QueryArray("type") {
+		for _, t := range strings.Split(types, ",") {
+			req.Types = append(req.Types, transaction.Type(t))
+		}
+	}
+	var err error
+	if v := ctx.Query("from"); v != "" {
+		if req.CreatedFrom, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, serr.ValidationErr("handler", "invalid date range", serr.ErrInvalidFilter)
+		}
+	}
+	if v := ctx.Query("to"); v != "" {
+		if req.CreatedTo, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, serr.ValidationErr("handler", "invalid date range", serr.ErrInvalidFilter)
+		}
+	}
+	if req.MinAmount, err = queryInt64(ctx, "minAmount"); err != nil {
+		return nil, serr.ValidationErr("handler", "invalid amount range", serr.ErrInvalidFilter)
+	}
+	if req.MaxAmount, err = queryInt64(ctx, "maxAmount"); err != nil {
+		return nil, serr.ValidationErr("handler", "invalid amount range", serr.ErrInvalidFilter)
+	}
+	if v := ctx.Query("limit"); v != "" {
+		if req.Limit, err = strconv.Atoi(v); err != nil {
+			return nil, serr.ValidationErr("handler", "invalid page size", serr.ErrInvalidFilter)
+		}
+	}
+	return req, nil
+}
@@ -0,0 +1,247 @@
+package handler_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"wallet/handler"
+	"wallet/internal/auth"
+	"wallet/internal/serr"
+	repomocks "wallet/mocks/repomocks/wallet"
+	"wallet/server"
+	"wallet/service/wallet"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	admin   = &auth.Identity{Role: auth.Admin}
+	member  = &auth.Identity{Role: auth.Member, MemberID: 7}
+	service = &auth.Identity{Role: auth.Service, APIKeyID: 3,
+		Scopes: []auth.Scope{auth.WalletRead, auth.WalletCredit, auth.WalletDebit}}
+)
+
+// walletServer serves the wallet routes to requests made by caller.
+func walletServer(t *testing.T, wallets wallet.UseCase, caller *auth.Identity) *server.Server {
+	gin.SetMode(gin.TestMode)
+	v, err := auth.NewVerifier("HS256", "secret", "", "")
+	require.NoError(t, err)
+	s := server.NewServer(v)
+	s.WithMiddlewares(func(ctx *gin.Context) {
+		auth.WithIdentity(ctx, caller)
+		ctx.Next()
+	})
+	handler.SetupWalletRoutes(s, handler.NewWalletHandler(wallets, nil, nil))
+	return s
+}
+
+// call makes a request to s and returns its status and the error code of an error response.
+func call(s *server.Server, method, path, body string, headers ...string) (int, serr.ErrorCode) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	s.Engine.ServeHTTP(w, req)
+	var e handler.Error
+	_ = json.Unmarshal(w.Body.Bytes(), &e)
+	return w.Code, e.Code
+}
+
+func TestRecharge(t *testing.T) {
+	wallets := repomocks.NewUseCase(t)
+	wallets.On("Recharge", mock.Anything, int64(1), int64(500), "key-1").Return(&wallet.DTO{ID: 1, Balance: 500}, nil)
+	s := walletServer(t, wallets, admin)
+
+	status, _ := call(s, http.MethodPost, "/wallet/1/recharge", `{"amount":500}`, "Idempotency-Key", "key-1")
+	assert.Equal(t, http.StatusOK, status)
+
+	status, code := call(s, http.MethodPost, "/wallet/abc/recharge", `{"amount":500}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, serr.ErrInvalidWalletID, code)
+
+	status, code = call(s, http.MethodPost, "/wallet/0/recharge", `{"amount":500}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, serr.ErrInvalidWalletID, code)
+
+	status, code = call(s, http.MethodPost, "/wallet/1/recharge", `{"amount":"500"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, serr.ErrInvalidRequest, code)
+}
+
+func TestWithdraw(t *testing.T) {
+	wallets := repomocks.NewUseCase(t)
+	wallets.On("Withdraw", mock.Anything, int64(1), int64(200), "").Return(&wallet.DTO{ID: 1, Balance: 300}, nil)
+	wallets.On("Withdraw", mock.Anything, int64(1), int64(0), "").
+		Return(nil, serr.ValidationErr("wallet", "invalid amount", serr.ErrInvalidAmount))
+	s := walletServer(t, wallets, admin)
+
+	status, _ := call(s, http.MethodPost, "/wallet/1/withdraw", `{"amount":200}`)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, code := call(s, http.MethodPost, "/wallet/1/withdraw", `{"amount":0}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, serr.ErrInvalidAmount, code)
+
+	status, code = call(s, http.MethodPost, "/wallet/1/withdraw", `not json`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, serr.ErrInvalidRequest, code)
+}
+
+func TestTransfer(t *testing.T) {
+	wallets := repomocks.NewUseCase(t)
+	wallets.On("Transfer", mock.Anything, int64(1), int64(2), int64(100), false, "").Return(&wallet.DTO{ID: 1}, nil)
+	wallets.On("Transfer", mock.Anything, int64(1), int64(1), int64(100), false, "").
+		Return(nil, serr.ValidationErr("wallet", "source and destination wallets are the same", serr.ErrSameWallet))
+	s := walletServer(t, wallets, admin)
+
+	status, _ := call(s, http.MethodPost, "/wallet/transfer", `{"fromWalletID":1,"toWalletID":2,"amount":100}`)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, code := call(s, http.MethodPost, "/wallet/transfer", `{"fromWalletID":1,"toWalletID":1,"amount":100}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, serr.ErrSameWallet, code)
+
+	status, code = call(s, http.MethodPost, "/wallet/transfer", ``)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, serr.ErrInvalidRequest, code)
+}
+
+func TestRefund(t *testing.T) {
+	wallets := repomocks.NewUseCase(t)
+	wallets.On("Refund", mock.Anything, int64(3), int64(0), "").Return(&wallet.DTO{ID: 1}, nil)
+	wallets.On("Refund", mock.Anything, int64(3), int64(50), "").Return(&wallet.DTO{ID: 1}, nil)
+	s := walletServer(t, wallets, admin)
+
+	// the body may be left out to refund the whole transaction
+	status, _ := call(s, http.MethodPost, "/transaction/3/refund", ``)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, _ = call(s, http.MethodPost, "/transaction/3/refund", `{"amount":50}`)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, code := call(s, http.MethodPost, "/transaction/-3/refund", ``)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, serr.ErrInvalidTransactionID, code)
+}
+
+func TestRecharge_Permissions(t *testing.T) {
+	wallets := repomocks.NewUseCase(t)
+	wallets.On("Recharge", mock.Anything, int64(1), int64(500), "").Return(&wallet.DTO{ID: 1, Balance: 500}, nil)
+
+	// members can not recharge their own wallets
+	status, code := call(walletServer(t, wallets, member), http.MethodPost, "/wallet/1/recharge", `{"amount":500}`)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, serr.ErrPermission, code)
+
+	status, _ = call(walletServer(t, wallets, service), http.MethodPost, "/wallet/1/recharge", `{"amount":500}`)
+	assert.Equal(t, http.StatusOK, status)
+
+	// a service needs the credit scope
+	reader := &auth.Identity{Role: auth.Service, APIKeyID: 4, Scopes: []auth.Scope{auth.WalletRead}}
+	status, code = call(walletServer(t, wallets, reader), http.MethodPost, "/wallet/1/recharge", `{"amount":500}`)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, serr.ErrPermission, code)
+}
+
+func TestWithdraw_Ownership(t *testing.T) {
+	wallets := repomocks.NewUseCase(t)
+	wallets.On("GetByID", mock.Anything, int64(1)).Return(&wallet.DTO{ID: 1, MemberID: 7}, nil)
+	wallets.On("GetByID", mock.Anything, int64(2)).Return(&wallet.DTO{ID: 2, MemberID: 8}, nil)
+	wallets.On("Withdraw", mock.Anything, int64(1), int64(200), "").Return(&wallet.DTO{ID: 1}, nil)
+	s := walletServer(t, wallets, member)
+
+	status, _ := call(s, http.MethodPost, "/wallet/1/withdraw", `{"amount":200}`)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, code := call(s, http.MethodPost, "/wallet/2/withdraw", `{"amount":200}`)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, serr.ErrPermission, code)
+
+	// the source wallet of a transfer has to be the caller's
+	status, code = call(s, http.MethodPost, "/wallet/transfer", `{"fromWalletID":2,"toWalletID":1,"amount":100}`)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, serr.ErrPermission, code)
+}
+
+func TestAuthorize_Ownership(t *testing.T) {
+	wallets := repomocks.NewUseCase(t)
+	wallets.On("GetByID", mock.Anything, int64(1)).Return(&wallet.DTO{ID: 1, MemberID: 7}, nil)
+	wallets.On("GetByID", mock.Anything, int64(2)).Return(&wallet.DTO{ID: 2, MemberID: 8}, nil)
+	wallets.On("Authorize", mock.Anything, int64(1), int64(100), time.Minute, "").Return(&wallet.HoldDTO{ID: 5}, nil)
+	s := walletServer(t, wallets, member)
+
+	status, _ := call(s, http.MethodPost, "/wallet/1/hold", `{"amount":100,"ttlSeconds":60}`)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, code := call(s, http.MethodPost, "/wallet/2/hold", `{"amount":100,"ttlSeconds":60}`)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, serr.ErrPermission, code)
+}
+
+func TestMissingWallet(t *testing.T) {
+	wallets := repomocks.NewUseCase(t)
+	missing := serr.DBError("GetByID", "wallet", sql.ErrNoRows)
+	wallets.On("GetByID", mock.Anything, int64(9)).Return(nil, missing)
+	wallets.On("GetHold", mock.Anything, int64(5)).Return(&wallet.HoldDTO{ID: 5, WalletID: 9}, nil)
+	s := walletServer(t, wallets, member)
+
+	for _, r := range []struct{ method, path, body string }{
+		{http.MethodPost, "/wallet/9/withdraw", `{"amount":200}`},
+		{http.MethodPost, "/wallet/9/pay", `{"amount":200,"merchantReference":"shop","orderID":"1"}`},
+		{http.MethodPost, "/wallet/9/hold", `{"amount":100,"ttlSeconds":60}`},
+		{http.MethodGet, "/wallet/9/transactions", ``},
+		{http.MethodGet, "/wallet/9/statement", ``},
+		{http.MethodGet, "/hold/5", ``},
+	} {
+		status, _ := call(s, r.method, r.path, r.body)
+		assert.Equal(t, http.StatusNotFound, status, r.path)
+	}
+}
+
+func TestCaptureAndVoid_Permissions(t *testing.T) {
+	wallets := repomocks.NewUseCase(t)
+	wallets.On("Capture", mock.Anything, int64(5), int64(0), "").Return(&wallet.HoldDTO{ID: 5}, nil)
+	wallets.On("Void", mock.Anything, int64(5)).Return(&wallet.HoldDTO{ID: 5}, nil)
+
+	// members do not settle holds, not even on their own wallets
+	s := walletServer(t, wallets, member)
+	status, code := call(s, http.MethodPost, "/hold/5/capture", ``)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, serr.ErrPermission, code)
+	status, code = call(s, http.MethodPost, "/hold/5/void", ``)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, serr.ErrPermission, code)
+
+	s = walletServer(t, wallets, service)
+	status, _ = call(s, http.MethodPost, "/hold/5/capture", ``)
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = call(s, http.MethodPost, "/hold/5/void", ``)
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestRefund_Permissions(t *testing.T) {
+	wallets := repomocks.NewUseCase(t)
+	wallets.On("Refund", mock.Anything, int64(3), int64(0), "").Return(&wallet.DTO{ID: 1}, nil)
+
+	status, code := call(walletServer(t, wallets, member), http.MethodPost, "/transaction/3/refund", ``)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, serr.ErrPermission, code)
+
+	// crediting wallets does not let a key refund
+	status, code = call(walletServer(t, wallets, service), http.MethodPost, "/transaction/3/refund", ``)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, serr.ErrPermission, code)
+
+	refunder := &auth.Identity{Role: auth.Service, APIKeyID: 5, Scopes: []auth.Scope{auth.WalletRefund}}
+	status, _ = call(walletServer(t, wallets, refunder), http.MethodPost, "/transaction/3/refund", ``)
+	assert.Equal(t, http.StatusOK, status)
+}
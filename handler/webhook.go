@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"wallet/internal/serr"
+	"wallet/server"
+	"wallet/service/webhook"
+)
+
+type WebhookHandler struct {
+	webhook webhook.UseCase
+}
+
+func NewWebhookHandler(webhook webhook.UseCase) WebhookHandler {
+	return WebhookHandler{webhook: webhook}
+}
+
+func SetupWebhookRoutes(s *server.Server, h WebhookHandler) {
+	g := s.Authenticated("/webhook", server.RequireAdmin())
+	g.POST("", h.CreateWebhook)
+	g.GET("", h.GetWebhooks)
+	g.GET("/:id", h.GetWebhook)
+	g.PUT("/:id", h.UpdateWebhook)
+	g.DELETE("/:id", h.DeleteWebhook)
+	g.GET("/:id/deliveries", h.GetDeliveries)
+	g.POST("/:id/deliveries/:deliveryId/replay", h.ReplayDelivery)
+}
+
+// CreateWebhook godoc
+// @Summary      Create webhook
+// @Description  Subscribe a url to wallet events of the given types. The response carries the secret deliveries are
+// @Description  signed with, it is not shown again.
+// @Tags         WebhookDTO
+// @Accept       json
+// @Produce      json
+// @Param        body			body		webhook.CreateRequest		true	"Webhook create request"
+// @Success      200			{object}	webhook.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /webhook		[post]
+func (h WebhookHandler) CreateWebhook(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "createWebhook")
+	defer cancel()
+	var req webhook.CreateRequest
+	if err := bindJSON(ctx, &req); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.webhook.Create(c, &req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetWebhooks godoc
+// @Summary      List webhooks
+// @Description  List the webhook subscriptions.
+// @Tags         WebhookDTO
+// @Produce      json
+// @Param        page			query		int		false	"Page, 1 by default"
+// @Param        pageSize		query		int		false	"Page size, 10 by default"
+// @Success      200			{object}	[]webhook.DTO
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /webhook		[get]
+func (h WebhookHandler) GetWebhooks(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "getWebhooks")
+	defer cancel()
+	page, pageSize := getPaginationParams(ctx)
+	result, err := h.webhook.List(c, pageSize, (page-1)*pageSize)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetWebhook godoc
+// @Summary      Get webhook
+// @Description  Get a webhook subscription by id.
+// @Tags         WebhookDTO
+// @Produce      json
+// @Param        id		path		int64				true	"Webhook id"
+// @Success      200			{object}	webhook.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /webhook/{id}	[get]
+func (h WebhookHandler) GetWebhook(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "getWebhook")
+	defer cancel()
+	id, err := getIDParam(ctx, "id", "invalid webhook id", serr.ErrInvalidWebhookID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.webhook.GetByID(c, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// UpdateWebhook godoc
+// @Summary      Update webhook
+// @Description  Replace the url and event types of a webhook subscription, pause or resume it with active.
+// @Tags         WebhookDTO
+// @Accept       json
+// @Produce      json
+// @Param        id		path		int64				true	"Webhook id"
+// @Param        body			body		webhook.UpdateRequest		true	"Webhook update request"
+// @Success      200			{object}	webhook.DTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /webhook/{id}	[put]
+func (h WebhookHandler) UpdateWebhook(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "updateWebhook")
+	defer cancel()
+	id, err := getIDParam(ctx, "id", "invalid webhook id", serr.ErrInvalidWebhookID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	var req webhook.UpdateRequest
+	if err := bindJSON(ctx, &req); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.webhook.Update(c, id, &req)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// DeleteWebhook godoc
+// @Summary      Delete webhook
+// @Description  Delete a webhook subscription with its delivery log.
+// @Tags         WebhookDTO
+// @Param        id		path		int64				true	"Webhook id"
+// @Success      204
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /webhook/{id}	[delete]
+func (h WebhookHandler) DeleteWebhook(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "deleteWebhook")
+	defer cancel()
+	id, err := getIDParam(ctx, "id", "invalid webhook id", serr.ErrInvalidWebhookID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if err = h.webhook.Delete(c, id); err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetDeliveries godoc
+// @Summary      List webhook deliveries
+// @Description  List the deliveries of a webhook subscription newest first, with the outcome of their last attempt.
+// @Tags         WebhookDTO
+// @Produce      json
+// @Param        id		path		int64				true	"Webhook id"
+// @Param        page			query		int		false	"Page, 1 by default"
+// @Param        pageSize		query		int		false	"Page size, 10 by default"
+// @Success      200			{object}	[]webhook.DeliveryDTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /webhook/{id}/deliveries	[get]
+func (h WebhookHandler) GetDeliveries(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "getDeliveries")
+	defer cancel()
+	id, err := getIDParam(ctx, "id", "invalid webhook id", serr.ErrInvalidWebhookID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	page, pageSize := getPaginationParams(ctx)
+	result, err := h.webhook.Deliveries(c, id, pageSize, (page-1)*pageSize)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ReplayDelivery godoc
+// @Summary      Replay webhook delivery
+// @Description  Send a delivery of a webhook subscription again, whatever the outcome of its earlier attempts.
+// @Tags         WebhookDTO
+// @Produce      json
+// @Param        id		path		int64				true	"Webhook id"
+// @Param        deliveryId		path		int64				true	"Delivery id"
+// @Success      200			{object}	webhook.DeliveryDTO
+// @Failure      400  			{object}	Error
+// @Failure      401  			{object}	Error
+// @Failure      403  			{object}	Error
+// @Failure      404  			{object}	Error
+// @Failure      500  			{object}  	Error
+// @Security     BearerAuth
+// @Router       /webhook/{id}/deliveries/{deliveryId}/replay	[post]
+func (h WebhookHandler) ReplayDelivery(ctx *gin.Context) {
+	c, cancel := requestContext(ctx, "replayDelivery")
+	defer cancel()
+	id, err := getIDParam(ctx, "id", "invalid webhook id", serr.ErrInvalidWebhookID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	deliveryID, err := getIDParam(ctx, "deliveryId", "invalid delivery id", serr.ErrInvalidDeliveryID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	result, err := h.webhook.Replay(c, id, deliveryID)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
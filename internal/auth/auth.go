@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const identityKey = "identity"
+
+type Role string
+
+const (
+	Member Role = "member"
+	Admin  Role = "admin"
+	// Service is a backend calling the api with an api key, it is allowed what the scopes of its key are.
+	Service Role = "service"
+)
+
+// Scope is a part of the api an api key may call.
+type Scope string
+
+const (
+	WalletRead   Scope = "wallet:read"
+	WalletCredit Scope = "wallet:credit"
+	WalletDebit  Scope = "wallet:debit"
+	// WalletRefund is kept apart from WalletCredit, a refund credits money the wallet already spent
+	WalletRefund Scope = "wallet:refund"
+	MemberAdmin  Scope = "member:admin"
+)
+
+var Scopes = []Scope{WalletRead, WalletCredit, WalletDebit, WalletRefund, MemberAdmin}
+
+func (s Scope) Valid() bool {
+	for _, sc := range Scopes {
+		if s == sc {
+			return true
+		}
+	}
+	return false
+}
+
+// Identity is the caller of a request as told by its token or api key.
+type Identity struct {
+	MemberID int64
+	Role     Role
+	// APIKeyID and Scopes are set for services
+	APIKeyID int64
+	Scopes   []Scope
+}
+
+func (i *Identity) IsAdmin() bool {
+	return i.Role == Admin
+}
+
+func (i *Identity) IsService() bool {
+	return i.Role == Service
+}
+
+// String names the caller in the records of what it did, as apikey:<id>, admin:<id> or member:<id>.
+// Admin tokens without a subject are named admin.
+func (i *Identity) String() string {
+	switch {
+	case i.IsService():
+		return "apikey:" + strconv.FormatInt(i.APIKeyID, 10)
+	case i.MemberID == 0:
+		return string(i.Role)
+	}
+	return string(i.Role) + ":" + strconv.FormatInt(i.MemberID, 10)
+}
+
+// CanActFor tells whether the caller may act on the data of a member. Admins act for everyone and
+// services for the members the scopes of their key allow.
+func (i *Identity) CanActFor(memberID int64) bool {
+	return i.IsAdmin() || i.IsService() || (memberID != 0 && i.MemberID == memberID)
+}
+
+// HasScope tells whether the caller may call routes of a scope, scopes limit services only.
+func (i *Identity) HasScope(scope Scope) bool {
+	if !i.IsService() {
+		return true
+	}
+	for _, s := range i.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyAuthenticator returns the identity of the service an api key belongs to.
+type KeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*Identity, error)
+}
+
+// Claims of the tokens the API accepts, the subject is the member id.
+type Claims struct {
+	Role Role `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// Verifier checks tokens signed with HS256 and a shared secret or RS256 and a public key.
+type Verifier struct {
+	key    any
+	parser *jwt.Parser
+}
+
+// NewVerifier returns a verifier of tokens signed with algorithm. HS256 tokens are verified with
+// secret and RS256 ones with the PEM public key at publicKeyPath. Tokens must expire and, when issuer
+// is not empty, be issued by it.
+func NewVerifier(algorithm, secret, publicKeyPath, issuer string) (*Verifier, error) {
+	var key any
+	switch algorithm {
+	case jwt.SigningMethodHS256.Alg():
+		if secret == "" {
+			return nil, errors.New("jwt secret is not set")
+		}
+		key = []byte(secret)
+	case jwt.SigningMethodRS256.Alg():
+		pem, err := os.ReadFile(publicKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwt public key: %w", err)
+		}
+		if key, err = jwt.ParseRSAPublicKeyFromPEM(pem); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported jwt algorithm %q", algorithm)
+	}
+	opts := []jwt.ParserOption{jwt.WithValidMethods([]string{algorithm}), jwt.WithExpirationRequired()}
+	if issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+	return &Verifier{key: key, parser: jwt.NewParser(opts...)}, nil
+}
+
+// Verify returns the identity of a valid token. Members need their id as the subject.
+func (v *Verifier) Verify(token string) (*Identity, error) {
+	var claims Claims
+	_, err := v.parser.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return v.key, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	id := &Identity{Role: claims.Role}
+	switch claims.Role {
+	case Admin:
+		id.MemberID, _ = strconv.ParseInt(claims.Subject, 10, 64)
+	case Member, "":
+		id.Role = Member
+		id.MemberID, err = strconv.ParseInt(claims.Subject, 10, 64)
+		if err != nil || id.MemberID <= 0 {
+			return nil, errors.New("token subject is not a member id")
+		}
+	default:
+		return nil, fmt.Errorf("unknown role %q", claims.Role)
+	}
+	return id, nil
+}
+
+// WithIdentity keeps the caller of a request in its context.
+func WithIdentity(ctx *gin.Context, id *Identity) {
+	ctx.Set(identityKey, id)
+}
+
+// FromContext returns the caller of a request, or nil when the request is not authenticated.
+func FromContext(ctx *gin.Context) *Identity {
+	if v, ok := ctx.Get(identityKey); ok {
+		if id, ok := v.(*Identity); ok {
+			return id
+		}
+	}
+	return nil
+}
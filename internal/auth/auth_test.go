@@ -0,0 +1,100 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+	"wallet/internal/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, claims auth.Claims) string {
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	require.NoError(t, err)
+	return token
+}
+
+func claims(subject string, role auth.Role, ttl time.Duration) auth.Claims {
+	return auth.Claims{Role: role, RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   subject,
+		Issuer:    "wallet-test",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+	}}
+}
+
+func TestVerify_HS256(t *testing.T) {
+	v, err := auth.NewVerifier("HS256", "secret", "", "wallet-test")
+	require.NoError(t, err)
+
+	id, err := v.Verify(sign(t, jwt.SigningMethodHS256, []byte("secret"), claims("7", "", time.Minute)))
+	require.NoError(t, err)
+	assert.Equal(t, &auth.Identity{MemberID: 7, Role: auth.Member}, id)
+	assert.True(t, id.CanActFor(7))
+	assert.False(t, id.CanActFor(8))
+
+	id, err = v.Verify(sign(t, jwt.SigningMethodHS256, []byte("secret"), claims("", auth.Admin, time.Minute)))
+	require.NoError(t, err)
+	assert.True(t, id.IsAdmin())
+	assert.True(t, id.CanActFor(8))
+
+	for name, token := range map[string]string{
+		"wrong secret":    sign(t, jwt.SigningMethodHS256, []byte("other"), claims("7", auth.Member, time.Minute)),
+		"expired":         sign(t, jwt.SigningMethodHS256, []byte("secret"), claims("7", auth.Member, -time.Minute)),
+		"no subject":      sign(t, jwt.SigningMethodHS256, []byte("secret"), claims("", auth.Member, time.Minute)),
+		"unknown role":    sign(t, jwt.SigningMethodHS256, []byte("secret"), claims("7", "root", time.Minute)),
+		"other algorithm": sign(t, jwt.SigningMethodHS384, []byte("secret"), claims("7", auth.Member, time.Minute)),
+		"malformed":       "not a token",
+	} {
+		_, err = v.Verify(token)
+		assert.Error(t, err, name)
+	}
+
+	c := claims("7", auth.Member, time.Minute)
+	c.Issuer = "someone-else"
+	_, err = v.Verify(sign(t, jwt.SigningMethodHS256, []byte("secret"), c))
+	assert.Error(t, err)
+}
+
+func TestVerify_RS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwt.pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	v, err := auth.NewVerifier("RS256", "", path, "")
+	require.NoError(t, err)
+	id, err := v.Verify(sign(t, jwt.SigningMethodRS256, key, claims("3", auth.Member, time.Minute)))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), id.MemberID)
+
+	// a token signed with the public key as an HMAC secret must not pass
+	_, err = v.Verify(sign(t, jwt.SigningMethodHS256, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}),
+		claims("3", auth.Admin, time.Minute)))
+	assert.Error(t, err)
+}
+
+func TestNewVerifier_Invalid(t *testing.T) {
+	_, err := auth.NewVerifier("HS256", "", "", "")
+	assert.Error(t, err)
+	_, err = auth.NewVerifier("RS256", "", filepath.Join(t.TempDir(), "missing.pem"), "")
+	assert.Error(t, err)
+	_, err = auth.NewVerifier("none", "secret", "", "")
+	assert.Error(t, err)
+}
+
+func TestIdentity_String(t *testing.T) {
+	assert.Equal(t, "member:7", (&auth.Identity{MemberID: 7, Role: auth.Member}).String())
+	assert.Equal(t, "admin:1", (&auth.Identity{MemberID: 1, Role: auth.Admin}).String())
+	assert.Equal(t, "admin", (&auth.Identity{Role: auth.Admin}).String())
+	assert.Equal(t, "apikey:3", (&auth.Identity{APIKeyID: 3, Role: auth.Service}).String())
+}
@@ -16,6 +16,22 @@ func ServerDebug() bool {
 	return viper.GetBool("server.debug")
 }
 
+// TrustedProxies are the addresses or CIDRs of the proxies whose X-Forwarded-For header tells the
+// client ip. With none the client ip is the address the request came from.
+func TrustedProxies() []string {
+	return viper.GetStringSlice("server.trustedProxies")
+}
+
+// RequestTimeout is how long a request of an operation may run, the operation is the name of its
+// handler, e.g. getStatement. Operations without a timeout of their own get the default one, zero
+// lets requests run until the client goes away.
+func RequestTimeout(operation string) time.Duration {
+	if key := "server.timeouts." + operation; viper.IsSet(key) {
+		return viper.GetDuration(key)
+	}
+	return viper.GetDuration("server.timeouts.default")
+}
+
 func DBName() string {
 	return viper.GetString("db.postgres.name")
 }
@@ -80,10 +96,170 @@ func LogLevel() string {
 	return viper.GetString("app.log.level")
 }
 
+func DefaultCurrency() string {
+	return viper.GetString("app.currency")
+}
+
+// APIDiscountEngine picks the gifts the wallets redeem: "http" calls the discount api, "local" serves
+// the gifts managed at /admin/gift from the wallet database.
+func APIDiscountEngine() string {
+	return viper.GetString("api.discount.engine")
+}
+
 func APIDiscount() string {
 	return viper.GetString("api.discount.url")
 }
 
+// APIDiscountTimeout bounds every request to the discount api.
+func APIDiscountTimeout() time.Duration {
+	return viper.GetDuration("api.discount.timeout")
+}
+
+// APIDiscountRetries is how many times a gift lookup failing on the discount api is made again.
+func APIDiscountRetries() int {
+	return viper.GetInt("api.discount.retries")
+}
+
+func APIDiscountRetryDelay() time.Duration {
+	return viper.GetDuration("api.discount.retryDelay")
+}
+
+// APIDiscountBreakerFailures is how many failures in a row stop calls to the discount api, zero never
+// stops them.
+func APIDiscountBreakerFailures() int {
+	return viper.GetInt("api.discount.breaker.failures")
+}
+
+// APIDiscountBreakerCooldown is how long calls to the discount api are stopped before one is tried again.
+func APIDiscountBreakerCooldown() time.Duration {
+	return viper.GetDuration("api.discount.breaker.cooldown")
+}
+
+// APIDiscountCacheTTL is how long gifts looked up are kept in redis, zero does not keep them.
+func APIDiscountCacheTTL() time.Duration {
+	return viper.GetDuration("api.discount.cacheTTL")
+}
+
+func APIFX() string {
+	return viper.GetString("api.fx.url")
+}
+
+// FXRatesFile is a static rates file used instead of the fx api when set.
+func FXRatesFile() string {
+	return viper.GetString("api.fx.ratesFile")
+}
+
+// StatementFont is the ttf font statement pdfs are written with, it has to cover Persian.
+func StatementFont() string {
+	return viper.GetString("app.statement.font")
+}
+
+func StatementBoldFont() string {
+	return viper.GetString("app.statement.boldFont")
+}
+
+// ---- Limits
+
+// LimitTimezone is the zone daily and monthly spending limits start over in.
+func LimitTimezone() string {
+	return viper.GetString("limits.timezone")
+}
+
+// LimitTierExists tells whether spending limits are configured for a member tier.
+func LimitTierExists(tier string) bool {
+	return viper.IsSet("limits.tiers." + tier)
+}
+
+// LimitTierTransfersPerDay is the number of transfers a wallet of a tier may make a day, zero is no limit.
+func LimitTierTransfersPerDay(tier string) int64 {
+	return viper.GetInt64("limits.tiers." + tier + ".maxTransfersPerDay")
+}
+
+// LimitTierAmount is an amount limit of a tier in the minor units of a currency, zero is no limit.
+func LimitTierAmount(tier, currency, name string) int64 {
+	return viper.GetInt64("limits.tiers." + tier + ".amounts." + currency + "." + name)
+}
+
+// ---- Auth
+
+// JWTAlgorithm is the algorithm API tokens are signed with, HS256 or RS256.
+func JWTAlgorithm() string {
+	return viper.GetString("auth.jwt.algorithm")
+}
+
+// JWTSecret is the key of HS256 tokens.
+func JWTSecret() string {
+	return viper.GetString("auth.jwt.secret")
+}
+
+// JWTPublicKeyFile is the PEM public key of RS256 tokens.
+func JWTPublicKeyFile() string {
+	return viper.GetString("auth.jwt.publicKeyFile")
+}
+
+// JWTIssuer is the issuer tokens must have, any issuer is accepted when it is empty.
+func JWTIssuer() string {
+	return viper.GetString("auth.jwt.issuer")
+}
+
+// APIKeyRotationGrace is how long a rotated api key keeps working next to the key replacing it.
+func APIKeyRotationGrace() time.Duration {
+	return viper.GetDuration("auth.apiKeys.rotationGrace")
+}
+
+// ---- Jobs
+
+func ReconciliationRepair() bool {
+	return viper.GetBool("jobs.reconciliation.repair")
+}
+
+func ReconciliationInterval() time.Duration {
+	return viper.GetDuration("jobs.reconciliation.interval")
+}
+
+// AuditSealInterval is how often the audit log entries written since are chained, zero disables
+// sealing.
+func AuditSealInterval() time.Duration {
+	return viper.GetDuration("jobs.audit.sealInterval")
+}
+
+func HoldExpiryInterval() time.Duration {
+	return viper.GetDuration("jobs.holds.expiryInterval")
+}
+
+// OutboxRelayInterval is how often outbox events are published, zero disables the relay.
+func OutboxRelayInterval() time.Duration {
+	return viper.GetDuration("jobs.outbox.relayInterval")
+}
+
+// WebhookDeliveryInterval is how often due webhook deliveries are sent, zero disables sending them.
+func WebhookDeliveryInterval() time.Duration {
+	return viper.GetDuration("jobs.webhooks.deliveryInterval")
+}
+
+// ArchiveInterval is how often the transactions of closed and deleted wallets are archived, zero
+// disables archiving.
+func ArchiveInterval() time.Duration {
+	return viper.GetDuration("jobs.archive.interval")
+}
+
+// ArchiveAfter is how long a wallet stays closed or deleted before its transactions are archived.
+func ArchiveAfter() time.Duration {
+	return viper.GetDuration("jobs.archive.after")
+}
+
+// RedemptionRecoveryInterval is how often gift redemptions left unsettled are recovered, zero disables
+// the recovery.
+func RedemptionRecoveryInterval() time.Duration {
+	return viper.GetDuration("jobs.redemptions.interval")
+}
+
+// RedemptionRecoveryAfter is how long a gift redemption stays pending or used before it is recovered,
+// it has to outlast the addGift request.
+func RedemptionRecoveryAfter() time.Duration {
+	return viper.GetDuration("jobs.redemptions.after")
+}
+
 func Init() {
 	viper.SetConfigName(getEnv("CONFIG_NAME", "conf"))
 	viper.SetConfigType("yaml")              // REQUIRED if the config file does not have the extension in the name
@@ -0,0 +1,46 @@
+package currency
+
+import (
+	"strings"
+	"wallet/internal/config"
+)
+
+// fallback is used when app.currency is not configured.
+const fallback = "IRR"
+
+// Currency describes an ISO 4217 currency, amounts are stored as integers in its minor unit.
+type Currency struct {
+	Code       string `json:"code"`
+	MinorUnits int    `json:"minorUnits"`
+}
+
+var currencies = map[string]Currency{
+	"IRR": {Code: "IRR", MinorUnits: 0},
+	"USD": {Code: "USD", MinorUnits: 2},
+	"EUR": {Code: "EUR", MinorUnits: 2},
+	"GBP": {Code: "GBP", MinorUnits: 2},
+	"AED": {Code: "AED", MinorUnits: 2},
+	"TRY": {Code: "TRY", MinorUnits: 2},
+	"JPY": {Code: "JPY", MinorUnits: 0},
+	"KWD": {Code: "KWD", MinorUnits: 3},
+}
+
+// Get returns a supported currency by its code, ignoring case.
+func Get(code string) (Currency, bool) {
+	c, ok := currencies[strings.ToUpper(strings.TrimSpace(code))]
+	return c, ok
+}
+
+// Default returns the code of the configured default currency.
+func Default() string {
+	if c, ok := Get(config.DefaultCurrency()); ok {
+		return c.Code
+	}
+	return fallback
+}
+
+// MinorUnits returns the number of decimal places of a currency, or 0 if it is not supported.
+func MinorUnits(code string) int {
+	c, _ := Get(code)
+	return c.MinorUnits
+}
@@ -0,0 +1,26 @@
+package currency_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"wallet/internal/currency"
+)
+
+func TestGet(t *testing.T) {
+	c, ok := currency.Get(" usd ")
+	assert.True(t, ok)
+	assert.Equal(t, currency.Currency{Code: "USD", MinorUnits: 2}, c)
+
+	_, ok = currency.Get("XXX")
+	assert.False(t, ok)
+}
+
+func TestDefault(t *testing.T) {
+	assert.Equal(t, "IRR", currency.Default())
+}
+
+func TestMinorUnits(t *testing.T) {
+	assert.Equal(t, 0, currency.MinorUnits("IRR"))
+	assert.Equal(t, 3, currency.MinorUnits("KWD"))
+	assert.Equal(t, 0, currency.MinorUnits("XXX"))
+}
@@ -15,6 +15,14 @@ var l *Localizer
 
 func Localize(msgID string, lang language.Tag) string {
 	lang = language.Persian
+	return Translate(msgID, lang)
+}
+
+// Translate returns the message in the given language, or msgID itself when there is no translation.
+func Translate(msgID string, lang language.Tag) string {
+	if l == nil {
+		return msgID
+	}
 	if loc, ok := l.locales[lang]; ok {
 		localize, err := loc.Localize(&i18n.LocalizeConfig{MessageID: msgID})
 		if err != nil || localize == "" {
@@ -40,6 +48,10 @@ func Init() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("failed to load errors.fa.toml file")
 	}
+	_, err = bundle.LoadMessageFile("resources/locale/statements.fa.toml")
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load statements.fa.toml file")
+	}
 	l = &Localizer{locales: make(map[language.Tag]*i18n.Localizer)}
 	l.locales[language.Persian] = i18n.NewLocalizer(bundle, "fa")
 	l.locales[language.English] = i18n.NewLocalizer(bundle, "en")
@@ -1,7 +1,9 @@
 package serr
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -13,14 +15,66 @@ const (
 	ErrInvalidUserID                ErrorCode = "INVALID_USER_ID"
 	ErrInvalidWalletID              ErrorCode = "INVALID_WALLET_ID"
 	ErrPermission                   ErrorCode = "PERMISSION"
+	ErrUnauthorized                 ErrorCode = "UNAUTHORIZED"
 	ErrDiscountCodeUsed             ErrorCode = "DISCOUNT_CODE_USED"
 	ErrNotEnoughBalance             ErrorCode = "NOT_ENOUGH_BALANCE"
 	ErrTransactionTypeNotWithdrawal ErrorCode = "TRANSACTION_TYPE_NOT_WITHDRAWAL"
+	ErrTransactionNotRefundable     ErrorCode = "TRANSACTION_NOT_REFUNDABLE"
 	ErrDiscountClient               ErrorCode = "DISCOUNT_CLIENT"
 	ErrGiftNotFound                 ErrorCode = "GIFT_NOT_FOUND"
 	ErrGiftUsageLimitReached        ErrorCode = "GIFT_USAGE_LIMIT_REACHED"
 	ErrGiftExpired                  ErrorCode = "GIFT_EXPIRED"
 	ErrGiftNotStarted               ErrorCode = "GIFT_NOT_STARTED"
+	ErrInvalidRequest               ErrorCode = "INVALID_REQUEST"
+	ErrInvalidAmount                ErrorCode = "INVALID_AMOUNT"
+	ErrInvalidTransactionID         ErrorCode = "INVALID_TRANSACTION_ID"
+	ErrSameWallet                   ErrorCode = "SAME_WALLET"
+	ErrInvalidIdempotencyKey        ErrorCode = "INVALID_IDEMPOTENCY_KEY"
+	ErrIdempotencyKeyReused         ErrorCode = "IDEMPOTENCY_KEY_REUSED"
+	ErrInvalidTransactionType       ErrorCode = "INVALID_TRANSACTION_TYPE"
+	ErrUnsupportedCurrency          ErrorCode = "UNSUPPORTED_CURRENCY"
+	ErrCurrencyMismatch             ErrorCode = "CURRENCY_MISMATCH"
+	ErrFXClient                     ErrorCode = "FX_CLIENT"
+	ErrRateNotFound                 ErrorCode = "RATE_NOT_FOUND"
+	ErrInvalidMerchantReference     ErrorCode = "INVALID_MERCHANT_REFERENCE"
+	ErrInvalidOrderID               ErrorCode = "INVALID_ORDER_ID"
+	ErrRefundExceedsAmount          ErrorCode = "REFUND_EXCEEDS_AMOUNT"
+	ErrTransactionRefunded          ErrorCode = "TRANSACTION_REFUNDED"
+	ErrInvalidHoldID                ErrorCode = "INVALID_HOLD_ID"
+	ErrInvalidHoldTTL               ErrorCode = "INVALID_HOLD_TTL"
+	ErrHoldNotActive                ErrorCode = "HOLD_NOT_ACTIVE"
+	ErrHoldExpired                  ErrorCode = "HOLD_EXPIRED"
+	ErrInvalidFilter                ErrorCode = "INVALID_FILTER"
+	ErrInvalidCursor                ErrorCode = "INVALID_CURSOR"
+	ErrInvalidStatementFormat       ErrorCode = "INVALID_STATEMENT_FORMAT"
+	ErrInvalidWebhookURL            ErrorCode = "INVALID_WEBHOOK_URL"
+	ErrInvalidEventType             ErrorCode = "INVALID_EVENT_TYPE"
+	ErrInvalidWebhookID             ErrorCode = "INVALID_WEBHOOK_ID"
+	ErrWithdrawalLimitExceeded      ErrorCode = "WITHDRAWAL_LIMIT_EXCEEDED"
+	ErrDailyLimitExceeded           ErrorCode = "DAILY_LIMIT_EXCEEDED"
+	ErrMonthlyLimitExceeded         ErrorCode = "MONTHLY_LIMIT_EXCEEDED"
+	ErrBalanceLimitExceeded         ErrorCode = "BALANCE_LIMIT_EXCEEDED"
+	ErrTransferLimitExceeded        ErrorCode = "TRANSFER_LIMIT_EXCEEDED"
+	ErrInvalidLimit                 ErrorCode = "INVALID_LIMIT"
+	ErrInvalidTier                  ErrorCode = "INVALID_TIER"
+	ErrInvalidScope                 ErrorCode = "INVALID_SCOPE"
+	ErrInvalidAPIKeyID              ErrorCode = "INVALID_API_KEY_ID"
+	ErrAPIKeyRevoked                ErrorCode = "API_KEY_REVOKED"
+	ErrInvalidDeliveryID            ErrorCode = "INVALID_DELIVERY_ID"
+	ErrWalletFrozen                 ErrorCode = "WALLET_FROZEN"
+	ErrWalletClosed                 ErrorCode = "WALLET_CLOSED"
+	ErrInvalidStatusTransition      ErrorCode = "INVALID_STATUS_TRANSITION"
+	ErrWalletNotEmpty               ErrorCode = "WALLET_NOT_EMPTY"
+	ErrInvalidReason                ErrorCode = "INVALID_REASON"
+	ErrTimeout                      ErrorCode = "TIMEOUT"
+	ErrDiscountUnavailable          ErrorCode = "DISCOUNT_UNAVAILABLE"
+	ErrRedemptionNotActive          ErrorCode = "REDEMPTION_NOT_ACTIVE"
+	ErrInvalidGift                  ErrorCode = "INVALID_GIFT"
+	ErrInvalidGiftID                ErrorCode = "INVALID_GIFT_ID"
+	ErrGiftCodeExists               ErrorCode = "GIFT_CODE_EXISTS"
+	ErrGiftUsed                     ErrorCode = "GIFT_USED"
+	ErrGiftMemberLimitReached       ErrorCode = "GIFT_MEMBER_LIMIT_REACHED"
+	ErrGiftUseNotFound              ErrorCode = "GIFT_USE_NOT_FOUND"
 )
 
 type ServiceError struct {
@@ -38,6 +92,11 @@ func (e ServiceError) Error() string {
 	)
 }
 
+// Unwrap returns the cause, so errors.Is and errors.As see the error a service error wraps.
+func (e ServiceError) Unwrap() error {
+	return e.Cause
+}
+
 func ValidationErr(method, message string, code ErrorCode) error {
 	return &ServiceError{
 		Method:    method,
@@ -47,7 +106,64 @@ func ValidationErr(method, message string, code ErrorCode) error {
 	}
 }
 
+func ConflictErr(method, message string, code ErrorCode) error {
+	return &ServiceError{
+		Method:    method,
+		Message:   message,
+		Code:      http.StatusConflict,
+		ErrorCode: code,
+	}
+}
+
+func UnauthorizedErr(method, message string) error {
+	return &ServiceError{
+		Method:    method,
+		Message:   message,
+		Code:      http.StatusUnauthorized,
+		ErrorCode: ErrUnauthorized,
+	}
+}
+
+func PermissionErr(method, message string) error {
+	return &ServiceError{
+		Method:    method,
+		Message:   message,
+		Code:      http.StatusForbidden,
+		ErrorCode: ErrPermission,
+	}
+}
+
+// TimeoutErr is the error of an action cut off because its context ran out of time or was canceled.
+func TimeoutErr(method string, cause error) error {
+	return &ServiceError{
+		Method:    method,
+		Cause:     cause,
+		Message:   "request timed out",
+		Code:      http.StatusGatewayTimeout,
+		ErrorCode: ErrTimeout,
+	}
+}
+
+// UnavailableErr is the error of an action that needs a service which failed or can not be reached.
+func UnavailableErr(method, message string, code ErrorCode, cause error) error {
+	return &ServiceError{
+		Method:    method,
+		Cause:     cause,
+		Message:   message,
+		Code:      http.StatusServiceUnavailable,
+		ErrorCode: code,
+	}
+}
+
+// IsTimeout tells whether err comes from a context that ran out of time or was canceled.
+func IsTimeout(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
+
 func DBError(method, repo string, cause error) error {
+	if IsTimeout(cause) {
+		return TimeoutErr(fmt.Sprintf("%s.%s", repo, method), cause)
+	}
 	err := &ServiceError{
 		Method: fmt.Sprintf("%s.%s", repo, method),
 		Cause:  cause,
@@ -0,0 +1,208 @@
+// Code generated by mockery v2.40.1. DO NOT EDIT.
+
+package repomocks
+
+import (
+	context "context"
+	audit "wallet/storage/audit"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// Find provides a mock function with given fields: ctx, f
+func (_m *Repository) Find(ctx context.Context, f *audit.Filter) ([]*audit.Entry, error) {
+	ret := _m.Called(ctx, f)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 []*audit.Entry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *audit.Filter) ([]*audit.Entry, error)); ok {
+		return rf(ctx, f)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *audit.Filter) []*audit.Entry); ok {
+		r0 = rf(ctx, f)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*audit.Entry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *audit.Filter) error); ok {
+		r1 = rf(ctx, f)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAfter provides a mock function with given fields: ctx, seq, limit
+func (_m *Repository) GetAfter(ctx context.Context, seq int64, limit int) ([]*audit.Entry, error) {
+	ret := _m.Called(ctx, seq, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAfter")
+	}
+
+	var r0 []*audit.Entry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) ([]*audit.Entry, error)); ok {
+		return rf(ctx, seq, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) []*audit.Entry); ok {
+		r0 = rf(ctx, seq, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*audit.Entry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int) error); ok {
+		r1 = rf(ctx, seq, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUnsealed provides a mock function with given fields: ctx, limit
+func (_m *Repository) GetUnsealed(ctx context.Context, limit int) ([]*audit.Entry, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUnsealed")
+	}
+
+	var r0 []*audit.Entry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*audit.Entry, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*audit.Entry); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*audit.Entry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, e
+func (_m *Repository) Insert(ctx context.Context, e *audit.Entry) error {
+	ret := _m.Called(ctx, e)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Insert")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *audit.Entry) error); ok {
+		r0 = rf(ctx, e)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LastSealed provides a mock function with given fields: ctx
+func (_m *Repository) LastSealed(ctx context.Context) (int64, string, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LastSealed")
+	}
+
+	var r0 int64
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, string, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) string); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Lock provides a mock function with given fields: ctx
+func (_m *Repository) Lock(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Seal provides a mock function with given fields: ctx, e
+func (_m *Repository) Seal(ctx context.Context, e *audit.Entry) error {
+	ret := _m.Called(ctx, e)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Seal")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *audit.Entry) error); ok {
+		r0 = rf(ctx, e)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Repository {
+	mock := &Repository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,9 +3,11 @@
 package repomocks
 
 import (
-	sql "database/sql"
+	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	sql "database/sql"
 )
 
 // SQLExt is an autogenerated mock type for the SQLExt type
@@ -13,32 +15,32 @@ type SQLExt struct {
 	mock.Mock
 }
 
-// Exec provides a mock function with given fields: query, args
-func (_m *SQLExt) Exec(query string, args ...interface{}) (sql.Result, error) {
+// ExecContext provides a mock function with given fields: ctx, query, args
+func (_m *SQLExt) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
 	var _ca []interface{}
-	_ca = append(_ca, query)
+	_ca = append(_ca, ctx, query)
 	_ca = append(_ca, args...)
 	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Exec")
+		panic("no return value specified for ExecContext")
 	}
 
 	var r0 sql.Result
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, ...interface{}) (sql.Result, error)); ok {
-		return rf(query, args...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) (sql.Result, error)); ok {
+		return rf(ctx, query, args...)
 	}
-	if rf, ok := ret.Get(0).(func(string, ...interface{}) sql.Result); ok {
-		r0 = rf(query, args...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) sql.Result); ok {
+		r0 = rf(ctx, query, args...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(sql.Result)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, ...interface{}) error); ok {
-		r1 = rf(query, args...)
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...interface{}) error); ok {
+		r1 = rf(ctx, query, args...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -46,32 +48,32 @@ func (_m *SQLExt) Exec(query string, args ...interface{}) (sql.Result, error) {
 	return r0, r1
 }
 
-// Query provides a mock function with given fields: query, args
-func (_m *SQLExt) Query(query string, args ...interface{}) (*sql.Rows, error) {
+// QueryContext provides a mock function with given fields: ctx, query, args
+func (_m *SQLExt) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
 	var _ca []interface{}
-	_ca = append(_ca, query)
+	_ca = append(_ca, ctx, query)
 	_ca = append(_ca, args...)
 	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Query")
+		panic("no return value specified for QueryContext")
 	}
 
 	var r0 *sql.Rows
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, ...interface{}) (*sql.Rows, error)); ok {
-		return rf(query, args...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) (*sql.Rows, error)); ok {
+		return rf(ctx, query, args...)
 	}
-	if rf, ok := ret.Get(0).(func(string, ...interface{}) *sql.Rows); ok {
-		r0 = rf(query, args...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) *sql.Rows); ok {
+		r0 = rf(ctx, query, args...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*sql.Rows)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, ...interface{}) error); ok {
-		r1 = rf(query, args...)
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...interface{}) error); ok {
+		r1 = rf(ctx, query, args...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -79,20 +81,20 @@ func (_m *SQLExt) Query(query string, args ...interface{}) (*sql.Rows, error) {
 	return r0, r1
 }
 
-// QueryRow provides a mock function with given fields: query, args
-func (_m *SQLExt) QueryRow(query string, args ...interface{}) *sql.Row {
+// QueryRowContext provides a mock function with given fields: ctx, query, args
+func (_m *SQLExt) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
 	var _ca []interface{}
-	_ca = append(_ca, query)
+	_ca = append(_ca, ctx, query)
 	_ca = append(_ca, args...)
 	ret := _m.Called(_ca...)
 
 	if len(ret) == 0 {
-		panic("no return value specified for QueryRow")
+		panic("no return value specified for QueryRowContext")
 	}
 
 	var r0 *sql.Row
-	if rf, ok := ret.Get(0).(func(string, ...interface{}) *sql.Row); ok {
-		r0 = rf(query, args...)
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) *sql.Row); ok {
+		r0 = rf(ctx, query, args...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*sql.Row)
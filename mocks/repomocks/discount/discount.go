@@ -3,6 +3,7 @@
 package repomocks
 
 import (
+	context "context"
 	discount "wallet/client/discount"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,9 +14,9 @@ type Client struct {
 	mock.Mock
 }
 
-// GetGiftByCode provides a mock function with given fields: code
-func (_m *Client) GetGiftByCode(code string) (*discount.Gift, error) {
-	ret := _m.Called(code)
+// GetGiftByCode provides a mock function with given fields: ctx, code
+func (_m *Client) GetGiftByCode(ctx context.Context, code string) (*discount.Gift, error) {
+	ret := _m.Called(ctx, code)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetGiftByCode")
@@ -23,19 +24,19 @@ func (_m *Client) GetGiftByCode(code string) (*discount.Gift, error) {
 
 	var r0 *discount.Gift
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*discount.Gift, error)); ok {
-		return rf(code)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*discount.Gift, error)); ok {
+		return rf(ctx, code)
 	}
-	if rf, ok := ret.Get(0).(func(string) *discount.Gift); ok {
-		r0 = rf(code)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *discount.Gift); ok {
+		r0 = rf(ctx, code)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*discount.Gift)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(code)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -43,9 +44,39 @@ func (_m *Client) GetGiftByCode(code string) (*discount.Gift, error) {
 	return r0, r1
 }
 
-// UseGift provides a mock function with given fields: code
-func (_m *Client) UseGift(code string) (*discount.Gift, error) {
-	ret := _m.Called(code)
+// ReleaseGift provides a mock function with given fields: ctx, code, reference
+func (_m *Client) ReleaseGift(ctx context.Context, code string, reference string) (*discount.Gift, error) {
+	ret := _m.Called(ctx, code, reference)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseGift")
+	}
+
+	var r0 *discount.Gift
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*discount.Gift, error)); ok {
+		return rf(ctx, code, reference)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *discount.Gift); ok {
+		r0 = rf(ctx, code, reference)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*discount.Gift)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, code, reference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseGift provides a mock function with given fields: ctx, code, memberID, reference
+func (_m *Client) UseGift(ctx context.Context, code string, memberID int64, reference string) (*discount.Gift, error) {
+	ret := _m.Called(ctx, code, memberID, reference)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UseGift")
@@ -53,19 +84,19 @@ func (_m *Client) UseGift(code string) (*discount.Gift, error) {
 
 	var r0 *discount.Gift
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*discount.Gift, error)); ok {
-		return rf(code)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string) (*discount.Gift, error)); ok {
+		return rf(ctx, code, memberID, reference)
 	}
-	if rf, ok := ret.Get(0).(func(string) *discount.Gift); ok {
-		r0 = rf(code)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, string) *discount.Gift); ok {
+		r0 = rf(ctx, code, memberID, reference)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*discount.Gift)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(code)
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, string) error); ok {
+		r1 = rf(ctx, code, memberID, reference)
 	} else {
 		r1 = ret.Error(1)
 	}
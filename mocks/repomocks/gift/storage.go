@@ -0,0 +1,315 @@
+// Code generated by mockery v2.40.1. DO NOT EDIT.
+
+package repomocks
+
+import (
+	context "context"
+	gift "wallet/storage/gift"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// CountMemberUses provides a mock function with given fields: ctx, giftID, memberID
+func (_m *Repository) CountMemberUses(ctx context.Context, giftID int64, memberID int64) (int64, error) {
+	ret := _m.Called(ctx, giftID, memberID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountMemberUses")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (int64, error)); ok {
+		return rf(ctx, giftID, memberID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) int64); ok {
+		r0 = rf(ctx, giftID, memberID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, giftID, memberID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, g
+func (_m *Repository) Create(ctx context.Context, g *gift.Gift) error {
+	ret := _m.Called(ctx, g)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gift.Gift) error); ok {
+		r0 = rf(ctx, g)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateUse provides a mock function with given fields: ctx, u
+func (_m *Repository) CreateUse(ctx context.Context, u *gift.Use) error {
+	ret := _m.Called(ctx, u)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateUse")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gift.Use) error); ok {
+		r0 = rf(ctx, u)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *Repository) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByCode provides a mock function with given fields: ctx, code
+func (_m *Repository) GetByCode(ctx context.Context, code string) (*gift.Gift, error) {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByCode")
+	}
+
+	var r0 *gift.Gift
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*gift.Gift, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *gift.Gift); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gift.Gift)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByCodeForUpdate provides a mock function with given fields: ctx, code
+func (_m *Repository) GetByCodeForUpdate(ctx context.Context, code string) (*gift.Gift, error) {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByCodeForUpdate")
+	}
+
+	var r0 *gift.Gift
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*gift.Gift, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *gift.Gift); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gift.Gift)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Repository) GetByID(ctx context.Context, id int64) (*gift.Gift, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *gift.Gift
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*gift.Gift, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *gift.Gift); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gift.Gift)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUseByReference provides a mock function with given fields: ctx, reference
+func (_m *Repository) GetUseByReference(ctx context.Context, reference string) (*gift.Use, error) {
+	ret := _m.Called(ctx, reference)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUseByReference")
+	}
+
+	var r0 *gift.Use
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*gift.Use, error)); ok {
+		return rf(ctx, reference)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *gift.Use); ok {
+		r0 = rf(ctx, reference)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*gift.Use)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, reference)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, limit, offset
+func (_m *Repository) List(ctx context.Context, limit int, offset int) ([]*gift.Gift, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*gift.Gift
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*gift.Gift, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*gift.Gift); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*gift.Gift)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseUse provides a mock function with given fields: ctx, u
+func (_m *Repository) ReleaseUse(ctx context.Context, u *gift.Use) error {
+	ret := _m.Called(ctx, u)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseUse")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gift.Use) error); ok {
+		r0 = rf(ctx, u)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, g
+func (_m *Repository) Update(ctx context.Context, g *gift.Gift) error {
+	ret := _m.Called(ctx, g)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gift.Gift) error); ok {
+		r0 = rf(ctx, g)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateUsedCount provides a mock function with given fields: ctx, g
+func (_m *Repository) UpdateUsedCount(ctx context.Context, g *gift.Gift) error {
+	ret := _m.Called(ctx, g)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUsedCount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *gift.Gift) error); ok {
+		r0 = rf(ctx, g)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Repository {
+	mock := &Repository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
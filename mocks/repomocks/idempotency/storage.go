@@ -0,0 +1,77 @@
+// Code generated by mockery v2.40.1. DO NOT EDIT.
+
+package repomocks
+
+import (
+	context "context"
+	idempotency "wallet/storage/idempotency"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// GetByKey provides a mock function with given fields: ctx, key
+func (_m *Repository) GetByKey(ctx context.Context, key string) (*idempotency.Key, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByKey")
+	}
+
+	var r0 *idempotency.Key
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*idempotency.Key, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *idempotency.Key); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*idempotency.Key)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: ctx, k
+func (_m *Repository) Insert(ctx context.Context, k *idempotency.Key) error {
+	ret := _m.Called(ctx, k)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Insert")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *idempotency.Key) error); ok {
+		r0 = rf(ctx, k)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Repository {
+	mock := &Repository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
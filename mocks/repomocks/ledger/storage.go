@@ -0,0 +1,133 @@
+// Code generated by mockery v2.40.1. DO NOT EDIT.
+
+package repomocks
+
+import (
+	context "context"
+	ledger "wallet/storage/ledger"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// GetAccountBalance provides a mock function with given fields: ctx, account, currency
+func (_m *Repository) GetAccountBalance(ctx context.Context, account ledger.Account, currency string) (int64, error) {
+	ret := _m.Called(ctx, account, currency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccountBalance")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ledger.Account, string) (int64, error)); ok {
+		return rf(ctx, account, currency)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ledger.Account, string) int64); ok {
+		r0 = rf(ctx, account, currency)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ledger.Account, string) error); ok {
+		r1 = rf(ctx, account, currency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEntryByID provides a mock function with given fields: ctx, id
+func (_m *Repository) GetEntryByID(ctx context.Context, id int64) (*ledger.JournalEntry, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEntryByID")
+	}
+
+	var r0 *ledger.JournalEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*ledger.JournalEntry, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *ledger.JournalEntry); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ledger.JournalEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWalletBalance provides a mock function with given fields: ctx, walletID
+func (_m *Repository) GetWalletBalance(ctx context.Context, walletID int64) (int64, error) {
+	ret := _m.Called(ctx, walletID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWalletBalance")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (int64, error)); ok {
+		return rf(ctx, walletID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, walletID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, walletID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertEntry provides a mock function with given fields: ctx, e
+func (_m *Repository) InsertEntry(ctx context.Context, e *ledger.JournalEntry) error {
+	ret := _m.Called(ctx, e)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertEntry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ledger.JournalEntry) error); ok {
+		r0 = rf(ctx, e)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Repository {
+	mock := &Repository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,11 +3,10 @@
 package repomocks
 
 import (
+	context "context"
 	member "wallet/service/member"
 
 	mock "github.com/stretchr/testify/mock"
-
-	sql "database/sql"
 )
 
 // UseCase is an autogenerated mock type for the UseCase type
@@ -15,9 +14,9 @@ type UseCase struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: r
-func (_m *UseCase) Create(r *member.CreateRequest) (*member.DTO, error) {
-	ret := _m.Called(r)
+// Create provides a mock function with given fields: ctx, r
+func (_m *UseCase) Create(ctx context.Context, r *member.CreateRequest) (*member.DTO, error) {
+	ret := _m.Called(ctx, r)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
@@ -25,19 +24,19 @@ func (_m *UseCase) Create(r *member.CreateRequest) (*member.DTO, error) {
 
 	var r0 *member.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*member.CreateRequest) (*member.DTO, error)); ok {
-		return rf(r)
+	if rf, ok := ret.Get(0).(func(context.Context, *member.CreateRequest) (*member.DTO, error)); ok {
+		return rf(ctx, r)
 	}
-	if rf, ok := ret.Get(0).(func(*member.CreateRequest) *member.DTO); ok {
-		r0 = rf(r)
+	if rf, ok := ret.Get(0).(func(context.Context, *member.CreateRequest) *member.DTO); ok {
+		r0 = rf(ctx, r)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*member.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*member.CreateRequest) error); ok {
-		r1 = rf(r)
+	if rf, ok := ret.Get(1).(func(context.Context, *member.CreateRequest) error); ok {
+		r1 = rf(ctx, r)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -45,9 +44,27 @@ func (_m *UseCase) Create(r *member.CreateRequest) (*member.DTO, error) {
 	return r0, r1
 }
 
-// GetById provides a mock function with given fields: id
-func (_m *UseCase) GetById(id int64) (*member.DTO, error) {
-	ret := _m.Called(id)
+// Delete provides a mock function with given fields: ctx, id
+func (_m *UseCase) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetById provides a mock function with given fields: ctx, id
+func (_m *UseCase) GetById(ctx context.Context, id int64) (*member.DTO, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetById")
@@ -55,19 +72,19 @@ func (_m *UseCase) GetById(id int64) (*member.DTO, error) {
 
 	var r0 *member.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) (*member.DTO, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*member.DTO, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int64) *member.DTO); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *member.DTO); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*member.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -75,9 +92,9 @@ func (_m *UseCase) GetById(id int64) (*member.DTO, error) {
 	return r0, r1
 }
 
-// GetByPhone provides a mock function with given fields: phone
-func (_m *UseCase) GetByPhone(phone string) (*member.DTO, error) {
-	ret := _m.Called(phone)
+// GetByPhone provides a mock function with given fields: ctx, phone
+func (_m *UseCase) GetByPhone(ctx context.Context, phone string) (*member.DTO, error) {
+	ret := _m.Called(ctx, phone)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByPhone")
@@ -85,19 +102,19 @@ func (_m *UseCase) GetByPhone(phone string) (*member.DTO, error) {
 
 	var r0 *member.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*member.DTO, error)); ok {
-		return rf(phone)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*member.DTO, error)); ok {
+		return rf(ctx, phone)
 	}
-	if rf, ok := ret.Get(0).(func(string) *member.DTO); ok {
-		r0 = rf(phone)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *member.DTO); ok {
+		r0 = rf(ctx, phone)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*member.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(phone)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, phone)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -105,9 +122,9 @@ func (_m *UseCase) GetByPhone(phone string) (*member.DTO, error) {
 	return r0, r1
 }
 
-// GetMembersByGiftCode provides a mock function with given fields: gift, limit, offset
-func (_m *UseCase) GetMembersByGiftCode(gift string, limit int, offset int) ([]*member.DTO, error) {
-	ret := _m.Called(gift, limit, offset)
+// GetMembersByGiftCode provides a mock function with given fields: ctx, gift, limit, offset
+func (_m *UseCase) GetMembersByGiftCode(ctx context.Context, gift string, limit int, offset int) ([]*member.DTO, error) {
+	ret := _m.Called(ctx, gift, limit, offset)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetMembersByGiftCode")
@@ -115,19 +132,19 @@ func (_m *UseCase) GetMembersByGiftCode(gift string, limit int, offset int) ([]*
 
 	var r0 []*member.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, int, int) ([]*member.DTO, error)); ok {
-		return rf(gift, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) ([]*member.DTO, error)); ok {
+		return rf(ctx, gift, limit, offset)
 	}
-	if rf, ok := ret.Get(0).(func(string, int, int) []*member.DTO); ok {
-		r0 = rf(gift, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []*member.DTO); ok {
+		r0 = rf(ctx, gift, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*member.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, int, int) error); ok {
-		r1 = rf(gift, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, gift, limit, offset)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -135,29 +152,29 @@ func (_m *UseCase) GetMembersByGiftCode(gift string, limit int, offset int) ([]*
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: r
-func (_m *UseCase) Update(r *member.DTO) (*member.DTO, error) {
-	ret := _m.Called(r)
+// SetTier provides a mock function with given fields: ctx, id, tier
+func (_m *UseCase) SetTier(ctx context.Context, id int64, tier string) (*member.DTO, error) {
+	ret := _m.Called(ctx, id, tier)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Update")
+		panic("no return value specified for SetTier")
 	}
 
 	var r0 *member.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*member.DTO) (*member.DTO, error)); ok {
-		return rf(r)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (*member.DTO, error)); ok {
+		return rf(ctx, id, tier)
 	}
-	if rf, ok := ret.Get(0).(func(*member.DTO) *member.DTO); ok {
-		r0 = rf(r)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) *member.DTO); ok {
+		r0 = rf(ctx, id, tier)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*member.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*member.DTO) error); ok {
-		r1 = rf(r)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, id, tier)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -165,29 +182,29 @@ func (_m *UseCase) Update(r *member.DTO) (*member.DTO, error) {
 	return r0, r1
 }
 
-// WithTX provides a mock function with given fields: tx
-func (_m *UseCase) WithTX(tx *sql.Tx) (*member.Service, error) {
-	ret := _m.Called(tx)
+// Update provides a mock function with given fields: ctx, r
+func (_m *UseCase) Update(ctx context.Context, r *member.DTO) (*member.DTO, error) {
+	ret := _m.Called(ctx, r)
 
 	if len(ret) == 0 {
-		panic("no return value specified for WithTX")
+		panic("no return value specified for Update")
 	}
 
-	var r0 *member.Service
+	var r0 *member.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx) (*member.Service, error)); ok {
-		return rf(tx)
+	if rf, ok := ret.Get(0).(func(context.Context, *member.DTO) (*member.DTO, error)); ok {
+		return rf(ctx, r)
 	}
-	if rf, ok := ret.Get(0).(func(*sql.Tx) *member.Service); ok {
-		r0 = rf(tx)
+	if rf, ok := ret.Get(0).(func(context.Context, *member.DTO) *member.DTO); ok {
+		r0 = rf(ctx, r)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*member.Service)
+			r0 = ret.Get(0).(*member.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*sql.Tx) error); ok {
-		r1 = rf(tx)
+	if rf, ok := ret.Get(1).(func(context.Context, *member.DTO) error); ok {
+		r1 = rf(ctx, r)
 	} else {
 		r1 = ret.Error(1)
 	}
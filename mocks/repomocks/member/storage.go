@@ -3,11 +3,10 @@
 package repomocks
 
 import (
+	context "context"
 	member "wallet/storage/member"
 
 	mock "github.com/stretchr/testify/mock"
-
-	sql "database/sql"
 )
 
 // Repository is an autogenerated mock type for the Repository type
@@ -15,17 +14,35 @@ type Repository struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: u
-func (_m *Repository) Create(u *member.Member) error {
-	ret := _m.Called(u)
+// Create provides a mock function with given fields: ctx, u
+func (_m *Repository) Create(ctx context.Context, u *member.Member) error {
+	ret := _m.Called(ctx, u)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*member.Member) error); ok {
-		r0 = rf(u)
+	if rf, ok := ret.Get(0).(func(context.Context, *member.Member) error); ok {
+		r0 = rf(ctx, u)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *Repository) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -33,9 +50,9 @@ func (_m *Repository) Create(u *member.Member) error {
 	return r0
 }
 
-// GetAllByPage provides a mock function with given fields: limit, offset, count
-func (_m *Repository) GetAllByPage(limit int, offset int, count bool) ([]*member.Member, int, error) {
-	ret := _m.Called(limit, offset, count)
+// GetAllByPage provides a mock function with given fields: ctx, limit, offset, count
+func (_m *Repository) GetAllByPage(ctx context.Context, limit int, offset int, count bool) ([]*member.Member, int, error) {
+	ret := _m.Called(ctx, limit, offset, count)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllByPage")
@@ -44,25 +61,25 @@ func (_m *Repository) GetAllByPage(limit int, offset int, count bool) ([]*member
 	var r0 []*member.Member
 	var r1 int
 	var r2 error
-	if rf, ok := ret.Get(0).(func(int, int, bool) ([]*member.Member, int, error)); ok {
-		return rf(limit, offset, count)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, bool) ([]*member.Member, int, error)); ok {
+		return rf(ctx, limit, offset, count)
 	}
-	if rf, ok := ret.Get(0).(func(int, int, bool) []*member.Member); ok {
-		r0 = rf(limit, offset, count)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, bool) []*member.Member); ok {
+		r0 = rf(ctx, limit, offset, count)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*member.Member)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, int, bool) int); ok {
-		r1 = rf(limit, offset, count)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, bool) int); ok {
+		r1 = rf(ctx, limit, offset, count)
 	} else {
 		r1 = ret.Get(1).(int)
 	}
 
-	if rf, ok := ret.Get(2).(func(int, int, bool) error); ok {
-		r2 = rf(limit, offset, count)
+	if rf, ok := ret.Get(2).(func(context.Context, int, int, bool) error); ok {
+		r2 = rf(ctx, limit, offset, count)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -70,9 +87,9 @@ func (_m *Repository) GetAllByPage(limit int, offset int, count bool) ([]*member
 	return r0, r1, r2
 }
 
-// GetById provides a mock function with given fields: id
-func (_m *Repository) GetById(id int64) (*member.Member, error) {
-	ret := _m.Called(id)
+// GetById provides a mock function with given fields: ctx, id
+func (_m *Repository) GetById(ctx context.Context, id int64) (*member.Member, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetById")
@@ -80,19 +97,19 @@ func (_m *Repository) GetById(id int64) (*member.Member, error) {
 
 	var r0 *member.Member
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) (*member.Member, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*member.Member, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int64) *member.Member); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *member.Member); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*member.Member)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -100,9 +117,9 @@ func (_m *Repository) GetById(id int64) (*member.Member, error) {
 	return r0, r1
 }
 
-// GetByPhone provides a mock function with given fields: phone
-func (_m *Repository) GetByPhone(phone string) (*member.Member, error) {
-	ret := _m.Called(phone)
+// GetByPhone provides a mock function with given fields: ctx, phone
+func (_m *Repository) GetByPhone(ctx context.Context, phone string) (*member.Member, error) {
+	ret := _m.Called(ctx, phone)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByPhone")
@@ -110,19 +127,19 @@ func (_m *Repository) GetByPhone(phone string) (*member.Member, error) {
 
 	var r0 *member.Member
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*member.Member, error)); ok {
-		return rf(phone)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*member.Member, error)); ok {
+		return rf(ctx, phone)
 	}
-	if rf, ok := ret.Get(0).(func(string) *member.Member); ok {
-		r0 = rf(phone)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *member.Member); ok {
+		r0 = rf(ctx, phone)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*member.Member)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(phone)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, phone)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -130,17 +147,17 @@ func (_m *Repository) GetByPhone(phone string) (*member.Member, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: u
-func (_m *Repository) Update(u *member.Member) error {
-	ret := _m.Called(u)
+// SetTier provides a mock function with given fields: ctx, id, tier
+func (_m *Repository) SetTier(ctx context.Context, id int64, tier string) error {
+	ret := _m.Called(ctx, id, tier)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Update")
+		panic("no return value specified for SetTier")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*member.Member) error); ok {
-		r0 = rf(u)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, id, tier)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -148,34 +165,22 @@ func (_m *Repository) Update(u *member.Member) error {
 	return r0
 }
 
-// WithTX provides a mock function with given fields: tx
-func (_m *Repository) WithTX(tx *sql.Tx) (member.Repository, error) {
-	ret := _m.Called(tx)
+// Update provides a mock function with given fields: ctx, u
+func (_m *Repository) Update(ctx context.Context, u *member.Member) error {
+	ret := _m.Called(ctx, u)
 
 	if len(ret) == 0 {
-		panic("no return value specified for WithTX")
-	}
-
-	var r0 member.Repository
-	var r1 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx) (member.Repository, error)); ok {
-		return rf(tx)
-	}
-	if rf, ok := ret.Get(0).(func(*sql.Tx) member.Repository); ok {
-		r0 = rf(tx)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(member.Repository)
-		}
+		panic("no return value specified for Update")
 	}
 
-	if rf, ok := ret.Get(1).(func(*sql.Tx) error); ok {
-		r1 = rf(tx)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *member.Member) error); ok {
+		r0 = rf(ctx, u)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
 // NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -0,0 +1,89 @@
+// Code generated by mockery v2.40.1. DO NOT EDIT.
+
+package repomocks
+
+import (
+	context "context"
+	reconciliation "wallet/service/reconciliation"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UseCase is an autogenerated mock type for the UseCase type
+type UseCase struct {
+	mock.Mock
+}
+
+// GetReports provides a mock function with given fields: ctx, runID
+func (_m *UseCase) GetReports(ctx context.Context, runID string) ([]*reconciliation.DTO, error) {
+	ret := _m.Called(ctx, runID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReports")
+	}
+
+	var r0 []*reconciliation.DTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*reconciliation.DTO, error)); ok {
+		return rf(ctx, runID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*reconciliation.DTO); ok {
+		r0 = rf(ctx, runID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*reconciliation.DTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, runID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Run provides a mock function with given fields: ctx, repair
+func (_m *UseCase) Run(ctx context.Context, repair bool) (*reconciliation.Summary, error) {
+	ret := _m.Called(ctx, repair)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Run")
+	}
+
+	var r0 *reconciliation.Summary
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, bool) (*reconciliation.Summary, error)); ok {
+		return rf(ctx, repair)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, bool) *reconciliation.Summary); ok {
+		r0 = rf(ctx, repair)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*reconciliation.Summary)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, bool) error); ok {
+		r1 = rf(ctx, repair)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewUseCase creates a new instance of UseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UseCase {
+	mock := &UseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
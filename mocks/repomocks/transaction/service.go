@@ -3,7 +3,8 @@
 package repomocks
 
 import (
-	sql "database/sql"
+	context "context"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
 
@@ -15,29 +16,27 @@ type UseCase struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: r
-func (_m *UseCase) Create(r *transaction.CreateRequest) (*transaction.DTO, error) {
-	ret := _m.Called(r)
+// Archive provides a mock function with given fields: ctx, before
+func (_m *UseCase) Archive(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Create")
+		panic("no return value specified for Archive")
 	}
 
-	var r0 *transaction.DTO
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*transaction.CreateRequest) (*transaction.DTO, error)); ok {
-		return rf(r)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
 	}
-	if rf, ok := ret.Get(0).(func(*transaction.CreateRequest) *transaction.DTO); ok {
-		r0 = rf(r)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*transaction.DTO)
-		}
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(*transaction.CreateRequest) error); ok {
-		r1 = rf(r)
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -45,45 +44,69 @@ func (_m *UseCase) Create(r *transaction.CreateRequest) (*transaction.DTO, error
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: id
-func (_m *UseCase) Delete(id int64) error {
-	ret := _m.Called(id)
+// Create provides a mock function with given fields: ctx, r
+func (_m *UseCase) Create(ctx context.Context, r *transaction.CreateRequest) (*transaction.DTO, error) {
+	ret := _m.Called(ctx, r)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Delete")
+		panic("no return value specified for Create")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(int64) error); ok {
-		r0 = rf(id)
+	var r0 *transaction.DTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateRequest) (*transaction.DTO, error)); ok {
+		return rf(ctx, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.CreateRequest) *transaction.DTO); ok {
+		r0 = rf(ctx, r)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.DTO)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, *transaction.CreateRequest) error); ok {
+		r1 = rf(ctx, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// DeleteByWalletID provides a mock function with given fields: walletID
-func (_m *UseCase) DeleteByWalletID(walletID int64) error {
-	ret := _m.Called(walletID)
+// CreateTransfer provides a mock function with given fields: ctx, r
+func (_m *UseCase) CreateTransfer(ctx context.Context, r *transaction.TransferRequest) ([]*transaction.DTO, error) {
+	ret := _m.Called(ctx, r)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteByWalletID")
+		panic("no return value specified for CreateTransfer")
+	}
+
+	var r0 []*transaction.DTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.TransferRequest) ([]*transaction.DTO, error)); ok {
+		return rf(ctx, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.TransferRequest) []*transaction.DTO); ok {
+		r0 = rf(ctx, r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*transaction.DTO)
+		}
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(int64) error); ok {
-		r0 = rf(walletID)
+	if rf, ok := ret.Get(1).(func(context.Context, *transaction.TransferRequest) error); ok {
+		r1 = rf(ctx, r)
 	} else {
-		r0 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
-// GetBalance provides a mock function with given fields: walletID
-func (_m *UseCase) GetBalance(walletID int64) (int64, error) {
-	ret := _m.Called(walletID)
+// GetBalance provides a mock function with given fields: ctx, walletID
+func (_m *UseCase) GetBalance(ctx context.Context, walletID int64) (int64, error) {
+	ret := _m.Called(ctx, walletID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetBalance")
@@ -91,17 +114,17 @@ func (_m *UseCase) GetBalance(walletID int64) (int64, error) {
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
-		return rf(walletID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (int64, error)); ok {
+		return rf(ctx, walletID)
 	}
-	if rf, ok := ret.Get(0).(func(int64) int64); ok {
-		r0 = rf(walletID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, walletID)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(walletID)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, walletID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -109,9 +132,9 @@ func (_m *UseCase) GetBalance(walletID int64) (int64, error) {
 	return r0, r1
 }
 
-// GetByDiscountCodeWithPagination provides a mock function with given fields: discountCode, limit, offset
-func (_m *UseCase) GetByDiscountCodeWithPagination(discountCode string, limit int, offset int) ([]*transaction.DTO, error) {
-	ret := _m.Called(discountCode, limit, offset)
+// GetByDiscountCodeWithPagination provides a mock function with given fields: ctx, discountCode, limit, offset
+func (_m *UseCase) GetByDiscountCodeWithPagination(ctx context.Context, discountCode string, limit int, offset int) ([]*transaction.DTO, error) {
+	ret := _m.Called(ctx, discountCode, limit, offset)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByDiscountCodeWithPagination")
@@ -119,19 +142,19 @@ func (_m *UseCase) GetByDiscountCodeWithPagination(discountCode string, limit in
 
 	var r0 []*transaction.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, int, int) ([]*transaction.DTO, error)); ok {
-		return rf(discountCode, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) ([]*transaction.DTO, error)); ok {
+		return rf(ctx, discountCode, limit, offset)
 	}
-	if rf, ok := ret.Get(0).(func(string, int, int) []*transaction.DTO); ok {
-		r0 = rf(discountCode, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []*transaction.DTO); ok {
+		r0 = rf(ctx, discountCode, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*transaction.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, int, int) error); ok {
-		r1 = rf(discountCode, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, discountCode, limit, offset)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -139,9 +162,9 @@ func (_m *UseCase) GetByDiscountCodeWithPagination(discountCode string, limit in
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: id
-func (_m *UseCase) GetByID(id int64) (*transaction.DTO, error) {
-	ret := _m.Called(id)
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *UseCase) GetByID(ctx context.Context, id int64) (*transaction.DTO, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByID")
@@ -149,19 +172,19 @@ func (_m *UseCase) GetByID(id int64) (*transaction.DTO, error) {
 
 	var r0 *transaction.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) (*transaction.DTO, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*transaction.DTO, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int64) *transaction.DTO); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *transaction.DTO); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*transaction.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -169,9 +192,9 @@ func (_m *UseCase) GetByID(id int64) (*transaction.DTO, error) {
 	return r0, r1
 }
 
-// GetByWalletID provides a mock function with given fields: walletID
-func (_m *UseCase) GetByWalletID(walletID int64) ([]*transaction.DTO, error) {
-	ret := _m.Called(walletID)
+// GetByWalletID provides a mock function with given fields: ctx, walletID
+func (_m *UseCase) GetByWalletID(ctx context.Context, walletID int64) ([]*transaction.DTO, error) {
+	ret := _m.Called(ctx, walletID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByWalletID")
@@ -179,19 +202,19 @@ func (_m *UseCase) GetByWalletID(walletID int64) ([]*transaction.DTO, error) {
 
 	var r0 []*transaction.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) ([]*transaction.DTO, error)); ok {
-		return rf(walletID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*transaction.DTO, error)); ok {
+		return rf(ctx, walletID)
 	}
-	if rf, ok := ret.Get(0).(func(int64) []*transaction.DTO); ok {
-		r0 = rf(walletID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*transaction.DTO); ok {
+		r0 = rf(ctx, walletID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*transaction.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(walletID)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, walletID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -199,9 +222,9 @@ func (_m *UseCase) GetByWalletID(walletID int64) ([]*transaction.DTO, error) {
 	return r0, r1
 }
 
-// GetByWalletIDAndDiscountCode provides a mock function with given fields: walletID, discountCode
-func (_m *UseCase) GetByWalletIDAndDiscountCode(walletID int64, discountCode string) ([]*transaction.DTO, error) {
-	ret := _m.Called(walletID, discountCode)
+// GetByWalletIDAndDiscountCode provides a mock function with given fields: ctx, walletID, discountCode
+func (_m *UseCase) GetByWalletIDAndDiscountCode(ctx context.Context, walletID int64, discountCode string) ([]*transaction.DTO, error) {
+	ret := _m.Called(ctx, walletID, discountCode)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByWalletIDAndDiscountCode")
@@ -209,19 +232,19 @@ func (_m *UseCase) GetByWalletIDAndDiscountCode(walletID int64, discountCode str
 
 	var r0 []*transaction.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, string) ([]*transaction.DTO, error)); ok {
-		return rf(walletID, discountCode)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) ([]*transaction.DTO, error)); ok {
+		return rf(ctx, walletID, discountCode)
 	}
-	if rf, ok := ret.Get(0).(func(int64, string) []*transaction.DTO); ok {
-		r0 = rf(walletID, discountCode)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) []*transaction.DTO); ok {
+		r0 = rf(ctx, walletID, discountCode)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*transaction.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, string) error); ok {
-		r1 = rf(walletID, discountCode)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, walletID, discountCode)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -229,9 +252,9 @@ func (_m *UseCase) GetByWalletIDAndDiscountCode(walletID int64, discountCode str
 	return r0, r1
 }
 
-// GetByWalletIDAndType provides a mock function with given fields: walletID, transactionType
-func (_m *UseCase) GetByWalletIDAndType(walletID int64, transactionType transaction.Type) ([]*transaction.DTO, error) {
-	ret := _m.Called(walletID, transactionType)
+// GetByWalletIDAndType provides a mock function with given fields: ctx, walletID, transactionType
+func (_m *UseCase) GetByWalletIDAndType(ctx context.Context, walletID int64, transactionType transaction.Type) ([]*transaction.DTO, error) {
+	ret := _m.Called(ctx, walletID, transactionType)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByWalletIDAndType")
@@ -239,19 +262,19 @@ func (_m *UseCase) GetByWalletIDAndType(walletID int64, transactionType transact
 
 	var r0 []*transaction.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, transaction.Type) ([]*transaction.DTO, error)); ok {
-		return rf(walletID, transactionType)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, transaction.Type) ([]*transaction.DTO, error)); ok {
+		return rf(ctx, walletID, transactionType)
 	}
-	if rf, ok := ret.Get(0).(func(int64, transaction.Type) []*transaction.DTO); ok {
-		r0 = rf(walletID, transactionType)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, transaction.Type) []*transaction.DTO); ok {
+		r0 = rf(ctx, walletID, transactionType)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*transaction.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, transaction.Type) error); ok {
-		r1 = rf(walletID, transactionType)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, transaction.Type) error); ok {
+		r1 = rf(ctx, walletID, transactionType)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -259,9 +282,9 @@ func (_m *UseCase) GetByWalletIDAndType(walletID int64, transactionType transact
 	return r0, r1
 }
 
-// GetByWalletIDAndTypeAndDiscountCode provides a mock function with given fields: walletID, transactionType, discountCode
-func (_m *UseCase) GetByWalletIDAndTypeAndDiscountCode(walletID int64, transactionType transaction.Type, discountCode string) ([]*transaction.DTO, error) {
-	ret := _m.Called(walletID, transactionType, discountCode)
+// GetByWalletIDAndTypeAndDiscountCode provides a mock function with given fields: ctx, walletID, transactionType, discountCode
+func (_m *UseCase) GetByWalletIDAndTypeAndDiscountCode(ctx context.Context, walletID int64, transactionType transaction.Type, discountCode string) ([]*transaction.DTO, error) {
+	ret := _m.Called(ctx, walletID, transactionType, discountCode)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByWalletIDAndTypeAndDiscountCode")
@@ -269,19 +292,19 @@ func (_m *UseCase) GetByWalletIDAndTypeAndDiscountCode(walletID int64, transacti
 
 	var r0 []*transaction.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, transaction.Type, string) ([]*transaction.DTO, error)); ok {
-		return rf(walletID, transactionType, discountCode)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, transaction.Type, string) ([]*transaction.DTO, error)); ok {
+		return rf(ctx, walletID, transactionType, discountCode)
 	}
-	if rf, ok := ret.Get(0).(func(int64, transaction.Type, string) []*transaction.DTO); ok {
-		r0 = rf(walletID, transactionType, discountCode)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, transaction.Type, string) []*transaction.DTO); ok {
+		r0 = rf(ctx, walletID, transactionType, discountCode)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*transaction.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, transaction.Type, string) error); ok {
-		r1 = rf(walletID, transactionType, discountCode)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, transaction.Type, string) error); ok {
+		r1 = rf(ctx, walletID, transactionType, discountCode)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -289,9 +312,9 @@ func (_m *UseCase) GetByWalletIDAndTypeAndDiscountCode(walletID int64, transacti
 	return r0, r1
 }
 
-// GetByWalletIDWithPagination provides a mock function with given fields: walletID, limit, offset
-func (_m *UseCase) GetByWalletIDWithPagination(walletID int64, limit int, offset int) ([]*transaction.DTO, error) {
-	ret := _m.Called(walletID, limit, offset)
+// GetByWalletIDWithPagination provides a mock function with given fields: ctx, walletID, limit, offset
+func (_m *UseCase) GetByWalletIDWithPagination(ctx context.Context, walletID int64, limit int, offset int) ([]*transaction.DTO, error) {
+	ret := _m.Called(ctx, walletID, limit, offset)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByWalletIDWithPagination")
@@ -299,19 +322,77 @@ func (_m *UseCase) GetByWalletIDWithPagination(walletID int64, limit int, offset
 
 	var r0 []*transaction.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, int, int) ([]*transaction.DTO, error)); ok {
-		return rf(walletID, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) ([]*transaction.DTO, error)); ok {
+		return rf(ctx, walletID, limit, offset)
 	}
-	if rf, ok := ret.Get(0).(func(int64, int, int) []*transaction.DTO); ok {
-		r0 = rf(walletID, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) []*transaction.DTO); ok {
+		r0 = rf(ctx, walletID, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*transaction.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, int, int) error); ok {
-		r1 = rf(walletID, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int, int) error); ok {
+		r1 = rf(ctx, walletID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRefundedAmount provides a mock function with given fields: ctx, id
+func (_m *UseCase) GetRefundedAmount(ctx context.Context, id int64) (int64, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRefundedAmount")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (int64, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, r
+func (_m *UseCase) List(ctx context.Context, r *transaction.ListRequest) (*transaction.Page, error) {
+	ret := _m.Called(ctx, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *transaction.Page
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.ListRequest) (*transaction.Page, error)); ok {
+		return rf(ctx, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.ListRequest) *transaction.Page); ok {
+		r0 = rf(ctx, r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.Page)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *transaction.ListRequest) error); ok {
+		r1 = rf(ctx, r)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -319,29 +400,29 @@ func (_m *UseCase) GetByWalletIDWithPagination(walletID int64, limit int, offset
 	return r0, r1
 }
 
-// WithTX provides a mock function with given fields: tx
-func (_m *UseCase) WithTX(tx *sql.Tx) (*transaction.Service, error) {
-	ret := _m.Called(tx)
+// Statement provides a mock function with given fields: ctx, r
+func (_m *UseCase) Statement(ctx context.Context, r *transaction.StatementRequest) (*transaction.Statement, error) {
+	ret := _m.Called(ctx, r)
 
 	if len(ret) == 0 {
-		panic("no return value specified for WithTX")
+		panic("no return value specified for Statement")
 	}
 
-	var r0 *transaction.Service
+	var r0 *transaction.Statement
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx) (*transaction.Service, error)); ok {
-		return rf(tx)
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.StatementRequest) (*transaction.Statement, error)); ok {
+		return rf(ctx, r)
 	}
-	if rf, ok := ret.Get(0).(func(*sql.Tx) *transaction.Service); ok {
-		r0 = rf(tx)
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.StatementRequest) *transaction.Statement); ok {
+		r0 = rf(ctx, r)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*transaction.Service)
+			r0 = ret.Get(0).(*transaction.Statement)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*sql.Tx) error); ok {
-		r1 = rf(tx)
+	if rf, ok := ret.Get(1).(func(context.Context, *transaction.StatementRequest) error); ok {
+		r1 = rf(ctx, r)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -3,7 +3,8 @@
 package repomocks
 
 import (
-	sql "database/sql"
+	context "context"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
 
@@ -15,99 +16,27 @@ type Repository struct {
 	mock.Mock
 }
 
-// DeleteByID provides a mock function with given fields: id
-func (_m *Repository) DeleteByID(id int64) error {
-	ret := _m.Called(id)
+// Archive provides a mock function with given fields: ctx, walletID
+func (_m *Repository) Archive(ctx context.Context, walletID int64) (int64, error) {
+	ret := _m.Called(ctx, walletID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteByID")
-	}
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(int64) error); ok {
-		r0 = rf(id)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// DeleteByWalletID provides a mock function with given fields: walletID
-func (_m *Repository) DeleteByWalletID(walletID int64) error {
-	ret := _m.Called(walletID)
-
-	if len(ret) == 0 {
-		panic("no return value specified for DeleteByWalletID")
-	}
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(int64) error); ok {
-		r0 = rf(walletID)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// DeleteByWalletIDAndDiscountCode provides a mock function with given fields: walletID, discountCode
-func (_m *Repository) DeleteByWalletIDAndDiscountCode(walletID int64, discountCode string) error {
-	ret := _m.Called(walletID, discountCode)
-
-	if len(ret) == 0 {
-		panic("no return value specified for DeleteByWalletIDAndDiscountCode")
-	}
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(int64, string) error); ok {
-		r0 = rf(walletID, discountCode)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// DeleteByWalletIDAndType provides a mock function with given fields: walletID, transactionType
-func (_m *Repository) DeleteByWalletIDAndType(walletID int64, transactionType transaction.Type) error {
-	ret := _m.Called(walletID, transactionType)
-
-	if len(ret) == 0 {
-		panic("no return value specified for DeleteByWalletIDAndType")
-	}
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(int64, transaction.Type) error); ok {
-		r0 = rf(walletID, transactionType)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
-// GetBalance provides a mock function with given fields: walletID
-func (_m *Repository) GetBalance(walletID int64) (int64, error) {
-	ret := _m.Called(walletID)
-
-	if len(ret) == 0 {
-		panic("no return value specified for GetBalance")
+		panic("no return value specified for Archive")
 	}
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
-		return rf(walletID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (int64, error)); ok {
+		return rf(ctx, walletID)
 	}
-	if rf, ok := ret.Get(0).(func(int64) int64); ok {
-		r0 = rf(walletID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, walletID)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(walletID)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, walletID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -115,29 +44,29 @@ func (_m *Repository) GetBalance(walletID int64) (int64, error) {
 	return r0, r1
 }
 
-// GetByDiscountCodeWithPagination provides a mock function with given fields: discountCode, limit, offset
-func (_m *Repository) GetByDiscountCodeWithPagination(discountCode string, limit int, offset int) ([]*transaction.Transaction, error) {
-	ret := _m.Called(discountCode, limit, offset)
+// Find provides a mock function with given fields: ctx, f
+func (_m *Repository) Find(ctx context.Context, f *transaction.Filter) ([]*transaction.Transaction, error) {
+	ret := _m.Called(ctx, f)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetByDiscountCodeWithPagination")
+		panic("no return value specified for Find")
 	}
 
 	var r0 []*transaction.Transaction
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, int, int) ([]*transaction.Transaction, error)); ok {
-		return rf(discountCode, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.Filter) ([]*transaction.Transaction, error)); ok {
+		return rf(ctx, f)
 	}
-	if rf, ok := ret.Get(0).(func(string, int, int) []*transaction.Transaction); ok {
-		r0 = rf(discountCode, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.Filter) []*transaction.Transaction); ok {
+		r0 = rf(ctx, f)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*transaction.Transaction)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, int, int) error); ok {
-		r1 = rf(discountCode, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, *transaction.Filter) error); ok {
+		r1 = rf(ctx, f)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -145,29 +74,29 @@ func (_m *Repository) GetByDiscountCodeWithPagination(discountCode string, limit
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: id
-func (_m *Repository) GetByID(id int64) (*transaction.Transaction, error) {
-	ret := _m.Called(id)
+// GetArchivableWalletIDs provides a mock function with given fields: ctx, before, limit
+func (_m *Repository) GetArchivableWalletIDs(ctx context.Context, before time.Time, limit int) ([]int64, error) {
+	ret := _m.Called(ctx, before, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetByID")
+		panic("no return value specified for GetArchivableWalletIDs")
 	}
 
-	var r0 *transaction.Transaction
+	var r0 []int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) (*transaction.Transaction, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]int64, error)); ok {
+		return rf(ctx, before, limit)
 	}
-	if rf, ok := ret.Get(0).(func(int64) *transaction.Transaction); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []int64); ok {
+		r0 = rf(ctx, before, limit)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*transaction.Transaction)
+			r0 = ret.Get(0).([]int64)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, before, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -175,29 +104,27 @@ func (_m *Repository) GetByID(id int64) (*transaction.Transaction, error) {
 	return r0, r1
 }
 
-// GetByWalletID provides a mock function with given fields: walletID
-func (_m *Repository) GetByWalletID(walletID int64) ([]*transaction.Transaction, error) {
-	ret := _m.Called(walletID)
+// GetBalance provides a mock function with given fields: ctx, walletID
+func (_m *Repository) GetBalance(ctx context.Context, walletID int64) (int64, error) {
+	ret := _m.Called(ctx, walletID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetByWalletID")
+		panic("no return value specified for GetBalance")
 	}
 
-	var r0 []*transaction.Transaction
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) ([]*transaction.Transaction, error)); ok {
-		return rf(walletID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (int64, error)); ok {
+		return rf(ctx, walletID)
 	}
-	if rf, ok := ret.Get(0).(func(int64) []*transaction.Transaction); ok {
-		r0 = rf(walletID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, walletID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*transaction.Transaction)
-		}
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(walletID)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, walletID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -205,29 +132,27 @@ func (_m *Repository) GetByWalletID(walletID int64) ([]*transaction.Transaction,
 	return r0, r1
 }
 
-// GetByWalletIDAndDiscountCode provides a mock function with given fields: walletID, discountCode
-func (_m *Repository) GetByWalletIDAndDiscountCode(walletID int64, discountCode string) ([]*transaction.Transaction, error) {
-	ret := _m.Called(walletID, discountCode)
+// GetBalanceBefore provides a mock function with given fields: ctx, walletID, before
+func (_m *Repository) GetBalanceBefore(ctx context.Context, walletID int64, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, walletID, before)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetByWalletIDAndDiscountCode")
+		panic("no return value specified for GetBalanceBefore")
 	}
 
-	var r0 []*transaction.Transaction
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, string) ([]*transaction.Transaction, error)); ok {
-		return rf(walletID, discountCode)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) (int64, error)); ok {
+		return rf(ctx, walletID, before)
 	}
-	if rf, ok := ret.Get(0).(func(int64, string) []*transaction.Transaction); ok {
-		r0 = rf(walletID, discountCode)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) int64); ok {
+		r0 = rf(ctx, walletID, before)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*transaction.Transaction)
-		}
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, string) error); ok {
-		r1 = rf(walletID, discountCode)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time) error); ok {
+		r1 = rf(ctx, walletID, before)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -235,29 +160,29 @@ func (_m *Repository) GetByWalletIDAndDiscountCode(walletID int64, discountCode
 	return r0, r1
 }
 
-// GetByWalletIDAndType provides a mock function with given fields: walletID, transactionType
-func (_m *Repository) GetByWalletIDAndType(walletID int64, transactionType transaction.Type) ([]*transaction.Transaction, error) {
-	ret := _m.Called(walletID, transactionType)
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Repository) GetByID(ctx context.Context, id int64) (*transaction.Transaction, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetByWalletIDAndType")
+		panic("no return value specified for GetByID")
 	}
 
-	var r0 []*transaction.Transaction
+	var r0 *transaction.Transaction
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, transaction.Type) ([]*transaction.Transaction, error)); ok {
-		return rf(walletID, transactionType)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*transaction.Transaction, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int64, transaction.Type) []*transaction.Transaction); ok {
-		r0 = rf(walletID, transactionType)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *transaction.Transaction); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*transaction.Transaction)
+			r0 = ret.Get(0).(*transaction.Transaction)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, transaction.Type) error); ok {
-		r1 = rf(walletID, transactionType)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -265,29 +190,29 @@ func (_m *Repository) GetByWalletIDAndType(walletID int64, transactionType trans
 	return r0, r1
 }
 
-// GetByWalletIDAndTypeAndDiscountCode provides a mock function with given fields: walletID, transactionType, discountCode
-func (_m *Repository) GetByWalletIDAndTypeAndDiscountCode(walletID int64, transactionType transaction.Type, discountCode string) ([]*transaction.Transaction, error) {
-	ret := _m.Called(walletID, transactionType, discountCode)
+// GetDebitTotals provides a mock function with given fields: ctx, walletID, dayStart, monthStart
+func (_m *Repository) GetDebitTotals(ctx context.Context, walletID int64, dayStart time.Time, monthStart time.Time) (*transaction.DebitTotals, error) {
+	ret := _m.Called(ctx, walletID, dayStart, monthStart)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetByWalletIDAndTypeAndDiscountCode")
+		panic("no return value specified for GetDebitTotals")
 	}
 
-	var r0 []*transaction.Transaction
+	var r0 *transaction.DebitTotals
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, transaction.Type, string) ([]*transaction.Transaction, error)); ok {
-		return rf(walletID, transactionType, discountCode)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, time.Time) (*transaction.DebitTotals, error)); ok {
+		return rf(ctx, walletID, dayStart, monthStart)
 	}
-	if rf, ok := ret.Get(0).(func(int64, transaction.Type, string) []*transaction.Transaction); ok {
-		r0 = rf(walletID, transactionType, discountCode)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time, time.Time) *transaction.DebitTotals); ok {
+		r0 = rf(ctx, walletID, dayStart, monthStart)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*transaction.Transaction)
+			r0 = ret.Get(0).(*transaction.DebitTotals)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, transaction.Type, string) error); ok {
-		r1 = rf(walletID, transactionType, discountCode)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, walletID, dayStart, monthStart)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -295,29 +220,27 @@ func (_m *Repository) GetByWalletIDAndTypeAndDiscountCode(walletID int64, transa
 	return r0, r1
 }
 
-// GetByWalletIDWithPagination provides a mock function with given fields: walletID, limit, offset
-func (_m *Repository) GetByWalletIDWithPagination(walletID int64, limit int, offset int) ([]*transaction.Transaction, error) {
-	ret := _m.Called(walletID, limit, offset)
+// GetRefundedAmount provides a mock function with given fields: ctx, parentID
+func (_m *Repository) GetRefundedAmount(ctx context.Context, parentID int64) (int64, error) {
+	ret := _m.Called(ctx, parentID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetByWalletIDWithPagination")
+		panic("no return value specified for GetRefundedAmount")
 	}
 
-	var r0 []*transaction.Transaction
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, int, int) ([]*transaction.Transaction, error)); ok {
-		return rf(walletID, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (int64, error)); ok {
+		return rf(ctx, parentID)
 	}
-	if rf, ok := ret.Get(0).(func(int64, int, int) []*transaction.Transaction); ok {
-		r0 = rf(walletID, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) int64); ok {
+		r0 = rf(ctx, parentID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*transaction.Transaction)
-		}
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, int, int) error); ok {
-		r1 = rf(walletID, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, parentID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -325,17 +248,17 @@ func (_m *Repository) GetByWalletIDWithPagination(walletID int64, limit int, off
 	return r0, r1
 }
 
-// Insert provides a mock function with given fields: t
-func (_m *Repository) Insert(t *transaction.Transaction) error {
-	ret := _m.Called(t)
+// Insert provides a mock function with given fields: ctx, t
+func (_m *Repository) Insert(ctx context.Context, t *transaction.Transaction) error {
+	ret := _m.Called(ctx, t)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Insert")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*transaction.Transaction) error); ok {
-		r0 = rf(t)
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.Transaction) error); ok {
+		r0 = rf(ctx, t)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -343,36 +266,6 @@ func (_m *Repository) Insert(t *transaction.Transaction) error {
 	return r0
 }
 
-// WithTX provides a mock function with given fields: tx
-func (_m *Repository) WithTX(tx *sql.Tx) (transaction.Repository, error) {
-	ret := _m.Called(tx)
-
-	if len(ret) == 0 {
-		panic("no return value specified for WithTX")
-	}
-
-	var r0 transaction.Repository
-	var r1 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx) (transaction.Repository, error)); ok {
-		return rf(tx)
-	}
-	if rf, ok := ret.Get(0).(func(*sql.Tx) transaction.Repository); ok {
-		r0 = rf(tx)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(transaction.Repository)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(*sql.Tx) error); ok {
-		r1 = rf(tx)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
 // NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRepository(t interface {
@@ -3,7 +3,8 @@
 package repomocks
 
 import (
-	sql "database/sql"
+	context "context"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
 
@@ -17,9 +18,9 @@ type UseCase struct {
 	mock.Mock
 }
 
-// AddGift provides a mock function with given fields: r
-func (_m *UseCase) AddGift(r *wallet.AddGiftRequest) (*wallet.DTO, error) {
-	ret := _m.Called(r)
+// AddGift provides a mock function with given fields: ctx, r, idempotencyKey
+func (_m *UseCase) AddGift(ctx context.Context, r *wallet.AddGiftRequest, idempotencyKey string) (*wallet.DTO, error) {
+	ret := _m.Called(ctx, r, idempotencyKey)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AddGift")
@@ -27,19 +28,19 @@ func (_m *UseCase) AddGift(r *wallet.AddGiftRequest) (*wallet.DTO, error) {
 
 	var r0 *wallet.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*wallet.AddGiftRequest) (*wallet.DTO, error)); ok {
-		return rf(r)
+	if rf, ok := ret.Get(0).(func(context.Context, *wallet.AddGiftRequest, string) (*wallet.DTO, error)); ok {
+		return rf(ctx, r, idempotencyKey)
 	}
-	if rf, ok := ret.Get(0).(func(*wallet.AddGiftRequest) *wallet.DTO); ok {
-		r0 = rf(r)
+	if rf, ok := ret.Get(0).(func(context.Context, *wallet.AddGiftRequest, string) *wallet.DTO); ok {
+		r0 = rf(ctx, r, idempotencyKey)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*wallet.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*wallet.AddGiftRequest) error); ok {
-		r1 = rf(r)
+	if rf, ok := ret.Get(1).(func(context.Context, *wallet.AddGiftRequest, string) error); ok {
+		r1 = rf(ctx, r, idempotencyKey)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -47,9 +48,99 @@ func (_m *UseCase) AddGift(r *wallet.AddGiftRequest) (*wallet.DTO, error) {
 	return r0, r1
 }
 
-// Create provides a mock function with given fields: r
-func (_m *UseCase) Create(r *wallet.CreateRequest) (*wallet.DTO, error) {
-	ret := _m.Called(r)
+// Authorize provides a mock function with given fields: ctx, walletID, amount, ttl, idempotencyKey
+func (_m *UseCase) Authorize(ctx context.Context, walletID int64, amount int64, ttl time.Duration, idempotencyKey string) (*wallet.HoldDTO, error) {
+	ret := _m.Called(ctx, walletID, amount, ttl, idempotencyKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authorize")
+	}
+
+	var r0 *wallet.HoldDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, time.Duration, string) (*wallet.HoldDTO, error)); ok {
+		return rf(ctx, walletID, amount, ttl, idempotencyKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, time.Duration, string) *wallet.HoldDTO); ok {
+		r0 = rf(ctx, walletID, amount, ttl, idempotencyKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.HoldDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, time.Duration, string) error); ok {
+		r1 = rf(ctx, walletID, amount, ttl, idempotencyKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Capture provides a mock function with given fields: ctx, holdID, amount, idempotencyKey
+func (_m *UseCase) Capture(ctx context.Context, holdID int64, amount int64, idempotencyKey string) (*wallet.HoldDTO, error) {
+	ret := _m.Called(ctx, holdID, amount, idempotencyKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Capture")
+	}
+
+	var r0 *wallet.HoldDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string) (*wallet.HoldDTO, error)); ok {
+		return rf(ctx, holdID, amount, idempotencyKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string) *wallet.HoldDTO); ok {
+		r0 = rf(ctx, holdID, amount, idempotencyKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.HoldDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, string) error); ok {
+		r1 = rf(ctx, holdID, amount, idempotencyKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Close provides a mock function with given fields: ctx, id, reason, actor
+func (_m *UseCase) Close(ctx context.Context, id int64, reason string, actor string) (*wallet.DTO, error) {
+	ret := _m.Called(ctx, id, reason, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 *wallet.DTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) (*wallet.DTO, error)); ok {
+		return rf(ctx, id, reason, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) *wallet.DTO); ok {
+		r0 = rf(ctx, id, reason, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.DTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, id, reason, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, r
+func (_m *UseCase) Create(ctx context.Context, r *wallet.CreateRequest) (*wallet.DTO, error) {
+	ret := _m.Called(ctx, r)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
@@ -57,19 +148,19 @@ func (_m *UseCase) Create(r *wallet.CreateRequest) (*wallet.DTO, error) {
 
 	var r0 *wallet.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*wallet.CreateRequest) (*wallet.DTO, error)); ok {
-		return rf(r)
+	if rf, ok := ret.Get(0).(func(context.Context, *wallet.CreateRequest) (*wallet.DTO, error)); ok {
+		return rf(ctx, r)
 	}
-	if rf, ok := ret.Get(0).(func(*wallet.CreateRequest) *wallet.DTO); ok {
-		r0 = rf(r)
+	if rf, ok := ret.Get(0).(func(context.Context, *wallet.CreateRequest) *wallet.DTO); ok {
+		r0 = rf(ctx, r)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*wallet.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*wallet.CreateRequest) error); ok {
-		r1 = rf(r)
+	if rf, ok := ret.Get(1).(func(context.Context, *wallet.CreateRequest) error); ok {
+		r1 = rf(ctx, r)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -77,9 +168,9 @@ func (_m *UseCase) Create(r *wallet.CreateRequest) (*wallet.DTO, error) {
 	return r0, r1
 }
 
-// CreateTransactionAndUpdateWallet provides a mock function with given fields: id, amount, transactionType, description, discountCode
-func (_m *UseCase) CreateTransactionAndUpdateWallet(id int64, amount int64, transactionType transaction.Type, description string, discountCode string) (*wallet.DTO, error) {
-	ret := _m.Called(id, amount, transactionType, description, discountCode)
+// CreateTransactionAndUpdateWallet provides a mock function with given fields: ctx, id, amount, transactionType, description, discountCode, idempotencyKey
+func (_m *UseCase) CreateTransactionAndUpdateWallet(ctx context.Context, id int64, amount int64, transactionType transaction.Type, description string, discountCode string, idempotencyKey string) (*wallet.DTO, error) {
+	ret := _m.Called(ctx, id, amount, transactionType, description, discountCode, idempotencyKey)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateTransactionAndUpdateWallet")
@@ -87,19 +178,19 @@ func (_m *UseCase) CreateTransactionAndUpdateWallet(id int64, amount int64, tran
 
 	var r0 *wallet.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, int64, transaction.Type, string, string) (*wallet.DTO, error)); ok {
-		return rf(id, amount, transactionType, description, discountCode)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, transaction.Type, string, string, string) (*wallet.DTO, error)); ok {
+		return rf(ctx, id, amount, transactionType, description, discountCode, idempotencyKey)
 	}
-	if rf, ok := ret.Get(0).(func(int64, int64, transaction.Type, string, string) *wallet.DTO); ok {
-		r0 = rf(id, amount, transactionType, description, discountCode)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, transaction.Type, string, string, string) *wallet.DTO); ok {
+		r0 = rf(ctx, id, amount, transactionType, description, discountCode, idempotencyKey)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*wallet.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, int64, transaction.Type, string, string) error); ok {
-		r1 = rf(id, amount, transactionType, description, discountCode)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, transaction.Type, string, string, string) error); ok {
+		r1 = rf(ctx, id, amount, transactionType, description, discountCode, idempotencyKey)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -107,17 +198,17 @@ func (_m *UseCase) CreateTransactionAndUpdateWallet(id int64, amount int64, tran
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: id
-func (_m *UseCase) Delete(id int64) error {
-	ret := _m.Called(id)
+// Delete provides a mock function with given fields: ctx, id
+func (_m *UseCase) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Delete")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int64) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -125,17 +216,17 @@ func (_m *UseCase) Delete(id int64) error {
 	return r0
 }
 
-// DeleteByMemberID provides a mock function with given fields: memberID
-func (_m *UseCase) DeleteByMemberID(memberID int64) error {
-	ret := _m.Called(memberID)
+// DeleteByMemberID provides a mock function with given fields: ctx, memberID
+func (_m *UseCase) DeleteByMemberID(ctx context.Context, memberID int64) error {
+	ret := _m.Called(ctx, memberID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteByMemberID")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int64) error); ok {
-		r0 = rf(memberID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, memberID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -143,9 +234,67 @@ func (_m *UseCase) DeleteByMemberID(memberID int64) error {
 	return r0
 }
 
-// GetByDiscountCodeWithPagination provides a mock function with given fields: discountCode, limit, offset
-func (_m *UseCase) GetByDiscountCodeWithPagination(discountCode string, limit int, offset int) ([]*wallet.DTO, error) {
-	ret := _m.Called(discountCode, limit, offset)
+// ExpireHolds provides a mock function with given fields: ctx
+func (_m *UseCase) ExpireHolds(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireHolds")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Freeze provides a mock function with given fields: ctx, id, reason, actor
+func (_m *UseCase) Freeze(ctx context.Context, id int64, reason string, actor string) (*wallet.DTO, error) {
+	ret := _m.Called(ctx, id, reason, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Freeze")
+	}
+
+	var r0 *wallet.DTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) (*wallet.DTO, error)); ok {
+		return rf(ctx, id, reason, actor)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) *wallet.DTO); ok {
+		r0 = rf(ctx, id, reason, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.DTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, id, reason, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByDiscountCodeWithPagination provides a mock function with given fields: ctx, discountCode, limit, offset
+func (_m *UseCase) GetByDiscountCodeWithPagination(ctx context.Context, discountCode string, limit int, offset int) ([]*wallet.DTO, error) {
+	ret := _m.Called(ctx, discountCode, limit, offset)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByDiscountCodeWithPagination")
@@ -153,19 +302,19 @@ func (_m *UseCase) GetByDiscountCodeWithPagination(discountCode string, limit in
 
 	var r0 []*wallet.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, int, int) ([]*wallet.DTO, error)); ok {
-		return rf(discountCode, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) ([]*wallet.DTO, error)); ok {
+		return rf(ctx, discountCode, limit, offset)
 	}
-	if rf, ok := ret.Get(0).(func(string, int, int) []*wallet.DTO); ok {
-		r0 = rf(discountCode, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []*wallet.DTO); ok {
+		r0 = rf(ctx, discountCode, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*wallet.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, int, int) error); ok {
-		r1 = rf(discountCode, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, discountCode, limit, offset)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -173,9 +322,9 @@ func (_m *UseCase) GetByDiscountCodeWithPagination(discountCode string, limit in
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: id
-func (_m *UseCase) GetByID(id int64) (*wallet.DTO, error) {
-	ret := _m.Called(id)
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *UseCase) GetByID(ctx context.Context, id int64) (*wallet.DTO, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByID")
@@ -183,19 +332,19 @@ func (_m *UseCase) GetByID(id int64) (*wallet.DTO, error) {
 
 	var r0 *wallet.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) (*wallet.DTO, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*wallet.DTO, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int64) *wallet.DTO); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *wallet.DTO); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*wallet.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -203,9 +352,9 @@ func (_m *UseCase) GetByID(id int64) (*wallet.DTO, error) {
 	return r0, r1
 }
 
-// GetByMemberID provides a mock function with given fields: memberID
-func (_m *UseCase) GetByMemberID(memberID int64) ([]*wallet.DTO, error) {
-	ret := _m.Called(memberID)
+// GetByMemberID provides a mock function with given fields: ctx, memberID
+func (_m *UseCase) GetByMemberID(ctx context.Context, memberID int64) ([]*wallet.DTO, error) {
+	ret := _m.Called(ctx, memberID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByMemberID")
@@ -213,19 +362,49 @@ func (_m *UseCase) GetByMemberID(memberID int64) ([]*wallet.DTO, error) {
 
 	var r0 []*wallet.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) ([]*wallet.DTO, error)); ok {
-		return rf(memberID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*wallet.DTO, error)); ok {
+		return rf(ctx, memberID)
 	}
-	if rf, ok := ret.Get(0).(func(int64) []*wallet.DTO); ok {
-		r0 = rf(memberID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*wallet.DTO); ok {
+		r0 = rf(ctx, memberID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*wallet.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(memberID)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, memberID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHold provides a mock function with given fields: ctx, holdID
+func (_m *UseCase) GetHold(ctx context.Context, holdID int64) (*wallet.HoldDTO, error) {
+	ret := _m.Called(ctx, holdID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHold")
+	}
+
+	var r0 *wallet.HoldDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*wallet.HoldDTO, error)); ok {
+		return rf(ctx, holdID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *wallet.HoldDTO); ok {
+		r0 = rf(ctx, holdID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.HoldDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, holdID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -233,9 +412,69 @@ func (_m *UseCase) GetByMemberID(memberID int64) ([]*wallet.DTO, error) {
 	return r0, r1
 }
 
-// Recharge provides a mock function with given fields: id, amount
-func (_m *UseCase) Recharge(id int64, amount int64) (*wallet.DTO, error) {
-	ret := _m.Called(id, amount)
+// ListTransactions provides a mock function with given fields: ctx, r
+func (_m *UseCase) ListTransactions(ctx context.Context, r *transaction.ListRequest) (*transaction.Page, error) {
+	ret := _m.Called(ctx, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTransactions")
+	}
+
+	var r0 *transaction.Page
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.ListRequest) (*transaction.Page, error)); ok {
+		return rf(ctx, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.ListRequest) *transaction.Page); ok {
+		r0 = rf(ctx, r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.Page)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *transaction.ListRequest) error); ok {
+		r1 = rf(ctx, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Pay provides a mock function with given fields: ctx, id, r, idempotencyKey
+func (_m *UseCase) Pay(ctx context.Context, id int64, r *wallet.PayRequest, idempotencyKey string) (*wallet.DTO, error) {
+	ret := _m.Called(ctx, id, r, idempotencyKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Pay")
+	}
+
+	var r0 *wallet.DTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *wallet.PayRequest, string) (*wallet.DTO, error)); ok {
+		return rf(ctx, id, r, idempotencyKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *wallet.PayRequest, string) *wallet.DTO); ok {
+		r0 = rf(ctx, id, r, idempotencyKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.DTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *wallet.PayRequest, string) error); ok {
+		r1 = rf(ctx, id, r, idempotencyKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Recharge provides a mock function with given fields: ctx, id, amount, idempotencyKey
+func (_m *UseCase) Recharge(ctx context.Context, id int64, amount int64, idempotencyKey string) (*wallet.DTO, error) {
+	ret := _m.Called(ctx, id, amount, idempotencyKey)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Recharge")
@@ -243,19 +482,19 @@ func (_m *UseCase) Recharge(id int64, amount int64) (*wallet.DTO, error) {
 
 	var r0 *wallet.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, int64) (*wallet.DTO, error)); ok {
-		return rf(id, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string) (*wallet.DTO, error)); ok {
+		return rf(ctx, id, amount, idempotencyKey)
 	}
-	if rf, ok := ret.Get(0).(func(int64, int64) *wallet.DTO); ok {
-		r0 = rf(id, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string) *wallet.DTO); ok {
+		r0 = rf(ctx, id, amount, idempotencyKey)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*wallet.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
-		r1 = rf(id, amount)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, string) error); ok {
+		r1 = rf(ctx, id, amount, idempotencyKey)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -263,9 +502,37 @@ func (_m *UseCase) Recharge(id int64, amount int64) (*wallet.DTO, error) {
 	return r0, r1
 }
 
-// Refund provides a mock function with given fields: id
-func (_m *UseCase) Refund(id int64) (*wallet.DTO, error) {
-	ret := _m.Called(id)
+// RecoverRedemptions provides a mock function with given fields: ctx, before
+func (_m *UseCase) RecoverRedemptions(ctx context.Context, before time.Time) (int, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecoverRedemptions")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Refund provides a mock function with given fields: ctx, id, amount, idempotencyKey
+func (_m *UseCase) Refund(ctx context.Context, id int64, amount int64, idempotencyKey string) (*wallet.DTO, error) {
+	ret := _m.Called(ctx, id, amount, idempotencyKey)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Refund")
@@ -273,19 +540,49 @@ func (_m *UseCase) Refund(id int64) (*wallet.DTO, error) {
 
 	var r0 *wallet.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) (*wallet.DTO, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string) (*wallet.DTO, error)); ok {
+		return rf(ctx, id, amount, idempotencyKey)
 	}
-	if rf, ok := ret.Get(0).(func(int64) *wallet.DTO); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string) *wallet.DTO); ok {
+		r0 = rf(ctx, id, amount, idempotencyKey)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*wallet.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, string) error); ok {
+		r1 = rf(ctx, id, amount, idempotencyKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Statement provides a mock function with given fields: ctx, r
+func (_m *UseCase) Statement(ctx context.Context, r *transaction.StatementRequest) (*transaction.Statement, error) {
+	ret := _m.Called(ctx, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Statement")
+	}
+
+	var r0 *transaction.Statement
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.StatementRequest) (*transaction.Statement, error)); ok {
+		return rf(ctx, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *transaction.StatementRequest) *transaction.Statement); ok {
+		r0 = rf(ctx, r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*transaction.Statement)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *transaction.StatementRequest) error); ok {
+		r1 = rf(ctx, r)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -293,9 +590,39 @@ func (_m *UseCase) Refund(id int64) (*wallet.DTO, error) {
 	return r0, r1
 }
 
-// Transfer provides a mock function with given fields: fromID, toID, amount
-func (_m *UseCase) Transfer(fromID int64, toID int64, amount int64) (*wallet.DTO, error) {
-	ret := _m.Called(fromID, toID, amount)
+// StatusHistory provides a mock function with given fields: ctx, id
+func (_m *UseCase) StatusHistory(ctx context.Context, id int64) ([]*wallet.StatusChangeDTO, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StatusHistory")
+	}
+
+	var r0 []*wallet.StatusChangeDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*wallet.StatusChangeDTO, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*wallet.StatusChangeDTO); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*wallet.StatusChangeDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Transfer provides a mock function with given fields: ctx, fromID, toID, amount, convert, idempotencyKey
+func (_m *UseCase) Transfer(ctx context.Context, fromID int64, toID int64, amount int64, convert bool, idempotencyKey string) (*wallet.DTO, error) {
+	ret := _m.Called(ctx, fromID, toID, amount, convert, idempotencyKey)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Transfer")
@@ -303,19 +630,49 @@ func (_m *UseCase) Transfer(fromID int64, toID int64, amount int64) (*wallet.DTO
 
 	var r0 *wallet.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, int64, int64) (*wallet.DTO, error)); ok {
-		return rf(fromID, toID, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64, bool, string) (*wallet.DTO, error)); ok {
+		return rf(ctx, fromID, toID, amount, convert, idempotencyKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64, bool, string) *wallet.DTO); ok {
+		r0 = rf(ctx, fromID, toID, amount, convert, idempotencyKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*wallet.DTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, int64, bool, string) error); ok {
+		r1 = rf(ctx, fromID, toID, amount, convert, idempotencyKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unfreeze provides a mock function with given fields: ctx, id, reason, actor
+func (_m *UseCase) Unfreeze(ctx context.Context, id int64, reason string, actor string) (*wallet.DTO, error) {
+	ret := _m.Called(ctx, id, reason, actor)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unfreeze")
+	}
+
+	var r0 *wallet.DTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) (*wallet.DTO, error)); ok {
+		return rf(ctx, id, reason, actor)
 	}
-	if rf, ok := ret.Get(0).(func(int64, int64, int64) *wallet.DTO); ok {
-		r0 = rf(fromID, toID, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) *wallet.DTO); ok {
+		r0 = rf(ctx, id, reason, actor)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*wallet.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, int64, int64) error); ok {
-		r1 = rf(fromID, toID, amount)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
+		r1 = rf(ctx, id, reason, actor)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -323,29 +680,29 @@ func (_m *UseCase) Transfer(fromID int64, toID int64, amount int64) (*wallet.DTO
 	return r0, r1
 }
 
-// WithTX provides a mock function with given fields: tx
-func (_m *UseCase) WithTX(tx *sql.Tx) (*wallet.Service, error) {
-	ret := _m.Called(tx)
+// Void provides a mock function with given fields: ctx, holdID
+func (_m *UseCase) Void(ctx context.Context, holdID int64) (*wallet.HoldDTO, error) {
+	ret := _m.Called(ctx, holdID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for WithTX")
+		panic("no return value specified for Void")
 	}
 
-	var r0 *wallet.Service
+	var r0 *wallet.HoldDTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx) (*wallet.Service, error)); ok {
-		return rf(tx)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*wallet.HoldDTO, error)); ok {
+		return rf(ctx, holdID)
 	}
-	if rf, ok := ret.Get(0).(func(*sql.Tx) *wallet.Service); ok {
-		r0 = rf(tx)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *wallet.HoldDTO); ok {
+		r0 = rf(ctx, holdID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*wallet.Service)
+			r0 = ret.Get(0).(*wallet.HoldDTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*sql.Tx) error); ok {
-		r1 = rf(tx)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, holdID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -353,9 +710,9 @@ func (_m *UseCase) WithTX(tx *sql.Tx) (*wallet.Service, error) {
 	return r0, r1
 }
 
-// Withdraw provides a mock function with given fields: id, amount
-func (_m *UseCase) Withdraw(id int64, amount int64) (*wallet.DTO, error) {
-	ret := _m.Called(id, amount)
+// Withdraw provides a mock function with given fields: ctx, id, amount, idempotencyKey
+func (_m *UseCase) Withdraw(ctx context.Context, id int64, amount int64, idempotencyKey string) (*wallet.DTO, error) {
+	ret := _m.Called(ctx, id, amount, idempotencyKey)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Withdraw")
@@ -363,19 +720,19 @@ func (_m *UseCase) Withdraw(id int64, amount int64) (*wallet.DTO, error) {
 
 	var r0 *wallet.DTO
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, int64) (*wallet.DTO, error)); ok {
-		return rf(id, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string) (*wallet.DTO, error)); ok {
+		return rf(ctx, id, amount, idempotencyKey)
 	}
-	if rf, ok := ret.Get(0).(func(int64, int64) *wallet.DTO); ok {
-		r0 = rf(id, amount)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string) *wallet.DTO); ok {
+		r0 = rf(ctx, id, amount, idempotencyKey)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*wallet.DTO)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
-		r1 = rf(id, amount)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, string) error); ok {
+		r1 = rf(ctx, id, amount, idempotencyKey)
 	} else {
 		r1 = ret.Error(1)
 	}
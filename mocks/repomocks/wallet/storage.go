@@ -3,11 +3,10 @@
 package repomocks
 
 import (
-	sql "database/sql"
+	context "context"
+	wallet "wallet/storage/wallet"
 
 	mock "github.com/stretchr/testify/mock"
-
-	wallet "wallet/storage/wallet"
 )
 
 // Repository is an autogenerated mock type for the Repository type
@@ -15,17 +14,73 @@ type Repository struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: w
-func (_m *Repository) Create(w *wallet.Wallet) error {
-	ret := _m.Called(w)
+// AdjustBalance provides a mock function with given fields: ctx, id, delta
+func (_m *Repository) AdjustBalance(ctx context.Context, id int64, delta int64) (int64, error) {
+	ret := _m.Called(ctx, id, delta)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdjustBalance")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (int64, error)); ok {
+		return rf(ctx, id, delta)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) int64); ok {
+		r0 = rf(ctx, id, delta)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, delta)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AdjustHeld provides a mock function with given fields: ctx, id, delta
+func (_m *Repository) AdjustHeld(ctx context.Context, id int64, delta int64) (int64, error) {
+	ret := _m.Called(ctx, id, delta)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AdjustHeld")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (int64, error)); ok {
+		return rf(ctx, id, delta)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) int64); ok {
+		r0 = rf(ctx, id, delta)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, id, delta)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, w
+func (_m *Repository) Create(ctx context.Context, w *wallet.Wallet) error {
+	ret := _m.Called(ctx, w)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*wallet.Wallet) error); ok {
-		r0 = rf(w)
+	if rf, ok := ret.Get(0).(func(context.Context, *wallet.Wallet) error); ok {
+		r0 = rf(ctx, w)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -33,17 +88,35 @@ func (_m *Repository) Create(w *wallet.Wallet) error {
 	return r0
 }
 
-// Delete provides a mock function with given fields: id
-func (_m *Repository) Delete(id int64) error {
-	ret := _m.Called(id)
+// CreateStatusChange provides a mock function with given fields: ctx, c
+func (_m *Repository) CreateStatusChange(ctx context.Context, c *wallet.StatusChange) error {
+	ret := _m.Called(ctx, c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateStatusChange")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *wallet.StatusChange) error); ok {
+		r0 = rf(ctx, c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *Repository) Delete(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Delete")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int64) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -51,17 +124,17 @@ func (_m *Repository) Delete(id int64) error {
 	return r0
 }
 
-// DeleteByMemberID provides a mock function with given fields: memberID
-func (_m *Repository) DeleteByMemberID(memberID int64) error {
-	ret := _m.Called(memberID)
+// DeleteByMemberID provides a mock function with given fields: ctx, memberID
+func (_m *Repository) DeleteByMemberID(ctx context.Context, memberID int64) error {
+	ret := _m.Called(ctx, memberID)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteByMemberID")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int64) error); ok {
-		r0 = rf(memberID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, memberID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -69,9 +142,39 @@ func (_m *Repository) DeleteByMemberID(memberID int64) error {
 	return r0
 }
 
-// GetByID provides a mock function with given fields: id
-func (_m *Repository) GetByID(id int64) (*wallet.Wallet, error) {
-	ret := _m.Called(id)
+// GetAllByPage provides a mock function with given fields: ctx, limit, offset
+func (_m *Repository) GetAllByPage(ctx context.Context, limit int, offset int) ([]*wallet.Wallet, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllByPage")
+	}
+
+	var r0 []*wallet.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*wallet.Wallet, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*wallet.Wallet); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*wallet.Wallet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Repository) GetByID(ctx context.Context, id int64) (*wallet.Wallet, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByID")
@@ -79,19 +182,19 @@ func (_m *Repository) GetByID(id int64) (*wallet.Wallet, error) {
 
 	var r0 *wallet.Wallet
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) (*wallet.Wallet, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*wallet.Wallet, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int64) *wallet.Wallet); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *wallet.Wallet); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*wallet.Wallet)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -99,29 +202,29 @@ func (_m *Repository) GetByID(id int64) (*wallet.Wallet, error) {
 	return r0, r1
 }
 
-// GetByMemberID provides a mock function with given fields: memberID
-func (_m *Repository) GetByMemberID(memberID int64) ([]*wallet.Wallet, error) {
-	ret := _m.Called(memberID)
+// GetByIDForUpdate provides a mock function with given fields: ctx, id
+func (_m *Repository) GetByIDForUpdate(ctx context.Context, id int64) (*wallet.Wallet, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetByMemberID")
+		panic("no return value specified for GetByIDForUpdate")
 	}
 
-	var r0 []*wallet.Wallet
+	var r0 *wallet.Wallet
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) ([]*wallet.Wallet, error)); ok {
-		return rf(memberID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*wallet.Wallet, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(int64) []*wallet.Wallet); ok {
-		r0 = rf(memberID)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *wallet.Wallet); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*wallet.Wallet)
+			r0 = ret.Get(0).(*wallet.Wallet)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(memberID)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -129,47 +232,59 @@ func (_m *Repository) GetByMemberID(memberID int64) ([]*wallet.Wallet, error) {
 	return r0, r1
 }
 
-// UpdateBalance provides a mock function with given fields: id, balance
-func (_m *Repository) UpdateBalance(id int64, balance int64) error {
-	ret := _m.Called(id, balance)
+// GetByMemberID provides a mock function with given fields: ctx, memberID
+func (_m *Repository) GetByMemberID(ctx context.Context, memberID int64) ([]*wallet.Wallet, error) {
+	ret := _m.Called(ctx, memberID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateBalance")
+		panic("no return value specified for GetByMemberID")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(int64, int64) error); ok {
-		r0 = rf(id, balance)
+	var r0 []*wallet.Wallet
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*wallet.Wallet, error)); ok {
+		return rf(ctx, memberID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*wallet.Wallet); ok {
+		r0 = rf(ctx, memberID)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*wallet.Wallet)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, memberID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// WithTX provides a mock function with given fields: tx
-func (_m *Repository) WithTX(tx *sql.Tx) (wallet.Repository, error) {
-	ret := _m.Called(tx)
+// GetStatusChanges provides a mock function with given fields: ctx, walletID
+func (_m *Repository) GetStatusChanges(ctx context.Context, walletID int64) ([]*wallet.StatusChange, error) {
+	ret := _m.Called(ctx, walletID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for WithTX")
+		panic("no return value specified for GetStatusChanges")
 	}
 
-	var r0 wallet.Repository
+	var r0 []*wallet.StatusChange
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*sql.Tx) (wallet.Repository, error)); ok {
-		return rf(tx)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]*wallet.StatusChange, error)); ok {
+		return rf(ctx, walletID)
 	}
-	if rf, ok := ret.Get(0).(func(*sql.Tx) wallet.Repository); ok {
-		r0 = rf(tx)
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []*wallet.StatusChange); ok {
+		r0 = rf(ctx, walletID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(wallet.Repository)
+			r0 = ret.Get(0).([]*wallet.StatusChange)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*sql.Tx) error); ok {
-		r1 = rf(tx)
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, walletID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -177,6 +292,24 @@ func (_m *Repository) WithTX(tx *sql.Tx) (wallet.Repository, error) {
 	return r0, r1
 }
 
+// SetStatus provides a mock function with given fields: ctx, id, status
+func (_m *Repository) SetStatus(ctx context.Context, id int64, status wallet.Status) error {
+	ret := _m.Called(ctx, id, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, wallet.Status) error); ok {
+		r0 = rf(ctx, id, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRepository(t interface {
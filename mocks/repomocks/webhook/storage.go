@@ -0,0 +1,301 @@
+// Code generated by mockery v2.40.1. DO NOT EDIT.
+
+package repomocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+
+	webhook "wallet/storage/webhook"
+)
+
+// Repository is an autogenerated mock type for the Repository type
+type Repository struct {
+	mock.Mock
+}
+
+// ClaimDue provides a mock function with given fields: ctx, limit, lease
+func (_m *Repository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*webhook.Delivery, error) {
+	ret := _m.Called(ctx, limit, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimDue")
+	}
+
+	var r0 []*webhook.Delivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) ([]*webhook.Delivery, error)); ok {
+		return rf(ctx, limit, lease)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) []*webhook.Delivery); ok {
+		r0 = rf(ctx, limit, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*webhook.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Duration) error); ok {
+		r1 = rf(ctx, limit, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateDelivery provides a mock function with given fields: ctx, d
+func (_m *Repository) CreateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	ret := _m.Called(ctx, d)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *webhook.Delivery) error); ok {
+		r0 = rf(ctx, d)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateSubscription provides a mock function with given fields: ctx, sub
+func (_m *Repository) CreateSubscription(ctx context.Context, sub *webhook.Subscription) error {
+	ret := _m.Called(ctx, sub)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSubscription")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *webhook.Subscription) error); ok {
+		r0 = rf(ctx, sub)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSubscription provides a mock function with given fields: ctx, id
+func (_m *Repository) DeleteSubscription(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSubscription")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDeliveries provides a mock function with given fields: ctx, subscriptionID, limit, offset
+func (_m *Repository) GetDeliveries(ctx context.Context, subscriptionID int64, limit int, offset int) ([]*webhook.Delivery, error) {
+	ret := _m.Called(ctx, subscriptionID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeliveries")
+	}
+
+	var r0 []*webhook.Delivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) ([]*webhook.Delivery, error)); ok {
+		return rf(ctx, subscriptionID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int, int) []*webhook.Delivery); ok {
+		r0 = rf(ctx, subscriptionID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*webhook.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int, int) error); ok {
+		r1 = rf(ctx, subscriptionID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDelivery provides a mock function with given fields: ctx, id
+func (_m *Repository) GetDelivery(ctx context.Context, id int64) (*webhook.Delivery, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDelivery")
+	}
+
+	var r0 *webhook.Delivery
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*webhook.Delivery, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *webhook.Delivery); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*webhook.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubscription provides a mock function with given fields: ctx, id
+func (_m *Repository) GetSubscription(ctx context.Context, id int64) (*webhook.Subscription, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubscription")
+	}
+
+	var r0 *webhook.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (*webhook.Subscription, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) *webhook.Subscription); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*webhook.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubscriptions provides a mock function with given fields: ctx, limit, offset
+func (_m *Repository) GetSubscriptions(ctx context.Context, limit int, offset int) ([]*webhook.Subscription, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubscriptions")
+	}
+
+	var r0 []*webhook.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*webhook.Subscription, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*webhook.Subscription); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*webhook.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubscriptionsByEventType provides a mock function with given fields: ctx, eventType
+func (_m *Repository) GetSubscriptionsByEventType(ctx context.Context, eventType string) ([]*webhook.Subscription, error) {
+	ret := _m.Called(ctx, eventType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubscriptionsByEventType")
+	}
+
+	var r0 []*webhook.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*webhook.Subscription, error)); ok {
+		return rf(ctx, eventType)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*webhook.Subscription); ok {
+		r0 = rf(ctx, eventType)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*webhook.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, eventType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateDelivery provides a mock function with given fields: ctx, d
+func (_m *Repository) UpdateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	ret := _m.Called(ctx, d)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDelivery")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *webhook.Delivery) error); ok {
+		r0 = rf(ctx, d)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateSubscription provides a mock function with given fields: ctx, sub
+func (_m *Repository) UpdateSubscription(ctx context.Context, sub *webhook.Subscription) error {
+	ret := _m.Called(ctx, sub)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSubscription")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *webhook.Subscription) error); ok {
+		r0 = rf(ctx, sub)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Repository {
+	mock := &Repository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -4,8 +4,15 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"golang.org/x/text/language"
+	"strings"
+	"wallet/internal/auth"
+	"wallet/internal/locale"
+	"wallet/internal/serr"
 )
 
+const apiKeyHeader = "X-API-Key"
+
 func WithTraceID() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		_, exist := ctx.Get("trace_id")
@@ -20,3 +27,79 @@ func WithTraceID() gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// WithAPIKey authenticates the services calling with an api key in the X-API-Key header. Requests
+// without one are left to Authenticate.
+func WithAPIKey(keys auth.KeyAuthenticator) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		key := ctx.GetHeader(apiKeyHeader)
+		if key == "" {
+			ctx.Next()
+			return
+		}
+		id, err := keys.Authenticate(ctx.Request.Context(), key)
+		if err != nil {
+			log.Debug().Err(err).Str("trace_id", ctx.GetString("trace_id")).Msg("rejected api key")
+			abort(ctx, serr.UnauthorizedErr("server.WithAPIKey", "invalid api key"))
+			return
+		}
+		auth.WithIdentity(ctx, id)
+		ctx.Next()
+	}
+}
+
+// Authenticate lets through requests with a valid bearer token, or already authenticated by their
+// api key, and keeps their caller in the context.
+func Authenticate(v *auth.Verifier) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if auth.FromContext(ctx) != nil {
+			ctx.Next()
+			return
+		}
+		token, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			abort(ctx, serr.UnauthorizedErr("server.Authenticate", "authentication required"))
+			return
+		}
+		id, err := v.Verify(token)
+		if err != nil {
+			log.Debug().Err(err).Str("trace_id", ctx.GetString("trace_id")).Msg("rejected token")
+			abort(ctx, serr.UnauthorizedErr("server.Authenticate", "invalid token"))
+			return
+		}
+		auth.WithIdentity(ctx, id)
+		ctx.Next()
+	}
+}
+
+// RequireAdmin lets through the requests of admins, it runs after Authenticate.
+func RequireAdmin() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if id := auth.FromContext(ctx); id == nil || !id.IsAdmin() {
+			abort(ctx, serr.PermissionErr("server.RequireAdmin", "permission denied"))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// RequireScope lets through services whose api key has scope, users are not limited by scopes. It
+// runs after Authenticate.
+func RequireScope(scope auth.Scope) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if id := auth.FromContext(ctx); id == nil || !id.HasScope(scope) {
+			abort(ctx, serr.PermissionErr("server.RequireScope", "insufficient scope"))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+func abort(ctx *gin.Context, err error) {
+	e := err.(*serr.ServiceError)
+	ctx.AbortWithStatusJSON(e.Code, gin.H{
+		"message":  locale.Localize(e.Message, language.Persian),
+		"code":     e.ErrorCode,
+		"trace_id": ctx.GetString("trace_id"),
+	})
+}
@@ -0,0 +1,164 @@
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"wallet/internal/auth"
+	"wallet/server"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	v, err := auth.NewVerifier("HS256", "secret", "", "")
+	require.NoError(t, err)
+	token := func(subject string, role auth.Role) string {
+		s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{Role: role, RegisteredClaims: jwt.RegisteredClaims{
+			Subject: subject, ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		}}).SignedString([]byte("secret"))
+		require.NoError(t, err)
+		return "Bearer " + s
+	}
+
+	e := gin.New()
+	e.GET("/me", server.Authenticate(v), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, auth.FromContext(ctx))
+	})
+	e.GET("/admin", server.Authenticate(v), server.RequireAdmin(), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	for _, tc := range []struct {
+		path, authorization string
+		status              int
+	}{
+		{"/me", "", http.StatusUnauthorized},
+		{"/me", "Bearer invalid", http.StatusUnauthorized},
+		{"/me", token("5", auth.Member), http.StatusOK},
+		{"/admin", token("5", auth.Member), http.StatusForbidden},
+		{"/admin", token("", auth.Admin), http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+		if tc.authorization != "" {
+			req.Header.Set("Authorization", tc.authorization)
+		}
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, req)
+		assert.Equal(t, tc.status, w.Code, tc.path+" "+tc.authorization)
+	}
+}
+
+type keys map[string]*auth.Identity
+
+func (k keys) Authenticate(_ context.Context, key string) (*auth.Identity, error) {
+	if id, ok := k[key]; ok {
+		return id, nil
+	}
+	return nil, errors.New("invalid api key")
+}
+
+func TestWithAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	v, err := auth.NewVerifier("HS256", "secret", "", "")
+	require.NoError(t, err)
+
+	e := gin.New()
+	e.Use(server.WithAPIKey(keys{
+		"wk_read": {Role: auth.Service, APIKeyID: 1, Scopes: []auth.Scope{auth.WalletRead}},
+	}))
+	ok := func(ctx *gin.Context) { ctx.Status(http.StatusOK) }
+	g := e.Group("/wallet", server.Authenticate(v))
+	g.GET("", server.RequireScope(auth.WalletRead), ok)
+	g.POST("", server.RequireScope(auth.WalletDebit), ok)
+	e.GET("/admin", server.Authenticate(v), server.RequireAdmin(), ok)
+
+	for _, tc := range []struct {
+		method, path, key string
+		status            int
+	}{
+		{http.MethodGet, "/wallet", "wk_read", http.StatusOK},
+		{http.MethodPost, "/wallet", "wk_read", http.StatusForbidden},
+		{http.MethodGet, "/wallet", "wk_unknown", http.StatusUnauthorized},
+		{http.MethodGet, "/wallet", "", http.StatusUnauthorized},
+		{http.MethodGet, "/admin", "wk_read", http.StatusForbidden},
+	} {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		if tc.key != "" {
+			req.Header.Set("X-API-Key", tc.key)
+		}
+		w := httptest.NewRecorder()
+		e.ServeHTTP(w, req)
+		assert.Equal(t, tc.status, w.Code, tc.method+" "+tc.path+" "+tc.key)
+	}
+}
+
+func TestNewServer_ClientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	v, err := auth.NewVerifier("HS256", "secret", "", "")
+	require.NoError(t, err)
+	clientIP := func() string {
+		s := server.NewServer(v)
+		s.Engine.GET("/ip", func(ctx *gin.Context) {
+			ctx.String(http.StatusOK, ctx.ClientIP())
+		})
+		req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+		req.RemoteAddr = "203.0.113.7:4000"
+		req.Header.Set("X-Forwarded-For", "10.0.0.1")
+		w := httptest.NewRecorder()
+		s.Engine.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	// without trusted proxies a forwarded address is not believed
+	assert.Equal(t, "203.0.113.7", clientIP())
+
+	viper.Set("server.trustedProxies", []string{"203.0.113.0/24"})
+	t.Cleanup(func() { viper.Set("server.trustedProxies", nil) })
+	assert.Equal(t, "10.0.0.1", clientIP())
+}
+
+func TestDebugVars(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	v, err := auth.NewVerifier("HS256", "secret", "", "")
+	require.NoError(t, err)
+	expvar.NewMap("discount").Add("getGift.ok", 1)
+	s := server.NewServer(v)
+	s.SetupRoutes()
+	get := func(role auth.Role) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+		if role != "" {
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{Role: role, RegisteredClaims: jwt.RegisteredClaims{
+				Subject: "1", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			}}).SignedString([]byte("secret"))
+			require.NoError(t, err)
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		s.Engine.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, get("").Code)
+	assert.Equal(t, http.StatusForbidden, get(auth.Member).Code)
+
+	w := get(auth.Admin)
+	require.Equal(t, http.StatusOK, w.Code)
+	var vars map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &vars))
+	// the command line and memory stats expvar publishes are left out
+	assert.Len(t, vars, 1)
+	assert.NotContains(t, vars, "cmdline")
+	assert.NotContains(t, vars, "memstats")
+	assert.JSONEq(t, `{"getGift.ok":1}`, string(vars["discount"]))
+}
@@ -2,7 +2,9 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"expvar"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
@@ -11,19 +13,36 @@ import (
 	"go.uber.org/fx"
 	"net/http"
 	"wallet/docs"
+	"wallet/internal/auth"
 	"wallet/internal/config"
 )
 
+// @securityDefinitions.apikey	BearerAuth
+// @in							header
+// @name						Authorization
+// @description				A JWT as "Bearer <token>", its subject is the member id and its role member or admin.
+
+// @securityDefinitions.apikey	ApiKeyAuth
+// @in							header
+// @name						X-API-Key
+// @description				An api key of a service, it may call the routes of its scopes.
+
 type Server struct {
 	Engine     *gin.Engine
 	healthFunc func(ctx *gin.Context)
+	auth       gin.HandlerFunc
 }
 
-func NewServer() *Server {
+func NewServer(verifier *auth.Verifier) *Server {
 	if !config.ServerDebug() {
 		gin.SetMode(gin.ReleaseMode)
 	}
-	s := &Server{Engine: gin.Default(), healthFunc: Health}
+	s := &Server{Engine: gin.Default(), healthFunc: Health, auth: Authenticate(verifier)}
+	// the client ip is recorded in the audit log, X-Forwarded-For is only believed from known proxies
+	if err := s.Engine.SetTrustedProxies(config.TrustedProxies()); err != nil {
+		log.Error().Err(err).Msg("invalid trusted proxies, no proxy is trusted")
+		_ = s.Engine.SetTrustedProxies(nil)
+	}
 	s.Engine.Use(WithTraceID())
 	s.Engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 	s.setDoc()
@@ -37,6 +56,11 @@ func (s *Server) WithMiddlewares(middlewares ...gin.HandlerFunc) *Server {
 	return s
 }
 
+// Authenticated returns a route group whose requests need a valid token.
+func (s *Server) Authenticated(relativePath string, handlers ...gin.HandlerFunc) *gin.RouterGroup {
+	return s.Engine.Group(relativePath, append([]gin.HandlerFunc{s.auth}, handlers...)...)
+}
+
 func (s *Server) SetHealthFunc(f func() error) *Server {
 	s.healthFunc = func(ctx *gin.Context) {
 		if err := f(); err != nil {
@@ -50,6 +74,25 @@ func (s *Server) SetHealthFunc(f func() error) *Server {
 
 func (s *Server) SetupRoutes() {
 	s.Engine.GET("/health", s.healthFunc)
+	s.Authenticated("/debug", RequireAdmin()).GET("/vars", Vars(publishedVars...))
+}
+
+// publishedVars are the expvar variables served at /debug/vars. The ones expvar publishes on its own,
+// the command line and memory stats, are left out.
+var publishedVars = []string{"discount"}
+
+// Vars serves the expvar variables of the given names as a JSON object, names not published are left
+// out.
+func Vars(names ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		vars := make(map[string]json.RawMessage, len(names))
+		for _, name := range names {
+			if v := expvar.Get(name); v != nil {
+				vars[name] = json.RawMessage(v.String())
+			}
+		}
+		ctx.JSON(http.StatusOK, vars)
+	}
 }
 
 func (s *Server) Run(port string) {
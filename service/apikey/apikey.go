@@ -0,0 +1,166 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+	"wallet/db"
+	"wallet/internal/auth"
+	"wallet/internal/serr"
+	"wallet/storage/apikey"
+)
+
+const (
+	keyPrefix = "wk_"
+	// the part of a key kept in the clear, enough to tell keys apart
+	displayLength = len(keyPrefix) + 8
+)
+
+var errInvalidKey = errors.New("invalid api key")
+
+// Create issues a key with the given scopes, the key is returned only once.
+func (s *Service) Create(ctx context.Context, r *CreateRequest) (*DTO, error) {
+	name := strings.TrimSpace(r.Name)
+	if name == "" {
+		return nil, serr.ValidationErr("apikey", "api key name is required", serr.ErrInvalidRequest)
+	}
+	scopes, err := validateScopes(r.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, name, scopes)
+}
+
+func (s *Service) issue(ctx context.Context, name string, scopes []string) (*DTO, error) {
+	key, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+	k := &apikey.APIKey{Name: name, Prefix: key[:displayLength], KeyHash: hash(key), Scopes: scopes}
+	if err = s.apikey.Create(ctx, k); err != nil {
+		return nil, err
+	}
+	dto := s.FromDBModel(k)
+	dto.Key = key
+	return dto, nil
+}
+
+func (s *Service) GetByID(ctx context.Context, id int64) (*DTO, error) {
+	k, err := s.apikey.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.FromDBModel(k), nil
+}
+
+func (s *Service) List(ctx context.Context, limit, offset int) ([]*DTO, error) {
+	keys, err := s.apikey.GetAll(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*DTO, 0, len(keys))
+	for _, k := range keys {
+		dtos = append(dtos, s.FromDBModel(k))
+	}
+	return dtos, nil
+}
+
+// Revoke stops a key from working at once.
+func (s *Service) Revoke(ctx context.Context, id int64) (*DTO, error) {
+	if err := s.apikey.Revoke(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id)
+}
+
+// Rotate issues a new key with the name and scopes of a key, the old key keeps working for the
+// rotation grace period so its callers can move to the new one.
+func (s *Service) Rotate(ctx context.Context, id int64) (*DTO, error) {
+	var rotated *DTO
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		k, err := s.apikey.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !active(k, time.Now()) {
+			return serr.ConflictErr("apikey", "api key is revoked", serr.ErrAPIKeyRevoked)
+		}
+		if rotated, err = s.issue(ctx, k.Name, k.Scopes); err != nil {
+			return err
+		}
+		// rotating a key twice does not make the first key live longer
+		expiresAt := time.Now().Add(s.rotationGrace)
+		if k.ExpiresAt != nil && k.ExpiresAt.Before(expiresAt) {
+			expiresAt = *k.ExpiresAt
+		}
+		return s.apikey.Expire(ctx, k.ID, expiresAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rotated, nil
+}
+
+// Authenticate returns the service identity of an active key.
+func (s *Service) Authenticate(ctx context.Context, key string) (*auth.Identity, error) {
+	if !strings.HasPrefix(key, keyPrefix) {
+		return nil, errInvalidKey
+	}
+	k, err := s.apikey.GetByHash(ctx, hash(key))
+	if err != nil {
+		var e *serr.ServiceError
+		if errors.As(err, &e) && errors.Is(e.Cause, sql.ErrNoRows) {
+			return nil, errInvalidKey
+		}
+		return nil, err
+	}
+	if !active(k, time.Now()) {
+		return nil, errInvalidKey
+	}
+	id := &auth.Identity{Role: auth.Service, APIKeyID: k.ID}
+	for _, sc := range k.Scopes {
+		id.Scopes = append(id.Scopes, auth.Scope(sc))
+	}
+	return id, nil
+}
+
+func active(k *apikey.APIKey, now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || k.ExpiresAt.After(now))
+}
+
+func validateScopes(scopes []auth.Scope) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, serr.ValidationErr("apikey", "at least one scope is required", serr.ErrInvalidScope)
+	}
+	result := make([]string, 0, len(scopes))
+	seen := make(map[auth.Scope]bool, len(scopes))
+	for _, sc := range scopes {
+		if !sc.Valid() {
+			return nil, serr.ValidationErr("apikey", "invalid scope", serr.ErrInvalidScope)
+		}
+		if !seen[sc] {
+			seen[sc] = true
+			result = append(result, string(sc))
+		}
+	}
+	return result, nil
+}
+
+// newKey returns a random key, keys are long enough for a plain sha256 to keep them safe at rest.
+func newKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return keyPrefix + hex.EncodeToString(b), nil
+}
+
+func hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
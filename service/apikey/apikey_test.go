@@ -0,0 +1,108 @@
+package apikey_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"wallet/internal/auth"
+	"wallet/internal/serr"
+	repomocks "wallet/mocks/repomocks/apikey"
+	"wallet/service/apikey"
+	apikeyStorage "wallet/storage/apikey"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestCreate(t *testing.T) {
+	var stored *apikeyStorage.APIKey
+	mockRepo := repomocks.NewRepository(t)
+	mockRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*apikeyStorage.APIKey)
+		stored.ID = 1
+	}).Return(nil)
+
+	dto, err := apikey.New(mockRepo, time.Hour).Create(context.Background(), &apikey.CreateRequest{
+		Name:   "settlement",
+		Scopes: []auth.Scope{auth.WalletRead, auth.WalletCredit, auth.WalletRead},
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(dto.Key, "wk_"))
+	assert.Equal(t, []auth.Scope{auth.WalletRead, auth.WalletCredit}, dto.Scopes)
+	// only the hash and the prefix of the key are kept
+	assert.Equal(t, hash(dto.Key), stored.KeyHash)
+	assert.Equal(t, dto.Key[:len(stored.Prefix)], stored.Prefix)
+	assert.NotContains(t, stored.Prefix+stored.KeyHash, dto.Key)
+}
+
+func TestCreate_Invalid(t *testing.T) {
+	s := apikey.New(repomocks.NewRepository(t), time.Hour)
+	for _, tc := range []struct {
+		name string
+		req  apikey.CreateRequest
+		code serr.ErrorCode
+	}{
+		{"no name", apikey.CreateRequest{Name: " ", Scopes: []auth.Scope{auth.WalletRead}}, serr.ErrInvalidRequest},
+		{"no scopes", apikey.CreateRequest{Name: "jobs"}, serr.ErrInvalidScope},
+		{"unknown scope", apikey.CreateRequest{Name: "jobs", Scopes: []auth.Scope{"wallet:*"}}, serr.ErrInvalidScope},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := s.Create(context.Background(), &tc.req)
+			var e *serr.ServiceError
+			require.True(t, errors.As(err, &e))
+			assert.Equal(t, tc.code, e.ErrorCode)
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	key := "wk_" + strings.Repeat("ab", 32)
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Minute)
+
+	t.Run("active", func(t *testing.T) {
+		mockRepo := repomocks.NewRepository(t)
+		mockRepo.On("GetByHash", mock.Anything, hash(key)).Return(&apikeyStorage.APIKey{ID: 4, Scopes: []string{"wallet:read"},
+			ExpiresAt: &future}, nil)
+
+		id, err := apikey.New(mockRepo, time.Hour).Authenticate(context.Background(), key)
+		require.NoError(t, err)
+		assert.Equal(t, &auth.Identity{Role: auth.Service, APIKeyID: 4, Scopes: []auth.Scope{auth.WalletRead}}, id)
+		assert.True(t, id.HasScope(auth.WalletRead))
+		assert.False(t, id.HasScope(auth.WalletDebit))
+	})
+
+	for name, k := range map[string]*apikeyStorage.APIKey{
+		"revoked": {ID: 4, RevokedAt: &past},
+		"expired": {ID: 4, ExpiresAt: &past},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := repomocks.NewRepository(t)
+			mockRepo.On("GetByHash", mock.Anything, hash(key)).Return(k, nil)
+			_, err := apikey.New(mockRepo, time.Hour).Authenticate(context.Background(), key)
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		mockRepo := repomocks.NewRepository(t)
+		mockRepo.On("GetByHash", mock.Anything, hash(key)).Return(nil, serr.DBError("GetByHash", "api key", sql.ErrNoRows))
+		_, err := apikey.New(mockRepo, time.Hour).Authenticate(context.Background(), key)
+		assert.Error(t, err)
+	})
+
+	t.Run("not a key", func(t *testing.T) {
+		_, err := apikey.New(repomocks.NewRepository(t), time.Hour).Authenticate(context.Background(), "Bearer token")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,24 @@
+package apikey
+
+import (
+	"time"
+	"wallet/internal/auth"
+)
+
+// DTO is an api key, the key itself is shown only when it is created or rotated.
+type DTO struct {
+	ID        int64        `json:"id"`
+	Name      string       `json:"name"`
+	Prefix    string       `json:"prefix"`
+	Key       string       `json:"key,omitempty"`
+	Scopes    []auth.Scope `json:"scopes"`
+	ExpiresAt *time.Time   `json:"expiresAt,omitempty"`
+	RevokedAt *time.Time   `json:"revokedAt,omitempty"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
+
+type CreateRequest struct {
+	Name   string       `json:"name"`
+	Scopes []auth.Scope `json:"scopes"`
+}
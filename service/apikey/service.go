@@ -0,0 +1,44 @@
+package apikey
+
+import (
+	"context"
+	"time"
+	"wallet/internal/auth"
+	"wallet/storage/apikey"
+)
+
+type UseCase interface {
+	Create(ctx context.Context, r *CreateRequest) (*DTO, error)
+	GetByID(ctx context.Context, id int64) (*DTO, error)
+	List(ctx context.Context, limit, offset int) ([]*DTO, error)
+	Revoke(ctx context.Context, id int64) (*DTO, error)
+	Rotate(ctx context.Context, id int64) (*DTO, error)
+	Authenticate(ctx context.Context, key string) (*auth.Identity, error)
+}
+
+type Service struct {
+	apikey apikey.Repository
+	// how long a rotated key keeps working next to the key replacing it
+	rotationGrace time.Duration
+}
+
+func New(apikey apikey.Repository, rotationGrace time.Duration) *Service {
+	return &Service{apikey: apikey, rotationGrace: rotationGrace}
+}
+
+func (s *Service) FromDBModel(k *apikey.APIKey) *DTO {
+	dto := &DTO{
+		ID:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Scopes:    make([]auth.Scope, 0, len(k.Scopes)),
+		ExpiresAt: k.ExpiresAt,
+		RevokedAt: k.RevokedAt,
+		CreatedAt: k.CreatedAt,
+		UpdatedAt: k.UpdatedAt,
+	}
+	for _, sc := range k.Scopes {
+		dto.Scopes = append(dto.Scopes, auth.Scope(sc))
+	}
+	return dto
+}
@@ -0,0 +1,225 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+	"wallet/db"
+	"wallet/internal/serr"
+	"wallet/storage/audit"
+)
+
+type Action string
+
+const (
+	MemberCreated  Action = "member.create"
+	MemberUpdated  Action = "member.update"
+	MemberTierSet  Action = "member.set_tier"
+	MemberDeleted  Action = "member.delete"
+	WalletCreated  Action = "wallet.create"
+	WalletTransfer Action = "wallet.transfer"
+	WalletFrozen   Action = "wallet.freeze"
+	WalletUnfrozen Action = "wallet.unfreeze"
+	WalletClosed   Action = "wallet.close"
+	WalletDeleted  Action = "wallet.delete"
+	HoldAuthorized Action = "hold.authorize"
+	HoldCaptured   Action = "hold.capture"
+	HoldVoided     Action = "hold.void"
+	HoldExpired    Action = "hold.expire"
+)
+
+// TransactionAction is the action of a transaction applied to a wallet, such as wallet.recharge or
+// wallet.payment.
+func TransactionAction(transactionType string) Action {
+	return Action("wallet." + transactionType)
+}
+
+type Entity string
+
+const (
+	Member Entity = "member"
+	Wallet Entity = "wallet"
+	Hold   Entity = "hold"
+)
+
+const (
+	// SystemActor acts for the jobs of the app and for calls made without a caller
+	SystemActor = "system"
+
+	// genesisHash is the previous hash of the first entry
+	genesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+	defaultPageSize = 50
+	maxPageSize     = 100
+	verifyBatchSize = 500
+	sealBatchSize   = 500
+)
+
+// Meta tells who made a call and where it came from, it is recorded with every action of the call.
+type Meta struct {
+	Actor    string
+	TraceID  string
+	ClientIP string
+}
+
+type metaKey struct{}
+
+// WithMeta returns a copy of ctx carrying the caller recorded with the actions taken in it.
+func WithMeta(ctx context.Context, m Meta) context.Context {
+	return context.WithValue(ctx, metaKey{}, m)
+}
+
+// MetaFrom returns the caller carried by ctx, contexts without one are the app's own.
+func MetaFrom(ctx context.Context) Meta {
+	if m, ok := ctx.Value(metaKey{}).(Meta); ok && m.Actor != "" {
+		return m
+	}
+	return Meta{Actor: SystemActor}
+}
+
+// Record appends an action of the caller carried by ctx to the audit log. before and after are the
+// state of the entity around the action and are stored as JSON, nil leaves them out. ctx has to
+// carry the db transaction of the action. The entry is chained by Seal once it is committed, so
+// writers do not wait on each other.
+func Record(ctx context.Context, r audit.Repository, action Action, entity Entity, entityID int64, before, after any) error {
+	m := MetaFrom(ctx)
+	e := &audit.Entry{
+		Actor:    m.Actor,
+		Action:   string(action),
+		Entity:   string(entity),
+		EntityID: entityID,
+		TraceID:  m.TraceID,
+		ClientIP: m.ClientIP,
+		// stored with microseconds, the precision of the column, so the hash can be checked again
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+	var err error
+	if e.Before, err = document(before); err != nil {
+		return err
+	}
+	if e.After, err = document(after); err != nil {
+		return err
+	}
+	return r.Insert(ctx, e)
+}
+
+// Seal chains the committed entries not sealed yet, in the order of their ids, and returns how many
+// it sealed. Entries committed after a later entry was sealed follow it in the chain.
+func (s *Service) Seal(ctx context.Context) (int, error) {
+	sealed := 0
+	for {
+		n := 0
+		err := db.Transaction(ctx, func(ctx context.Context) error {
+			n = 0
+			if err := s.audit.Lock(ctx); err != nil {
+				return err
+			}
+			seq, prevHash, err := s.audit.LastSealed(ctx)
+			if err != nil {
+				return err
+			}
+			if prevHash == "" {
+				prevHash = genesisHash
+			}
+			es, err := s.audit.GetUnsealed(ctx, sealBatchSize)
+			if err != nil {
+				return err
+			}
+			for _, e := range es {
+				seq++
+				e.Seq, e.PrevHash = seq, prevHash
+				e.Hash = Hash(e)
+				if err = s.audit.Seal(ctx, e); err != nil {
+					return err
+				}
+				prevHash = e.Hash
+			}
+			n = len(es)
+			return nil
+		})
+		if err != nil {
+			return sealed, err
+		}
+		sealed += n
+		if n < sealBatchSize {
+			return sealed, nil
+		}
+	}
+}
+
+// Hash returns the hash of an entry, chained to the entry before it by PrevHash.
+func Hash(e *audit.Entry) string {
+	payload, _ := json.Marshal([]any{
+		e.PrevHash, e.CreatedAt.UTC().Format(time.RFC3339Nano), e.Actor, e.Action, e.Entity, e.EntityID,
+		string(e.Before), string(e.After), e.TraceID, e.ClientIP,
+	})
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+func document(v any) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// List returns the entries matching the request, newest first.
+func (s *Service) List(ctx context.Context, r *ListRequest) ([]*DTO, error) {
+	if r.Limit == 0 {
+		r.Limit = defaultPageSize
+	}
+	if r.Limit < 0 || r.Limit > maxPageSize || r.Offset < 0 {
+		return nil, serr.ValidationErr("audit", "invalid page size", serr.ErrInvalidFilter)
+	}
+	if !r.CreatedFrom.IsZero() && !r.CreatedTo.IsZero() && !r.CreatedFrom.Before(r.CreatedTo) {
+		return nil, serr.ValidationErr("audit", "invalid date range", serr.ErrInvalidFilter)
+	}
+	es, err := s.audit.Find(ctx, &audit.Filter{
+		Actor:       strings.TrimSpace(r.Actor),
+		Action:      string(r.Action),
+		Entity:      string(r.Entity),
+		EntityID:    r.EntityID,
+		TraceID:     r.TraceID,
+		CreatedFrom: r.CreatedFrom,
+		CreatedTo:   r.CreatedTo,
+		Limit:       r.Limit,
+		Offset:      r.Offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*DTO, 0, len(es))
+	for _, e := range es {
+		result = append(result, s.FromDBModel(e))
+	}
+	return result, nil
+}
+
+// Verify walks the chain of the audit log from its first entry and checks that every entry has its
+// hash and follows the one before it. It stops at the first entry that does not. Entries not sealed
+// yet are not part of the chain.
+func (s *Service) Verify(ctx context.Context) (*Verification, error) {
+	v := &Verification{Valid: true, LastHash: genesisHash}
+	var lastSeq int64
+	for {
+		es, err := s.audit.GetAfter(ctx, lastSeq, verifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range es {
+			if e.PrevHash != v.LastHash || Hash(e) != e.Hash {
+				v.Valid, v.BrokenAt = false, &e.ID
+				return v, nil
+			}
+			v.Checked++
+			v.LastHash, lastSeq = e.Hash, e.Seq
+		}
+		if len(es) < verifyBatchSize {
+			return v, nil
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package audit_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+	"wallet/db"
+	"wallet/db/dbtest"
+	repomocks "wallet/mocks/repomocks/audit"
+	"wallet/service/audit"
+	auditStorage "wallet/storage/audit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const genesis = "0000000000000000000000000000000000000000000000000000000000000000"
+
+func TestRecord(t *testing.T) {
+	t.Run("writes an unsealed entry", func(t *testing.T) {
+		mockRepo := repomocks.NewRepository(t)
+		var e *auditStorage.Entry
+		mockRepo.On("Insert", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			e = args.Get(1).(*auditStorage.Entry)
+		}).Return(nil)
+
+		m := audit.Meta{Actor: "admin:1", TraceID: "trace", ClientIP: "10.0.0.1"}
+		err := audit.Record(audit.WithMeta(context.Background(), m), mockRepo, audit.WalletFrozen, audit.Wallet, 7, map[string]string{"status": "active"},
+			map[string]string{"status": "frozen"})
+		require.NoError(t, err)
+		assert.Equal(t, "admin:1", e.Actor)
+		assert.Equal(t, "wallet.freeze", e.Action)
+		assert.Equal(t, "wallet", e.Entity)
+		assert.Equal(t, int64(7), e.EntityID)
+		assert.Equal(t, `{"status":"active"}`, string(e.Before))
+		assert.Equal(t, `{"status":"frozen"}`, string(e.After))
+		assert.Equal(t, "trace", e.TraceID)
+		assert.Equal(t, "10.0.0.1", e.ClientIP)
+		assert.Equal(t, e.CreatedAt, e.CreatedAt.Truncate(time.Microsecond))
+		// the sealing job chains it
+		assert.Zero(t, e.Seq)
+		assert.Empty(t, e.PrevHash)
+		assert.Empty(t, e.Hash)
+	})
+
+	t.Run("by the system", func(t *testing.T) {
+		mockRepo := repomocks.NewRepository(t)
+		mockRepo.On("Insert", mock.Anything, mock.MatchedBy(func(e *auditStorage.Entry) bool {
+			return e.Actor == audit.SystemActor && e.Before == nil
+		})).Return(nil)
+
+		err := audit.Record(context.Background(), mockRepo, audit.HoldExpired, audit.Hold, 1, nil, map[string]int{"id": 1})
+		assert.NoError(t, err)
+	})
+
+	t.Run("insert failure", func(t *testing.T) {
+		mockRepo := repomocks.NewRepository(t)
+		mockRepo.On("Insert", mock.Anything, mock.Anything).Return(errors.New("forced error"))
+
+		err := audit.Record(context.Background(), mockRepo, audit.HoldExpired, audit.Hold, 1, nil, nil)
+		assert.EqualError(t, err, "forced error")
+	})
+}
+
+func TestSeal(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	r := auditStorage.NewStorage(psql)
+	s := audit.New(r)
+	ctx := audit.WithMeta(context.Background(), audit.Meta{Actor: "admin:1", TraceID: t.Name()})
+
+	// entries of concurrent db transactions are written without waiting on each other
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- db.Transaction(ctx, func(ctx context.Context) error {
+				return audit.Record(ctx, r, audit.WalletFrozen, audit.Wallet, int64(i), nil, map[string]int{"i": i})
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	n, err := s.Seal(context.Background())
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, n, 10)
+
+	es, err := r.Find(context.Background(), &auditStorage.Filter{TraceID: t.Name()})
+	require.NoError(t, err)
+	require.Len(t, es, 10)
+	for _, e := range es {
+		assert.NotZero(t, e.Seq)
+		assert.Equal(t, audit.Hash(e), e.Hash)
+	}
+
+	v, err := s.Verify(context.Background())
+	require.NoError(t, err)
+	assert.True(t, v.Valid)
+	assert.GreaterOrEqual(t, v.Checked, int64(10))
+
+	// sealed entries are not sealed again
+	_, err = psql.Exec("UPDATE audit_log SET hash = 'changed' WHERE id = $1", es[0].ID)
+	assert.ErrorContains(t, err, "audit log entries are immutable")
+	assert.Error(t, r.Seal(context.Background(), es[0]))
+}
+
+func TestHash(t *testing.T) {
+	e := &auditStorage.Entry{Actor: "admin", Action: "wallet.close", Entity: "wallet", EntityID: 1, PrevHash: genesis,
+		CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	h := audit.Hash(e)
+	assert.Len(t, h, 64)
+
+	// the hash does not depend on the location of the time
+	e.CreatedAt = e.CreatedAt.In(time.FixedZone("IRST", 12600))
+	assert.Equal(t, h, audit.Hash(e))
+
+	e.EntityID = 2
+	assert.NotEqual(t, h, audit.Hash(e))
+}
+
+// chain returns n chained entries.
+func chain(n int) []*auditStorage.Entry {
+	es := make([]*auditStorage.Entry, 0, n)
+	prev := genesis
+	for i := 1; i <= n; i++ {
+		e := &auditStorage.Entry{ID: int64(i), Seq: int64(i), Actor: "admin", Action: "wallet.recharge", Entity: "wallet",
+			EntityID: 1, After: []byte(`{"balance":100}`), PrevHash: prev, CreatedAt: time.Now().UTC()}
+		e.Hash = audit.Hash(e)
+		prev = e.Hash
+		es = append(es, e)
+	}
+	return es
+}
+
+func TestVerify(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		es := chain(3)
+		mockRepo := repomocks.NewRepository(t)
+		mockRepo.On("GetAfter", mock.Anything, int64(0), mock.Anything).Return(es, nil)
+
+		v, err := audit.New(mockRepo).Verify(context.Background())
+		require.NoError(t, err)
+		assert.True(t, v.Valid)
+		assert.Equal(t, int64(3), v.Checked)
+		assert.Equal(t, es[2].Hash, v.LastHash)
+		assert.Nil(t, v.BrokenAt)
+	})
+
+	t.Run("changed entry", func(t *testing.T) {
+		es := chain(3)
+		es[1].After = []byte(`{"balance":1000000}`)
+		mockRepo := repomocks.NewRepository(t)
+		mockRepo.On("GetAfter", mock.Anything, int64(0), mock.Anything).Return(es, nil)
+
+		v, err := audit.New(mockRepo).Verify(context.Background())
+		require.NoError(t, err)
+		assert.False(t, v.Valid)
+		assert.Equal(t, int64(1), v.Checked)
+		assert.Equal(t, int64(2), *v.BrokenAt)
+	})
+
+	t.Run("removed entry", func(t *testing.T) {
+		es := chain(3)
+		mockRepo := repomocks.NewRepository(t)
+		mockRepo.On("GetAfter", mock.Anything, int64(0), mock.Anything).Return([]*auditStorage.Entry{es[0], es[2]}, nil)
+
+		v, err := audit.New(mockRepo).Verify(context.Background())
+		require.NoError(t, err)
+		assert.False(t, v.Valid)
+		assert.Equal(t, int64(3), *v.BrokenAt)
+	})
+}
+
+func TestList_InvalidFilter(t *testing.T) {
+	s := audit.New(repomocks.NewRepository(t))
+	_, err := s.List(context.Background(), &audit.ListRequest{Limit: 1000})
+	assert.Error(t, err)
+
+	from := time.Now()
+	_, err = s.List(context.Background(), &audit.ListRequest{CreatedFrom: from, CreatedTo: from})
+	assert.Error(t, err)
+}
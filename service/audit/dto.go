@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type DTO struct {
+	ID        int64           `json:"id"`
+	Seq       int64           `json:"seq,omitempty"`
+	Actor     string          `json:"actor"`
+	Action    Action          `json:"action"`
+	Entity    Entity          `json:"entity"`
+	EntityID  int64           `json:"entityID"`
+	Before    json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After     json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	TraceID   string          `json:"traceID,omitempty"`
+	ClientIP  string          `json:"clientIP,omitempty"`
+	PrevHash  string          `json:"prevHash,omitempty"`
+	Hash      string          `json:"hash,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// ListRequest filters an audit log listing, zero fields match everything.
+type ListRequest struct {
+	Actor       string
+	Action      Action
+	Entity      Entity
+	EntityID    int64
+	TraceID     string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Limit       int
+	Offset      int
+}
+
+// Verification is the outcome of checking the hash chain of the audit log. LastHash is the hash of
+// the latest entry checked, keeping it outside the database lets a later check tell whether entries
+// were cut off the end of the log.
+type Verification struct {
+	Valid    bool   `json:"valid"`
+	Checked  int64  `json:"checked"`
+	BrokenAt *int64 `json:"brokenAt,omitempty"`
+	LastHash string `json:"lastHash"`
+}
@@ -0,0 +1,38 @@
+package audit
+
+import (
+	"context"
+	"wallet/storage/audit"
+)
+
+type UseCase interface {
+	List(ctx context.Context, r *ListRequest) ([]*DTO, error)
+	Verify(ctx context.Context) (*Verification, error)
+	Seal(ctx context.Context) (int, error)
+}
+
+type Service struct {
+	audit audit.Repository
+}
+
+func New(audit audit.Repository) *Service {
+	return &Service{audit: audit}
+}
+
+func (s *Service) FromDBModel(e *audit.Entry) *DTO {
+	return &DTO{
+		ID:        e.ID,
+		Seq:       e.Seq,
+		Actor:     e.Actor,
+		Action:    Action(e.Action),
+		Entity:    Entity(e.Entity),
+		EntityID:  e.EntityID,
+		Before:    e.Before,
+		After:     e.After,
+		TraceID:   e.TraceID,
+		ClientIP:  e.ClientIP,
+		PrevHash:  e.PrevHash,
+		Hash:      e.Hash,
+		CreatedAt: e.CreatedAt,
+	}
+}
@@ -0,0 +1,41 @@
+package gift
+
+import "time"
+
+type DTO struct {
+	ID             int64     `json:"id"`
+	Code           string    `json:"code"`
+	Amount         int64     `json:"amount"`
+	UsageLimit     int64     `json:"usageLimit"`
+	UsedCount      int64     `json:"usedCount"`
+	PerMemberLimit int64     `json:"perMemberLimit"`
+	StartsAt       time.Time `json:"startsAt"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// Terms are what a gift is worth and how and when it can be used. Amount is in minor units of the
+// default currency, a zero PerMemberLimit lets a member use a gift once and a zero StartsAt starts
+// it now.
+type Terms struct {
+	Amount         int64     `json:"amount"`
+	UsageLimit     int64     `json:"usageLimit"`
+	PerMemberLimit int64     `json:"perMemberLimit"`
+	StartsAt       time.Time `json:"startsAt"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+}
+
+type CreateRequest struct {
+	Code string `json:"code"`
+	Terms
+}
+
+// GenerateRequest creates Count gifts of the same terms with random codes of Length characters after
+// Prefix, 10 characters by default.
+type GenerateRequest struct {
+	Count  int    `json:"count"`
+	Prefix string `json:"prefix"`
+	Length int    `json:"length"`
+	Terms
+}
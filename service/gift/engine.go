@@ -0,0 +1,127 @@
+package gift
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"wallet/client/discount"
+	"wallet/db"
+	"wallet/internal/serr"
+	"wallet/storage/gift"
+)
+
+// maxReferenceLength is the longest use reference the gift_use table stores.
+const maxReferenceLength = 64
+
+// GetGiftByCode looks a gift up as the discount api does.
+func (s *Service) GetGiftByCode(ctx context.Context, code string) (*discount.Gift, error) {
+	g, err := s.gift.GetByCode(ctx, code)
+	if err != nil {
+		return nil, notFound("getGift", err)
+	}
+	return s.ToClientModel(g), nil
+}
+
+// UseGift uses a gift for a member within its period and limits. A use repeated with the same
+// reference returns the gift without using it again.
+func (s *Service) UseGift(ctx context.Context, code string, memberID int64, reference string) (*discount.Gift, error) {
+	if reference == "" || len(reference) > maxReferenceLength {
+		return nil, serr.ValidationErr("useGift", "invalid gift use reference", serr.ErrInvalidGift)
+	}
+	var result *discount.Gift
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		g, err := s.gift.GetByCodeForUpdate(ctx, code)
+		if err != nil {
+			return notFound("useGift", err)
+		}
+		u, err := s.gift.GetUseByReference(ctx, reference)
+		if err != nil {
+			return err
+		}
+		if u != nil {
+			if u.GiftID != g.ID || u.MemberID != memberID || u.ReleasedAt != nil {
+				return serr.ValidationErr("useGift", "invalid gift use reference", serr.ErrInvalidGift)
+			}
+			result = s.ToClientModel(g)
+			return nil
+		}
+		if err = usable(g, time.Now()); err != nil {
+			return err
+		}
+		n, err := s.gift.CountMemberUses(ctx, g.ID, memberID)
+		if err != nil {
+			return err
+		}
+		if n >= g.PerMemberLimit {
+			return serr.ValidationErr("useGift", "gift member limit reached", serr.ErrGiftMemberLimitReached)
+		}
+		if err = s.gift.CreateUse(ctx, &gift.Use{GiftID: g.ID, MemberID: memberID, Reference: reference}); err != nil {
+			return err
+		}
+		g.UsedCount++
+		if err = s.gift.UpdateUsedCount(ctx, g); err != nil {
+			return err
+		}
+		result = s.ToClientModel(g)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ReleaseGift gives back the use made with reference, releasing it again changes nothing.
+func (s *Service) ReleaseGift(ctx context.Context, code, reference string) (*discount.Gift, error) {
+	var result *discount.Gift
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		g, err := s.gift.GetByCodeForUpdate(ctx, code)
+		if err != nil {
+			return notFound("releaseGift", err)
+		}
+		u, err := s.gift.GetUseByReference(ctx, reference)
+		if err != nil {
+			return err
+		}
+		if u == nil || u.GiftID != g.ID {
+			return serr.ValidationErr("releaseGift", "gift use not found", serr.ErrGiftUseNotFound)
+		}
+		if u.ReleasedAt == nil {
+			if err = s.gift.ReleaseUse(ctx, u); err != nil {
+				return err
+			}
+			g.UsedCount--
+			if err = s.gift.UpdateUsedCount(ctx, g); err != nil {
+				return err
+			}
+		}
+		result = s.ToClientModel(g)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// usable tells why a gift can not be used at now, if it can not.
+func usable(g *gift.Gift, now time.Time) error {
+	switch {
+	case now.Before(g.StartsAt):
+		return serr.ValidationErr("gift", "gift not started", serr.ErrGiftNotStarted)
+	case !now.Before(g.ExpiresAt):
+		return serr.ValidationErr("gift", "gift expired", serr.ErrGiftExpired)
+	case g.UsedCount >= g.UsageLimit:
+		return serr.ValidationErr("gift", "gift usage limit reached", serr.ErrGiftUsageLimitReached)
+	}
+	return nil
+}
+
+// notFound turns a gift missing from the db into the error the discount api answers with.
+func notFound(method string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return serr.ValidationErr(method, "gift not found", serr.ErrGiftNotFound)
+	}
+	return err
+}
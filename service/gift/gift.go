@@ -0,0 +1,202 @@
+package gift
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"regexp"
+	"time"
+	"wallet/db"
+	"wallet/internal/serr"
+	"wallet/storage/gift"
+)
+
+const (
+	minCodeLength        = 3
+	maxCodeLength        = 64
+	defaultGeneratedCode = 10
+	minGeneratedCode     = 6
+	maxGenerateCount     = 1000
+	// generateAttempts is how many codes are drawn for a gift before generating gives up on collisions
+	generateAttempts = 5
+	// codeAlphabet leaves out characters read as one another, such as 0 and O
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+var codePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Create adds a gift with the given code.
+func (s *Service) Create(ctx context.Context, r *CreateRequest) (*DTO, error) {
+	if len(r.Code) < minCodeLength || len(r.Code) > maxCodeLength || !codePattern.MatchString(r.Code) {
+		return nil, serr.ValidationErr("gift", "invalid gift code", serr.ErrInvalidGift)
+	}
+	g, err := newGift(&r.Terms)
+	if err != nil {
+		return nil, err
+	}
+	g.Code = r.Code
+	if err = s.gift.Create(ctx, g); err != nil {
+		if errors.Is(err, gift.ErrCodeExists) {
+			return nil, serr.ConflictErr("gift", "gift code already exists", serr.ErrGiftCodeExists)
+		}
+		return nil, err
+	}
+	return s.FromDBModel(g), nil
+}
+
+// Generate adds gifts of the same terms with random codes, all of them or none.
+func (s *Service) Generate(ctx context.Context, r *GenerateRequest) ([]*DTO, error) {
+	if r.Count <= 0 || r.Count > maxGenerateCount {
+		return nil, serr.ValidationErr("gift", "invalid gift count", serr.ErrInvalidGift)
+	}
+	if r.Length == 0 {
+		r.Length = defaultGeneratedCode
+	}
+	if r.Length < minGeneratedCode || len(r.Prefix)+r.Length > maxCodeLength ||
+		(r.Prefix != "" && !codePattern.MatchString(r.Prefix)) {
+		return nil, serr.ValidationErr("gift", "invalid gift code", serr.ErrInvalidGift)
+	}
+	terms, err := newGift(&r.Terms)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*DTO, 0, r.Count)
+	err = db.Transaction(ctx, func(ctx context.Context) error {
+		dtos = dtos[:0]
+		for i := 0; i < r.Count; i++ {
+			g := *terms
+			if err := s.createWithRandomCode(ctx, &g, r.Prefix, r.Length); err != nil {
+				return err
+			}
+			dtos = append(dtos, s.FromDBModel(&g))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dtos, nil
+}
+
+// createWithRandomCode stores g under a random code, drawing another one when the code is taken.
+func (s *Service) createWithRandomCode(ctx context.Context, g *gift.Gift, prefix string, length int) error {
+	for attempt := 0; attempt < generateAttempts; attempt++ {
+		code, err := randomCode(length)
+		if err != nil {
+			return err
+		}
+		g.Code = prefix + code
+		err = s.gift.Create(ctx, g)
+		if !errors.Is(err, gift.ErrCodeExists) {
+			return err
+		}
+	}
+	return serr.ConflictErr("gift", "gift code already exists", serr.ErrGiftCodeExists)
+}
+
+func (s *Service) GetByID(ctx context.Context, id int64) (*DTO, error) {
+	g, err := s.gift.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.FromDBModel(g), nil
+}
+
+// List returns gifts newest first.
+func (s *Service) List(ctx context.Context, limit, offset int) ([]*DTO, error) {
+	gs, err := s.gift.List(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*DTO, 0, len(gs))
+	for _, g := range gs {
+		dtos = append(dtos, s.FromDBModel(g))
+	}
+	return dtos, nil
+}
+
+// Update replaces the terms of a gift, its usage limit can not go below the uses already made.
+func (s *Service) Update(ctx context.Context, id int64, r *Terms) (*DTO, error) {
+	terms, err := newGift(r)
+	if err != nil {
+		return nil, err
+	}
+	var result *DTO
+	err = db.Transaction(ctx, func(ctx context.Context) error {
+		g, err := s.gift.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		// locked, the used count can not change before the terms are written
+		if g, err = s.gift.GetByCodeForUpdate(ctx, g.Code); err != nil {
+			return err
+		}
+		if terms.UsageLimit < g.UsedCount {
+			return serr.ValidationErr("gift", "usage limit is below the used count", serr.ErrInvalidGift)
+		}
+		g.Amount, g.UsageLimit, g.PerMemberLimit = terms.Amount, terms.UsageLimit, terms.PerMemberLimit
+		g.StartsAt, g.ExpiresAt = terms.StartsAt, terms.ExpiresAt
+		if err = s.gift.Update(ctx, g); err != nil {
+			return err
+		}
+		result = s.FromDBModel(g)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Delete removes a gift no one has used, a used gift is expired by an update instead.
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	g, err := s.gift.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if g.UsedCount > 0 {
+		return serr.ConflictErr("gift", "gift has been used", serr.ErrGiftUsed)
+	}
+	return s.gift.Delete(ctx, id)
+}
+
+// newGift checks the terms of a gift and returns a gift of them without a code.
+func newGift(t *Terms) (*gift.Gift, error) {
+	if t.Amount <= 0 {
+		return nil, serr.ValidationErr("gift", "invalid amount", serr.ErrInvalidAmount)
+	}
+	if t.UsageLimit <= 0 || t.PerMemberLimit < 0 {
+		return nil, serr.ValidationErr("gift", "invalid gift usage limit", serr.ErrInvalidGift)
+	}
+	g := &gift.Gift{
+		Amount:         t.Amount,
+		UsageLimit:     t.UsageLimit,
+		PerMemberLimit: t.PerMemberLimit,
+		StartsAt:       t.StartsAt,
+		ExpiresAt:      t.ExpiresAt,
+	}
+	if g.PerMemberLimit == 0 {
+		g.PerMemberLimit = 1
+	}
+	if g.StartsAt.IsZero() {
+		g.StartsAt = time.Now()
+	}
+	if !g.ExpiresAt.After(g.StartsAt) {
+		return nil, serr.ValidationErr("gift", "invalid gift period", serr.ErrInvalidGift)
+	}
+	return g, nil
+}
+
+func randomCode(length int) (string, error) {
+	code := make([]byte, length)
+	max := big.NewInt(int64(len(codeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = codeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
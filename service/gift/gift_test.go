@@ -0,0 +1,230 @@
+package gift_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+	"wallet/db"
+	"wallet/internal/serr"
+	repomocks "wallet/mocks/repomocks/gift"
+	"wallet/service/gift"
+	giftStorage "wallet/storage/gift"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func errorCode(t *testing.T, err error) serr.ErrorCode {
+	var e *serr.ServiceError
+	require.True(t, errors.As(err, &e), "not a service error: %v", err)
+	return e.ErrorCode
+}
+
+func terms() gift.Terms {
+	return gift.Terms{Amount: 500, UsageLimit: 2, ExpiresAt: time.Now().Add(time.Hour)}
+}
+
+func TestCreate(t *testing.T) {
+	mockRepo := repomocks.NewRepository(t)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(g *giftStorage.Gift) bool {
+		return g.Code == "NOWRUZ" && g.Amount == 500 && g.PerMemberLimit == 1 && !g.StartsAt.IsZero()
+	})).Return(nil)
+
+	dto, err := gift.New(mockRepo).Create(context.Background(), &gift.CreateRequest{Code: "NOWRUZ", Terms: terms()})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), dto.PerMemberLimit)
+}
+
+func TestCreate_Invalid(t *testing.T) {
+	s := gift.New(repomocks.NewRepository(t))
+	for _, tc := range []struct {
+		name   string
+		modify func(r *gift.CreateRequest)
+		code   serr.ErrorCode
+	}{
+		{"short code", func(r *gift.CreateRequest) { r.Code = "AB" }, serr.ErrInvalidGift},
+		{"code with spaces", func(r *gift.CreateRequest) { r.Code = "NO WRUZ" }, serr.ErrInvalidGift},
+		{"no amount", func(r *gift.CreateRequest) { r.Amount = 0 }, serr.ErrInvalidAmount},
+		{"no usage limit", func(r *gift.CreateRequest) { r.UsageLimit = 0 }, serr.ErrInvalidGift},
+		{"negative member limit", func(r *gift.CreateRequest) { r.PerMemberLimit = -1 }, serr.ErrInvalidGift},
+		{"expires before it starts", func(r *gift.CreateRequest) { r.StartsAt = r.ExpiresAt.Add(time.Hour) },
+			serr.ErrInvalidGift},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &gift.CreateRequest{Code: "NOWRUZ", Terms: terms()}
+			tc.modify(r)
+			_, err := s.Create(context.Background(), r)
+			assert.Equal(t, tc.code, errorCode(t, err))
+		})
+	}
+}
+
+func TestCreate_CodeExists(t *testing.T) {
+	mockRepo := repomocks.NewRepository(t)
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(giftStorage.ErrCodeExists)
+
+	_, err := gift.New(mockRepo).Create(context.Background(), &gift.CreateRequest{Code: "NOWRUZ", Terms: terms()})
+	assert.Equal(t, serr.ErrGiftCodeExists, errorCode(t, err))
+}
+
+func TestGenerate_Invalid(t *testing.T) {
+	s := gift.New(repomocks.NewRepository(t))
+	for _, tc := range []struct {
+		name string
+		req  gift.GenerateRequest
+	}{
+		{"no count", gift.GenerateRequest{Terms: terms()}},
+		{"too many", gift.GenerateRequest{Count: 1001, Terms: terms()}},
+		{"short codes", gift.GenerateRequest{Count: 1, Length: 4, Terms: terms()}},
+		{"long codes", gift.GenerateRequest{Count: 1, Prefix: "YALDA-", Length: 60, Terms: terms()}},
+		{"invalid prefix", gift.GenerateRequest{Count: 1, Prefix: "YALDA ", Terms: terms()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := s.Generate(context.Background(), &tc.req)
+			assert.Equal(t, serr.ErrInvalidGift, errorCode(t, err))
+		})
+	}
+}
+
+func TestDelete_Used(t *testing.T) {
+	mockRepo := repomocks.NewRepository(t)
+	mockRepo.On("GetByID", mock.Anything, int64(1)).Return(&giftStorage.Gift{ID: 1, UsedCount: 1}, nil)
+
+	err := gift.New(mockRepo).Delete(context.Background(), 1)
+	assert.Equal(t, serr.ErrGiftUsed, errorCode(t, err))
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestGetGiftByCode(t *testing.T) {
+	t.Run("as the discount api", func(t *testing.T) {
+		starts := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+		mockRepo := repomocks.NewRepository(t)
+		mockRepo.On("GetByCode", mock.Anything, "NOWRUZ").Return(&giftStorage.Gift{
+			ID: 1, Code: "NOWRUZ", Amount: 500, UsageLimit: 10, UsedCount: 3, StartsAt: starts,
+			ExpiresAt: starts.AddDate(0, 0, 13),
+		}, nil)
+
+		g, err := gift.New(mockRepo).GetGiftByCode(context.Background(), "NOWRUZ")
+		require.NoError(t, err)
+		assert.Equal(t, int64(500), g.GiftAmount)
+		assert.Equal(t, int64(3), g.UsedCount)
+		assert.Equal(t, "2024-03-20T00:00:00Z", g.StartDateTime)
+		assert.Equal(t, "2024-04-02T00:00:00Z", g.ExpirationDate)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockRepo := repomocks.NewRepository(t)
+		mockRepo.On("GetByCode", mock.Anything, "NOWRUZ").
+			Return(nil, serr.DBError("GetByCode", "gift", sql.ErrNoRows))
+
+		_, err := gift.New(mockRepo).GetGiftByCode(context.Background(), "NOWRUZ")
+		assert.Equal(t, serr.ErrGiftNotFound, errorCode(t, err))
+	})
+}
+
+// testPostgres connects to the database given by the WALLET_TEST_DB_* environment variables and
+// skips the test when they are not set.
+func testPostgres(t *testing.T) *sql.DB {
+	host := os.Getenv("WALLET_TEST_DB_HOST")
+	if host == "" {
+		t.Skip("WALLET_TEST_DB_HOST is not set")
+	}
+	port := os.Getenv("WALLET_TEST_DB_PORT")
+	if port == "" {
+		port = "5432"
+	}
+	psql, err := db.NewPostgres(os.Getenv("WALLET_TEST_DB_NAME"), os.Getenv("WALLET_TEST_DB_USER"),
+		os.Getenv("WALLET_TEST_DB_PASSWORD"), host, port, 20, 5)
+	require.NoError(t, err)
+	viper.Set("db.postgres.migrationsPath", "../../db/migrations")
+	require.NoError(t, db.Migrate(psql))
+	return psql
+}
+
+func TestGiftService_Uses(t *testing.T) {
+	psql := testPostgres(t)
+	ctx := context.Background()
+	s := gift.New(giftStorage.NewStorage(psql))
+	ref := func(name string) string { return fmt.Sprintf("%s-%d", name, time.Now().UnixNano()) }
+
+	g, err := s.Create(ctx, &gift.CreateRequest{Code: ref("USES"), Terms: gift.Terms{
+		Amount: 500, UsageLimit: 2, PerMemberLimit: 1, ExpiresAt: time.Now().Add(time.Hour),
+	}})
+	require.NoError(t, err)
+
+	first := ref("first")
+	used, err := s.UseGift(ctx, g.Code, 1, first)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), used.UsedCount)
+
+	// the same reference uses it once
+	used, err = s.UseGift(ctx, g.Code, 1, first)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), used.UsedCount)
+
+	_, err = s.UseGift(ctx, g.Code, 1, ref("again"))
+	assert.Equal(t, serr.ErrGiftMemberLimitReached, errorCode(t, err))
+
+	_, err = s.UseGift(ctx, g.Code, 2, ref("second"))
+	require.NoError(t, err)
+	_, err = s.UseGift(ctx, g.Code, 3, ref("third"))
+	assert.Equal(t, serr.ErrGiftUsageLimitReached, errorCode(t, err))
+
+	// a released use is given back once and its reference is not used again
+	for i := 0; i < 2; i++ {
+		released, err := s.ReleaseGift(ctx, g.Code, first)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), released.UsedCount)
+	}
+	_, err = s.UseGift(ctx, g.Code, 1, first)
+	assert.Equal(t, serr.ErrInvalidGift, errorCode(t, err))
+	_, err = s.UseGift(ctx, g.Code, 1, ref("after release"))
+	require.NoError(t, err)
+
+	_, err = s.ReleaseGift(ctx, g.Code, ref("unknown"))
+	assert.Equal(t, serr.ErrGiftUseNotFound, errorCode(t, err))
+	_, err = s.Update(ctx, g.ID, &gift.Terms{Amount: 500, UsageLimit: 1, ExpiresAt: time.Now().Add(time.Hour)})
+	assert.Equal(t, serr.ErrInvalidGift, errorCode(t, err))
+	assert.Equal(t, serr.ErrGiftUsed, errorCode(t, s.Delete(ctx, g.ID)))
+}
+
+func TestGiftService_ConcurrentUses(t *testing.T) {
+	psql := testPostgres(t)
+	ctx := context.Background()
+	s := gift.New(giftStorage.NewStorage(psql))
+
+	const limit, members = 5, 20
+	gs, err := s.Generate(ctx, &gift.GenerateRequest{Count: 1, Prefix: "RACE-", Terms: gift.Terms{
+		Amount: 100, UsageLimit: limit, ExpiresAt: time.Now().Add(time.Hour),
+	}})
+	require.NoError(t, err)
+	require.Len(t, gs, 1)
+	assert.Len(t, gs[0].Code, len("RACE-")+10)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	used := 0
+	for i := 0; i < members; i++ {
+		wg.Add(1)
+		go func(member int64) {
+			defer wg.Done()
+			if _, err := s.UseGift(ctx, gs[0].Code, member, fmt.Sprintf("%s-%d", gs[0].Code, member)); err == nil {
+				mu.Lock()
+				used++
+				mu.Unlock()
+			}
+		}(int64(i + 1))
+	}
+	wg.Wait()
+	assert.Equal(t, limit, used)
+	g, err := s.GetByID(ctx, gs[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(limit), g.UsedCount)
+}
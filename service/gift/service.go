@@ -0,0 +1,59 @@
+package gift
+
+import (
+	"context"
+	"time"
+	"wallet/client/discount"
+	"wallet/storage/gift"
+)
+
+// UseCase manages the gifts of the local discount engine, which serves them as a discount.Client in
+// place of the discount api.
+type UseCase interface {
+	discount.Client
+	Create(ctx context.Context, r *CreateRequest) (*DTO, error)
+	Generate(ctx context.Context, r *GenerateRequest) ([]*DTO, error)
+	GetByID(ctx context.Context, id int64) (*DTO, error)
+	List(ctx context.Context, limit, offset int) ([]*DTO, error)
+	Update(ctx context.Context, id int64, r *Terms) (*DTO, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type Service struct {
+	gift gift.Repository
+}
+
+func New(gift gift.Repository) *Service {
+	return &Service{gift: gift}
+}
+
+func (s *Service) FromDBModel(g *gift.Gift) *DTO {
+	return &DTO{
+		ID:             g.ID,
+		Code:           g.Code,
+		Amount:         g.Amount,
+		UsageLimit:     g.UsageLimit,
+		UsedCount:      g.UsedCount,
+		PerMemberLimit: g.PerMemberLimit,
+		StartsAt:       g.StartsAt,
+		ExpiresAt:      g.ExpiresAt,
+		CreatedAt:      g.CreatedAt,
+		UpdatedAt:      g.UpdatedAt,
+	}
+}
+
+// ToClientModel returns a gift as the discount api does.
+func (s *Service) ToClientModel(g *gift.Gift) *discount.Gift {
+	return &discount.Gift{
+		Id:             int(g.ID),
+		Code:           g.Code,
+		GiftAmount:     g.Amount,
+		UsageLimit:     g.UsageLimit,
+		UsedCount:      g.UsedCount,
+		PerMemberLimit: g.PerMemberLimit,
+		ExpirationDate: g.ExpiresAt.Format(time.RFC3339),
+		StartDateTime:  g.StartsAt.Format(time.RFC3339),
+		CreatedAt:      g.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      g.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,29 @@
+package limit
+
+// Limits of a wallet in the minor units of its currency, zero is no limit.
+type Limits struct {
+	MaxWithdrawal      int64 `json:"maxWithdrawal"`
+	DailyDebit         int64 `json:"dailyDebit"`
+	MonthlyDebit       int64 `json:"monthlyDebit"`
+	MaxBalance         int64 `json:"maxBalance"`
+	MaxTransfersPerDay int64 `json:"maxTransfersPerDay"`
+}
+
+// SetRequest overrides limits of a wallet, a nil limit keeps the limit of its member tier and zero
+// lifts it.
+type SetRequest struct {
+	MaxWithdrawal      *int64 `json:"maxWithdrawal"`
+	DailyDebit         *int64 `json:"dailyDebit"`
+	MonthlyDebit       *int64 `json:"monthlyDebit"`
+	MaxBalance         *int64 `json:"maxBalance"`
+	MaxTransfersPerDay *int64 `json:"maxTransfersPerDay"`
+}
+
+// DTO is the limits a wallet is held to, made of the limits of its member tier and its overrides.
+type DTO struct {
+	WalletID int64       `json:"walletID"`
+	Tier     string      `json:"tier"`
+	Currency string      `json:"currency"`
+	Limits   Limits      `json:"limits"`
+	Override *SetRequest `json:"override,omitempty"`
+}
@@ -0,0 +1,135 @@
+package limit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+	"wallet/internal/serr"
+	"wallet/storage/limit"
+	"wallet/storage/wallet"
+)
+
+// Get returns the limits a wallet is held to.
+func (s *Service) Get(ctx context.Context, walletID int64) (*DTO, error) {
+	w, err := s.wallet.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	return s.forWallet(ctx, w)
+}
+
+// Set overrides the limits of a wallet, limits left nil in r come from its member tier.
+func (s *Service) Set(ctx context.Context, walletID int64, r *SetRequest) (*DTO, error) {
+	for _, v := range []*int64{r.MaxWithdrawal, r.DailyDebit, r.MonthlyDebit, r.MaxBalance, r.MaxTransfersPerDay} {
+		if v != nil && *v < 0 {
+			return nil, serr.ValidationErr("limit", "invalid limit", serr.ErrInvalidLimit)
+		}
+	}
+	w, err := s.wallet.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.limit.Upsert(ctx, &limit.WalletLimit{
+		WalletID:           w.ID,
+		MaxWithdrawal:      r.MaxWithdrawal,
+		DailyDebit:         r.DailyDebit,
+		MonthlyDebit:       r.MonthlyDebit,
+		MaxBalance:         r.MaxBalance,
+		MaxTransfersPerDay: r.MaxTransfersPerDay,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.forWallet(ctx, w)
+}
+
+// Reset drops the overrides of a wallet, it is held to the limits of its member tier again.
+func (s *Service) Reset(ctx context.Context, walletID int64) (*DTO, error) {
+	w, err := s.wallet.GetByID(ctx, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.limit.Delete(ctx, w.ID); err != nil {
+		return nil, err
+	}
+	return s.forWallet(ctx, w)
+}
+
+// CheckDebit returns the error of the first limit a debit of amount from a wallet breaks. The debits
+// the wallet already made are read on the service db transaction, which has to hold the wallet lock
+// for concurrent debits not to pass the check together. The open holds of the wallet count as debits
+// made, so capturing a hold, which never takes more than it reserved, stays within the limits.
+func (s *Service) CheckDebit(ctx context.Context, w *wallet.Wallet, amount int64, transfer bool) error {
+	dto, err := s.forWallet(ctx, w)
+	if err != nil {
+		return err
+	}
+	l := dto.Limits
+	if l.MaxWithdrawal > 0 && amount > l.MaxWithdrawal {
+		return serr.ValidationErr("limit", "amount exceeds the withdrawal limit", serr.ErrWithdrawalLimitExceeded)
+	}
+	if l.DailyDebit == 0 && l.MonthlyDebit == 0 && (!transfer || l.MaxTransfersPerDay == 0) {
+		return nil
+	}
+	dayStart, monthStart := s.periods(time.Now())
+	totals, err := s.transaction.GetDebitTotals(ctx, w.ID, dayStart, monthStart)
+	if err != nil {
+		return err
+	}
+	if l.DailyDebit > 0 && totals.Day+w.HeldBalance+amount > l.DailyDebit {
+		return serr.ValidationErr("limit", "daily debit limit exceeded", serr.ErrDailyLimitExceeded)
+	}
+	if l.MonthlyDebit > 0 && totals.Month+w.HeldBalance+amount > l.MonthlyDebit {
+		return serr.ValidationErr("limit", "monthly debit limit exceeded", serr.ErrMonthlyLimitExceeded)
+	}
+	if transfer && l.MaxTransfersPerDay > 0 && int64(totals.TransfersToday) >= l.MaxTransfersPerDay {
+		return serr.ValidationErr("limit", "daily transfer limit exceeded", serr.ErrTransferLimitExceeded)
+	}
+	return nil
+}
+
+// CheckCredit returns an error when crediting amount would take a wallet over its balance limit.
+func (s *Service) CheckCredit(ctx context.Context, w *wallet.Wallet, amount int64) error {
+	dto, err := s.forWallet(ctx, w)
+	if err != nil {
+		return err
+	}
+	if dto.Limits.MaxBalance > 0 && w.Balance+amount > dto.Limits.MaxBalance {
+		return serr.ValidationErr("limit", "balance limit exceeded", serr.ErrBalanceLimitExceeded)
+	}
+	return nil
+}
+
+func (s *Service) forWallet(ctx context.Context, w *wallet.Wallet) (*DTO, error) {
+	tier := DefaultTier
+	m, err := s.member.GetById(ctx, w.MemberID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if m != nil && m.Tier != "" {
+		tier = m.Tier
+	}
+	dto := &DTO{WalletID: w.ID, Tier: tier, Currency: w.Currency, Limits: tierLimits(tier, w.Currency)}
+	l, err := s.limit.Get(ctx, w.ID)
+	if err != nil && !notFound(err) {
+		return nil, err
+	}
+	if l != nil {
+		dto.Override = toOverride(l)
+		dto.Limits = dto.Limits.apply(dto.Override)
+	}
+	return dto, nil
+}
+
+// periods returns the start of the current day and month.
+func (s *Service) periods(now time.Time) (dayStart, monthStart time.Time) {
+	y, m, d := now.In(s.location).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, s.location), time.Date(y, m, 1, 0, 0, 0, 0, s.location)
+}
+
+func notFound(err error) bool {
+	var e *serr.ServiceError
+	return errors.As(err, &e) && e.Code == http.StatusNotFound
+}
@@ -0,0 +1,190 @@
+package limit_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	"wallet/internal/serr"
+	limitMocks "wallet/mocks/repomocks/limit"
+	memberMocks "wallet/mocks/repomocks/member"
+	transMocks "wallet/mocks/repomocks/transaction"
+	walletMocks "wallet/mocks/repomocks/wallet"
+	"wallet/service/limit"
+	limitStorage "wallet/storage/limit"
+	memberStorage "wallet/storage/member"
+	transStorage "wallet/storage/transaction"
+	walletStorage "wallet/storage/wallet"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mocks struct {
+	limit       *limitMocks.Repository
+	member      *memberMocks.Repository
+	wallet      *walletMocks.Repository
+	transaction *transMocks.Repository
+}
+
+func newService(t *testing.T) (*limit.Service, mocks) {
+	viper.Set("limits.tiers.gold.maxTransfersPerDay", 2)
+	viper.Set("limits.tiers.gold.amounts.IRR.maxWithdrawal", 1000)
+	viper.Set("limits.tiers.gold.amounts.IRR.dailyDebit", 2000)
+	viper.Set("limits.tiers.gold.amounts.IRR.monthlyDebit", 5000)
+	viper.Set("limits.tiers.gold.amounts.IRR.maxBalance", 10000)
+	t.Cleanup(func() { viper.Set("limits.tiers", nil) })
+
+	m := mocks{
+		limit:       limitMocks.NewRepository(t),
+		member:      memberMocks.NewRepository(t),
+		wallet:      walletMocks.NewRepository(t),
+		transaction: transMocks.NewRepository(t),
+	}
+	return limit.New(m.limit, m.member, m.wallet, m.transaction, time.UTC), m
+}
+
+var goldWallet = &walletStorage.Wallet{ID: 1, MemberID: 7, Balance: 9000, Currency: "IRR"}
+
+func (m mocks) goldMember() {
+	m.member.On("GetById", mock.Anything, int64(7)).Return(&memberStorage.Member{ID: 7, Tier: "gold"}, nil)
+}
+
+func (m mocks) noOverride() {
+	m.limit.On("Get", mock.Anything, int64(1)).Return(nil, serr.DBError("Get", "wallet limit", sql.ErrNoRows))
+}
+
+func (m mocks) totals(day, month int64, transfers int) {
+	m.transaction.On("GetDebitTotals", mock.Anything, int64(1), mock.Anything, mock.Anything).
+		Return(&transStorage.DebitTotals{Day: day, Month: month, TransfersToday: transfers}, nil)
+}
+
+func assertCode(t *testing.T, err error, code serr.ErrorCode) {
+	t.Helper()
+	var e *serr.ServiceError
+	require.True(t, errors.As(err, &e), "got %v", err)
+	assert.Equal(t, code, e.ErrorCode)
+}
+
+func TestCheckDebit(t *testing.T) {
+	t.Run("within limits", func(t *testing.T) {
+		s, m := newService(t)
+		m.goldMember()
+		m.noOverride()
+		m.totals(500, 1000, 1)
+		assert.NoError(t, s.CheckDebit(context.Background(), goldWallet, 1000, true))
+	})
+
+	t.Run("withdrawal limit", func(t *testing.T) {
+		s, m := newService(t)
+		m.goldMember()
+		m.noOverride()
+		assertCode(t, s.CheckDebit(context.Background(), goldWallet, 1001, false), serr.ErrWithdrawalLimitExceeded)
+	})
+
+	t.Run("daily limit", func(t *testing.T) {
+		s, m := newService(t)
+		m.goldMember()
+		m.noOverride()
+		m.totals(1500, 1500, 0)
+		assertCode(t, s.CheckDebit(context.Background(), goldWallet, 600, false), serr.ErrDailyLimitExceeded)
+	})
+
+	t.Run("monthly limit", func(t *testing.T) {
+		s, m := newService(t)
+		m.goldMember()
+		m.noOverride()
+		m.totals(0, 4500, 0)
+		assertCode(t, s.CheckDebit(context.Background(), goldWallet, 600, false), serr.ErrMonthlyLimitExceeded)
+	})
+
+	t.Run("open holds count as debits", func(t *testing.T) {
+		s, m := newService(t)
+		m.goldMember()
+		m.noOverride()
+		m.totals(500, 500, 0)
+		held := *goldWallet
+		held.HeldBalance = 1000
+		assertCode(t, s.CheckDebit(context.Background(), &held, 600, false), serr.ErrDailyLimitExceeded)
+		assert.NoError(t, s.CheckDebit(context.Background(), &held, 500, false))
+	})
+
+	t.Run("open holds count towards the month", func(t *testing.T) {
+		s, m := newService(t)
+		m.goldMember()
+		m.noOverride()
+		m.totals(0, 4000, 0)
+		held := *goldWallet
+		held.HeldBalance = 600
+		assertCode(t, s.CheckDebit(context.Background(), &held, 500, false), serr.ErrMonthlyLimitExceeded)
+	})
+
+	t.Run("transfers per day", func(t *testing.T) {
+		s, m := newService(t)
+		m.goldMember()
+		m.noOverride()
+		m.totals(0, 0, 2)
+		assertCode(t, s.CheckDebit(context.Background(), goldWallet, 10, true), serr.ErrTransferLimitExceeded)
+	})
+
+	t.Run("override lifts a limit", func(t *testing.T) {
+		s, m := newService(t)
+		m.goldMember()
+		unlimited := int64(0)
+		m.limit.On("Get", mock.Anything, int64(1)).Return(&limitStorage.WalletLimit{WalletID: 1, DailyDebit: &unlimited}, nil)
+		m.totals(1900, 1900, 0)
+		assert.NoError(t, s.CheckDebit(context.Background(), goldWallet, 600, false))
+	})
+
+	t.Run("wallet without member has the default tier", func(t *testing.T) {
+		s, m := newService(t)
+		m.member.On("GetById", mock.Anything, int64(7)).Return(nil, sql.ErrNoRows)
+		m.noOverride()
+		assert.NoError(t, s.CheckDebit(context.Background(), goldWallet, 1000000, true))
+	})
+}
+
+func TestCheckCredit(t *testing.T) {
+	s, m := newService(t)
+	m.goldMember()
+	m.noOverride()
+	assert.NoError(t, s.CheckCredit(context.Background(), goldWallet, 1000))
+	assertCode(t, s.CheckCredit(context.Background(), goldWallet, 1001), serr.ErrBalanceLimitExceeded)
+}
+
+func TestGet(t *testing.T) {
+	s, m := newService(t)
+	m.wallet.On("GetByID", mock.Anything, int64(1)).Return(goldWallet, nil)
+	m.goldMember()
+	transfers := int64(5)
+	m.limit.On("Get", mock.Anything, int64(1)).Return(&limitStorage.WalletLimit{WalletID: 1, MaxTransfersPerDay: &transfers}, nil)
+
+	dto, err := s.Get(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "gold", dto.Tier)
+	assert.Equal(t, limit.Limits{
+		MaxWithdrawal:      1000,
+		DailyDebit:         2000,
+		MonthlyDebit:       5000,
+		MaxBalance:         10000,
+		MaxTransfersPerDay: 5,
+	}, dto.Limits)
+	assert.Equal(t, &transfers, dto.Override.MaxTransfersPerDay)
+}
+
+func TestSet_InvalidLimit(t *testing.T) {
+	s, _ := newService(t)
+	negative := int64(-1)
+	_, err := s.Set(context.Background(), 1, &limit.SetRequest{DailyDebit: &negative})
+	assertCode(t, err, serr.ErrInvalidLimit)
+}
+
+func TestValidTier(t *testing.T) {
+	newService(t)
+	assert.True(t, limit.ValidTier(limit.DefaultTier))
+	assert.True(t, limit.ValidTier("gold"))
+	assert.False(t, limit.ValidTier("platinum"))
+}
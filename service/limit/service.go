@@ -0,0 +1,92 @@
+package limit
+
+import (
+	"context"
+	"time"
+	"wallet/internal/config"
+	"wallet/storage/limit"
+	"wallet/storage/member"
+	"wallet/storage/transaction"
+	"wallet/storage/wallet"
+)
+
+// DefaultTier is the tier of members nobody moved to another one.
+const DefaultTier = "standard"
+
+type UseCase interface {
+	Get(ctx context.Context, walletID int64) (*DTO, error)
+	Set(ctx context.Context, walletID int64, r *SetRequest) (*DTO, error)
+	Reset(ctx context.Context, walletID int64) (*DTO, error)
+	CheckDebit(ctx context.Context, w *wallet.Wallet, amount int64, transfer bool) error
+	CheckCredit(ctx context.Context, w *wallet.Wallet, amount int64) error
+}
+
+type Service struct {
+	limit       limit.Repository
+	member      member.Repository
+	wallet      wallet.Repository
+	transaction transaction.Repository
+	// days and months start over at midnight in location
+	location *time.Location
+}
+
+func New(
+	limit limit.Repository,
+	member member.Repository,
+	wallet wallet.Repository,
+	transaction transaction.Repository,
+	location *time.Location,
+) *Service {
+	return &Service{
+		limit:       limit,
+		member:      member,
+		wallet:      wallet,
+		transaction: transaction,
+		location:    location,
+	}
+}
+
+// ValidTier tells whether members may be moved to a tier.
+func ValidTier(tier string) bool {
+	return tier == DefaultTier || config.LimitTierExists(tier)
+}
+
+// tierLimits returns the configured limits of a tier in a currency.
+func tierLimits(tier, currency string) Limits {
+	return Limits{
+		MaxWithdrawal:      config.LimitTierAmount(tier, currency, "maxWithdrawal"),
+		DailyDebit:         config.LimitTierAmount(tier, currency, "dailyDebit"),
+		MonthlyDebit:       config.LimitTierAmount(tier, currency, "monthlyDebit"),
+		MaxBalance:         config.LimitTierAmount(tier, currency, "maxBalance"),
+		MaxTransfersPerDay: config.LimitTierTransfersPerDay(tier),
+	}
+}
+
+func toOverride(l *limit.WalletLimit) *SetRequest {
+	return &SetRequest{
+		MaxWithdrawal:      l.MaxWithdrawal,
+		DailyDebit:         l.DailyDebit,
+		MonthlyDebit:       l.MonthlyDebit,
+		MaxBalance:         l.MaxBalance,
+		MaxTransfersPerDay: l.MaxTransfersPerDay,
+	}
+}
+
+// apply returns the limits with the overrides set in r.
+func (l Limits) apply(r *SetRequest) Limits {
+	for _, o := range []struct {
+		limit    *int64
+		override *int64
+	}{
+		{&l.MaxWithdrawal, r.MaxWithdrawal},
+		{&l.DailyDebit, r.DailyDebit},
+		{&l.MonthlyDebit, r.MonthlyDebit},
+		{&l.MaxBalance, r.MaxBalance},
+		{&l.MaxTransfersPerDay, r.MaxTransfersPerDay},
+	} {
+		if o.override != nil {
+			*o.limit = *o.override
+		}
+	}
+	return l
+}
@@ -8,6 +8,7 @@ type DTO struct {
 	LastName  string    `json:"lastName"`
 	Email     string    `json:"email"`
 	Phone     string    `json:"phone"`
+	Tier      string    `json:"tier"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
@@ -17,4 +18,10 @@ type CreateRequest struct {
 	LastName  string `json:"lastName"`
 	Email     string `json:"email"`
 	Phone     string `json:"phone"`
+	// Tier is the tier the spending limits of the member come from, standard when empty.
+	Tier string `json:"tier,omitempty"`
+}
+
+type SetTierRequest struct {
+	Tier string `json:"tier"`
 }
@@ -1,25 +1,42 @@
 package member
 
 import (
+	"context"
 	"github.com/rs/zerolog/log"
 	"time"
+	"wallet/db"
+	"wallet/internal/serr"
+	"wallet/service/audit"
+	"wallet/service/limit"
 )
 
 // creates a new member.
-func (s *Service) Create(r *CreateRequest) (*DTO, error) {
+func (s *Service) Create(ctx context.Context, r *CreateRequest) (*DTO, error) {
+	if r.Tier == "" {
+		r.Tier = limit.DefaultTier
+	}
+	if !limit.ValidTier(r.Tier) {
+		return nil, serr.ValidationErr("member", "invalid tier", serr.ErrInvalidTier)
+	}
 	memberRecord := s.FromCreateRequest(r)
 
-	err := s.member.Create(memberRecord)
+	var result *DTO
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.member.Create(ctx, memberRecord); err != nil {
+			return err
+		}
+		result = s.FromDBModel(memberRecord)
+		return s.recordAudit(ctx, audit.MemberCreated, result.ID, nil, result)
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	return s.FromDBModel(memberRecord), nil
+	return result, nil
 }
 
 // gets a member by id.
-func (s *Service) GetById(id int64) (*DTO, error) {
-	member, err := s.member.GetById(id)
+func (s *Service) GetById(ctx context.Context, id int64) (*DTO, error) {
+	member, err := s.member.GetById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -27,45 +44,97 @@ func (s *Service) GetById(id int64) (*DTO, error) {
 }
 
 // updates a member by id.
-func (s *Service) Update(r *DTO) (*DTO, error) {
+func (s *Service) Update(ctx context.Context, r *DTO) (*DTO, error) {
 	memberRecord := s.ToDBModel(r)
 
-	err := s.member.Update(memberRecord)
+	var result *DTO
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		before, err := s.GetById(ctx, r.ID)
+		if err != nil {
+			return err
+		}
+		if err = s.member.Update(ctx, memberRecord); err != nil {
+			return err
+		}
+		result = s.FromDBModel(memberRecord)
+		return s.recordAudit(ctx, audit.MemberUpdated, r.ID, before, result)
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	return s.FromDBModel(memberRecord), nil
+	return result, nil
 }
 
 // get by phone
-func (s *Service) GetByPhone(phone string) (*DTO, error) {
-	member, err := s.member.GetByPhone(phone)
+func (s *Service) GetByPhone(ctx context.Context, phone string) (*DTO, error) {
+	member, err := s.member.GetByPhone(ctx, phone)
 	if err != nil {
 		return nil, err
 	}
 	return s.FromDBModel(member), nil
 }
 
-func (s *Service) GetMembersByGiftCode(gift string, limit, offset int) ([]*DTO, error) {
+func (s *Service) GetMembersByGiftCode(ctx context.Context, gift string, limit, offset int) ([]*DTO, error) {
 	var members []*DTO
-	members, err := s.RetrieveFromRedis(keyRdb + gift)
+	members, err := s.RetrieveFromRedis(ctx, keyRdb+gift)
 	if err != nil {
-		wallets, err := s.wallet.GetByDiscountCodeWithPagination(gift, limit, offset)
+		wallets, err := s.wallet.GetByDiscountCodeWithPagination(ctx, gift, limit, offset)
 		if err != nil {
 			return nil, err
 		}
 		for _, w := range wallets {
-			member, err := s.member.GetById(w.MemberID)
+			member, err := s.member.GetById(ctx, w.MemberID)
 			if err != nil {
 				return nil, err
 			}
 			members = append(members, s.FromDBModel(member))
 		}
-		err = s.UpdateOrInsertInRedis(keyRdb+gift, members, time.Minute*10)
+		err = s.UpdateOrInsertInRedis(ctx, keyRdb+gift, members, time.Minute*10)
 		if err != nil {
 			log.Error().Err(err).Msg("failed to update or insert in redis")
 		}
 	}
 	return members, nil
 }
+
+// SetTier moves a member to the tier its spending limits come from.
+func (s *Service) SetTier(ctx context.Context, id int64, tier string) (*DTO, error) {
+	if !limit.ValidTier(tier) {
+		return nil, serr.ValidationErr("member", "invalid tier", serr.ErrInvalidTier)
+	}
+	var result *DTO
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		before, err := s.GetById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err = s.member.SetTier(ctx, id, tier); err != nil {
+			return err
+		}
+		if result, err = s.GetById(ctx, id); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, audit.MemberTierSet, id, before, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Delete marks a member and its wallets deleted, the wallets have to be empty.
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	return db.Transaction(ctx, func(ctx context.Context) error {
+		before, err := s.GetById(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err = s.wallet.DeleteByMemberID(ctx, id); err != nil {
+			return err
+		}
+		if err = s.member.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, audit.MemberDeleted, id, before, nil)
+	})
+}
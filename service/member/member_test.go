@@ -1,6 +1,7 @@
 package member_test
 
 import (
+	"context"
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -28,133 +29,133 @@ var MockRequest = &member.CreateRequest{
 func TestCreate(t *testing.T) {
 	mockUseCase := repomocks.NewUseCase(t)
 
-	mockUseCase.On("Create", MockRequest).Return(MockMember, nil)
+	mockUseCase.On("Create", mock.Anything, MockRequest).Return(MockMember, nil)
 
-	result, err := mockUseCase.Create(MockRequest)
+	result, err := mockUseCase.Create(context.Background(), MockRequest)
 
 	assert.Nil(t, err)
 	assert.Equal(t, MockMember, result)
-	mockUseCase.AssertCalled(t, "Create", MockRequest)
+	mockUseCase.AssertCalled(t, "Create", mock.Anything, MockRequest)
 }
 
 // Test case for unsuccessful usage of the `Create` function.
 func TestCreateError(t *testing.T) {
 	mockUseCase := repomocks.NewUseCase(t)
 
-	mockUseCase.On("Create", MockRequest).Return(nil, errors.New("Failed to create member"))
+	mockUseCase.On("Create", mock.Anything, MockRequest).Return(nil, errors.New("Failed to create member"))
 
-	result, err := mockUseCase.Create(MockRequest)
+	result, err := mockUseCase.Create(context.Background(), MockRequest)
 
 	assert.Nil(t, result)
 	assert.NotNil(t, err)
 	assert.Equal(t, "Failed to create member", err.Error())
-	mockUseCase.AssertCalled(t, "Create", MockRequest)
+	mockUseCase.AssertCalled(t, "Create", mock.Anything, MockRequest)
 }
 
 // Test case for successful usage of the `GetById` function.
 func TestGetById(t *testing.T) {
 	mockUseCase := repomocks.NewUseCase(t)
 
-	mockUseCase.On("GetById", int64(1)).Return(MockMember, nil)
+	mockUseCase.On("GetById", mock.Anything, int64(1)).Return(MockMember, nil)
 
-	result, err := mockUseCase.GetById(int64(1))
+	result, err := mockUseCase.GetById(context.Background(), int64(1))
 
 	assert.Nil(t, err)
 	assert.Equal(t, MockMember, result)
-	mockUseCase.AssertCalled(t, "GetById", int64(1))
+	mockUseCase.AssertCalled(t, "GetById", mock.Anything, int64(1))
 }
 
 // Test case for unsuccessful usage of the `GetById` function.
 func TestGetByIdError(t *testing.T) {
 	mockUseCase := repomocks.NewUseCase(t)
 
-	mockUseCase.On("GetById", int64(1)).Return(nil, errors.New("Failed to get member"))
+	mockUseCase.On("GetById", mock.Anything, int64(1)).Return(nil, errors.New("Failed to get member"))
 
-	result, err := mockUseCase.GetById(int64(1))
+	result, err := mockUseCase.GetById(context.Background(), int64(1))
 
 	assert.Nil(t, result)
 	assert.NotNil(t, err)
 	assert.Equal(t, "Failed to get member", err.Error())
-	mockUseCase.AssertCalled(t, "GetById", int64(1))
+	mockUseCase.AssertCalled(t, "GetById", mock.Anything, int64(1))
 }
 
 // Test case for successful usage of the `GetByPhone` function.
 func TestGetByPhone(t *testing.T) {
 	mockUseCase := repomocks.NewUseCase(t)
 
-	mockUseCase.On("GetByPhone", "+989123456789").Return(MockMember, nil)
+	mockUseCase.On("GetByPhone", mock.Anything, "+989123456789").Return(MockMember, nil)
 
-	result, err := mockUseCase.GetByPhone("+989123456789")
+	result, err := mockUseCase.GetByPhone(context.Background(), "+989123456789")
 
 	assert.Nil(t, err)
 	assert.Equal(t, MockMember, result)
-	mockUseCase.AssertCalled(t, "GetByPhone", "+989123456789")
+	mockUseCase.AssertCalled(t, "GetByPhone", mock.Anything, "+989123456789")
 }
 
 // Test case for unsuccessful usage of the `GetByPhone` function.
 func TestGetByPhoneError(t *testing.T) {
 	mockUseCase := repomocks.NewUseCase(t)
 
-	mockUseCase.On("GetByPhone", "+989123456789").Return(nil, errors.New("Failed to get member"))
+	mockUseCase.On("GetByPhone", mock.Anything, "+989123456789").Return(nil, errors.New("Failed to get member"))
 
-	result, err := mockUseCase.GetByPhone("+989123456789")
+	result, err := mockUseCase.GetByPhone(context.Background(), "+989123456789")
 
 	assert.Nil(t, result)
 	assert.NotNil(t, err)
 	assert.Equal(t, "Failed to get member", err.Error())
-	mockUseCase.AssertCalled(t, "GetByPhone", "+989123456789")
+	mockUseCase.AssertCalled(t, "GetByPhone", mock.Anything, "+989123456789")
 }
 
 // Test case for successful usage of the `GetMembersByGiftCode` function.
 func TestGetMembersByGiftCode(t *testing.T) {
 	mockUseCase := repomocks.NewUseCase(t)
 
-	mockUseCase.On("GetMembersByGiftCode", mock.Anything, mock.Anything, mock.Anything).Return([]*member.DTO{MockMember}, nil)
+	mockUseCase.On("GetMembersByGiftCode", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]*member.DTO{MockMember}, nil)
 
-	result, err := mockUseCase.GetMembersByGiftCode("TEST", 1, 1)
+	result, err := mockUseCase.GetMembersByGiftCode(context.Background(), "TEST", 1, 1)
 
 	assert.Nil(t, err)
 	assert.Equal(t, []*member.DTO{MockMember}, result)
-	mockUseCase.AssertCalled(t, "GetMembersByGiftCode", "TEST", 1, 1)
+	mockUseCase.AssertCalled(t, "GetMembersByGiftCode", mock.Anything, "TEST", 1, 1)
 }
 
 // Test case for unsuccessful usage of the `GetMembersByGiftCode` function.
 func TestGetMembersByGiftCodeError(t *testing.T) {
 	mockUseCase := repomocks.NewUseCase(t)
 
-	mockUseCase.On("GetMembersByGiftCode", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("Failed to get members"))
+	mockUseCase.On("GetMembersByGiftCode", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("Failed to get members"))
 
-	result, err := mockUseCase.GetMembersByGiftCode("TEST", 1, 1)
+	result, err := mockUseCase.GetMembersByGiftCode(context.Background(), "TEST", 1, 1)
 
 	assert.Nil(t, result)
 	assert.NotNil(t, err)
 	assert.Equal(t, "Failed to get members", err.Error())
-	mockUseCase.AssertCalled(t, "GetMembersByGiftCode", "TEST", 1, 1)
+	mockUseCase.AssertCalled(t, "GetMembersByGiftCode", mock.Anything, "TEST", 1, 1)
 }
 
 // Test case for successful usage of the `Update` function.
 func TestUpdate(t *testing.T) {
 	mockUseCase := repomocks.NewUseCase(t)
 
-	mockUseCase.On("Update", MockMember).Return(MockMember, nil)
+	mockUseCase.On("Update", mock.Anything, MockMember).Return(MockMember, nil)
 
-	result, err := mockUseCase.Update(MockMember)
+	result, err := mockUseCase.Update(context.Background(), MockMember)
 
 	assert.Nil(t, err)
 	assert.Equal(t, MockMember, result)
-	mockUseCase.AssertCalled(t, "Update", MockMember)
+	mockUseCase.AssertCalled(t, "Update", mock.Anything, MockMember)
 }
 
 // Test case for unsuccessful usage of the `Update` function.
 func TestUpdateError(t *testing.T) {
 	mockUseCase := repomocks.NewUseCase(t)
 
-	mockUseCase.On("Update", MockMember).Return(nil, errors.New("Failed to update member"))
+	mockUseCase.On("Update", mock.Anything, MockMember).Return(nil, errors.New("Failed to update member"))
 
-	result, err := mockUseCase.Update(MockMember)
+	result, err := mockUseCase.Update(context.Background(), MockMember)
 
 	assert.Nil(t, result)
 	assert.NotNil(t, err)
 	assert.Equal(t, "Failed to update member", err.Error())
-	mockUseCase.AssertCalled(t, "Update", MockMember)
+	mockUseCase.AssertCalled(t, "Update", mock.Anything, MockMember)
 }
@@ -2,61 +2,52 @@ package member
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
-	"sync"
 	"time"
 	"wallet/db"
 	"wallet/internal/config"
+	"wallet/service/audit"
 	"wallet/service/wallet"
+	auditStorage "wallet/storage/audit"
 	"wallet/storage/member"
 )
 
 const keyRdb = ":MEMBERS:"
 
 type UseCase interface {
-	Create(r *CreateRequest) (*DTO, error)
-	GetById(id int64) (*DTO, error)
-	Update(r *DTO) (*DTO, error)
-	GetByPhone(phone string) (*DTO, error)
-	GetMembersByGiftCode(gift string, limit, offset int) ([]*DTO, error)
-	WithTX(tx *sql.Tx) (*Service, error)
+	Create(ctx context.Context, r *CreateRequest) (*DTO, error)
+	GetById(ctx context.Context, id int64) (*DTO, error)
+	Update(ctx context.Context, r *DTO) (*DTO, error)
+	GetByPhone(ctx context.Context, phone string) (*DTO, error)
+	GetMembersByGiftCode(ctx context.Context, gift string, limit, offset int) ([]*DTO, error)
+	SetTier(ctx context.Context, id int64, tier string) (*DTO, error)
+	Delete(ctx context.Context, id int64) error
 }
 
 type Service struct {
-	member member.Repository
-	wallet wallet.UseCase
-	rdb    db.RedisClient
-
-	mu   sync.Mutex
-	inTx bool
+	member   member.Repository
+	wallet   wallet.UseCase
+	auditLog auditStorage.Repository
+	rdb      db.RedisClient
 }
 
 func New(
 	member member.Repository,
 	wallet wallet.UseCase,
+	auditLog auditStorage.Repository,
 	rdb db.RedisClient,
 ) *Service {
 	return &Service{
-		member: member,
-		wallet: wallet,
-		rdb:    rdb,
+		member:   member,
+		wallet:   wallet,
+		auditLog: auditLog,
+		rdb:      rdb,
 	}
 }
 
-func (s *Service) WithTX(tx *sql.Tx) (*Service, error) {
-	var err error
-	service := *s
-	service.member, err = s.member.WithTX(tx)
-	if err != nil {
-		return nil, err
-	}
-	service.wallet, err = s.wallet.WithTX(tx)
-	if err != nil {
-		return nil, err
-	}
-	service.inTx = true
-	return &service, nil
+// recordAudit writes an action of the caller into the audit log, ctx has to carry a db transaction.
+func (s *Service) recordAudit(ctx context.Context, action audit.Action, id int64, before, after any) error {
+	return audit.Record(ctx, s.auditLog, action, audit.Member, id, before, after)
 }
 
 func (s *Service) ToDBModel(u *DTO) *member.Member {
@@ -77,6 +68,7 @@ func (s *Service) FromDBModel(u *member.Member) *DTO {
 		LastName:  u.LastName,
 		Email:     u.Email,
 		Phone:     u.Phone,
+		Tier:      u.Tier,
 		CreatedAt: u.CreatedAt,
 		UpdatedAt: u.UpdatedAt,
 	}
@@ -88,11 +80,12 @@ func (s *Service) FromCreateRequest(r *CreateRequest) *member.Member {
 		LastName:  r.LastName,
 		Email:     r.Email,
 		Phone:     r.Phone,
+		Tier:      r.Tier,
 	}
 }
 
-func (s *Service) RetrieveFromRedis(key string) ([]*DTO, error) {
-	ms, err := s.rdb.Get(context.Background(), config.RDBPrefix()+key)
+func (s *Service) RetrieveFromRedis(ctx context.Context, key string) ([]*DTO, error) {
+	ms, err := s.rdb.Get(ctx, config.RDBPrefix()+key)
 	if err != nil {
 		return nil, err
 	}
@@ -104,20 +97,20 @@ func (s *Service) RetrieveFromRedis(key string) ([]*DTO, error) {
 	return members, nil
 }
 
-func (s *Service) UpdateOrInsertInRedis(key string, ms []*DTO, exp time.Duration) error {
+func (s *Service) UpdateOrInsertInRedis(ctx context.Context, key string, ms []*DTO, exp time.Duration) error {
 	m, err := json.Marshal(ms)
 	if err != nil {
 		return err
 	}
-	err = s.rdb.Set(context.Background(), config.RDBPrefix()+key, m, exp)
+	err = s.rdb.Set(ctx, config.RDBPrefix()+key, m, exp)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *Service) RemoveWithKey(k string) {
-	s.rdb.Del(context.Background(), config.RDBPrefix()+k)
+func (s *Service) RemoveWithKey(ctx context.Context, k string) {
+	s.rdb.Del(ctx, config.RDBPrefix()+k)
 }
 
 func (ms *DTO) MarshalBinary() ([]byte, error) {
@@ -0,0 +1,62 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"wallet/storage/outbox"
+)
+
+type EventType string
+
+const (
+	WalletCreated       EventType = "WalletCreated"
+	BalanceCredited     EventType = "BalanceCredited"
+	BalanceDebited      EventType = "BalanceDebited"
+	GiftRedeemed        EventType = "GiftRedeemed"
+	TransferCompleted   EventType = "TransferCompleted"
+	RefundIssued        EventType = "RefundIssued"
+	WalletStatusChanged EventType = "WalletStatusChanged"
+)
+
+var EventTypes = []EventType{WalletCreated, BalanceCredited, BalanceDebited, GiftRedeemed, TransferCompleted, RefundIssued,
+	WalletStatusChanged}
+
+func (t EventType) Valid() bool {
+	for _, et := range EventTypes {
+		if t == et {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a published domain event. Events are delivered at least once, consumers tell a
+// redelivery by its ID.
+type Event struct {
+	ID        int64           `json:"id"`
+	Type      EventType       `json:"type"`
+	WalletID  int64           `json:"walletID"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// Record writes an event into the outbox. ctx has to carry the db transaction of the change the event
+// describes, so the event is kept only if the change is.
+func Record(ctx context.Context, r outbox.Repository, t EventType, walletID int64, payload any) error {
+	p, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return r.Insert(ctx, &outbox.Event{Type: string(t), WalletID: walletID, Payload: p})
+}
+
+func FromDBModel(e *outbox.Event) *Event {
+	return &Event{
+		ID:        e.ID,
+		Type:      EventType(e.Type),
+		WalletID:  e.WalletID,
+		Payload:   e.Payload,
+		CreatedAt: e.CreatedAt,
+	}
+}
@@ -0,0 +1,37 @@
+package outbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	repomocks "wallet/mocks/repomocks/outbox"
+	"wallet/service/outbox"
+	outboxStorage "wallet/storage/outbox"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecord(t *testing.T) {
+	mockRepo := repomocks.NewRepository(t)
+	mockRepo.On("Insert", mock.Anything, mock.MatchedBy(func(e *outboxStorage.Event) bool {
+		return e.Type == string(outbox.BalanceCredited) && e.WalletID == 1 && string(e.Payload) == `{"amount":100}`
+	})).Return(nil)
+
+	err := outbox.Record(context.Background(), mockRepo, outbox.BalanceCredited, 1, map[string]int64{"amount": 100})
+	assert.NoError(t, err)
+}
+
+func TestMemoryPublisher(t *testing.T) {
+	p := outbox.NewMemoryPublisher()
+	e := outbox.FromDBModel(&outboxStorage.Event{ID: 1, Type: "WalletCreated", WalletID: 2, Payload: []byte(`{}`)})
+	assert.NoError(t, p.Publish(context.Background(), e))
+
+	p.FailWith(errors.New("broker down"))
+	assert.Error(t, p.Publish(context.Background(), e))
+	p.FailWith(nil)
+
+	assert.Equal(t, []*outbox.Event{{ID: 1, Type: outbox.WalletCreated, WalletID: 2, Payload: json.RawMessage(`{}`)}},
+		p.Events())
+}
@@ -0,0 +1,70 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Publisher delivers events to the services reacting to them.
+type Publisher interface {
+	Publish(ctx context.Context, e *Event) error
+}
+
+// Publishers publishes each event to all of its publishers in order and stops at the first failure,
+// the publishers before it get the event again when it is retried.
+type Publishers []Publisher
+
+func (ps Publishers) Publish(ctx context.Context, e *Event) error {
+	for _, p := range ps {
+		if err := p.Publish(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MemoryPublisher keeps published events in memory, for tests.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []*Event
+	err    error
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(_ context.Context, e *Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	p.events = append(p.events, e)
+	return nil
+}
+
+// Events returns the events published so far.
+func (p *MemoryPublisher) Events() []*Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*Event(nil), p.events...)
+}
+
+// FailWith makes Publish return err until it is called again with nil.
+func (p *MemoryPublisher) FailWith(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+// LogPublisher writes events to the application log.
+type LogPublisher struct{}
+
+func (LogPublisher) Publish(_ context.Context, e *Event) error {
+	log.Info().Int64("event_id", e.ID).Str("type", string(e.Type)).Int64("wallet_id", e.WalletID).
+		RawJSON("payload", e.Payload).Msg("event published")
+	return nil
+}
@@ -0,0 +1,59 @@
+package outbox
+
+import (
+	"context"
+	"wallet/db"
+	"wallet/storage/outbox"
+)
+
+const relayBatchSize = 100
+
+// Relay publishes the events waiting in the outbox.
+type Relay struct {
+	outbox    outbox.Repository
+	publisher Publisher
+}
+
+func NewRelay(outbox outbox.Repository, publisher Publisher) *Relay {
+	return &Relay{outbox: outbox, publisher: publisher}
+}
+
+// Publish publishes a batch of events in the order they were written and returns how many were
+// published. An event is marked published only after the publisher took it, so it is published again
+// if marking it fails. A failed event stops the batch and is retried by the next call.
+func (r *Relay) Publish(ctx context.Context) (int, error) {
+	published := 0
+	var publishErr error
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		events, err := r.outbox.GetUnpublished(ctx, relayBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			if publishErr = r.publisher.Publish(ctx, FromDBModel(e)); publishErr != nil {
+				return r.outbox.MarkFailed(ctx, e.ID, publishErr.Error())
+			}
+			if err = r.outbox.MarkPublished(ctx, e.ID); err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return published, publishErr
+}
+
+// Drain publishes batches until the outbox is empty or publishing fails.
+func (r *Relay) Drain(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := r.Publish(ctx)
+		total += n
+		if err != nil || n < relayBatchSize {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package reconciliation
+
+import "time"
+
+type Summary struct {
+	RunID      string `json:"runID"`
+	Repair     bool   `json:"repair"`
+	Checked    int    `json:"checked"`
+	Mismatched int    `json:"mismatched"`
+	Repaired   int    `json:"repaired"`
+	Failed     int    `json:"failed"`
+}
+
+type DTO struct {
+	ID                      int64     `json:"id"`
+	RunID                   string    `json:"runID"`
+	WalletID                int64     `json:"walletID"`
+	WalletBalance           int64     `json:"walletBalance"`
+	TransactionBalance      int64     `json:"transactionBalance"`
+	LedgerBalance           int64     `json:"ledgerBalance"`
+	Difference              int64     `json:"difference"`
+	Repaired                bool      `json:"repaired"`
+	CorrectionEntryID       int64     `json:"correctionEntryID"`
+	CorrectionTransactionID int64     `json:"correctionTransactionID"`
+	CreatedAt               time.Time `json:"createdAt"`
+}
@@ -0,0 +1,131 @@
+package reconciliation
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"wallet/db"
+	"wallet/storage/reconciliation"
+	"wallet/storage/transaction"
+)
+
+const pageSize = 100
+
+// Run compares the balance and the transaction history of every wallet with its ledger, and reports
+// the wallets that differ. The ledger is taken as the truth since its postings are balanced and
+// immutable, and it holds the opening balances of wallets that predate it. With repair a correction
+// transaction brings the history in line with the ledger and the wallet balance is set to it.
+func (s *Service) Run(ctx context.Context, repair bool) (*Summary, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+	summary := &Summary{RunID: id.String(), Repair: repair}
+	for offset := 0; ; offset += pageSize {
+		ws, err := s.wallet.GetAllByPage(ctx, pageSize, offset)
+		if err != nil {
+			return summary, err
+		}
+		for _, w := range ws {
+			r, err := s.check(ctx, summary.RunID, w.ID, repair)
+			if err != nil {
+				log.Error().Str("method", "reconciliation.Run").Int64("wallet_id", w.ID).Err(err).
+					Msg("failed to reconcile wallet")
+				summary.Failed++
+				continue
+			}
+			summary.Checked++
+			if r == nil {
+				continue
+			}
+			summary.Mismatched++
+			if r.Repaired {
+				summary.Repaired++
+			}
+		}
+		if len(ws) < pageSize {
+			return summary, nil
+		}
+	}
+}
+
+// check reconciles a single wallet while holding its row lock and returns nil if it is consistent.
+func (s *Service) check(ctx context.Context, runID string, walletID int64, repair bool) (*reconciliation.Report, error) {
+	var report *reconciliation.Report
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		w, err := s.wallet.GetByIDForUpdate(ctx, walletID)
+		if err != nil {
+			return err
+		}
+		transactionBalance, err := s.transaction.GetBalance(ctx, walletID)
+		if err != nil {
+			return err
+		}
+		ledgerBalance, err := s.ledger.GetWalletBalance(ctx, walletID)
+		if err != nil {
+			return err
+		}
+		if w.Balance == ledgerBalance && transactionBalance == ledgerBalance {
+			return nil
+		}
+		report = &reconciliation.Report{
+			RunID:              runID,
+			WalletID:           walletID,
+			WalletBalance:      w.Balance,
+			TransactionBalance: transactionBalance,
+			LedgerBalance:      ledgerBalance,
+			Difference:         ledgerBalance - w.Balance,
+		}
+		log.Warn().Str("run_id", runID).Int64("wallet_id", walletID).Int64("wallet_balance", w.Balance).
+			Int64("transaction_balance", transactionBalance).Int64("ledger_balance", ledgerBalance).
+			Msg("wallet balance mismatch")
+		// a negative ledger can not become a wallet balance, it is left for manual review
+		if repair && ledgerBalance >= 0 {
+			if err = s.repair(ctx, report, w.Currency); err != nil {
+				return err
+			}
+		}
+		return s.report.Insert(ctx, report)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// repair must be called in the db transaction that locked the wallet. The correction transaction has
+// no journal entry, its amount is already in the ledger.
+func (s *Service) repair(ctx context.Context, r *reconciliation.Report, currency string) error {
+	if delta := r.LedgerBalance - r.TransactionBalance; delta != 0 {
+		t := &transaction.Transaction{
+			WalletID:        r.WalletID,
+			Amount:          delta,
+			Currency:        currency,
+			TransactionType: transaction.Correction,
+			Description:     "reconciliation " + r.RunID,
+		}
+		if err := s.transaction.Insert(ctx, t); err != nil {
+			return err
+		}
+		r.CorrectionTransactionID = t.ID
+	}
+	if r.Difference != 0 {
+		if _, err := s.wallet.AdjustBalance(ctx, r.WalletID, r.Difference); err != nil {
+			return err
+		}
+	}
+	r.Repaired = true
+	return nil
+}
+
+func (s *Service) GetReports(ctx context.Context, runID string) ([]*DTO, error) {
+	rs, err := s.report.GetByRunID(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	var result []*DTO
+	for _, r := range rs {
+		result = append(result, s.FromDBModel(r))
+	}
+	return result, nil
+}
@@ -0,0 +1,153 @@
+package reconciliation_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"wallet/db"
+	"wallet/db/dbtest"
+	"wallet/service/reconciliation"
+	"wallet/storage/ledger"
+	reconciliationstorage "wallet/storage/reconciliation"
+	transactionstorage "wallet/storage/transaction"
+	walletstorage "wallet/storage/wallet"
+)
+
+// onlyWallet lists a single wallet so a run leaves the wallets of other tests alone.
+type onlyWallet struct {
+	walletstorage.Storage
+	id int64
+}
+
+func (w onlyWallet) GetAllByPage(ctx context.Context, limit, offset int) ([]*walletstorage.Wallet, error) {
+	if offset > 0 {
+		return nil, nil
+	}
+	wl, err := w.GetByID(ctx, w.id)
+	if err != nil {
+		return nil, err
+	}
+	return []*walletstorage.Wallet{wl}, nil
+}
+
+func TestRun_Repair(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	ctx := context.Background()
+	wallets, transactions := walletstorage.NewStorage(psql), transactionstorage.NewStorage(psql)
+	ledgers, reports := ledger.NewStorage(psql), reconciliationstorage.NewStorage(psql)
+
+	// a wallet that predates the ledger, its balance is only in the opening balance posting
+	walletID := dbtest.Wallet(t, psql, 1000)
+	require.NoError(t, db.Transaction(ctx, func(ctx context.Context) error {
+		return ledgers.InsertEntry(ctx, &ledger.JournalEntry{Description: "opening balance", Postings: []*ledger.Posting{
+			{Account: ledger.OpeningBalance, Direction: ledger.Debit, Amount: 1000, Currency: "IRR"},
+			{WalletID: walletID, Direction: ledger.Credit, Amount: 1000, Currency: "IRR"},
+		}})
+	}))
+	// and a balance column that drifted from it
+	_, err := wallets.AdjustBalance(ctx, walletID, 50)
+	require.NoError(t, err)
+
+	s := reconciliation.New(onlyWallet{Storage: wallets, id: walletID}, transactions, ledgers, reports)
+	summary, err := s.Run(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Checked)
+	assert.Equal(t, 1, summary.Mismatched)
+	assert.Equal(t, 1, summary.Repaired)
+
+	rs, err := s.GetReports(ctx, summary.RunID)
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+	assert.Equal(t, int64(1050), rs[0].WalletBalance)
+	assert.Equal(t, int64(0), rs[0].TransactionBalance)
+	assert.Equal(t, int64(1000), rs[0].LedgerBalance)
+	assert.Equal(t, int64(-50), rs[0].Difference)
+	assert.Zero(t, rs[0].CorrectionEntryID)
+
+	correction, err := transactions.GetByID(ctx, rs[0].CorrectionTransactionID)
+	require.NoError(t, err)
+	assert.Equal(t, transactionstorage.Correction, correction.TransactionType)
+	assert.Equal(t, int64(1000), correction.Amount)
+
+	w, err := wallets.GetByID(ctx, walletID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), w.Balance)
+	balance, err := transactions.GetBalance(ctx, walletID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), balance)
+
+	// the correction has no journal entry, the ledger it was taken from is left as it was
+	balance, err = ledgers.GetWalletBalance(ctx, walletID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), balance)
+
+	summary, err = s.Run(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, 0, summary.Mismatched)
+}
+
+func TestRun_BalanceDrift(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	ctx := context.Background()
+	wallets, transactions := walletstorage.NewStorage(psql), transactionstorage.NewStorage(psql)
+	ledgers, reports := ledger.NewStorage(psql), reconciliationstorage.NewStorage(psql)
+
+	// a recharge recorded in the history and the ledger
+	walletID := dbtest.Wallet(t, psql, 0)
+	require.NoError(t, db.Transaction(ctx, func(ctx context.Context) error {
+		if err := transactions.Insert(ctx, &transactionstorage.Transaction{WalletID: walletID, Amount: 500,
+			Currency: "IRR", TransactionType: transactionstorage.Recharge, Description: "recharge"}); err != nil {
+			return err
+		}
+		if err := ledgers.InsertEntry(ctx, &ledger.JournalEntry{Description: "recharge", Postings: []*ledger.Posting{
+			{Account: ledger.CashIn, Direction: ledger.Debit, Amount: 500, Currency: "IRR"},
+			{WalletID: walletID, Direction: ledger.Credit, Amount: 500, Currency: "IRR"},
+		}}); err != nil {
+			return err
+		}
+		_, err := wallets.AdjustBalance(ctx, walletID, 500)
+		return err
+	}))
+	s := reconciliation.New(onlyWallet{Storage: wallets, id: walletID}, transactions, ledgers, reports)
+
+	summary, err := s.Run(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Checked)
+	assert.Zero(t, summary.Mismatched)
+
+	// a balance column that lost part of the recharge
+	_, err = wallets.AdjustBalance(ctx, walletID, -30)
+	require.NoError(t, err)
+
+	// a run without repair only reports it
+	summary, err = s.Run(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Mismatched)
+	assert.Zero(t, summary.Repaired)
+	rs, err := s.GetReports(ctx, summary.RunID)
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+	assert.Equal(t, int64(470), rs[0].WalletBalance)
+	assert.Equal(t, int64(500), rs[0].TransactionBalance)
+	assert.Equal(t, int64(500), rs[0].LedgerBalance)
+	assert.Equal(t, int64(30), rs[0].Difference)
+	w, err := wallets.GetByID(ctx, walletID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(470), w.Balance)
+
+	// the history already matches the ledger, a repair only corrects the balance
+	summary, err = s.Run(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Repaired)
+	rs, err = s.GetReports(ctx, summary.RunID)
+	require.NoError(t, err)
+	require.Len(t, rs, 1)
+	assert.Zero(t, rs[0].CorrectionTransactionID)
+	w, err = wallets.GetByID(ctx, walletID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(500), w.Balance)
+	balance, err := transactions.GetBalance(ctx, walletID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(500), balance)
+}
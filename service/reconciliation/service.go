@@ -0,0 +1,51 @@
+package reconciliation
+
+import (
+	"context"
+	"wallet/storage/ledger"
+	"wallet/storage/reconciliation"
+	"wallet/storage/transaction"
+	"wallet/storage/wallet"
+)
+
+type UseCase interface {
+	Run(ctx context.Context, repair bool) (*Summary, error)
+	GetReports(ctx context.Context, runID string) ([]*DTO, error)
+}
+
+type Service struct {
+	wallet      wallet.Repository
+	transaction transaction.Repository
+	ledger      ledger.Repository
+	report      reconciliation.Repository
+}
+
+func New(
+	wallet wallet.Repository,
+	transaction transaction.Repository,
+	ledger ledger.Repository,
+	report reconciliation.Repository,
+) *Service {
+	return &Service{
+		wallet:      wallet,
+		transaction: transaction,
+		ledger:      ledger,
+		report:      report,
+	}
+}
+
+func (s *Service) FromDBModel(r *reconciliation.Report) *DTO {
+	return &DTO{
+		ID:                      r.ID,
+		RunID:                   r.RunID,
+		WalletID:                r.WalletID,
+		WalletBalance:           r.WalletBalance,
+		TransactionBalance:      r.TransactionBalance,
+		LedgerBalance:           r.LedgerBalance,
+		Difference:              r.Difference,
+		Repaired:                r.Repaired,
+		CorrectionEntryID:       r.CorrectionEntryID,
+		CorrectionTransactionID: r.CorrectionTransactionID,
+		CreatedAt:               r.CreatedAt,
+	}
+}
@@ -0,0 +1,19 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"wallet/service/transaction"
+)
+
+// utf8BOM lets spreadsheet applications open Persian statements with the right encoding.
+const utf8BOM = "\ufeff"
+
+// writeCSV writes a statement as a single table, the opening and closing balances are its first
+// and last rows. Amounts are plain numbers so they can be summed by spreadsheets.
+func writeCSV(w io.Writer, st *transaction.Statement, l layout) error {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return err
+	}
+	return csv.NewWriter(w).WriteAll(append([][]string{l.header(st)}, l.rows(st)...))
+}
@@ -0,0 +1,145 @@
+package statement
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"wallet/service/transaction"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	fontFamily = "dejavu"
+	margin     = 12.5
+	rowHeight  = 7.0
+	ellipsis   = "…"
+)
+
+// widths of the statement columns in mm, in the order of layout.header
+var widths = []float64{28, 16, 24, 67, 25, 25}
+
+// numeric tells the columns printed with Persian digits in Persian statements.
+var numeric = []bool{true, true, false, false, true, true}
+
+type pdfWriter struct {
+	pdf *fpdf.Fpdf
+	l   layout
+}
+
+func (e *Exporter) writePDF(w io.Writer, st *transaction.Statement, l layout) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(fontFamily, "", e.font)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", e.boldFont)
+	pdf.SetMargins(margin, margin, margin)
+	pdf.SetAutoPageBreak(false, margin)
+	pdf.SetTitle(l.text("Account statement"), true)
+	p := &pdfWriter{pdf: pdf, l: l}
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-margin)
+		pdf.SetFont(fontFamily, "", 8)
+		pdf.CellFormat(0, 5, p.number(l.text("Page")+" "+strconv.Itoa(pdf.PageNo())), "", 0, "C", false, 0, "")
+	})
+
+	pdf.AddPage()
+	pdf.SetFont(fontFamily, "B", 14)
+	pdf.CellFormat(0, 10, p.text(l.text("Account statement")), "", 1, p.align(false), false, 0, "")
+	pdf.SetFont(fontFamily, "", 9)
+	for _, line := range []string{
+		l.text("Wallet") + ": " + strconv.FormatInt(st.WalletID, 10),
+		l.text("Currency") + ": " + st.Currency,
+		l.text("Period") + ": " + l.date(st.From) + " – " + l.date(st.To),
+	} {
+		pdf.CellFormat(0, 5, p.number(line), "", 1, p.align(false), false, 0, "")
+	}
+	pdf.Ln(4)
+
+	p.header(st)
+	rows := l.rows(st)
+	for i, row := range rows {
+		// the opening and closing balances stand out of the transactions
+		balance := i == 0 || i == len(rows)-1
+		p.row(st, row, balance)
+	}
+	return pdf.Output(w)
+}
+
+func (p *pdfWriter) header(st *transaction.Statement) {
+	p.pdf.SetFillColor(225, 225, 225)
+	p.draw(p.l.header(st), "B", true)
+}
+
+// row prints a row of the table, moving to a new page with the header repeated when the page is full.
+func (p *pdfWriter) row(st *transaction.Statement, cells []string, bold bool) {
+	_, pageHeight := p.pdf.GetPageSize()
+	if p.pdf.GetY()+rowHeight > pageHeight-2*margin {
+		p.pdf.AddPage()
+		p.header(st)
+	}
+	style := ""
+	if bold {
+		style = "B"
+	}
+	p.pdf.SetFillColor(245, 245, 245)
+	p.draw(cells, style, bold)
+}
+
+// draw prints cells in reading order, Persian rows run from the right edge of the page.
+func (p *pdfWriter) draw(cells []string, style string, fill bool) {
+	p.pdf.SetFont(fontFamily, style, 8)
+	order := make([]int, len(cells))
+	for i := range order {
+		order[i] = i
+		if p.l.rtl {
+			order[i] = len(cells) - 1 - i
+		}
+	}
+	for _, i := range order {
+		text := p.fit(cells[i], widths[i], numeric[i])
+		p.pdf.CellFormat(widths[i], rowHeight, text, "1", 0, p.align(numeric[i]), fill, 0, "")
+	}
+	p.pdf.Ln(-1)
+}
+
+// align returns the alignment of text, or of numbers, in the reading direction of the statement.
+func (p *pdfWriter) align(number bool) string {
+	if number == p.l.rtl {
+		return "L"
+	}
+	return "R"
+}
+
+// text shapes and orders s for printing.
+func (p *pdfWriter) text(s string) string {
+	return visual(s, p.l.rtl)
+}
+
+// number is text with the digits of the statement language.
+func (p *pdfWriter) number(s string) string {
+	if p.l.rtl {
+		s = strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '9' {
+				return '۰' + r - '0'
+			}
+			return r
+		}, s)
+	}
+	return p.text(s)
+}
+
+// fit cuts s short with an ellipsis when it is wider than a cell.
+func (p *pdfWriter) fit(s string, width float64, number bool) string {
+	format := p.text
+	if number {
+		format = p.number
+	}
+	s = strings.Join(strings.Fields(s), " ")
+	// room for the padding fpdf leaves on both sides of a cell
+	width -= 2
+	text := format(s)
+	for rs := []rune(s); len(rs) > 0 && p.pdf.GetStringWidth(text) > width; {
+		rs = rs[:len(rs)-1]
+		text = format(strings.TrimSpace(string(rs)) + ellipsis)
+	}
+	return text
+}
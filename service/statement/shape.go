@@ -0,0 +1,224 @@
+package statement
+
+import "unicode"
+
+const (
+	zwnj    = '\u200c'
+	lam     = 'ل'
+	tatweel = '\u0640'
+)
+
+// forms are the isolated, final, initial and medial presentation forms of a letter. Letters without
+// initial and medial forms only join the letter before them.
+type forms [4]rune
+
+func (f forms) dual() bool {
+	return f[2] != 0
+}
+
+var letters = map[rune]forms{
+	'ء': {0xFE80, 0, 0, 0},
+	'آ': {0xFE81, 0xFE82, 0, 0},
+	'أ': {0xFE83, 0xFE84, 0, 0},
+	'ؤ': {0xFE85, 0xFE86, 0, 0},
+	'إ': {0xFE87, 0xFE88, 0, 0},
+	'ئ': {0xFE89, 0xFE8A, 0xFE8B, 0xFE8C},
+	'ا': {0xFE8D, 0xFE8E, 0, 0},
+	'ب': {0xFE8F, 0xFE90, 0xFE91, 0xFE92},
+	'ة': {0xFE93, 0xFE94, 0, 0},
+	'ت': {0xFE95, 0xFE96, 0xFE97, 0xFE98},
+	'ث': {0xFE99, 0xFE9A, 0xFE9B, 0xFE9C},
+	'ج': {0xFE9D, 0xFE9E, 0xFE9F, 0xFEA0},
+	'ح': {0xFEA1, 0xFEA2, 0xFEA3, 0xFEA4},
+	'خ': {0xFEA5, 0xFEA6, 0xFEA7, 0xFEA8},
+	'د': {0xFEA9, 0xFEAA, 0, 0},
+	'ذ': {0xFEAB, 0xFEAC, 0, 0},
+	'ر': {0xFEAD, 0xFEAE, 0, 0},
+	'ز': {0xFEAF, 0xFEB0, 0, 0},
+	'س': {0xFEB1, 0xFEB2, 0xFEB3, 0xFEB4},
+	'ش': {0xFEB5, 0xFEB6, 0xFEB7, 0xFEB8},
+	'ص': {0xFEB9, 0xFEBA, 0xFEBB, 0xFEBC},
+	'ض': {0xFEBD, 0xFEBE, 0xFEBF, 0xFEC0},
+	'ط': {0xFEC1, 0xFEC2, 0xFEC3, 0xFEC4},
+	'ظ': {0xFEC5, 0xFEC6, 0xFEC7, 0xFEC8},
+	'ع': {0xFEC9, 0xFECA, 0xFECB, 0xFECC},
+	'غ': {0xFECD, 0xFECE, 0xFECF, 0xFED0},
+	'ف': {0xFED1, 0xFED2, 0xFED3, 0xFED4},
+	'ق': {0xFED5, 0xFED6, 0xFED7, 0xFED8},
+	'ك': {0xFED9, 0xFEDA, 0xFEDB, 0xFEDC},
+	'ل': {0xFEDD, 0xFEDE, 0xFEDF, 0xFEE0},
+	'م': {0xFEE1, 0xFEE2, 0xFEE3, 0xFEE4},
+	'ن': {0xFEE5, 0xFEE6, 0xFEE7, 0xFEE8},
+	'ه': {0xFEE9, 0xFEEA, 0xFEEB, 0xFEEC},
+	'و': {0xFEED, 0xFEEE, 0, 0},
+	'ى': {0xFEEF, 0xFEF0, 0, 0},
+	'ي': {0xFEF1, 0xFEF2, 0xFEF3, 0xFEF4},
+	'پ': {0xFB56, 0xFB57, 0xFB58, 0xFB59},
+	'چ': {0xFB7A, 0xFB7B, 0xFB7C, 0xFB7D},
+	'ژ': {0xFB8A, 0xFB8B, 0, 0},
+	'ک': {0xFB8E, 0xFB8F, 0xFB90, 0xFB91},
+	'گ': {0xFB92, 0xFB93, 0xFB94, 0xFB95},
+	'ی': {0xFBFC, 0xFBFD, 0xFBFE, 0xFBFF},
+}
+
+// lamAlef are the isolated and final ligatures of lam followed by an alef.
+var lamAlef = map[rune][2]rune{
+	'آ': {0xFEF5, 0xFEF6},
+	'أ': {0xFEF7, 0xFEF8},
+	'إ': {0xFEF9, 0xFEFA},
+	'ا': {0xFEFB, 0xFEFC},
+}
+
+var mirrored = map[rune]rune{'(': ')', ')': '(', '[': ']', ']': '[', '{': '}', '}': '{', '<': '>', '>': '<'}
+
+// shape replaces Persian and Arabic letters with their presentation forms, the pdf font has no
+// shaping of its own and would print every letter in its isolated form otherwise.
+func shape(s string) string {
+	rs := []rune(s)
+	out := make([]rune, 0, len(rs))
+	// joinsNext reports whether the letter at i is followed by one it joins to, ignoring diacritics
+	joinsNext := func(i int) bool {
+		for j := i + 1; j < len(rs); j++ {
+			if unicode.Is(unicode.Mn, rs[j]) {
+				continue
+			}
+			f, ok := letters[rs[j]]
+			return ok && f[1] != 0 || rs[j] == tatweel
+		}
+		return false
+	}
+	joined := false
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		if unicode.Is(unicode.Mn, r) {
+			out = append(out, r)
+			continue
+		}
+		f, ok := letters[r]
+		if !ok {
+			joined = r == tatweel
+			if r != zwnj {
+				out = append(out, r)
+			}
+			continue
+		}
+		if r == lam && i+1 < len(rs) {
+			if l, ok := lamAlef[rs[i+1]]; ok {
+				if joined {
+					out = append(out, l[1])
+				} else {
+					out = append(out, l[0])
+				}
+				joined = false
+				i++
+				continue
+			}
+		}
+		// hamza joins neither side
+		joined = joined && f[1] != 0
+		next := f.dual() && joinsNext(i)
+		switch {
+		case joined && next:
+			out = append(out, f[3])
+		case joined:
+			out = append(out, f[1])
+		case next:
+			out = append(out, f[2])
+		default:
+			out = append(out, f[0])
+		}
+		joined = f.dual()
+	}
+	return string(out)
+}
+
+type direction int
+
+const (
+	neutral direction = iota
+	ltr
+	rtl
+)
+
+func directionOf(r rune) direction {
+	switch {
+	case r >= '\u06f0' && r <= '\u06f9', r >= '\u0660' && r <= '\u066c':
+		// Persian and Arabic digits and separators are laid out left to right like any number
+		return ltr
+	case r >= '\u0590' && r <= '\u08ff', r >= '\ufb1d' && r <= '\ufdff', r >= '\ufe70' && r <= '\ufeff':
+		return rtl
+	case unicode.IsLetter(r), unicode.IsDigit(r):
+		return ltr
+	}
+	return neutral
+}
+
+// visual shapes s and reorders it for printing from left to right in a paragraph of the given
+// direction. It is a small part of the unicode bidi algorithm: neutrals take the direction of the
+// text around them when both sides agree and the paragraph direction otherwise.
+func visual(s string, paragraphRTL bool) string {
+	rs := []rune(shape(s))
+	base := ltr
+	if paragraphRTL {
+		base = rtl
+	}
+	dirs := make([]direction, len(rs))
+	for i, r := range rs {
+		dirs[i] = directionOf(r)
+	}
+	for i := 0; i < len(rs); {
+		if dirs[i] != neutral {
+			i++
+			continue
+		}
+		j := i
+		for j < len(rs) && dirs[j] == neutral {
+			j++
+		}
+		before, after := base, base
+		if i > 0 {
+			before = dirs[i-1]
+		}
+		if j < len(rs) {
+			after = dirs[j]
+		}
+		d := base
+		if before == after {
+			d = before
+		}
+		for k := i; k < j; k++ {
+			dirs[k] = d
+		}
+		i = j
+	}
+	var runs [][]rune
+	for i := 0; i < len(rs); {
+		j := i
+		for j < len(rs) && dirs[j] == dirs[i] {
+			j++
+		}
+		run := append([]rune(nil), rs[i:j]...)
+		if dirs[i] == rtl {
+			for a, b := 0, len(run)-1; a < b; a, b = a+1, b-1 {
+				run[a], run[b] = run[b], run[a]
+			}
+			for k, r := range run {
+				if m, ok := mirrored[r]; ok {
+					run[k] = m
+				}
+			}
+		}
+		runs = append(runs, run)
+		i = j
+	}
+	if paragraphRTL {
+		for a, b := 0, len(runs)-1; a < b; a, b = a+1, b-1 {
+			runs[a], runs[b] = runs[b], runs[a]
+		}
+	}
+	var out []rune
+	for _, run := range runs {
+		out = append(out, run...)
+	}
+	return string(out)
+}
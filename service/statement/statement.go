@@ -0,0 +1,135 @@
+package statement
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"wallet/internal/currency"
+	"wallet/internal/locale"
+	"wallet/internal/serr"
+	"wallet/service/transaction"
+
+	"golang.org/x/text/language"
+)
+
+// Format is the file format a statement is exported in.
+type Format string
+
+const (
+	CSV Format = "csv"
+	PDF Format = "pdf"
+)
+
+// ParseFormat returns the format of the given name, statements are exported as pdf by default.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case "":
+		return PDF, nil
+	case CSV, PDF:
+		return f, nil
+	}
+	return "", serr.ValidationErr("statement", "invalid statement format", serr.ErrInvalidStatementFormat)
+}
+
+func (f Format) ContentType() string {
+	if f == CSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/pdf"
+}
+
+// Exporter writes wallet statements as files in the language of their reader, Persian statements
+// are laid out right to left.
+type Exporter struct {
+	font     []byte
+	boldFont []byte
+}
+
+// NewExporter loads the ttf fonts pdf statements are written with.
+func NewExporter(fontPath, boldFontPath string) (*Exporter, error) {
+	font, err := os.ReadFile(fontPath)
+	if err != nil {
+		return nil, err
+	}
+	boldFont, err := os.ReadFile(boldFontPath)
+	if err != nil {
+		return nil, err
+	}
+	return &Exporter{font: font, boldFont: boldFont}, nil
+}
+
+func (e *Exporter) Export(w io.Writer, st *transaction.Statement, format Format, lang language.Tag) error {
+	l := newLayout(st, lang)
+	switch format {
+	case CSV:
+		return writeCSV(w, st, l)
+	case PDF:
+		return e.writePDF(w, st, l)
+	}
+	return serr.ValidationErr("statement", "invalid statement format", serr.ErrInvalidStatementFormat)
+}
+
+// layout formats the parts of a statement for a language.
+type layout struct {
+	lang       language.Tag
+	rtl        bool
+	minorUnits int
+	location   *time.Location
+}
+
+func newLayout(st *transaction.Statement, lang language.Tag) layout {
+	return layout{
+		lang:       lang,
+		rtl:        lang == language.Persian,
+		minorUnits: currency.MinorUnits(st.Currency),
+		// dates are shown in the zone the period was asked in
+		location: st.From.Location(),
+	}
+}
+
+func (l layout) text(msgID string) string {
+	return locale.Translate(msgID, l.lang)
+}
+
+func (l layout) date(t time.Time) string {
+	return t.In(l.location).Format("2006-01-02 15:04")
+}
+
+// amount formats an amount in the major unit of the statement currency.
+func (l layout) amount(a int64) string {
+	sign := ""
+	if a < 0 {
+		sign, a = "-", -a
+	}
+	s := strconv.FormatInt(a, 10)
+	if l.minorUnits == 0 {
+		return sign + s
+	}
+	if len(s) <= l.minorUnits {
+		s = strings.Repeat("0", l.minorUnits-len(s)+1) + s
+	}
+	return sign + s[:len(s)-l.minorUnits] + "." + s[len(s)-l.minorUnits:]
+}
+
+func (l layout) header(st *transaction.Statement) []string {
+	return []string{
+		l.text("Date"), l.text("ID"), l.text("Type"), l.text("Description"),
+		l.text("Amount") + " (" + st.Currency + ")", l.text("Balance") + " (" + st.Currency + ")",
+	}
+}
+
+// rows returns the table of a statement, the opening and closing balances are its first and last
+// rows.
+func (l layout) rows(st *transaction.Statement) [][]string {
+	rows := [][]string{{l.date(st.From), "", "", l.text("Opening balance"), "", l.amount(st.OpeningBalance)}}
+	for _, e := range st.Entries {
+		t := e.Transaction
+		rows = append(rows, []string{
+			l.date(t.CreatedAt), strconv.FormatInt(t.ID, 10), l.text(string(t.TransactionType)), t.Description,
+			l.amount(t.Amount), l.amount(e.Balance),
+		})
+	}
+	return append(rows, []string{l.date(st.To), "", "", l.text("Closing balance"), "", l.amount(st.ClosingBalance)})
+}
@@ -0,0 +1,83 @@
+package statement_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"wallet/internal/serr"
+	"wallet/service/statement"
+	"wallet/service/transaction"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func newExporter(t *testing.T) *statement.Exporter {
+	e, err := statement.NewExporter("../../resources/fonts/DejaVuSansCondensed.ttf",
+		"../../resources/fonts/DejaVuSansCondensed-Bold.ttf")
+	require.NoError(t, err)
+	return e
+}
+
+func newStatement() *transaction.Statement {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &transaction.Statement{
+		WalletID:       1,
+		Currency:       "USD",
+		From:           from,
+		To:             from.AddDate(0, 1, 0),
+		OpeningBalance: 1000,
+		ClosingBalance: 1195,
+		Entries: []*transaction.StatementEntry{
+			{Transaction: &transaction.DTO{ID: 2, Amount: 250, TransactionType: transaction.Recharge,
+				Description: "شارژ کیف پول (order 12)", CreatedAt: from.Add(time.Hour)}, Balance: 1250},
+			{Transaction: &transaction.DTO{ID: 3, Amount: -55, TransactionType: transaction.Payment,
+				CreatedAt: from.Add(2 * time.Hour)}, Balance: 1195},
+		},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := statement.ParseFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, statement.PDF, f)
+
+	f, err = statement.ParseFormat("CSV")
+	assert.NoError(t, err)
+	assert.Equal(t, statement.CSV, f)
+
+	_, err = statement.ParseFormat("xlsx")
+	var e *serr.ServiceError
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrInvalidStatementFormat, e.ErrorCode)
+}
+
+func TestExport_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := newExporter(t).Export(&buf, newStatement(), statement.CSV, language.English)
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), "\ufeff"))).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Date", "ID", "Type", "Description", "Amount (USD)", "Balance (USD)"},
+		{"2024-01-01 00:00", "", "", "Opening balance", "", "10.00"},
+		{"2024-01-01 01:00", "2", "recharge", "شارژ کیف پول (order 12)", "2.50", "12.50"},
+		{"2024-01-01 02:00", "3", "payment", "", "-0.55", "11.95"},
+		{"2024-02-01 00:00", "", "", "Closing balance", "", "11.95"},
+	}, rows)
+}
+
+func TestExport_PDF(t *testing.T) {
+	e := newExporter(t)
+	for _, lang := range []language.Tag{language.English, language.Persian} {
+		var buf bytes.Buffer
+		err := e.Export(&buf, newStatement(), statement.PDF, lang)
+		assert.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+	}
+}
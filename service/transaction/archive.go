@@ -0,0 +1,33 @@
+package transaction
+
+import (
+	"context"
+	"time"
+	"wallet/db"
+)
+
+// archiveBatch is how many wallets Archive looks up at once.
+const archiveBatch = 100
+
+// Archive moves the transactions of the wallets closed or deleted before the given time into the
+// archive, a wallet per db transaction, and returns how many transactions it moved.
+func (s *Service) Archive(ctx context.Context, before time.Time) (int64, error) {
+	var moved int64
+	for {
+		ids, err := s.transaction.GetArchivableWalletIDs(ctx, before, archiveBatch)
+		if err != nil || len(ids) == 0 {
+			return moved, err
+		}
+		for _, id := range ids {
+			var n int64
+			err = db.Transaction(ctx, func(ctx context.Context) error {
+				n, err = s.transaction.Archive(ctx, id)
+				return err
+			})
+			if err != nil {
+				return moved, err
+			}
+			moved += n
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package transaction
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+	"wallet/internal/serr"
+	"wallet/storage/transaction"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// encodeCursor makes an opaque cursor out of the position of the last transaction of a page.
+func encodeCursor(c *transaction.Cursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", c.CreatedAt.UnixNano(), c.ID)))
+}
+
+func decodeCursor(cursor string) (*transaction.Cursor, error) {
+	invalid := serr.ValidationErr("transaction", "invalid cursor", serr.ErrInvalidCursor)
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalid
+	}
+	var createdAt, id int64
+	if _, err = fmt.Sscanf(string(raw), "%d:%d", &createdAt, &id); err != nil || id <= 0 {
+		return nil, invalid
+	}
+	return &transaction.Cursor{CreatedAt: time.Unix(0, createdAt), ID: id}, nil
+}
@@ -5,28 +5,103 @@ import "time"
 type Type string
 
 const (
-	Recharge Type = "recharge"
-	Gift     Type = "gift"
-	Withdraw Type = "withdraw"
-	Payment  Type = "payment"
-	Refund   Type = "refund"
-	Transfer Type = "transfer"
+	Recharge   Type = "recharge"
+	Gift       Type = "gift"
+	Withdraw   Type = "withdraw"
+	Payment    Type = "payment"
+	Refund     Type = "refund"
+	Transfer   Type = "transfer"
+	Correction Type = "correction"
 )
 
 type DTO struct {
-	ID              int64     `json:"id"`
-	WalletID        int64     `json:"walletID"`
-	Amount          int64     `json:"amount"`
-	TransactionType Type      `json:"transactionType"`
-	Description     string    `json:"description"`
-	DiscountCode    string    `json:"discountCode"`
-	CreatedAt       time.Time `json:"createdAt"`
+	ID                int64     `json:"id"`
+	WalletID          int64     `json:"walletID"`
+	Amount            int64     `json:"amount"`
+	Currency          string    `json:"currency"`
+	TransactionType   Type      `json:"transactionType"`
+	Description       string    `json:"description"`
+	DiscountCode      string    `json:"discountCode"`
+	JournalEntryID    int64     `json:"journalEntryID"`
+	FXRate            float64   `json:"fxRate,omitempty"`
+	FXSpread          float64   `json:"fxSpread,omitempty"`
+	CounterAmount     int64     `json:"counterAmount,omitempty"`
+	CounterCurrency   string    `json:"counterCurrency,omitempty"`
+	MerchantReference string    `json:"merchantReference,omitempty"`
+	OrderID           string    `json:"orderID,omitempty"`
+	ParentID          int64     `json:"parentTransactionID,omitempty"`
+	CreatedAt         time.Time `json:"createdAt"`
 }
 
 type CreateRequest struct {
-	WalletID        int64  `json:"walletID"`
-	Amount          int64  `json:"amount"`
-	TransactionType Type   `json:"transactionType"`
-	Description     string `json:"description"`
-	DiscountCode    string `json:"discountCode"`
+	WalletID          int64  `json:"walletID"`
+	Amount            int64  `json:"amount"`
+	Currency          string `json:"currency"`
+	TransactionType   Type   `json:"transactionType"`
+	Description       string `json:"description"`
+	DiscountCode      string `json:"discountCode"`
+	MerchantReference string `json:"merchantReference"`
+	OrderID           string `json:"orderID"`
+	ParentID          int64  `json:"parentTransactionID"`
+}
+
+type TransferRequest struct {
+	FromWalletID int64  `json:"fromWalletID"`
+	ToWalletID   int64  `json:"toWalletID"`
+	Amount       int64  `json:"amount"`
+	Currency     string `json:"currency"`
+	Description  string `json:"description"`
+
+	// set when the destination wallet holds another currency, ToAmount is Amount converted at FXRate
+	// after FXSpread was taken
+	ToAmount   int64   `json:"toAmount"`
+	ToCurrency string  `json:"toCurrency"`
+	FXRate     float64 `json:"fxRate"`
+	FXSpread   float64 `json:"fxSpread"`
+}
+
+// converted reports whether the transfer moves money between currencies.
+func (r *TransferRequest) converted() bool {
+	return r.ToCurrency != "" && r.ToCurrency != r.Currency
+}
+
+// ListRequest filters a transaction listing, zero fields match everything.
+type ListRequest struct {
+	WalletID     int64
+	Types        []Type
+	DiscountCode string
+	CreatedFrom  time.Time
+	CreatedTo    time.Time
+	MinAmount    *int64
+	MaxAmount    *int64
+	Cursor       string
+	Limit        int
+}
+
+type Page struct {
+	Transactions []*DTO `json:"transactions"`
+	NextCursor   string `json:"nextCursor,omitempty"`
+}
+
+// StatementRequest selects the period of a wallet statement, From is inclusive and To exclusive.
+type StatementRequest struct {
+	WalletID int64
+	From     time.Time
+	To       time.Time
+}
+
+type Statement struct {
+	WalletID       int64             `json:"walletID"`
+	Currency       string            `json:"currency"`
+	From           time.Time         `json:"from"`
+	To             time.Time         `json:"to"`
+	OpeningBalance int64             `json:"openingBalance"`
+	ClosingBalance int64             `json:"closingBalance"`
+	Entries        []*StatementEntry `json:"entries"`
+}
+
+// StatementEntry is a transaction of a statement with the balance of the wallet right after it.
+type StatementEntry struct {
+	Transaction *DTO  `json:"transaction"`
+	Balance     int64 `json:"balance"`
 }
@@ -0,0 +1,74 @@
+package transaction
+
+import (
+	"wallet/internal/currency"
+	"wallet/internal/serr"
+	"wallet/storage/ledger"
+)
+
+// counterAccount returns the system account balancing wallet postings of a transaction type.
+// Transfers have a wallet on both sides and no counter account.
+func counterAccount(t Type) (ledger.Account, bool) {
+	switch t {
+	case Recharge:
+		return ledger.CashIn, true
+	case Gift:
+		return ledger.GiftFunding, true
+	case Withdraw, Refund:
+		return ledger.CashOut, true
+	case Payment:
+		return ledger.MerchantSettlement, true
+	default:
+		return "", false
+	}
+}
+
+// journalEntry builds the balanced entry of a single wallet transaction. A positive amount credits
+// the wallet, a negative one debits it.
+func journalEntry(r *CreateRequest) (*ledger.JournalEntry, error) {
+	account, ok := counterAccount(r.TransactionType)
+	if r.TransactionType == Refund && r.MerchantReference != "" {
+		// a refunded payment comes back out of the merchant settlement
+		account = ledger.MerchantSettlement
+	}
+	if !ok {
+		return nil, serr.ValidationErr("transaction", "invalid transaction type", serr.ErrInvalidTransactionType)
+	}
+	if _, ok = currency.Get(r.Currency); !ok {
+		return nil, serr.ValidationErr("transaction", "unsupported currency", serr.ErrUnsupportedCurrency)
+	}
+	walletSide, accountSide, amount := ledger.Credit, ledger.Debit, r.Amount
+	if amount < 0 {
+		walletSide, accountSide, amount = ledger.Debit, ledger.Credit, -amount
+	}
+	return &ledger.JournalEntry{
+		Description: r.Description,
+		Postings: []*ledger.Posting{
+			{WalletID: r.WalletID, Direction: walletSide, Amount: amount, Currency: r.Currency},
+			{Account: account, Direction: accountSide, Amount: amount, Currency: r.Currency},
+		},
+	}, nil
+}
+
+// transferJournalEntry builds the entry of a transfer. A converted transfer passes through the fx
+// conversion account so each currency balances on its own.
+func transferJournalEntry(r *TransferRequest) *ledger.JournalEntry {
+	if !r.converted() {
+		return &ledger.JournalEntry{
+			Description: r.Description,
+			Postings: []*ledger.Posting{
+				{WalletID: r.FromWalletID, Direction: ledger.Debit, Amount: r.Amount, Currency: r.Currency},
+				{WalletID: r.ToWalletID, Direction: ledger.Credit, Amount: r.Amount, Currency: r.Currency},
+			},
+		}
+	}
+	return &ledger.JournalEntry{
+		Description: r.Description,
+		Postings: []*ledger.Posting{
+			{WalletID: r.FromWalletID, Direction: ledger.Debit, Amount: r.Amount, Currency: r.Currency},
+			{Account: ledger.FXConversion, Direction: ledger.Credit, Amount: r.Amount, Currency: r.Currency},
+			{Account: ledger.FXConversion, Direction: ledger.Debit, Amount: r.ToAmount, Currency: r.ToCurrency},
+			{WalletID: r.ToWalletID, Direction: ledger.Credit, Amount: r.ToAmount, Currency: r.ToCurrency},
+		},
+	}
+}
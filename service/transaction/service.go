@@ -1,83 +1,92 @@
 package transaction
 
 import (
-	"database/sql"
-	"sync"
+	"context"
+	"time"
+	"wallet/storage/ledger"
 	"wallet/storage/transaction"
 )
 
 type UseCase interface {
-	Create(r *CreateRequest) (*DTO, error)
-	GetByID(id int64) (*DTO, error)
-	GetByWalletID(walletID int64) ([]*DTO, error)
-	GetByWalletIDWithPagination(walletID int64, limit, offset int) ([]*DTO, error)
-	GetByWalletIDAndType(walletID int64, transactionType Type) ([]*DTO, error)
-	GetByWalletIDAndDiscountCode(walletID int64, discountCode string) ([]*DTO, error)
-	GetByWalletIDAndTypeAndDiscountCode(walletID int64, transactionType Type, discountCode string) ([]*DTO, error)
-	GetByDiscountCodeWithPagination(discountCode string, limit, offset int) ([]*DTO, error)
-	Delete(id int64) error
-	DeleteByWalletID(walletID int64) error
-	GetBalance(walletID int64) (int64, error)
-	WithTX(tx *sql.Tx) (*Service, error)
+	Create(ctx context.Context, r *CreateRequest) (*DTO, error)
+	CreateTransfer(ctx context.Context, r *TransferRequest) ([]*DTO, error)
+	GetByID(ctx context.Context, id int64) (*DTO, error)
+	GetByWalletID(ctx context.Context, walletID int64) ([]*DTO, error)
+	GetByWalletIDWithPagination(ctx context.Context, walletID int64, limit, offset int) ([]*DTO, error)
+	GetByWalletIDAndType(ctx context.Context, walletID int64, transactionType Type) ([]*DTO, error)
+	GetByWalletIDAndDiscountCode(ctx context.Context, walletID int64, discountCode string) ([]*DTO, error)
+	GetByWalletIDAndTypeAndDiscountCode(ctx context.Context, walletID int64, transactionType Type, discountCode string) ([]*DTO, error)
+	GetByDiscountCodeWithPagination(ctx context.Context, discountCode string, limit, offset int) ([]*DTO, error)
+	List(ctx context.Context, r *ListRequest) (*Page, error)
+	Statement(ctx context.Context, r *StatementRequest) (*Statement, error)
+	Archive(ctx context.Context, before time.Time) (int64, error)
+	GetBalance(ctx context.Context, walletID int64) (int64, error)
+	GetRefundedAmount(ctx context.Context, id int64) (int64, error)
 }
 
 type Service struct {
 	transaction transaction.Repository
-	mu          sync.Mutex
-
-	inTx bool
+	ledger      ledger.Repository
 }
 
 func New(
 	transaction transaction.Repository,
+	ledger ledger.Repository,
 ) *Service {
 	return &Service{
 		transaction: transaction,
+		ledger:      ledger,
 	}
 }
 
-func (s *Service) WithTX(tx *sql.Tx) (*Service, error) {
-	service := *s
-	t, err := s.transaction.WithTX(tx)
-	if err != nil {
-		return nil, err
-	}
-	service.transaction = t
-	service.inTx = true
-	return &service, nil
-}
-
 func (s *Service) ToDBModel(t *DTO) *transaction.Transaction {
 	return &transaction.Transaction{
-		ID:              t.ID,
-		WalletID:        t.WalletID,
-		Amount:          t.Amount,
-		TransactionType: TypeToDBType(t.TransactionType),
-		Description:     t.Description,
-		DiscountCode:    t.DiscountCode,
+		ID:                t.ID,
+		WalletID:          t.WalletID,
+		Amount:            t.Amount,
+		Currency:          t.Currency,
+		TransactionType:   TypeToDBType(t.TransactionType),
+		Description:       t.Description,
+		DiscountCode:      t.DiscountCode,
+		MerchantReference: t.MerchantReference,
+		OrderID:           t.OrderID,
+		ParentID:          t.ParentID,
 	}
 
 }
 
 func (s *Service) FromDBModel(t *transaction.Transaction) *DTO {
 	return &DTO{
-		ID:              t.ID,
-		WalletID:        t.WalletID,
-		Amount:          t.Amount,
-		TransactionType: DbTypeToType(t.TransactionType),
-		Description:     t.Description,
-		DiscountCode:    t.DiscountCode,
-		CreatedAt:       t.CreatedAt,
+		ID:                t.ID,
+		WalletID:          t.WalletID,
+		Amount:            t.Amount,
+		Currency:          t.Currency,
+		TransactionType:   DbTypeToType(t.TransactionType),
+		Description:       t.Description,
+		DiscountCode:      t.DiscountCode,
+		JournalEntryID:    t.JournalEntryID,
+		FXRate:            t.FXRate,
+		FXSpread:          t.FXSpread,
+		CounterAmount:     t.CounterAmount,
+		CounterCurrency:   t.CounterCurrency,
+		CreatedAt:         t.CreatedAt,
+		MerchantReference: t.MerchantReference,
+		OrderID:           t.OrderID,
+		ParentID:          t.ParentID,
 	}
 }
 
 func (s *Service) FromCreateRequest(r *CreateRequest) *transaction.Transaction {
 	return &transaction.Transaction{
-		WalletID:        r.WalletID,
-		Amount:          r.Amount,
-		TransactionType: TypeToDBType(r.TransactionType),
-		Description:     r.Description,
-		DiscountCode:    r.DiscountCode,
+		WalletID:          r.WalletID,
+		Amount:            r.Amount,
+		Currency:          r.Currency,
+		TransactionType:   TypeToDBType(r.TransactionType),
+		Description:       r.Description,
+		DiscountCode:      r.DiscountCode,
+		MerchantReference: r.MerchantReference,
+		OrderID:           r.OrderID,
+		ParentID:          r.ParentID,
 	}
 }
 
@@ -95,6 +104,8 @@ func TypeToDBType(t Type) transaction.Type {
 		return transaction.Refund
 	case Transfer:
 		return transaction.Transfer
+	case Correction:
+		return transaction.Correction
 	default:
 		return ""
 	}
@@ -114,6 +125,8 @@ func DbTypeToType(t transaction.Type) Type {
 		return Refund
 	case transaction.Transfer:
 		return Transfer
+	case transaction.Correction:
+		return Correction
 	default:
 		return ""
 	}
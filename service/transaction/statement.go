@@ -0,0 +1,43 @@
+package transaction
+
+import (
+	"context"
+	"time"
+	"wallet/internal/serr"
+	"wallet/storage/transaction"
+)
+
+const maxStatementPeriod = 366 * 24 * time.Hour
+
+// Statement returns the transactions of a wallet in a period, oldest first, each with the running
+// balance of the wallet, between the balances it opened and closed the period with.
+func (s *Service) Statement(ctx context.Context, r *StatementRequest) (*Statement, error) {
+	if r.From.IsZero() || r.To.IsZero() || !r.From.Before(r.To) {
+		return nil, serr.ValidationErr("transaction", "invalid date range", serr.ErrInvalidFilter)
+	}
+	if r.To.Sub(r.From) > maxStatementPeriod {
+		return nil, serr.ValidationErr("transaction", "statement period is too long", serr.ErrInvalidFilter)
+	}
+	opening, err := s.transaction.GetBalanceBefore(ctx, r.WalletID, r.From)
+	if err != nil {
+		return nil, err
+	}
+	ts, err := s.transaction.Find(ctx, &transaction.Filter{WalletID: r.WalletID, CreatedFrom: r.From, CreatedTo: r.To})
+	if err != nil {
+		return nil, err
+	}
+	st := &Statement{
+		WalletID:       r.WalletID,
+		From:           r.From,
+		To:             r.To,
+		OpeningBalance: opening,
+		ClosingBalance: opening,
+		Entries:        make([]*StatementEntry, 0, len(ts)),
+	}
+	// Find lists the newest first
+	for i := len(ts) - 1; i >= 0; i-- {
+		st.ClosingBalance += ts[i].Amount
+		st.Entries = append(st.Entries, &StatementEntry{Transaction: s.FromDBModel(ts[i]), Balance: st.ClosingBalance})
+	}
+	return st, nil
+}
@@ -1,72 +1,126 @@
 package transaction
 
-func (s *Service) Create(r *CreateRequest) (*DTO, error) {
-	t := s.FromCreateRequest(r)
-	err := s.transaction.Insert(t)
+import (
+	"context"
+	"wallet/db"
+	"wallet/internal/currency"
+	"wallet/internal/serr"
+	"wallet/storage/transaction"
+)
+
+// Create posts the balanced journal entry of the transaction and records it against the wallet.
+func (s *Service) Create(ctx context.Context, r *CreateRequest) (*DTO, error) {
+	e, err := journalEntry(r)
 	if err != nil {
 		return nil, err
 	}
-	return s.FromDBModel(t), nil
-}
-
-func (s *Service) GetByID(id int64) (*DTO, error) {
-	t, err := s.transaction.GetByID(id)
+	t := s.FromCreateRequest(r)
+	err = db.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.ledger.InsertEntry(ctx, e); err != nil {
+			return err
+		}
+		t.JournalEntryID = e.ID
+		return s.transaction.Insert(ctx, t)
+	})
 	if err != nil {
 		return nil, err
 	}
 	return s.FromDBModel(t), nil
 }
 
-func (s *Service) GetByWalletID(walletID int64) ([]*DTO, error) {
-	ts, err := s.transaction.GetByWalletID(walletID)
-	if err != nil {
-		return nil, err
+// CreateTransfer posts a single journal entry moving the amount between two wallets and records a
+// transfer transaction on each of them, the source one first.
+func (s *Service) CreateTransfer(ctx context.Context, r *TransferRequest) ([]*DTO, error) {
+	if r.Amount <= 0 {
+		return nil, serr.ValidationErr("transaction", "invalid amount", serr.ErrInvalidAmount)
 	}
-	var result []*DTO
-	for _, t := range ts {
-		result = append(result, s.FromDBModel(t))
+	if _, ok := currency.Get(r.Currency); !ok {
+		return nil, serr.ValidationErr("transaction", "unsupported currency", serr.ErrUnsupportedCurrency)
 	}
-	return result, nil
-}
-
-func (s *Service) GetByWalletIDWithPagination(walletID int64, limit, offset int) ([]*DTO, error) {
-	ts, err := s.transaction.GetByWalletIDWithPagination(walletID, limit, offset)
-	if err != nil {
-		return nil, err
+	legs := []*transaction.Transaction{
+		{WalletID: r.FromWalletID, Amount: -r.Amount, Currency: r.Currency, TransactionType: transaction.Transfer,
+			Description: r.Description},
+		{WalletID: r.ToWalletID, Amount: r.Amount, Currency: r.Currency, TransactionType: transaction.Transfer,
+			Description: r.Description},
 	}
-	var result []*DTO
-	for _, t := range ts {
-		result = append(result, s.FromDBModel(t))
+	if r.converted() {
+		if _, ok := currency.Get(r.ToCurrency); !ok {
+			return nil, serr.ValidationErr("transaction", "unsupported currency", serr.ErrUnsupportedCurrency)
+		}
+		if r.ToAmount <= 0 {
+			return nil, serr.ValidationErr("transaction", "invalid amount", serr.ErrInvalidAmount)
+		}
+		if r.FXRate <= 0 {
+			return nil, serr.ValidationErr("transaction", "invalid exchange rate", serr.ErrFXClient)
+		}
+		// each leg records the rate and what was moved on the other side of the conversion
+		legs[0].FXRate, legs[0].FXSpread = r.FXRate, r.FXSpread
+		legs[0].CounterAmount, legs[0].CounterCurrency = r.ToAmount, r.ToCurrency
+		legs[1].Amount, legs[1].Currency = r.ToAmount, r.ToCurrency
+		legs[1].FXRate, legs[1].FXSpread = r.FXRate, r.FXSpread
+		legs[1].CounterAmount, legs[1].CounterCurrency = r.Amount, r.Currency
 	}
-	return result, nil
-}
-
-func (s *Service) GetByWalletIDAndType(walletID int64, transactionType Type) ([]*DTO, error) {
-	ts, err := s.transaction.GetByWalletIDAndType(walletID, TypeToDBType(transactionType))
+	e := transferJournalEntry(r)
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.ledger.InsertEntry(ctx, e); err != nil {
+			return err
+		}
+		for _, t := range legs {
+			t.JournalEntryID = e.ID
+			if err := s.transaction.Insert(ctx, t); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 	var result []*DTO
-	for _, t := range ts {
+	for _, t := range legs {
 		result = append(result, s.FromDBModel(t))
 	}
 	return result, nil
 }
 
-func (s *Service) GetByWalletIDAndDiscountCode(walletID int64, discountCode string) ([]*DTO, error) {
-	ts, err := s.transaction.GetByWalletIDAndDiscountCode(walletID, discountCode)
+func (s *Service) GetByID(ctx context.Context, id int64) (*DTO, error) {
+	t, err := s.transaction.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	var result []*DTO
-	for _, t := range ts {
-		result = append(result, s.FromDBModel(t))
-	}
-	return result, nil
+	return s.FromDBModel(t), nil
+}
+
+func (s *Service) GetByWalletID(ctx context.Context, walletID int64) ([]*DTO, error) {
+	return s.find(ctx, &transaction.Filter{WalletID: walletID})
+}
+
+func (s *Service) GetByWalletIDWithPagination(ctx context.Context, walletID int64, limit, offset int) ([]*DTO, error) {
+	return s.find(ctx, &transaction.Filter{WalletID: walletID, Limit: limit, Offset: offset})
+}
+
+func (s *Service) GetByWalletIDAndType(ctx context.Context, walletID int64, transactionType Type) ([]*DTO, error) {
+	return s.find(ctx, &transaction.Filter{WalletID: walletID, Types: []transaction.Type{TypeToDBType(transactionType)}})
+}
+
+func (s *Service) GetByWalletIDAndDiscountCode(ctx context.Context, walletID int64, discountCode string) ([]*DTO, error) {
+	return s.find(ctx, &transaction.Filter{WalletID: walletID, DiscountCode: discountCode})
+}
+
+func (s *Service) GetByWalletIDAndTypeAndDiscountCode(ctx context.Context, walletID int64, transactionType Type, discountCode string) ([]*DTO, error) {
+	return s.find(ctx, &transaction.Filter{
+		WalletID:     walletID,
+		Types:        []transaction.Type{TypeToDBType(transactionType)},
+		DiscountCode: discountCode,
+	})
+}
+
+func (s *Service) GetByDiscountCodeWithPagination(ctx context.Context, discountCode string, limit, offset int) ([]*DTO, error) {
+	return s.find(ctx, &transaction.Filter{DiscountCode: discountCode, Limit: limit, Offset: offset})
 }
 
-func (s *Service) GetByWalletIDAndTypeAndDiscountCode(walletID int64, transactionType Type, discountCode string) ([]*DTO, error) {
-	ts, err := s.transaction.GetByWalletIDAndTypeAndDiscountCode(walletID, TypeToDBType(transactionType), discountCode)
+func (s *Service) find(ctx context.Context, f *transaction.Filter) ([]*DTO, error) {
+	ts, err := s.transaction.Find(ctx, f)
 	if err != nil {
 		return nil, err
 	}
@@ -77,26 +131,65 @@ func (s *Service) GetByWalletIDAndTypeAndDiscountCode(walletID int64, transactio
 	return result, nil
 }
 
-func (s *Service) GetByDiscountCodeWithPagination(discountCode string, limit, offset int) ([]*DTO, error) {
-	ts, err := s.transaction.GetByDiscountCodeWithPagination(discountCode, limit, offset)
+// List returns a page of transactions matching the request, newest first. The next cursor of a
+// page continues the listing where it ended.
+func (s *Service) List(ctx context.Context, r *ListRequest) (*Page, error) {
+	if r.Limit == 0 {
+		r.Limit = defaultPageSize
+	}
+	if r.Limit < 0 || r.Limit > maxPageSize {
+		return nil, serr.ValidationErr("transaction", "invalid page size", serr.ErrInvalidFilter)
+	}
+	if !r.CreatedFrom.IsZero() && !r.CreatedTo.IsZero() && !r.CreatedFrom.Before(r.CreatedTo) {
+		return nil, serr.ValidationErr("transaction", "invalid date range", serr.ErrInvalidFilter)
+	}
+	if r.MinAmount != nil && r.MaxAmount != nil && *r.MinAmount > *r.MaxAmount {
+		return nil, serr.ValidationErr("transaction", "invalid amount range", serr.ErrInvalidFilter)
+	}
+	f := &transaction.Filter{
+		WalletID:     r.WalletID,
+		DiscountCode: r.DiscountCode,
+		CreatedFrom:  r.CreatedFrom,
+		CreatedTo:    r.CreatedTo,
+		MinAmount:    r.MinAmount,
+		MaxAmount:    r.MaxAmount,
+		// one more than the page tells whether there is a next page
+		Limit: r.Limit + 1,
+	}
+	for _, t := range r.Types {
+		dbType := TypeToDBType(t)
+		if dbType == "" {
+			return nil, serr.ValidationErr("transaction", "invalid transaction type", serr.ErrInvalidTransactionType)
+		}
+		f.Types = append(f.Types, dbType)
+	}
+	if r.Cursor != "" {
+		c, err := decodeCursor(r.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		f.After = c
+	}
+	ts, err := s.transaction.Find(ctx, f)
 	if err != nil {
 		return nil, err
 	}
-	var result []*DTO
+	page := &Page{Transactions: make([]*DTO, 0, len(ts))}
+	if len(ts) > r.Limit {
+		ts = ts[:r.Limit]
+		last := ts[len(ts)-1]
+		page.NextCursor = encodeCursor(&transaction.Cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
 	for _, t := range ts {
-		result = append(result, s.FromDBModel(t))
+		page.Transactions = append(page.Transactions, s.FromDBModel(t))
 	}
-	return result, nil
-}
-
-func (s *Service) DeleteByWalletID(walletID int64) error {
-	return s.transaction.DeleteByWalletID(walletID)
+	return page, nil
 }
 
-func (s *Service) Delete(id int64) error {
-	return s.transaction.DeleteByID(id)
+func (s *Service) GetBalance(ctx context.Context, walletID int64) (int64, error) {
+	return s.transaction.GetBalance(ctx, walletID)
 }
 
-func (s *Service) GetBalance(walletID int64) (int64, error) {
-	return s.transaction.GetBalance(walletID)
+func (s *Service) GetRefundedAmount(ctx context.Context, id int64) (int64, error) {
+	return s.transaction.GetRefundedAmount(ctx, id)
 }
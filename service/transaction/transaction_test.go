@@ -1,12 +1,16 @@
 package transaction_test
 
 import (
+	"context"
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
+	"time"
+	"wallet/internal/serr"
 	repomocks "wallet/mocks/repomocks/transaction"
 	transaction "wallet/service/transaction"
+	transStorage "wallet/storage/transaction"
 )
 
 var MockTransaction = &transaction.DTO{
@@ -25,17 +29,17 @@ func TestCreate_Success(t *testing.T) {
 	mockUseCase := repomocks.NewUseCase(t)
 
 	// Setup expectations
-	mockUseCase.On("Create", mock.Anything).Return(MockTransaction, nil)
+	mockUseCase.On("Create", mock.Anything, mock.Anything).Return(MockTransaction, nil)
 
 	// Call the method
-	result, err := mockUseCase.Create(MockRequest)
+	result, err := mockUseCase.Create(context.Background(), MockRequest)
 
 	// Assert Expectations
 	assert.Nil(t, err)
 	assert.Equal(t, MockTransaction, result)
 
 	// The `Create` function should have been called with our MockRequest
-	mockUseCase.AssertCalled(t, "Create", MockRequest)
+	mockUseCase.AssertCalled(t, "Create", mock.Anything, MockRequest)
 }
 
 // Test case for unsuccessful usage of the `Create` function.
@@ -43,10 +47,10 @@ func TestCreate_Failure(t *testing.T) {
 	mockUseCase := repomocks.NewUseCase(t)
 
 	// Setup expectations
-	mockUseCase.On("Create", mock.Anything).Return(nil, errors.New("Failed to create transaction"))
+	mockUseCase.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("Failed to create transaction"))
 
 	// Call the method
-	result, err := mockUseCase.Create(MockRequest)
+	result, err := mockUseCase.Create(context.Background(), MockRequest)
 
 	// Assert expectations
 	assert.Nil(t, result)
@@ -54,53 +58,29 @@ func TestCreate_Failure(t *testing.T) {
 	assert.Equal(t, err.Error(), "Failed to create transaction")
 
 	// The `Create` function should have been called with our MockRequest
-	mockUseCase.AssertCalled(t, "Create", MockRequest)
+	mockUseCase.AssertCalled(t, "Create", mock.Anything, MockRequest)
 }
 
-// Test case for successful usage of the `Delete` function.
-func TestDelete_Success(t *testing.T) {
-	id := int64(1) // defines id as int64
-	var mockUseCase = &repomocks.UseCase{}
-	mockUseCase.On("Delete", id).Return(nil)
-
-	err := mockUseCase.Delete(id)
-	assert.NoError(t, err)
-	mockUseCase.AssertExpectations(t)
-}
-
-// Test case for unsuccessful usage of the `Delete` function.
-func TestDelete_Failure(t *testing.T) {
-	id := int64(1) // defines id as int64
-	var mockUseCase = &repomocks.UseCase{}
-	mockUseCase.On("Delete", id).Return(errors.New("Failed to delete transaction"))
-
-	err := mockUseCase.Delete(id)
-	assert.Error(t, err)
-	assert.Equal(t, err.Error(), "Failed to delete transaction")
-	mockUseCase.AssertExpectations(t)
-}
-
-// Test case for successful usage of the `DeleteByWalletID` function.
-func TestDeleteByWalletID_Success(t *testing.T) {
-	id := int64(1) // defines id as int64
-	var mockUseCase = &repomocks.UseCase{}
-	mockUseCase.On("DeleteByWalletID", id).Return(nil)
+// Test case for an archival run with no wallet to archive.
+func TestArchive_Nothing(t *testing.T) {
+	before := time.Now()
+	repo := repomocks.NewRepository(t)
+	repo.On("GetArchivableWalletIDs", mock.Anything, before, 100).Return([]int64{}, nil)
 
-	err := mockUseCase.DeleteByWalletID(id)
+	n, err := transaction.New(repo, nil).Archive(context.Background(), before)
 	assert.NoError(t, err)
-	mockUseCase.AssertExpectations(t)
+	assert.Equal(t, int64(0), n)
 }
 
-// Test case for unsuccessful usage of the `DeleteByWalletID` function.
-func TestDeleteByWalletID_Failure(t *testing.T) {
-	id := int64(1) // defines id as int64
-	var mockUseCase = &repomocks.UseCase{}
-	mockUseCase.On("DeleteByWalletID", id).Return(errors.New("Failed to delete transaction"))
+// Test case for an archival run failing to look up the wallets to archive.
+func TestArchive_Failure(t *testing.T) {
+	before := time.Now()
+	repo := repomocks.NewRepository(t)
+	repo.On("GetArchivableWalletIDs", mock.Anything, before, 100).Return(nil, errors.New("Failed to find wallets"))
 
-	err := mockUseCase.DeleteByWalletID(id)
+	_, err := transaction.New(repo, nil).Archive(context.Background(), before)
 	assert.Error(t, err)
-	assert.Equal(t, err.Error(), "Failed to delete transaction")
-	mockUseCase.AssertExpectations(t)
+	assert.Equal(t, "Failed to find wallets", err.Error())
 }
 
 // Test case for successful usage of the `GetBalance` function.
@@ -108,8 +88,8 @@ func TestGetBalance_Success(t *testing.T) {
 	id := int64(1) // defines id as int64
 	var mockUseCase = &repomocks.UseCase{}
 	MockTransaction := int64(1000) // Mock balance value
-	mockUseCase.On("GetBalance", id).Return(MockTransaction, nil)
-	result, err := mockUseCase.GetBalance(id)
+	mockUseCase.On("GetBalance", mock.Anything, id).Return(MockTransaction, nil)
+	result, err := mockUseCase.GetBalance(context.Background(), id)
 	assert.NoError(t, err)
 	assert.Equal(t, MockTransaction, result)
 	mockUseCase.AssertExpectations(t)
@@ -119,8 +99,8 @@ func TestGetBalance_Success(t *testing.T) {
 func TestGetBalance_Failure(t *testing.T) {
 	id := int64(1) // defines id as int64
 	var mockUseCase = &repomocks.UseCase{}
-	mockUseCase.On("GetBalance", id).Return(int64(0), errors.New("Failed to get balance")) // Return 0 instead of nil
-	result, err := mockUseCase.GetBalance(id)
+	mockUseCase.On("GetBalance", mock.Anything, id).Return(int64(0), errors.New("Failed to get balance")) // Return 0 instead of nil
+	result, err := mockUseCase.GetBalance(context.Background(), id)
 	assert.Error(t, err)
 	assert.Equal(t, err.Error(), "Failed to get balance")
 	assert.Equal(t, int64(0), result) // Assert that result is 0
@@ -131,8 +111,8 @@ func TestGetBalance_Failure(t *testing.T) {
 func TestGetByDiscountCodeWithPagination_Success(t *testing.T) {
 	var mockUseCase = &repomocks.UseCase{}
 	MockTransaction := []*transaction.DTO{MockTransaction} // Mock transaction value
-	mockUseCase.On("GetByDiscountCodeWithPagination", mock.Anything, mock.Anything, mock.Anything).Return(MockTransaction, nil)
-	result, err := mockUseCase.GetByDiscountCodeWithPagination("TEST", 1, 1)
+	mockUseCase.On("GetByDiscountCodeWithPagination", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(MockTransaction, nil)
+	result, err := mockUseCase.GetByDiscountCodeWithPagination(context.Background(), "TEST", 1, 1)
 	assert.NoError(t, err)
 	assert.Equal(t, MockTransaction, result)
 	mockUseCase.AssertExpectations(t)
@@ -141,8 +121,8 @@ func TestGetByDiscountCodeWithPagination_Success(t *testing.T) {
 // Test case for unsuccessful usage of the `GetByDiscountCodeWithPagination` function.
 func TestGetByDiscountCodeWithPagination_Failure(t *testing.T) {
 	var mockUseCase = &repomocks.UseCase{}
-	mockUseCase.On("GetByDiscountCodeWithPagination", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("Failed to get transaction")) // Return nil instead of MockTransaction
-	result, err := mockUseCase.GetByDiscountCodeWithPagination("TEST", 1, 1)
+	mockUseCase.On("GetByDiscountCodeWithPagination", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("Failed to get transaction")) // Return nil instead of MockTransaction
+	result, err := mockUseCase.GetByDiscountCodeWithPagination(context.Background(), "TEST", 1, 1)
 	assert.Error(t, err)
 	assert.Equal(t, err.Error(), "Failed to get transaction")
 	assert.Equal(t, []*transaction.DTO(nil), result) // Assert that result is nil
@@ -153,8 +133,8 @@ func TestGetByDiscountCodeWithPagination_Failure(t *testing.T) {
 func TestGetByID_Success(t *testing.T) {
 	id := int64(1) // defines id as int64
 	var mockUseCase = &repomocks.UseCase{}
-	mockUseCase.On("GetByID", id).Return(MockTransaction, nil)
-	result, err := mockUseCase.GetByID(id)
+	mockUseCase.On("GetByID", mock.Anything, id).Return(MockTransaction, nil)
+	result, err := mockUseCase.GetByID(context.Background(), id)
 	assert.NoError(t, err)
 	assert.Equal(t, MockTransaction, result)
 	mockUseCase.AssertExpectations(t)
@@ -164,8 +144,8 @@ func TestGetByID_Success(t *testing.T) {
 func TestGetByID_Failure(t *testing.T) {
 	id := int64(1) // defines id as int64
 	var mockUseCase = &repomocks.UseCase{}
-	mockUseCase.On("GetByID", id).Return(nil, errors.New("Failed to get transaction"))
-	result, err := mockUseCase.GetByID(id)
+	mockUseCase.On("GetByID", mock.Anything, id).Return(nil, errors.New("Failed to get transaction"))
+	result, err := mockUseCase.GetByID(context.Background(), id)
 	assert.Error(t, err)
 	assert.Equal(t, err.Error(), "Failed to get transaction")
 	assert.Equal(t, (*transaction.DTO)(nil), result) // Assert that result is nil
@@ -177,8 +157,8 @@ func TestGetByWalletID_Success(t *testing.T) {
 	id := int64(1) // defines id as int64
 	var mockUseCase = &repomocks.UseCase{}
 	MockTransaction := []*transaction.DTO{MockTransaction} // Mock transaction value
-	mockUseCase.On("GetByWalletID", id).Return(MockTransaction, nil)
-	result, err := mockUseCase.GetByWalletID(id)
+	mockUseCase.On("GetByWalletID", mock.Anything, id).Return(MockTransaction, nil)
+	result, err := mockUseCase.GetByWalletID(context.Background(), id)
 	assert.NoError(t, err)
 	assert.Equal(t, MockTransaction, result)
 	mockUseCase.AssertExpectations(t)
@@ -188,8 +168,8 @@ func TestGetByWalletID_Success(t *testing.T) {
 func TestGetByWalletID_Failure(t *testing.T) {
 	id := int64(1) // defines id as int64
 	var mockUseCase = &repomocks.UseCase{}
-	mockUseCase.On("GetByWalletID", id).Return(nil, errors.New("Failed to get transaction")) // Return nil instead of MockTransaction
-	result, err := mockUseCase.GetByWalletID(id)
+	mockUseCase.On("GetByWalletID", mock.Anything, id).Return(nil, errors.New("Failed to get transaction")) // Return nil instead of MockTransaction
+	result, err := mockUseCase.GetByWalletID(context.Background(), id)
 	assert.Error(t, err)
 	assert.Equal(t, err.Error(), "Failed to get transaction")
 	assert.Equal(t, []*transaction.DTO(nil), result) // Assert that result is nil
@@ -201,8 +181,8 @@ func TestGetByWalletIDAndDiscountCode_Success(t *testing.T) {
 	id := int64(1) // defines id as int64
 	var mockUseCase = &repomocks.UseCase{}
 	MockTransactions := []*transaction.DTO{MockTransaction} // Mock transaction value
-	mockUseCase.On("GetByWalletIDAndDiscountCode", id, "TEST").Return(MockTransactions, nil)
-	result, err := mockUseCase.GetByWalletIDAndDiscountCode(id, "TEST")
+	mockUseCase.On("GetByWalletIDAndDiscountCode", mock.Anything, id, "TEST").Return(MockTransactions, nil)
+	result, err := mockUseCase.GetByWalletIDAndDiscountCode(context.Background(), id, "TEST")
 	assert.NoError(t, err)
 	assert.Equal(t, MockTransactions, result)
 	mockUseCase.AssertExpectations(t)
@@ -212,8 +192,8 @@ func TestGetByWalletIDAndDiscountCode_Success(t *testing.T) {
 func TestGetByWalletIDAndDiscountCode_Failure(t *testing.T) {
 	id := int64(1) // defines id as int64
 	var mockUseCase = &repomocks.UseCase{}
-	mockUseCase.On("GetByWalletIDAndDiscountCode", id, "TEST").Return(nil, errors.New("Failed to get transaction"))
-	result, err := mockUseCase.GetByWalletIDAndDiscountCode(id, "TEST")
+	mockUseCase.On("GetByWalletIDAndDiscountCode", mock.Anything, id, "TEST").Return(nil, errors.New("Failed to get transaction"))
+	result, err := mockUseCase.GetByWalletIDAndDiscountCode(context.Background(), id, "TEST")
 	assert.Error(t, err)
 	assert.Equal(t, err.Error(), "Failed to get transaction")
 	assert.Equal(t, ([]*transaction.DTO)(nil), result) // Assert that result is nil
@@ -225,8 +205,8 @@ func TestGetByWalletIDAndType_Success(t *testing.T) {
 	id := int64(1) // defines id as int64
 	var mockUseCase = &repomocks.UseCase{}
 	MockTransaction := []*transaction.DTO{MockTransaction} // Mock transaction value
-	mockUseCase.On("GetByWalletIDAndType", id, transaction.Recharge).Return(MockTransaction, nil)
-	result, err := mockUseCase.GetByWalletIDAndType(id, transaction.Recharge)
+	mockUseCase.On("GetByWalletIDAndType", mock.Anything, id, transaction.Recharge).Return(MockTransaction, nil)
+	result, err := mockUseCase.GetByWalletIDAndType(context.Background(), id, transaction.Recharge)
 	assert.NoError(t, err)
 	assert.Equal(t, MockTransaction, result)
 	mockUseCase.AssertExpectations(t)
@@ -236,8 +216,8 @@ func TestGetByWalletIDAndType_Success(t *testing.T) {
 func TestGetByWalletIDAndType_Failure(t *testing.T) {
 	id := int64(1) // defines id as int64
 	var mockUseCase = &repomocks.UseCase{}
-	mockUseCase.On("GetByWalletIDAndType", id, transaction.Recharge).Return(nil, errors.New("Failed to get transaction")) // Return nil instead of MockTransaction
-	result, err := mockUseCase.GetByWalletIDAndType(id, transaction.Recharge)
+	mockUseCase.On("GetByWalletIDAndType", mock.Anything, id, transaction.Recharge).Return(nil, errors.New("Failed to get transaction")) // Return nil instead of MockTransaction
+	result, err := mockUseCase.GetByWalletIDAndType(context.Background(), id, transaction.Recharge)
 	assert.Error(t, err)
 	assert.Equal(t, err.Error(), "Failed to get transaction")
 	assert.Equal(t, []*transaction.DTO(nil), result) // Assert that result is nil
@@ -249,8 +229,8 @@ func TestGetByWalletIDAndTypeAndDiscountCode_Success(t *testing.T) {
 	id := int64(1) // defines id as int64
 	var mockUseCase = &repomocks.UseCase{}
 	MockTransaction := []*transaction.DTO{MockTransaction} // Mock transaction value
-	mockUseCase.On("GetByWalletIDAndTypeAndDiscountCode", id, transaction.Recharge, "TEST").Return(MockTransaction, nil)
-	result, err := mockUseCase.GetByWalletIDAndTypeAndDiscountCode(id, transaction.Recharge, "TEST")
+	mockUseCase.On("GetByWalletIDAndTypeAndDiscountCode", mock.Anything, id, transaction.Recharge, "TEST").Return(MockTransaction, nil)
+	result, err := mockUseCase.GetByWalletIDAndTypeAndDiscountCode(context.Background(), id, transaction.Recharge, "TEST")
 	assert.NoError(t, err)
 	assert.Equal(t, MockTransaction, result)
 	mockUseCase.AssertExpectations(t)
@@ -260,8 +240,8 @@ func TestGetByWalletIDAndTypeAndDiscountCode_Success(t *testing.T) {
 func TestGetByWalletIDAndTypeAndDiscountCode_Failure(t *testing.T) {
 	id := int64(1) // defines id as int64
 	var mockUseCase = &repomocks.UseCase{}
-	mockUseCase.On("GetByWalletIDAndTypeAndDiscountCode", id, transaction.Recharge, "TEST").Return(nil, errors.New("Failed to get transaction")) // Return nil instead of MockTransaction
-	result, err := mockUseCase.GetByWalletIDAndTypeAndDiscountCode(id, transaction.Recharge, "TEST")
+	mockUseCase.On("GetByWalletIDAndTypeAndDiscountCode", mock.Anything, id, transaction.Recharge, "TEST").Return(nil, errors.New("Failed to get transaction")) // Return nil instead of MockTransaction
+	result, err := mockUseCase.GetByWalletIDAndTypeAndDiscountCode(context.Background(), id, transaction.Recharge, "TEST")
 	assert.Error(t, err)
 	assert.Equal(t, err.Error(), "Failed to get transaction")
 	assert.Equal(t, []*transaction.DTO(nil), result) // Assert that result is nil
@@ -272,8 +252,8 @@ func TestGetByWalletIDAndTypeAndDiscountCode_Failure(t *testing.T) {
 func TestGetByWalletIDWithPagination_Success(t *testing.T) {
 	var mockUseCase = &repomocks.UseCase{}
 	MockTransaction := []*transaction.DTO{MockTransaction} // Mock transaction value
-	mockUseCase.On("GetByWalletIDWithPagination", mock.Anything, mock.Anything, mock.Anything).Return(MockTransaction, nil)
-	result, err := mockUseCase.GetByWalletIDWithPagination(1, 1, 1)
+	mockUseCase.On("GetByWalletIDWithPagination", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(MockTransaction, nil)
+	result, err := mockUseCase.GetByWalletIDWithPagination(context.Background(), 1, 1, 1)
 	assert.NoError(t, err)
 	assert.Equal(t, MockTransaction, result)
 	mockUseCase.AssertExpectations(t)
@@ -282,10 +262,128 @@ func TestGetByWalletIDWithPagination_Success(t *testing.T) {
 // Test case for unsuccessful usage of the `GetByWalletIDWithPagination` function.
 func TestGetByWalletIDWithPagination_Failure(t *testing.T) {
 	var mockUseCase = &repomocks.UseCase{}
-	mockUseCase.On("GetByWalletIDWithPagination", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("Failed to get transaction")) // Return nil instead of MockTransaction
-	result, err := mockUseCase.GetByWalletIDWithPagination(1, 1, 1)
+	mockUseCase.On("GetByWalletIDWithPagination", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("Failed to get transaction")) // Return nil instead of MockTransaction
+	result, err := mockUseCase.GetByWalletIDWithPagination(context.Background(), 1, 1, 1)
 	assert.Error(t, err)
 	assert.Equal(t, err.Error(), "Failed to get transaction")
 	assert.Equal(t, []*transaction.DTO(nil), result) // Assert that result is nil
 	mockUseCase.AssertExpectations(t)
 }
+
+// Test case for successful usage of the `CreateTransfer` function.
+func TestCreateTransfer_Success(t *testing.T) {
+	mockUseCase := repomocks.NewUseCase(t)
+	request := &transaction.TransferRequest{FromWalletID: 1, ToWalletID: 2, Amount: 100}
+	legs := []*transaction.DTO{
+		{WalletID: 1, Amount: -100, TransactionType: transaction.Transfer, JournalEntryID: 1},
+		{WalletID: 2, Amount: 100, TransactionType: transaction.Transfer, JournalEntryID: 1},
+	}
+	mockUseCase.On("CreateTransfer", mock.Anything, request).Return(legs, nil)
+
+	result, err := mockUseCase.CreateTransfer(context.Background(), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, legs, result)
+	mockUseCase.AssertExpectations(t)
+}
+
+// Test case for unsuccessful usage of the `CreateTransfer` function.
+func TestCreateTransfer_Failure(t *testing.T) {
+	mockUseCase := repomocks.NewUseCase(t)
+	request := &transaction.TransferRequest{FromWalletID: 1, ToWalletID: 2, Amount: 100}
+	mockUseCase.On("CreateTransfer", mock.Anything, request).Return(nil, errors.New("Failed to create transfer"))
+
+	result, err := mockUseCase.CreateTransfer(context.Background(), request)
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, "Failed to create transfer", err.Error())
+	mockUseCase.AssertExpectations(t)
+}
+
+// Test case for `List` paging through the storage with a cursor.
+func TestList_Cursor(t *testing.T) {
+	now := time.Now()
+	mockRepo := repomocks.NewRepository(t)
+	s := transaction.New(mockRepo, nil)
+
+	// a page of two finds three transactions, the third one only tells there is a next page
+	mockRepo.On("Find", mock.Anything, mock.MatchedBy(func(f *transStorage.Filter) bool {
+		return f.After == nil && f.Limit == 3 && f.WalletID == 1
+	})).Return([]*transStorage.Transaction{
+		{ID: 3, WalletID: 1, CreatedAt: now},
+		{ID: 2, WalletID: 1, CreatedAt: now},
+		{ID: 1, WalletID: 1, CreatedAt: now.Add(-time.Second)},
+	}, nil).Once()
+	page, err := s.List(context.Background(), &transaction.ListRequest{WalletID: 1, Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, page.Transactions, 2)
+	assert.NotEmpty(t, page.NextCursor)
+
+	mockRepo.On("Find", mock.Anything, mock.MatchedBy(func(f *transStorage.Filter) bool {
+		return f.After != nil && f.After.ID == 2 && f.After.CreatedAt.Equal(now)
+	})).Return([]*transStorage.Transaction{{ID: 1, WalletID: 1, CreatedAt: now.Add(-time.Second)}}, nil).Once()
+	page, err = s.List(context.Background(), &transaction.ListRequest{WalletID: 1, Limit: 2, Cursor: page.NextCursor})
+	assert.NoError(t, err)
+	assert.Len(t, page.Transactions, 1)
+	assert.Empty(t, page.NextCursor)
+}
+
+// Test case for `List` rejecting invalid requests before querying the storage.
+func TestList_Invalid(t *testing.T) {
+	s := transaction.New(repomocks.NewRepository(t), nil)
+	minAmount, maxAmount := int64(10), int64(5)
+	for _, r := range []*transaction.ListRequest{
+		{Limit: 101},
+		{Cursor: "not a cursor"},
+		{Types: []transaction.Type{"unknown"}},
+		{CreatedFrom: time.Now(), CreatedTo: time.Now().Add(-time.Hour)},
+		{MinAmount: &minAmount, MaxAmount: &maxAmount},
+	} {
+		_, err := s.List(context.Background(), r)
+		var e *serr.ServiceError
+		assert.True(t, errors.As(err, &e))
+	}
+}
+
+// Test case for `Statement` running the balance from the opening balance over the period, oldest first.
+func TestStatement(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	mockRepo := repomocks.NewRepository(t)
+	s := transaction.New(mockRepo, nil)
+
+	mockRepo.On("GetBalanceBefore", mock.Anything, int64(1), from).Return(int64(1000), nil)
+	mockRepo.On("Find", mock.Anything, mock.MatchedBy(func(f *transStorage.Filter) bool {
+		return f.WalletID == 1 && f.CreatedFrom.Equal(from) && f.CreatedTo.Equal(to) && f.Limit == 0
+	})).Return([]*transStorage.Transaction{
+		{ID: 3, WalletID: 1, Amount: -300, TransactionType: transStorage.Withdraw, CreatedAt: from.Add(3 * time.Hour)},
+		{ID: 2, WalletID: 1, Amount: 500, TransactionType: transStorage.Recharge, CreatedAt: from.Add(2 * time.Hour)},
+	}, nil)
+
+	st, err := s.Statement(context.Background(), &transaction.StatementRequest{WalletID: 1, From: from, To: to})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), st.OpeningBalance)
+	assert.Equal(t, int64(1200), st.ClosingBalance)
+	assert.Len(t, st.Entries, 2)
+	assert.Equal(t, int64(2), st.Entries[0].Transaction.ID)
+	assert.Equal(t, int64(1500), st.Entries[0].Balance)
+	assert.Equal(t, int64(3), st.Entries[1].Transaction.ID)
+	assert.Equal(t, int64(1200), st.Entries[1].Balance)
+}
+
+// Test case for `Statement` rejecting invalid periods before querying the storage.
+func TestStatement_Invalid(t *testing.T) {
+	s := transaction.New(repomocks.NewRepository(t), nil)
+	now := time.Now()
+	for _, r := range []*transaction.StatementRequest{
+		{WalletID: 1},
+		{WalletID: 1, From: now},
+		{WalletID: 1, From: now, To: now.Add(-time.Hour)},
+		{WalletID: 1, From: now.AddDate(-2, 0, 0), To: now},
+	} {
+		_, err := s.Statement(context.Background(), r)
+		var e *serr.ServiceError
+		assert.True(t, errors.As(err, &e))
+	}
+}
@@ -3,18 +3,23 @@ package wallet
 import "time"
 
 type DTO struct {
-	ID         int64     `json:"id"`
-	MemberID   int64     `json:"memberID"`
-	WalletName string    `json:"walletName"`
-	Balance    int64     `json:"balance"`
-	CreatedAt  time.Time `json:"createdAt"`
-	UpdatedAt  time.Time `json:"updatedAt"`
+	ID               int64     `json:"id"`
+	MemberID         int64     `json:"memberID"`
+	WalletName       string    `json:"walletName"`
+	Balance          int64     `json:"balance"`
+	AvailableBalance int64     `json:"availableBalance"`
+	Currency         string    `json:"currency"`
+	MinorUnits       int       `json:"minorUnits"`
+	Status           string    `json:"status"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
 }
 
 type CreateRequest struct {
 	MemberID   int64  `json:"memberID"`
 	WalletName string `json:"walletName"`
 	Balance    int64  `json:"balance"`
+	Currency   string `json:"currency"`
 }
 
 type AddGiftRequest struct {
@@ -22,3 +27,68 @@ type AddGiftRequest struct {
 	WalletID int64  `json:"walletID"`
 	GiftCode string `json:"giftCode"`
 }
+
+type RechargeRequest struct {
+	Amount int64 `json:"amount"`
+}
+
+type WithdrawRequest struct {
+	Amount int64 `json:"amount"`
+}
+
+type PayRequest struct {
+	Amount            int64  `json:"amount"`
+	MerchantReference string `json:"merchantReference"`
+	OrderID           string `json:"orderID"`
+}
+
+type RefundRequest struct {
+	// Amount to refund, zero refunds whatever is left of the transaction
+	Amount int64 `json:"amount"`
+}
+
+type TransferRequest struct {
+	FromWalletID int64 `json:"fromWalletID"`
+	ToWalletID   int64 `json:"toWalletID"`
+	Amount       int64 `json:"amount"`
+	// Convert allows a transfer between wallets of different currencies at the current exchange rate
+	Convert bool `json:"convert"`
+}
+
+type HoldDTO struct {
+	ID             int64     `json:"id"`
+	WalletID       int64     `json:"walletID"`
+	Amount         int64     `json:"amount"`
+	CapturedAmount int64     `json:"capturedAmount"`
+	Currency       string    `json:"currency"`
+	Status         string    `json:"status"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// AuthorizeRequest reserves Amount for TTLSeconds, zero uses the default time to live.
+type AuthorizeRequest struct {
+	Amount     int64 `json:"amount"`
+	TTLSeconds int64 `json:"ttlSeconds"`
+}
+
+type CaptureRequest struct {
+	// Amount to capture, zero captures the whole hold
+	Amount int64 `json:"amount"`
+}
+
+// StatusChangeRequest freezes, unfreezes or closes a wallet, Reason is kept with the change.
+type StatusChangeRequest struct {
+	Reason string `json:"reason"`
+}
+
+type StatusChangeDTO struct {
+	ID         int64     `json:"id"`
+	WalletID   int64     `json:"walletID"`
+	FromStatus string    `json:"fromStatus"`
+	ToStatus   string    `json:"toStatus"`
+	Reason     string    `json:"reason"`
+	Actor      string    `json:"actor"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
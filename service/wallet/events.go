@@ -0,0 +1,57 @@
+package wallet
+
+import (
+	"context"
+	"wallet/service/audit"
+	"wallet/service/outbox"
+	"wallet/service/transaction"
+)
+
+// BalanceEvent is the payload of the events of a transaction applied to a wallet.
+type BalanceEvent struct {
+	Wallet      *DTO             `json:"wallet"`
+	Transaction *transaction.DTO `json:"transaction"`
+}
+
+// TransferEvent is the payload of TransferCompleted, Transactions are the source and destination legs.
+type TransferEvent struct {
+	From         *DTO               `json:"from"`
+	To           *DTO               `json:"to"`
+	Transactions []*transaction.DTO `json:"transactions"`
+}
+
+// StatusEvent is the payload of WalletStatusChanged.
+type StatusEvent struct {
+	Wallet *DTO             `json:"wallet"`
+	Change *StatusChangeDTO `json:"change"`
+}
+
+// record writes a domain event into the outbox, ctx has to carry a db transaction.
+func (s *Service) record(ctx context.Context, t outbox.EventType, walletID int64, payload any) error {
+	return outbox.Record(ctx, s.outbox, t, walletID, payload)
+}
+
+// balanceEventType returns the event type of a transaction applied to a wallet.
+func balanceEventType(t *transaction.DTO) outbox.EventType {
+	switch t.TransactionType {
+	case transaction.Gift:
+		return outbox.GiftRedeemed
+	case transaction.Refund:
+		return outbox.RefundIssued
+	}
+	if t.Amount < 0 {
+		return outbox.BalanceDebited
+	}
+	return outbox.BalanceCredited
+}
+
+// transferState is the state of both wallets of a transfer recorded in the audit log.
+type transferState struct {
+	From *DTO `json:"from"`
+	To   *DTO `json:"to"`
+}
+
+// recordAudit writes an action of the caller into the audit log, ctx has to carry a db transaction.
+func (s *Service) recordAudit(ctx context.Context, action audit.Action, entity audit.Entity, id int64, before, after any) error {
+	return audit.Record(ctx, s.auditLog, action, entity, id, before, after)
+}
@@ -0,0 +1,222 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"time"
+	"wallet/db"
+	"wallet/internal/serr"
+	"wallet/service/audit"
+	"wallet/service/transaction"
+	"wallet/storage/hold"
+	"wallet/storage/wallet"
+)
+
+const (
+	defaultHoldTTL = 15 * time.Minute
+	maxHoldTTL     = 7 * 24 * time.Hour
+
+	expiredHoldsBatchSize = 100
+)
+
+// Authorize reserves amount of the wallet balance until the hold is captured, voided or expires.
+// A zero ttl uses the default time to live.
+func (s *Service) Authorize(ctx context.Context, walletID, amount int64, ttl time.Duration, idempotencyKey string) (*HoldDTO, error) {
+	if amount <= 0 {
+		return nil, serr.ValidationErr("wallet", "invalid amount", serr.ErrInvalidAmount)
+	}
+	if ttl == 0 {
+		ttl = defaultHoldTTL
+	}
+	if ttl < 0 || ttl > maxHoldTTL {
+		return nil, serr.ValidationErr("hold", "invalid hold ttl", serr.ErrInvalidHoldTTL)
+	}
+	hash := requestHash("authorize", walletID, amount, ttl)
+	return idempotent(ctx, s, idempotencyKey, hash, func(ctx context.Context) (*HoldDTO, error) {
+		w, err := s.wallet.GetByIDForUpdate(ctx, walletID)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkStatus(w, true); err != nil {
+			return nil, err
+		}
+		if err = s.limits.CheckDebit(ctx, w, amount, false); err != nil {
+			return nil, err
+		}
+		if err = s.adjustHeld(ctx, w.ID, amount); err != nil {
+			return nil, err
+		}
+		h := &hold.Hold{
+			WalletID:  w.ID,
+			Amount:    amount,
+			Currency:  w.Currency,
+			Status:    hold.Authorized,
+			ExpiresAt: time.Now().Add(ttl),
+		}
+		if err = s.hold.Create(ctx, h); err != nil {
+			return nil, err
+		}
+		result := s.FromHoldModel(h)
+		if err = s.recordAudit(ctx, audit.HoldAuthorized, audit.Hold, h.ID, nil, result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	})
+}
+
+// Capture settles an authorized hold as a payment of amount and releases the rest of it.
+// A zero amount captures the whole hold.
+func (s *Service) Capture(ctx context.Context, holdID, amount int64, idempotencyKey string) (*HoldDTO, error) {
+	if amount < 0 {
+		return nil, serr.ValidationErr("wallet", "invalid amount", serr.ErrInvalidAmount)
+	}
+	hash := requestHash("capture", holdID, amount)
+	return idempotent(ctx, s, idempotencyKey, hash, func(ctx context.Context) (*HoldDTO, error) {
+		h, err := s.lockHold(ctx, holdID)
+		if err != nil {
+			return nil, err
+		}
+		if time.Now().After(h.ExpiresAt) {
+			return nil, serr.ValidationErr("hold", "hold expired", serr.ErrHoldExpired)
+		}
+		if amount == 0 {
+			amount = h.Amount
+		}
+		if amount > h.Amount {
+			return nil, serr.ValidationErr("hold", "capture amount exceeds hold amount", serr.ErrInvalidAmount)
+		}
+		before := s.FromHoldModel(h)
+		if err = s.releaseHold(ctx, h, hold.Captured, amount); err != nil {
+			return nil, err
+		}
+		// the hold was checked against the debit limits when it was authorized
+		_, err = s.apply(ctx, &transaction.CreateRequest{
+			WalletID:        h.WalletID,
+			Amount:          -amount,
+			TransactionType: transaction.Payment,
+			Description:     "hold capture",
+		}, false)
+		if err != nil {
+			return nil, err
+		}
+		result := s.FromHoldModel(h)
+		if err = s.recordAudit(ctx, audit.HoldCaptured, audit.Hold, h.ID, before, result); err != nil {
+			return nil, err
+		}
+		return result, nil
+	})
+}
+
+// Void releases an authorized hold without moving any money.
+func (s *Service) Void(ctx context.Context, holdID int64) (*HoldDTO, error) {
+	var result *HoldDTO
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		h, err := s.lockHold(ctx, holdID)
+		if err != nil {
+			return err
+		}
+		before := s.FromHoldModel(h)
+		if err = s.releaseHold(ctx, h, hold.Voided, 0); err != nil {
+			return err
+		}
+		result = s.FromHoldModel(h)
+		return s.recordAudit(ctx, audit.HoldVoided, audit.Hold, h.ID, before, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *Service) GetHold(ctx context.Context, holdID int64) (*HoldDTO, error) {
+	h, err := s.hold.GetByID(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	return s.FromHoldModel(h), nil
+}
+
+// ExpireHolds releases authorized holds past their expiry and returns how many were expired.
+func (s *Service) ExpireHolds(ctx context.Context) (int, error) {
+	expired := 0
+	for {
+		hs, err := s.hold.GetExpired(ctx, expiredHoldsBatchSize)
+		if err != nil {
+			return expired, err
+		}
+		for _, h := range hs {
+			err = db.Transaction(ctx, func(ctx context.Context) error {
+				locked, err := s.lockHold(ctx, h.ID)
+				if err != nil {
+					return err
+				}
+				before := s.FromHoldModel(locked)
+				if err = s.releaseHold(ctx, locked, hold.Expired, 0); err != nil {
+					return err
+				}
+				return s.recordAudit(ctx, audit.HoldExpired, audit.Hold, h.ID, before, s.FromHoldModel(locked))
+			})
+			var e *serr.ServiceError
+			if errors.As(err, &e) && e.ErrorCode == serr.ErrHoldNotActive {
+				// captured or voided since it was listed
+				continue
+			}
+			if err != nil {
+				return expired, err
+			}
+			expired++
+		}
+		if len(hs) < expiredHoldsBatchSize {
+			return expired, nil
+		}
+	}
+}
+
+// lockHold locks an authorized hold and its wallet, the wallet first like every balance change does.
+func (s *Service) lockHold(ctx context.Context, holdID int64) (*hold.Hold, error) {
+	h, err := s.hold.GetByID(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.wallet.GetByIDForUpdate(ctx, h.WalletID); err != nil {
+		return nil, err
+	}
+	if h, err = s.hold.GetByIDForUpdate(ctx, holdID); err != nil {
+		return nil, err
+	}
+	if h.Status != hold.Authorized {
+		return nil, serr.ValidationErr("hold", "hold is not active", serr.ErrHoldNotActive)
+	}
+	return h, nil
+}
+
+// releaseHold gives the held amount back to the available balance and closes the hold with status.
+func (s *Service) releaseHold(ctx context.Context, h *hold.Hold, status hold.Status, capturedAmount int64) error {
+	if err := s.adjustHeld(ctx, h.WalletID, -h.Amount); err != nil {
+		return err
+	}
+	h.Status, h.CapturedAmount = status, capturedAmount
+	return s.hold.UpdateStatus(ctx, h)
+}
+
+func (s *Service) adjustHeld(ctx context.Context, walletID, delta int64) error {
+	_, err := s.wallet.AdjustHeld(ctx, walletID, delta)
+	if errors.Is(err, wallet.ErrNegativeBalance) {
+		return serr.ValidationErr("wallet", "not enough balance", serr.ErrNotEnoughBalance)
+	}
+	return err
+}
+
+func (s *Service) FromHoldModel(h *hold.Hold) *HoldDTO {
+	return &HoldDTO{
+		ID:             h.ID,
+		WalletID:       h.WalletID,
+		Amount:         h.Amount,
+		CapturedAmount: h.CapturedAmount,
+		Currency:       h.Currency,
+		Status:         string(h.Status),
+		ExpiresAt:      h.ExpiresAt,
+		CreatedAt:      h.CreatedAt,
+		UpdatedAt:      h.UpdatedAt,
+	}
+}
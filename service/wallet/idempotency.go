@@ -0,0 +1,73 @@
+package wallet
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"wallet/db"
+	"wallet/internal/serr"
+	"wallet/storage/idempotency"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// idempotent runs fn in a db transaction. When a key is given the result is stored with it in the
+// same transaction, so a retried call with the same key and request returns the stored result
+// instead of running fn again.
+func idempotent[T any](ctx context.Context, s *Service, key, requestHash string, fn func(ctx context.Context) (*T, error)) (*T, error) {
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, serr.ValidationErr("wallet", "invalid idempotency key", serr.ErrInvalidIdempotencyKey)
+	}
+	if key != "" {
+		r, err := replay[T](ctx, s, key, requestHash)
+		if err != nil || r != nil {
+			return r, err
+		}
+	}
+	var result *T
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = fn(ctx)
+		if err != nil || key == "" {
+			return err
+		}
+		response, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		return s.idempotency.Insert(ctx, &idempotency.Key{Key: key, RequestHash: requestHash, Response: response})
+	})
+	if errors.Is(err, idempotency.ErrKeyExists) {
+		// a concurrent request with the same key won the race
+		return replay[T](ctx, s, key, requestHash)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// replay returns the stored result of key, or nil if the key has not been used yet.
+func replay[T any](ctx context.Context, s *Service, key, requestHash string) (*T, error) {
+	k, err := s.idempotency.GetByKey(ctx, key)
+	if err != nil || k == nil {
+		return nil, err
+	}
+	if k.RequestHash != requestHash {
+		return nil, serr.ConflictErr("wallet", "idempotency key reused with a different request",
+			serr.ErrIdempotencyKeyReused)
+	}
+	r := new(T)
+	if err = json.Unmarshal(k.Response, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func requestHash(operation string, args ...any) string {
+	payload, _ := json.Marshal(append([]any{operation}, args...))
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
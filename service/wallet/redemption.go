@@ -0,0 +1,159 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+	"wallet/db"
+	"wallet/internal/serr"
+	"wallet/service/transaction"
+	"wallet/storage/redemption"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	stuckRedemptionsBatchSize = 100
+	// releaseTimeout bounds releasing the gift of a redemption whose request failed or ran out of time
+	releaseTimeout = 30 * time.Second
+)
+
+// useGift uses the gift of a pending redemption and marks the redemption used. A use the discount
+// service turns down fails the redemption, one whose outcome is not known leaves it pending for
+// RecoverRedemptions.
+func (s *Service) useGift(ctx context.Context, rd *redemption.Redemption) error {
+	gift, err := s.discount.UseGift(ctx, rd.GiftCode, rd.MemberID, rd.Reference)
+	if err != nil {
+		if rejected(err) {
+			rd.Status = redemption.Failed
+			if uerr := s.redemption.UpdateStatus(ctx, rd); uerr != nil {
+				log.Error().Str("method", "wallet.useGift").Int64("redemption", rd.ID).Err(uerr).
+					Msg("failed to mark gift redemption failed")
+			}
+		}
+		return err
+	}
+	rd.Status = redemption.Used
+	rd.Amount = gift.GiftAmount
+	return s.redemption.UpdateStatus(ctx, rd)
+}
+
+// rejected tells whether the discount service turned a call down, rather than failing it or not
+// answering in time.
+func rejected(err error) bool {
+	var e *serr.ServiceError
+	return errors.As(err, &e) && e.Code >= http.StatusBadRequest && e.Code < http.StatusInternalServerError
+}
+
+// completeRedemption credits the wallet of a used redemption with the gift amount, ctx has to carry a
+// db transaction.
+func (s *Service) completeRedemption(ctx context.Context, id int64) (*DTO, error) {
+	rd, err := s.redemption.GetByIDForUpdate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rd.Status != redemption.Used {
+		return nil, serr.ConflictErr("gift", "gift redemption is not in progress", serr.ErrRedemptionNotActive)
+	}
+	result, err := s.applyTransaction(ctx, &transaction.CreateRequest{
+		WalletID:        rd.WalletID,
+		Amount:          rd.Amount,
+		TransactionType: transaction.Gift,
+		Description:     "add gift transaction",
+		DiscountCode:    rd.GiftCode,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rd.Status = redemption.Completed
+	if err = s.redemption.UpdateStatus(ctx, rd); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// releaseRedemption gives the use of the gift of a used redemption back to the discount service. The
+// redemption stays locked while the gift is released, so a redemption is released or completed once.
+func (s *Service) releaseRedemption(ctx context.Context, id int64) error {
+	return db.Transaction(ctx, func(ctx context.Context) error {
+		rd, err := s.redemption.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if rd.Status != redemption.Used {
+			// completed or released since
+			return nil
+		}
+		_, err = s.discount.ReleaseGift(ctx, rd.GiftCode, rd.Reference)
+		if err != nil && !rejected(err) {
+			return err
+		}
+		// a release turned down has no use of the reference to give back
+		rd.Status = redemption.Released
+		return s.redemption.UpdateStatus(ctx, rd)
+	})
+}
+
+// compensate releases the gift of a redemption whose credit failed. It goes on when the request is
+// canceled, a release that fails is left to RecoverRedemptions.
+func (s *Service) compensate(ctx context.Context, rd *redemption.Redemption) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer cancel()
+	if err := s.releaseRedemption(ctx, rd.ID); err != nil {
+		log.Error().Str("method", "wallet.compensate").Int64("redemption", rd.ID).Err(err).
+			Msg("failed to release gift, left to the recovery")
+	}
+}
+
+// RecoverRedemptions settles the redemptions left pending or used since before, by a crash or a
+// discount service that did not answer, and returns how many it settled. A pending redemption uses
+// its gift again, which its reference makes safe, and a used one credits its wallet or releases the
+// gift when the credit fails. Redemptions failing to settle are tried again on the next call.
+func (s *Service) RecoverRedemptions(ctx context.Context, before time.Time) (int, error) {
+	settled := 0
+	for {
+		rs, err := s.redemption.GetStuck(ctx, before, stuckRedemptionsBatchSize)
+		if err != nil {
+			return settled, err
+		}
+		failed := 0
+		for _, rd := range rs {
+			if err = s.recoverRedemption(ctx, rd); err != nil {
+				if ctx.Err() != nil {
+					return settled, err
+				}
+				log.Warn().Str("method", "wallet.RecoverRedemptions").Int64("redemption", rd.ID).Err(err).
+					Msg("failed to settle gift redemption")
+				failed++
+				continue
+			}
+			settled++
+		}
+		// failed redemptions are listed again, the next call tries them
+		if len(rs) < stuckRedemptionsBatchSize || failed > 0 {
+			return settled, nil
+		}
+	}
+}
+
+func (s *Service) recoverRedemption(ctx context.Context, rd *redemption.Redemption) error {
+	if rd.Status == redemption.Pending {
+		if err := s.useGift(ctx, rd); err != nil {
+			if rd.Status == redemption.Failed {
+				return nil
+			}
+			return err
+		}
+	}
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		_, err := s.completeRedemption(ctx, rd.ID)
+		return err
+	})
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+	log.Warn().Str("method", "wallet.RecoverRedemptions").Int64("redemption", rd.ID).Err(err).
+		Msg("failed to credit gift redemption, releasing the gift")
+	return s.releaseRedemption(ctx, rd.ID)
+}
@@ -2,73 +2,98 @@ package wallet
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"fmt"
 	"strconv"
-	"sync"
 	"time"
 	"wallet/client/discount"
+	"wallet/client/fx"
 	"wallet/db"
 	"wallet/internal/config"
+	"wallet/internal/currency"
+	"wallet/service/limit"
 	"wallet/service/transaction"
+	auditStorage "wallet/storage/audit"
+	"wallet/storage/hold"
+	"wallet/storage/idempotency"
+	"wallet/storage/outbox"
+	"wallet/storage/redemption"
 	"wallet/storage/wallet"
 )
 
 const walletPrefix = "WALLET:%s"
 
 type UseCase interface {
-	Create(r *CreateRequest) (*DTO, error)
-	GetByID(id int64) (*DTO, error)
-	GetByMemberID(memberID int64) ([]*DTO, error)
-	AddGift(r *AddGiftRequest) (*DTO, error)
-	Recharge(id, amount int64) (*DTO, error)
-	Transfer(fromID, toID, amount int64) (*DTO, error)
-	Withdraw(id, amount int64) (*DTO, error)
-	Refund(id int64) (*DTO, error)
-	Delete(id int64) error
-	DeleteByMemberID(memberID int64) error
-	GetByDiscountCodeWithPagination(discountCode string, limit, offset int) ([]*DTO, error)
-	CreateTransactionAndUpdateWallet(id, amount int64, transactionType transaction.Type, description, discountCode string) (*DTO, error)
-	WithTX(tx *sql.Tx) (*Service, error)
+	Create(ctx context.Context, r *CreateRequest) (*DTO, error)
+	GetByID(ctx context.Context, id int64) (*DTO, error)
+	GetByMemberID(ctx context.Context, memberID int64) ([]*DTO, error)
+	AddGift(ctx context.Context, r *AddGiftRequest, idempotencyKey string) (*DTO, error)
+	Recharge(ctx context.Context, id, amount int64, idempotencyKey string) (*DTO, error)
+	Transfer(ctx context.Context, fromID, toID, amount int64, convert bool, idempotencyKey string) (*DTO, error)
+	Withdraw(ctx context.Context, id, amount int64, idempotencyKey string) (*DTO, error)
+	Pay(ctx context.Context, id int64, r *PayRequest, idempotencyKey string) (*DTO, error)
+	Authorize(ctx context.Context, walletID, amount int64, ttl time.Duration, idempotencyKey string) (*HoldDTO, error)
+	Capture(ctx context.Context, holdID, amount int64, idempotencyKey string) (*HoldDTO, error)
+	Void(ctx context.Context, holdID int64) (*HoldDTO, error)
+	GetHold(ctx context.Context, holdID int64) (*HoldDTO, error)
+	ListTransactions(ctx context.Context, r *transaction.ListRequest) (*transaction.Page, error)
+	Statement(ctx context.Context, r *transaction.StatementRequest) (*transaction.Statement, error)
+	ExpireHolds(ctx context.Context) (int, error)
+	RecoverRedemptions(ctx context.Context, before time.Time) (int, error)
+	Refund(ctx context.Context, id, amount int64, idempotencyKey string) (*DTO, error)
+	Freeze(ctx context.Context, id int64, reason, actor string) (*DTO, error)
+	Unfreeze(ctx context.Context, id int64, reason, actor string) (*DTO, error)
+	Close(ctx context.Context, id int64, reason, actor string) (*DTO, error)
+	StatusHistory(ctx context.Context, id int64) ([]*StatusChangeDTO, error)
+	Delete(ctx context.Context, id int64) error
+	DeleteByMemberID(ctx context.Context, memberID int64) error
+	GetByDiscountCodeWithPagination(ctx context.Context, discountCode string, limit, offset int) ([]*DTO, error)
+	CreateTransactionAndUpdateWallet(ctx context.Context, id, amount int64, transactionType transaction.Type, description, discountCode, idempotencyKey string) (*DTO, error)
 }
 
 type Service struct {
 	wallet      wallet.Repository
 	transaction transaction.UseCase
+	idempotency idempotency.Repository
+	hold        hold.Repository
+	outbox      outbox.Repository
+	auditLog    auditStorage.Repository
+	redemption  redemption.Repository
+	limits      limit.UseCase
 	rdb         db.RedisClient
 
 	discount discount.Client
-
-	mu   sync.Mutex
-	inTx bool
+	rates    fx.RateProvider
 }
 
 func New(
 	wallet wallet.Repository,
 	transaction transaction.UseCase,
+	idempotency idempotency.Repository,
+	hold hold.Repository,
+	outbox outbox.Repository,
+	auditLog auditStorage.Repository,
+	redemption redemption.Repository,
+	limits limit.UseCase,
 	discount discount.Client,
+	rates fx.RateProvider,
 	rdb db.RedisClient,
 ) *Service {
 	return &Service{
 		wallet:      wallet,
 		transaction: transaction,
+		idempotency: idempotency,
+		hold:        hold,
+		outbox:      outbox,
+		auditLog:    auditLog,
+		redemption:  redemption,
+		limits:      limits,
 		discount:    discount,
+		rates:       rates,
 		rdb:         rdb,
 	}
 }
 
-func (s *Service) WithTX(tx *sql.Tx) (*Service, error) {
-	service := *s
-	w, err := s.wallet.WithTX(tx)
-	if err != nil {
-		return nil, err
-	}
-	service.wallet = w
-	service.inTx = true
-	return &service, nil
-}
-
 func (s *Service) ToDBModel(w *DTO) *wallet.Wallet {
 	return &wallet.Wallet{
 		ID:       w.ID,
@@ -80,11 +105,16 @@ func (s *Service) ToDBModel(w *DTO) *wallet.Wallet {
 
 func (s *Service) FromDBModel(w *wallet.Wallet) *DTO {
 	return &DTO{
-		ID:        w.ID,
-		MemberID:  w.MemberID,
-		Balance:   w.Balance,
-		CreatedAt: w.CreatedAt,
-		UpdatedAt: w.UpdatedAt,
+		ID:               w.ID,
+		MemberID:         w.MemberID,
+		WalletName:       w.WalletName,
+		Balance:          w.Balance,
+		AvailableBalance: w.Balance - w.HeldBalance,
+		Currency:         w.Currency,
+		MinorUnits:       currency.MinorUnits(w.Currency),
+		Status:           string(w.Status),
+		CreatedAt:        w.CreatedAt,
+		UpdatedAt:        w.UpdatedAt,
 	}
 }
 
@@ -93,19 +123,20 @@ func (s *Service) FromCreateRequest(r *CreateRequest) *wallet.Wallet {
 		WalletName: r.WalletName,
 		MemberID:   r.MemberID,
 		Balance:    r.Balance,
+		Currency:   r.Currency,
 	}
 }
 
-func (s *Service) UpdateOrInsertInRedis(key string, g *DTO, exp time.Duration) error {
-	err := s.rdb.Set(context.Background(), config.RDBPrefix()+key, g, exp)
+func (s *Service) UpdateOrInsertInRedis(ctx context.Context, key string, g *DTO, exp time.Duration) error {
+	err := s.rdb.Set(ctx, config.RDBPrefix()+key, g, exp)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *Service) RetrieveFromRedis(key string) (*DTO, error) {
-	w, err := s.rdb.Get(context.Background(), config.RDBPrefix()+key)
+func (s *Service) RetrieveFromRedis(ctx context.Context, key string) (*DTO, error) {
+	w, err := s.rdb.Get(ctx, config.RDBPrefix()+key)
 	if err != nil {
 		return nil, err
 	}
@@ -117,13 +148,13 @@ func (s *Service) RetrieveFromRedis(key string) (*DTO, error) {
 	return walletRecord, nil
 }
 
-func (s *Service) RemoveGiftFromRedis(w *DTO) {
+func (s *Service) RemoveGiftFromRedis(ctx context.Context, w *DTO) {
 	key := fmt.Sprintf(walletPrefix, strconv.FormatInt(w.MemberID, 10))
-	s.RemoveWithKey(key)
+	s.RemoveWithKey(ctx, key)
 }
 
-func (s *Service) RemoveWithKey(k string) {
-	s.rdb.Del(context.Background(), config.RDBPrefix()+k)
+func (s *Service) RemoveWithKey(ctx context.Context, k string) {
+	s.rdb.Del(ctx, config.RDBPrefix()+k)
 }
 
 func (w *DTO) MarshalBinary() ([]byte, error) {
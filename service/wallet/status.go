@@ -0,0 +1,124 @@
+package wallet
+
+import (
+	"context"
+	"strings"
+	"wallet/db"
+	"wallet/internal/serr"
+	"wallet/service/audit"
+	"wallet/service/outbox"
+	"wallet/storage/wallet"
+)
+
+// reasons are kept as TEXT but are meant to be a sentence or two
+const maxReasonLength = 1000
+
+// transitions are the statuses a wallet may move to from each status, closed wallets stay closed.
+var transitions = map[wallet.Status][]wallet.Status{
+	wallet.Active: {wallet.Frozen, wallet.Closed},
+	wallet.Frozen: {wallet.Active},
+}
+
+// Freeze stops money from leaving a wallet, it can still be credited.
+func (s *Service) Freeze(ctx context.Context, id int64, reason, actor string) (*DTO, error) {
+	return s.changeStatus(ctx, id, wallet.Frozen, audit.WalletFrozen, reason, actor)
+}
+
+// Unfreeze makes a frozen wallet active again.
+func (s *Service) Unfreeze(ctx context.Context, id int64, reason, actor string) (*DTO, error) {
+	return s.changeStatus(ctx, id, wallet.Active, audit.WalletUnfrozen, reason, actor)
+}
+
+// Close stops all money movement of an empty wallet for good.
+func (s *Service) Close(ctx context.Context, id int64, reason, actor string) (*DTO, error) {
+	return s.changeStatus(ctx, id, wallet.Closed, audit.WalletClosed, reason, actor)
+}
+
+// StatusHistory returns the status changes of a wallet, oldest first.
+func (s *Service) StatusHistory(ctx context.Context, id int64) ([]*StatusChangeDTO, error) {
+	if _, err := s.wallet.GetByID(ctx, id); err != nil {
+		return nil, serr.DBError("StatusHistory", "wallet", err)
+	}
+	cs, err := s.wallet.GetStatusChanges(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*StatusChangeDTO, 0, len(cs))
+	for _, c := range cs {
+		result = append(result, s.FromStatusChangeModel(c))
+	}
+	return result, nil
+}
+
+func (s *Service) changeStatus(ctx context.Context, id int64, to wallet.Status, action audit.Action, reason, actor string) (*DTO, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || len(reason) > maxReasonLength {
+		return nil, serr.ValidationErr("wallet", "invalid reason", serr.ErrInvalidReason)
+	}
+	var result *DTO
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		w, err := s.wallet.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !canMove(w.Status, to) {
+			return serr.ValidationErr("wallet", "invalid status transition", serr.ErrInvalidStatusTransition)
+		}
+		// closing is for good, the money of a wallet has to be moved out before
+		if to == wallet.Closed && (w.Balance != 0 || w.HeldBalance != 0) {
+			return serr.ValidationErr("wallet", "wallet is not empty", serr.ErrWalletNotEmpty)
+		}
+		if err = s.wallet.SetStatus(ctx, id, to); err != nil {
+			return err
+		}
+		c := &wallet.StatusChange{WalletID: id, FromStatus: w.Status, ToStatus: to, Reason: reason, Actor: actor}
+		if err = s.wallet.CreateStatusChange(ctx, c); err != nil {
+			return err
+		}
+		before := s.FromDBModel(w)
+		w.Status = to
+		result = s.FromDBModel(w)
+		event := &StatusEvent{Wallet: result, Change: s.FromStatusChangeModel(c)}
+		if err = s.record(ctx, outbox.WalletStatusChanged, id, event); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, action, audit.Wallet, id, before, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func canMove(from, to wallet.Status) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// checkStatus returns an error when the status of a wallet does not let money move out of it, or into
+// it when debit is not set. Frozen wallets can still be credited.
+func checkStatus(w *wallet.Wallet, debit bool) error {
+	switch {
+	case w.Status == wallet.Closed:
+		return serr.ValidationErr("wallet", "wallet is closed", serr.ErrWalletClosed)
+	case w.Status == wallet.Frozen && debit:
+		return serr.ValidationErr("wallet", "wallet is frozen", serr.ErrWalletFrozen)
+	}
+	return nil
+}
+
+func (s *Service) FromStatusChangeModel(c *wallet.StatusChange) *StatusChangeDTO {
+	return &StatusChangeDTO{
+		ID:         c.ID,
+		WalletID:   c.WalletID,
+		FromStatus: string(c.FromStatus),
+		ToStatus:   string(c.ToStatus),
+		Reason:     c.Reason,
+		Actor:      c.Actor,
+		CreatedAt:  c.CreatedAt,
+	}
+}
@@ -2,27 +2,72 @@ package wallet
 
 import (
 	"context"
-	"database/sql"
-	"github.com/rs/zerolog/log"
+	"errors"
 	"time"
+	"wallet/client/fx"
 	"wallet/db"
+	"wallet/internal/currency"
 	"wallet/internal/serr"
+	"wallet/service/audit"
+	"wallet/service/outbox"
 	"wallet/service/transaction"
+	"wallet/storage/redemption"
+	"wallet/storage/wallet"
+
+	"github.com/google/uuid"
 )
 
-// create wallet
-func (s *Service) Create(r *CreateRequest) (*DTO, error) {
-	w := s.FromCreateRequest(r)
-	err := s.wallet.Create(w)
+// merchant references and order ids are stored as VARCHAR(255)
+const maxMerchantFieldLength = 255
+
+// create wallet, an initial balance is recorded as a recharge so the ledger covers it
+func (s *Service) Create(ctx context.Context, r *CreateRequest) (*DTO, error) {
+	if r.Balance < 0 {
+		return nil, serr.ValidationErr("wallet", "invalid amount", serr.ErrInvalidAmount)
+	}
+	if r.Currency == "" {
+		r.Currency = currency.Default()
+	}
+	c, ok := currency.Get(r.Currency)
+	if !ok {
+		return nil, serr.ValidationErr("wallet", "unsupported currency", serr.ErrUnsupportedCurrency)
+	}
+	// the code is stored as the currency package spells it
+	r.Currency = c.Code
+	var result *DTO
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		w := s.FromCreateRequest(r)
+		w.Balance = 0
+		err := s.wallet.Create(ctx, w)
+		if err != nil {
+			return err
+		}
+		result = s.FromDBModel(w)
+		if err = s.record(ctx, outbox.WalletCreated, w.ID, result); err != nil {
+			return err
+		}
+		if err = s.recordAudit(ctx, audit.WalletCreated, audit.Wallet, w.ID, nil, result); err != nil {
+			return err
+		}
+		if r.Balance > 0 {
+			result, err = s.applyTransaction(ctx, &transaction.CreateRequest{
+				WalletID:        w.ID,
+				Amount:          r.Balance,
+				TransactionType: transaction.Recharge,
+				Description:     "initial balance",
+			})
+		}
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	return s.FromDBModel(w), nil
+	return result, nil
 }
 
 // get wallet by id
-func (s *Service) GetByID(id int64) (*DTO, error) {
-	w, err := s.wallet.GetByID(id)
+func (s *Service) GetByID(ctx context.Context, id int64) (*DTO, error) {
+	w, err := s.wallet.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -30,8 +75,8 @@ func (s *Service) GetByID(id int64) (*DTO, error) {
 }
 
 // get wallets by member id
-func (s *Service) GetByMemberID(memberID int64) ([]*DTO, error) {
-	ws, err := s.wallet.GetByMemberID(memberID)
+func (s *Service) GetByMemberID(ctx context.Context, memberID int64) ([]*DTO, error) {
+	ws, err := s.wallet.GetByMemberID(ctx, memberID)
 	if err != nil {
 		return nil, err
 	}
@@ -42,45 +87,99 @@ func (s *Service) GetByMemberID(memberID int64) ([]*DTO, error) {
 	return result, nil
 }
 
-func (s *Service) CreateTransactionAndUpdateWallet(id, amount int64, transactionType transaction.Type, description, discountCode string) (*DTO, error) {
-	w, err := s.GetByID(id)
-	if err != nil {
-		return nil, err
-	}
-	err = db.Transaction(context.Background(), func(tx *sql.Tx) error {
-		txService, err := s.WithTX(tx)
-		if err != nil {
-			return err
-		}
-		tr := &transaction.CreateRequest{
+func (s *Service) CreateTransactionAndUpdateWallet(ctx context.Context, id, amount int64, transactionType transaction.Type, description, discountCode, idempotencyKey string) (*DTO, error) {
+	hash := requestHash(string(transactionType), id, amount, description, discountCode)
+	return idempotent(ctx, s, idempotencyKey, hash, func(ctx context.Context) (*DTO, error) {
+		return s.applyTransaction(ctx, &transaction.CreateRequest{
 			WalletID:        id,
 			Amount:          amount,
 			TransactionType: transactionType,
 			Description:     description,
 			DiscountCode:    discountCode,
-		}
+		})
+	})
+}
 
-		t, err := txService.transaction.Create(tr)
-		if err != nil {
-			return err
-		}
-		log.Info().Msgf("transaction: %+v", t)
-		err = txService.wallet.UpdateBalance(id, w.Balance)
-		if err != nil {
-			return err
+// applyTransaction records a transaction in the wallet currency and applies its amount to the wallet balance.
+// It must be called in a db transaction, the wallet row stays locked until it ends.
+func (s *Service) applyTransaction(ctx context.Context, tr *transaction.CreateRequest) (*DTO, error) {
+	return s.apply(ctx, tr, true)
+}
+
+// apply is applyTransaction, holding the transaction to the wallet spending limits when limited is set.
+func (s *Service) apply(ctx context.Context, tr *transaction.CreateRequest, limited bool) (*DTO, error) {
+	w, err := s.wallet.GetByIDForUpdate(ctx, tr.WalletID)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkStatus(w, tr.Amount < 0); err != nil {
+		return nil, err
+	}
+	if w.Balance-w.HeldBalance+tr.Amount < 0 {
+		return nil, serr.ValidationErr("wallet", "not enough balance", serr.ErrNotEnoughBalance)
+	}
+	if limited {
+		if err = s.checkLimits(ctx, w, tr); err != nil {
+			return nil, err
 		}
-		w.Balance += t.Amount
-		return nil
-	})
+	}
+	tr.Currency = w.Currency
+	t, err := s.transaction.Create(ctx, tr)
 	if err != nil {
 		return nil, err
 	}
-	return w, nil
+	before := s.FromDBModel(w)
+	result, err := s.adjustBalance(ctx, w, t.Amount)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.record(ctx, balanceEventType(t), w.ID, &BalanceEvent{Wallet: result, Transaction: t}); err != nil {
+		return nil, err
+	}
+	action := audit.TransactionAction(string(t.TransactionType))
+	if err = s.recordAudit(ctx, action, audit.Wallet, w.ID, before, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// checkLimits checks a transaction against the spending limits of its wallet. Refunds give back money
+// the wallet was already debited and are not held to its balance limit.
+func (s *Service) checkLimits(ctx context.Context, w *wallet.Wallet, tr *transaction.CreateRequest) error {
+	switch {
+	case tr.Amount < 0:
+		return s.limits.CheckDebit(ctx, w, -tr.Amount, false)
+	case tr.TransactionType != transaction.Refund:
+		return s.limits.CheckCredit(ctx, w, tr.Amount)
+	}
+	return nil
 }
 
-func (s *Service) AddGift(r *AddGiftRequest) (*DTO, error) {
+// adjustBalance applies delta to a wallet locked by GetByIDForUpdate.
+func (s *Service) adjustBalance(ctx context.Context, w *wallet.Wallet, delta int64) (*DTO, error) {
+	balance, err := s.wallet.AdjustBalance(ctx, w.ID, delta)
+	if errors.Is(err, wallet.ErrNegativeBalance) {
+		return nil, serr.ValidationErr("wallet", "not enough balance", serr.ErrNotEnoughBalance)
+	}
+	if err != nil {
+		return nil, err
+	}
+	w.Balance = balance
+	return s.FromDBModel(w), nil
+}
+
+// AddGift credits a wallet with a gift of the discount service. The gift is used under a redemption
+// written first and its use is released again when the credit fails.
+func (s *Service) AddGift(ctx context.Context, r *AddGiftRequest, idempotencyKey string) (*DTO, error) {
+	hash := requestHash("gift", r.MemberID, r.WalletID, r.GiftCode)
+	if idempotencyKey != "" {
+		w, err := replay[DTO](ctx, s, idempotencyKey, hash)
+		if err != nil || w != nil {
+			return w, err
+		}
+	}
 	layout := "2006-01-02T15:04:05Z07:00"
-	g, err := s.discount.GetGiftByCode(r.GiftCode)
+	g, err := s.discount.GetGiftByCode(ctx, r.GiftCode)
 	if err != nil {
 		return nil, err
 	}
@@ -102,150 +201,321 @@ func (s *Service) AddGift(r *AddGiftRequest) (*DTO, error) {
 		return nil, serr.ValidationErr("gift", "gift not started", serr.ErrGiftNotStarted)
 	}
 
-	ws, err := s.GetByMemberID(r.MemberID)
+	ws, err := s.GetByMemberID(ctx, r.MemberID)
 	if err != nil {
 		return nil, err
 	}
-
+	// gift amounts are issued in the default currency
 	for _, w := range ws {
-		t, _ := s.transaction.GetByWalletIDAndDiscountCode(w.ID, r.GiftCode)
-		if t != nil {
-			return nil, serr.ValidationErr("wallet", "discount code has been used", serr.ErrDiscountCodeUsed)
+		if w.ID == r.WalletID && w.Currency != currency.Default() {
+			return nil, serr.ValidationErr("gift", "gift currency does not match wallet currency",
+				serr.ErrCurrencyMismatch)
 		}
 	}
 
-	gift, err := s.discount.UseGift(r.GiftCode)
-	if err != nil {
+	// a member redeems a gift once unless the discount service limits the uses of each member itself
+	once := g.PerMemberLimit == 0
+	if once {
+		for _, w := range ws {
+			t, _ := s.transaction.GetByWalletIDAndDiscountCode(ctx, w.ID, r.GiftCode)
+			if t != nil {
+				return nil, serr.ValidationErr("wallet", "discount code has been used", serr.ErrDiscountCodeUsed)
+			}
+		}
+	}
+
+	// the redemption is written before the gift is used, so a use whose credit fails or is cut off is
+	// released or finished afterwards
+	rd := &redemption.Redemption{
+		MemberID:  r.MemberID,
+		WalletID:  r.WalletID,
+		GiftCode:  r.GiftCode,
+		Reference: uuid.NewString(),
+		Status:    redemption.Pending,
+		Once:      once,
+	}
+	if err = s.redemption.Create(ctx, rd); err != nil {
+		if errors.Is(err, redemption.ErrActive) {
+			return nil, serr.ValidationErr("wallet", "discount code has been used", serr.ErrDiscountCodeUsed)
+		}
+		return nil, err
+	}
+	if err = s.useGift(ctx, rd); err != nil {
 		return nil, err
 	}
-	s.RemoveWithKey(":MEMBER:" + r.GiftCode)
+	s.RemoveWithKey(ctx, ":MEMBER:"+r.GiftCode)
 	// Create a transaction and update the wallet
-	return s.CreateTransactionAndUpdateWallet(r.WalletID, gift.GiftAmount, transaction.Gift, "add gift transaction", gift.Code)
+	result, err := idempotent(ctx, s, idempotencyKey, hash, func(ctx context.Context) (*DTO, error) {
+		return s.completeRedemption(ctx, rd.ID)
+	})
+	if err != nil {
+		s.compensate(ctx, rd)
+		return nil, err
+	}
+	return result, nil
 }
 
-func (s *Service) Recharge(id, amount int64) (*DTO, error) {
-	return s.CreateTransactionAndUpdateWallet(id, amount, transaction.Recharge, "add recharge transaction", "")
+func (s *Service) Recharge(ctx context.Context, id, amount int64, idempotencyKey string) (*DTO, error) {
+	if amount <= 0 {
+		return nil, serr.ValidationErr("wallet", "invalid amount", serr.ErrInvalidAmount)
+	}
+	return s.CreateTransactionAndUpdateWallet(ctx, id, amount, transaction.Recharge, "add recharge transaction", "", idempotencyKey)
 }
 
-func (s *Service) Transfer(fromID, toID, amount int64) (*DTO, error) {
-	ws, err := s.GetByID(fromID)
-	if err != nil {
-		return nil, err
+// transfer amount between two wallets in a single db transaction and return the source wallet.
+// Wallets of different currencies need convert to be set, the amount is then converted at the
+// rate provider's current rate.
+func (s *Service) Transfer(ctx context.Context, fromID, toID, amount int64, convert bool, idempotencyKey string) (*DTO, error) {
+	if amount <= 0 {
+		return nil, serr.ValidationErr("wallet", "invalid amount", serr.ErrInvalidAmount)
 	}
-	if ws.Balance < amount {
-		return nil, serr.ValidationErr("wallet", "not enough balance", serr.ErrNotEnoughBalance)
+	if fromID == toID {
+		return nil, serr.ValidationErr("wallet", "source and destination wallets are the same", serr.ErrSameWallet)
 	}
-	_, err = s.GetByID(toID)
-	if err != nil {
-		return nil, err
+	hash := requestHash(string(transaction.Transfer), fromID, toID, amount, convert)
+	if idempotencyKey != "" {
+		w, err := replay[DTO](ctx, s, idempotencyKey, hash)
+		if err != nil || w != nil {
+			return w, err
+		}
+	}
+	var rate *fx.Rate
+	if convert {
+		var err error
+		// the rate is fetched before any row is locked, wallet currencies never change
+		if rate, err = s.exchangeRate(ctx, fromID, toID); err != nil {
+			return nil, err
+		}
 	}
-	err = db.Transaction(context.Background(), func(tx *sql.Tx) error {
-		txService, err := s.WithTX(tx)
+	return idempotent(ctx, s, idempotencyKey, hash, func(ctx context.Context) (*DTO, error) {
+		// lock both wallets in id order so opposite transfers can not deadlock
+		locked := make(map[int64]*wallet.Wallet, 2)
+		for _, id := range []int64{min(fromID, toID), max(fromID, toID)} {
+			w, err := s.wallet.GetByIDForUpdate(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			locked[id] = w
+		}
+		from, to := locked[fromID], locked[toID]
+		if err := checkStatus(from, true); err != nil {
+			return nil, err
+		}
+		if err := checkStatus(to, false); err != nil {
+			return nil, err
+		}
+		if from.Balance-from.HeldBalance < amount {
+			return nil, serr.ValidationErr("wallet", "not enough balance", serr.ErrNotEnoughBalance)
+		}
+		if err := s.limits.CheckDebit(ctx, from, amount, true); err != nil {
+			return nil, err
+		}
+		r := &transaction.TransferRequest{
+			FromWalletID: fromID,
+			ToWalletID:   toID,
+			Amount:       amount,
+			Currency:     from.Currency,
+			Description:  "transfer transaction",
+		}
+		toAmount := amount
+		if from.Currency != to.Currency {
+			if rate == nil || rate.From != from.Currency || rate.To != to.Currency {
+				return nil, serr.ValidationErr("wallet", "wallets have different currencies", serr.ErrCurrencyMismatch)
+			}
+			toAmount = rate.Convert(amount)
+			if toAmount <= 0 {
+				return nil, serr.ValidationErr("wallet", "converted amount is too small", serr.ErrInvalidAmount)
+			}
+			r.ToAmount, r.ToCurrency = toAmount, to.Currency
+			r.FXRate, r.FXSpread = rate.Rate, rate.Spread
+		}
+		if err := s.limits.CheckCredit(ctx, to, toAmount); err != nil {
+			return nil, err
+		}
+		ts, err := s.transaction.CreateTransfer(ctx, r)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		_, err = txService.CreateTransactionAndUpdateWallet(fromID, -amount, transaction.Transfer, "transfer transaction", "")
+		before := &transferState{From: s.FromDBModel(from), To: s.FromDBModel(to)}
+		w, err := s.adjustBalance(ctx, from, -amount)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		_, err = txService.CreateTransactionAndUpdateWallet(toID, amount, transaction.Transfer, "transfer transaction", "")
+		toWallet, err := s.adjustBalance(ctx, to, toAmount)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		return nil
+		event := &TransferEvent{From: w, To: toWallet, Transactions: ts}
+		if err = s.record(ctx, outbox.TransferCompleted, fromID, event); err != nil {
+			return nil, err
+		}
+		after := &transferState{From: w, To: toWallet}
+		if err = s.recordAudit(ctx, audit.WalletTransfer, audit.Wallet, fromID, before, after); err != nil {
+			return nil, err
+		}
+		return w, nil
 	})
+}
+
+// exchangeRate returns the rate converting the source wallet currency into the destination one, or nil
+// when both wallets hold the same currency.
+func (s *Service) exchangeRate(ctx context.Context, fromID, toID int64) (*fx.Rate, error) {
+	from, err := s.wallet.GetByID(ctx, fromID)
 	if err != nil {
 		return nil, err
 	}
-	return ws, nil
+	to, err := s.wallet.GetByID(ctx, toID)
+	if err != nil {
+		return nil, err
+	}
+	if from.Currency == to.Currency {
+		return nil, nil
+	}
+	return s.rates.GetRate(ctx, from.Currency, to.Currency)
 }
 
 // withdraw wallet balance
-func (s *Service) Withdraw(id, amount int64) (*DTO, error) {
-	w, err := s.GetByID(id)
-	if err != nil {
-		return nil, err
+func (s *Service) Withdraw(ctx context.Context, id, amount int64, idempotencyKey string) (*DTO, error) {
+	if amount <= 0 {
+		return nil, serr.ValidationErr("wallet", "invalid amount", serr.ErrInvalidAmount)
 	}
-	if w.Balance < amount {
-		return nil, serr.ValidationErr("wallet", "not enough balance", serr.ErrNotEnoughBalance)
+	return s.CreateTransactionAndUpdateWallet(ctx, id, -amount, transaction.Withdraw, "withdraw transaction", "", idempotencyKey)
+}
+
+// pay a merchant order from the wallet balance
+func (s *Service) Pay(ctx context.Context, id int64, r *PayRequest, idempotencyKey string) (*DTO, error) {
+	if r.Amount <= 0 {
+		return nil, serr.ValidationErr("wallet", "invalid amount", serr.ErrInvalidAmount)
+	}
+	if r.MerchantReference == "" || len(r.MerchantReference) > maxMerchantFieldLength {
+		return nil, serr.ValidationErr("wallet", "invalid merchant reference", serr.ErrInvalidMerchantReference)
+	}
+	if r.OrderID == "" || len(r.OrderID) > maxMerchantFieldLength {
+		return nil, serr.ValidationErr("wallet", "invalid order id", serr.ErrInvalidOrderID)
 	}
-	return s.CreateTransactionAndUpdateWallet(id, -amount, transaction.Withdraw, "withdraw transaction", "")
+	hash := requestHash(string(transaction.Payment), id, r.Amount, r.MerchantReference, r.OrderID)
+	return idempotent(ctx, s, idempotencyKey, hash, func(ctx context.Context) (*DTO, error) {
+		return s.applyTransaction(ctx, &transaction.CreateRequest{
+			WalletID:          id,
+			Amount:            -r.Amount,
+			TransactionType:   transaction.Payment,
+			Description:       "payment transaction",
+			MerchantReference: r.MerchantReference,
+			OrderID:           r.OrderID,
+		})
+	})
 }
 
-// refund wallet balance by transaction id
-func (s *Service) Refund(id int64) (*DTO, error) {
-	t, err := s.transaction.GetByID(id)
+// refund a withdraw or payment transaction back to its wallet. A zero amount refunds whatever has not been
+// refunded yet, refunds of a transaction never add up to more than its amount.
+func (s *Service) Refund(ctx context.Context, id, amount int64, idempotencyKey string) (*DTO, error) {
+	if amount < 0 {
+		return nil, serr.ValidationErr("wallet", "invalid amount", serr.ErrInvalidAmount)
+	}
+	t, err := s.transaction.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	if t.TransactionType != transaction.Withdraw {
-		return nil, serr.ValidationErr("transaction", "transaction type is not withdraw",
-			serr.ErrTransactionTypeNotWithdrawal)
+	if t.TransactionType != transaction.Withdraw && t.TransactionType != transaction.Payment {
+		return nil, serr.ValidationErr("transaction", "transaction type is not refundable",
+			serr.ErrTransactionNotRefundable)
 	}
-	return s.CreateTransactionAndUpdateWallet(t.WalletID, t.Amount, transaction.Refund, "refund transaction", "")
-}
-
-// delete wallet by id and all transactions of that wallet
-func (s *Service) Delete(id int64) error {
-	err := db.Transaction(context.Background(), func(tx *sql.Tx) error {
-		s, err := s.WithTX(tx)
-		if err != nil {
-			return err
+	hash := requestHash(string(transaction.Refund), id, amount)
+	return idempotent(ctx, s, idempotencyKey, hash, func(ctx context.Context) (*DTO, error) {
+		// refunds credit the same wallet, holding its lock keeps concurrent refunds from passing the check together
+		if _, err := s.wallet.GetByIDForUpdate(ctx, t.WalletID); err != nil {
+			return nil, err
 		}
-		err = s.transaction.DeleteByWalletID(id)
+		refunded, err := s.transaction.GetRefundedAmount(ctx, t.ID)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		err = s.wallet.Delete(id)
-		if err != nil {
-			return err
+		// withdraw and payment amounts are stored negative, so refunding credits the opposite amount
+		remaining := -t.Amount - refunded
+		if remaining <= 0 {
+			return nil, serr.ValidationErr("transaction", "transaction is already refunded", serr.ErrTransactionRefunded)
 		}
-		return nil
+		if amount == 0 {
+			amount = remaining
+		}
+		if amount > remaining {
+			return nil, serr.ValidationErr("transaction", "refund amount exceeds transaction amount",
+				serr.ErrRefundExceedsAmount)
+		}
+		return s.applyTransaction(ctx, &transaction.CreateRequest{
+			WalletID:          t.WalletID,
+			Amount:            amount,
+			ParentID:          t.ID,
+			TransactionType:   transaction.Refund,
+			Description:       "refund transaction",
+			MerchantReference: t.MerchantReference,
+			OrderID:           t.OrderID,
+		})
 	})
-	if err != nil {
-		return err
-	}
-	return nil
 }
 
-// delete wallet by member id and all transactions of that wallet
-func (s *Service) DeleteByMemberID(memberID int64) error {
-	err := db.Transaction(context.Background(), func(tx *sql.Tx) error {
-		s, err := s.WithTX(tx)
+// Delete marks an empty wallet deleted, its transactions are kept until the archival job moves them.
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	return db.Transaction(ctx, func(ctx context.Context) error {
+		w, err := s.lockEmpty(ctx, id)
 		if err != nil {
 			return err
 		}
-		ws, err := s.GetByMemberID(memberID)
-		if err != nil {
+		if err = s.wallet.Delete(ctx, id); err != nil {
 			return err
 		}
+		return s.recordAudit(ctx, audit.WalletDeleted, audit.Wallet, id, s.FromDBModel(w), nil)
+	})
+}
+
+// DeleteByMemberID marks the wallets of a member deleted, all of them have to be empty.
+func (s *Service) DeleteByMemberID(ctx context.Context, memberID int64) error {
+	return db.Transaction(ctx, func(ctx context.Context) error {
+		ws, err := s.wallet.GetByMemberID(ctx, memberID)
+		if err != nil || len(ws) == 0 {
+			return err
+		}
+		locked := make([]*wallet.Wallet, 0, len(ws))
 		for _, w := range ws {
-			err = s.transaction.DeleteByWalletID(w.ID)
+			l, err := s.lockEmpty(ctx, w.ID)
 			if err != nil {
 				return err
 			}
-			err = s.wallet.Delete(w.ID)
-			if err != nil {
+			locked = append(locked, l)
+		}
+		if err = s.wallet.DeleteByMemberID(ctx, memberID); err != nil {
+			return err
+		}
+		for _, w := range locked {
+			if err = s.recordAudit(ctx, audit.WalletDeleted, audit.Wallet, w.ID, s.FromDBModel(w), nil); err != nil {
 				return err
 			}
 		}
 		return nil
 	})
+}
+
+// lockEmpty locks a wallet and returns it, or an error when money is left in it.
+func (s *Service) lockEmpty(ctx context.Context, id int64) (*wallet.Wallet, error) {
+	w, err := s.wallet.GetByIDForUpdate(ctx, id)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	if w.Balance != 0 || w.HeldBalance != 0 {
+		return nil, serr.ValidationErr("wallet", "wallet is not empty", serr.ErrWalletNotEmpty)
+	}
+	return w, nil
 }
 
 // get all wallets which use specific discount code in transactions of that wallet by offset and limit
-func (s *Service) GetByDiscountCodeWithPagination(discountCode string, limit, offset int) ([]*DTO, error) {
-	ts, err := s.transaction.GetByDiscountCodeWithPagination(discountCode, limit, offset)
+func (s *Service) GetByDiscountCodeWithPagination(ctx context.Context, discountCode string, limit, offset int) ([]*DTO, error) {
+	ts, err := s.transaction.GetByDiscountCodeWithPagination(ctx, discountCode, limit, offset)
 	if err != nil {
 		return nil, err
 	}
 	var result []*DTO
 	for _, t := range ts {
-		w, err := s.GetByID(t.WalletID)
+		w, err := s.GetByID(ctx, t.WalletID)
 		if err != nil {
 			return nil, err
 		}
@@ -253,3 +523,25 @@ func (s *Service) GetByDiscountCodeWithPagination(discountCode string, limit, of
 	}
 	return result, nil
 }
+
+// list the transactions of a wallet, see transaction.UseCase.List
+func (s *Service) ListTransactions(ctx context.Context, r *transaction.ListRequest) (*transaction.Page, error) {
+	if _, err := s.wallet.GetByID(ctx, r.WalletID); err != nil {
+		return nil, serr.DBError("ListTransactions", "wallet", err)
+	}
+	return s.transaction.List(ctx, r)
+}
+
+// Statement returns the statement of a wallet in its currency, see transaction.UseCase.Statement
+func (s *Service) Statement(ctx context.Context, r *transaction.StatementRequest) (*transaction.Statement, error) {
+	w, err := s.wallet.GetByID(ctx, r.WalletID)
+	if err != nil {
+		return nil, serr.DBError("Statement", "wallet", err)
+	}
+	st, err := s.transaction.Statement(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	st.Currency = w.Currency
+	return st, nil
+}
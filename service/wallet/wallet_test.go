@@ -1,10 +1,45 @@
 package wallet_test
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"os"
+	"sync"
 	"testing"
+	"time"
+	"wallet/client/discount"
+	"wallet/db"
+	"wallet/internal/serr"
+	dbMocks "wallet/mocks/repomocks/db"
+	discountMocks "wallet/mocks/repomocks/discount"
+	redemptionMocks "wallet/mocks/repomocks/redemption"
+	transMocks "wallet/mocks/repomocks/transaction"
 	repomocks "wallet/mocks/repomocks/wallet"
+	"wallet/service/audit"
+	giftService "wallet/service/gift"
+	limitService "wallet/service/limit"
+	outboxService "wallet/service/outbox"
+	transService "wallet/service/transaction"
 	wallet "wallet/service/wallet"
+	auditStorage "wallet/storage/audit"
+	giftStorage "wallet/storage/gift"
+	holdStorage "wallet/storage/hold"
+	idempotencyStorage "wallet/storage/idempotency"
+	ledgerStorage "wallet/storage/ledger"
+	limitStorage "wallet/storage/limit"
+	memberStorage "wallet/storage/member"
+	outboxStorage "wallet/storage/outbox"
+	redemptionStorage "wallet/storage/redemption"
+	transStorage "wallet/storage/transaction"
+	walletStorage "wallet/storage/wallet"
+
+	"github.com/redis/go-redis/v9"
 )
 
 func TestWalletService_AddGift(t *testing.T) {
@@ -27,10 +62,10 @@ func TestWalletService_AddGift(t *testing.T) {
 	}
 
 	// Expect the AddGift function to be called with our request.
-	mockUseCase.On("AddGift", request).Return(expected, nil)
+	mockUseCase.On("AddGift", mock.Anything, request, "").Return(expected, nil)
 
 	// Call AddGift on our wallet service.
-	result, err := mockUseCase.AddGift(request)
+	result, err := mockUseCase.AddGift(context.Background(), request, "")
 
 	// Assert that the function did not return an error.
 	assert.NoError(t, err)
@@ -53,10 +88,10 @@ func TestWalletService_AddGift_Error(t *testing.T) {
 	}
 
 	// Expect the AddGift function to be called with our request.
-	mockUseCase.On("AddGift", request).Return(nil, assert.AnError)
+	mockUseCase.On("AddGift", mock.Anything, request, "").Return(nil, assert.AnError)
 
 	// Call AddGift on our wallet service.
-	result, err := mockUseCase.AddGift(request)
+	result, err := mockUseCase.AddGift(context.Background(), request, "")
 
 	// Assert that the function returned an error.
 	assert.Error(t, err)
@@ -66,3 +101,698 @@ func TestWalletService_AddGift_Error(t *testing.T) {
 	// Assert that the expectations were met.
 	mockUseCase.AssertExpectations(t)
 }
+
+// testPostgres connects to the database given by the WALLET_TEST_DB_* environment variables and
+// skips the test when they are not set.
+func testPostgres(t *testing.T) *sql.DB {
+	host := os.Getenv("WALLET_TEST_DB_HOST")
+	if host == "" {
+		t.Skip("WALLET_TEST_DB_HOST is not set")
+	}
+	port := os.Getenv("WALLET_TEST_DB_PORT")
+	if port == "" {
+		port = "5432"
+	}
+	psql, err := db.NewPostgres(os.Getenv("WALLET_TEST_DB_NAME"), os.Getenv("WALLET_TEST_DB_USER"),
+		os.Getenv("WALLET_TEST_DB_PASSWORD"), host, port, 20, 5)
+	require.NoError(t, err)
+	viper.Set("db.postgres.migrationsPath", "../../db/migrations")
+	require.NoError(t, db.Migrate(psql))
+	return psql
+}
+
+// newTestService wires a wallet service on the test database without external clients.
+func newTestService(psql *sql.DB) *wallet.Service {
+	return newGiftTestService(psql, nil, nil)
+}
+
+// newGiftTestService wires a wallet service on the test database with a discount client.
+func newGiftTestService(psql *sql.DB, discounts discount.Client, rdb db.RedisClient) *wallet.Service {
+	return wallet.New(
+		walletStorage.NewStorage(psql),
+		transService.New(transStorage.NewStorage(psql), ledgerStorage.NewStorage(psql)),
+		idempotencyStorage.NewStorage(psql),
+		holdStorage.NewStorage(psql),
+		outboxStorage.NewStorage(psql),
+		auditStorage.NewStorage(psql),
+		redemptionStorage.NewStorage(psql),
+		limitService.New(limitStorage.NewStorage(psql), memberStorage.NewStorage(psql), walletStorage.NewStorage(psql),
+			transStorage.NewStorage(psql), time.UTC),
+		discounts,
+		nil,
+		rdb,
+	)
+}
+
+func TestWalletService_ConcurrentBalanceUpdates(t *testing.T) {
+	psql := testPostgres(t)
+	s := newTestService(psql)
+
+	m := &memberStorage.Member{FirstName: "a", LastName: "b", Phone: fmt.Sprintf("+98%d", time.Now().UnixNano()%1e10)}
+	require.NoError(t, memberStorage.NewStorage(psql).Create(context.Background(), m))
+	w, err := s.Create(context.Background(), &wallet.CreateRequest{MemberID: m.ID, WalletName: "concurrency"})
+	require.NoError(t, err)
+
+	const workers = 50
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.Recharge(context.Background(), w.ID, 10, "")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	w, err = s.GetByID(context.Background(), w.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(workers*10), w.Balance)
+
+	// more withdrawals than the balance can cover, only workers of them may succeed
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < workers+10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.Withdraw(context.Background(), w.ID, 10, "")
+			var e *serr.ServiceError
+			if err != nil && !(errors.As(err, &e) && e.ErrorCode == serr.ErrNotEnoughBalance) {
+				assert.NoError(t, err)
+			}
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	w, err = s.GetByID(context.Background(), w.ID)
+	require.NoError(t, err)
+	assert.Equal(t, workers, succeeded)
+	assert.Equal(t, int64(0), w.Balance)
+
+	ledgerBalance, err := ledgerStorage.NewStorage(psql).GetWalletBalance(context.Background(), w.ID)
+	require.NoError(t, err)
+	assert.Equal(t, w.Balance, ledgerBalance)
+}
+
+func TestWalletService_JoinsTransaction(t *testing.T) {
+	psql := testPostgres(t)
+	s := newTestService(psql)
+	ctx := context.Background()
+
+	m := &memberStorage.Member{FirstName: "a", LastName: "b", Phone: fmt.Sprintf("+98%d", time.Now().UnixNano()%1e10)}
+	require.NoError(t, memberStorage.NewStorage(psql).Create(ctx, m))
+	from, err := s.Create(ctx, &wallet.CreateRequest{MemberID: m.ID, WalletName: "from", Balance: 100})
+	require.NoError(t, err)
+	to, err := s.Create(ctx, &wallet.CreateRequest{MemberID: m.ID, WalletName: "to"})
+	require.NoError(t, err)
+
+	// calls made in a transaction of the caller are rolled back with it
+	errAbort := errors.New("abort")
+	err = db.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.Transfer(ctx, from.ID, to.ID, 40, false, ""); err != nil {
+			return err
+		}
+		if _, err := s.Withdraw(ctx, from.ID, 10, ""); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	require.ErrorIs(t, err, errAbort)
+	from, err = s.GetByID(ctx, from.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), from.Balance)
+	to, err = s.GetByID(ctx, to.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), to.Balance)
+
+	// a failed call rolls back to its savepoint and the caller's transaction goes on
+	err = db.Transaction(ctx, func(ctx context.Context) error {
+		_, err := s.Withdraw(ctx, from.ID, 1000, "")
+		require.Error(t, err)
+		_, err = s.Withdraw(ctx, from.ID, 10, "")
+		return err
+	})
+	require.NoError(t, err)
+	from, err = s.GetByID(ctx, from.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(90), from.Balance)
+}
+
+func TestWalletService_Validation(t *testing.T) {
+	s := wallet.New(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+	for name, tc := range map[string]struct {
+		call func() (*wallet.DTO, error)
+		code serr.ErrorCode
+	}{
+		"recharge without amount": {func() (*wallet.DTO, error) { return s.Recharge(ctx, 1, 0, "") }, serr.ErrInvalidAmount},
+		"negative recharge":       {func() (*wallet.DTO, error) { return s.Recharge(ctx, 1, -5, "") }, serr.ErrInvalidAmount},
+		"withdraw without amount": {func() (*wallet.DTO, error) { return s.Withdraw(ctx, 1, 0, "") }, serr.ErrInvalidAmount},
+		"negative transfer":       {func() (*wallet.DTO, error) { return s.Transfer(ctx, 1, 2, -5, false, "") }, serr.ErrInvalidAmount},
+		"transfer to itself":      {func() (*wallet.DTO, error) { return s.Transfer(ctx, 1, 1, 5, false, "") }, serr.ErrSameWallet},
+		"negative refund":         {func() (*wallet.DTO, error) { return s.Refund(ctx, 1, -5, "") }, serr.ErrInvalidAmount},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.call()
+			var e *serr.ServiceError
+			require.True(t, errors.As(err, &e))
+			assert.Equal(t, tc.code, e.ErrorCode)
+		})
+	}
+}
+
+func TestWalletService_Create_Currency(t *testing.T) {
+	psql := testPostgres(t)
+	ctx := context.Background()
+	s := newTestService(psql)
+	m := &memberStorage.Member{FirstName: "a", LastName: "b", Phone: fmt.Sprintf("+98%d", time.Now().UnixNano()%1e10)}
+	require.NoError(t, memberStorage.NewStorage(psql).Create(ctx, m))
+
+	// the code is stored as validated, not as sent
+	w, err := s.Create(ctx, &wallet.CreateRequest{MemberID: m.ID, WalletName: "dollars", Currency: " usd"})
+	require.NoError(t, err)
+	assert.Equal(t, "USD", w.Currency)
+	w, err = s.GetByID(ctx, w.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "USD", w.Currency)
+
+	_, err = s.Create(ctx, &wallet.CreateRequest{MemberID: m.ID, WalletName: "unknown", Currency: "XYZ"})
+	var e *serr.ServiceError
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrUnsupportedCurrency, e.ErrorCode)
+}
+
+func TestWalletService_Refund_NotRefundable(t *testing.T) {
+	transactions := transMocks.NewUseCase(t)
+	transactions.On("GetByID", mock.Anything, int64(3)).
+		Return(&transService.DTO{ID: 3, WalletID: 1, Amount: 500, TransactionType: transService.Recharge}, nil)
+	s := wallet.New(nil, transactions, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	_, err := s.Refund(context.Background(), 3, 0, "")
+	var e *serr.ServiceError
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrTransactionNotRefundable, e.ErrorCode)
+}
+
+func TestWalletService_Pay_Validation(t *testing.T) {
+	s := wallet.New(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	for name, tc := range map[string]struct {
+		request *wallet.PayRequest
+		code    serr.ErrorCode
+	}{
+		"invalid amount":    {&wallet.PayRequest{Amount: 0, MerchantReference: "shop", OrderID: "1"}, serr.ErrInvalidAmount},
+		"missing merchant":  {&wallet.PayRequest{Amount: 10, OrderID: "1"}, serr.ErrInvalidMerchantReference},
+		"missing order":     {&wallet.PayRequest{Amount: 10, MerchantReference: "shop"}, serr.ErrInvalidOrderID},
+		"order id too long": {&wallet.PayRequest{Amount: 10, MerchantReference: "shop", OrderID: string(make([]byte, 256))}, serr.ErrInvalidOrderID},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.Pay(context.Background(), 1, tc.request, "")
+			var e *serr.ServiceError
+			require.True(t, errors.As(err, &e))
+			assert.Equal(t, tc.code, e.ErrorCode)
+		})
+	}
+}
+
+func TestWalletService_PayAndRefund(t *testing.T) {
+	psql := testPostgres(t)
+	s := newTestService(psql)
+
+	m := &memberStorage.Member{FirstName: "a", LastName: "b", Phone: fmt.Sprintf("+98%d", time.Now().UnixNano()%1e10)}
+	require.NoError(t, memberStorage.NewStorage(psql).Create(context.Background(), m))
+	w, err := s.Create(context.Background(), &wallet.CreateRequest{MemberID: m.ID, WalletName: "payment", Balance: 100})
+	require.NoError(t, err)
+
+	_, err = s.Pay(context.Background(), w.ID, &wallet.PayRequest{Amount: 101, MerchantReference: "shop", OrderID: "1"}, "")
+	var e *serr.ServiceError
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrNotEnoughBalance, e.ErrorCode)
+
+	w, err = s.Pay(context.Background(), w.ID, &wallet.PayRequest{Amount: 30, MerchantReference: "shop", OrderID: "1"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(70), w.Balance)
+
+	ts, err := transStorage.NewStorage(psql).Find(context.Background(), &transStorage.Filter{WalletID: w.ID, Types: []transStorage.Type{transStorage.Payment}})
+	require.NoError(t, err)
+	require.Len(t, ts, 1)
+	assert.Equal(t, "shop", ts[0].MerchantReference)
+	assert.Equal(t, "1", ts[0].OrderID)
+
+	w, err = s.Refund(context.Background(), ts[0].ID, 10, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(80), w.Balance)
+
+	_, err = s.Refund(context.Background(), ts[0].ID, 25, "")
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrRefundExceedsAmount, e.ErrorCode)
+
+	// no amount refunds the rest
+	w, err = s.Refund(context.Background(), ts[0].ID, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), w.Balance)
+
+	_, err = s.Refund(context.Background(), ts[0].ID, 0, "")
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrTransactionRefunded, e.ErrorCode)
+
+	refunds, err := transStorage.NewStorage(psql).Find(context.Background(), &transStorage.Filter{WalletID: w.ID, Types: []transStorage.Type{transStorage.Refund}})
+	require.NoError(t, err)
+	require.Len(t, refunds, 2)
+	for _, r := range refunds {
+		assert.Equal(t, ts[0].ID, r.ParentID)
+	}
+
+	// only withdraws and payments are refunded
+	_, err = s.Refund(context.Background(), refunds[0].ID, 0, "")
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrTransactionNotRefundable, e.ErrorCode)
+
+	ledgerBalance, err := ledgerStorage.NewStorage(psql).GetWalletBalance(context.Background(), w.ID)
+	require.NoError(t, err)
+	assert.Equal(t, w.Balance, ledgerBalance)
+}
+
+func TestWalletService_Holds(t *testing.T) {
+	psql := testPostgres(t)
+	s := newTestService(psql)
+
+	m := &memberStorage.Member{FirstName: "a", LastName: "b", Phone: fmt.Sprintf("+98%d", time.Now().UnixNano()%1e10)}
+	require.NoError(t, memberStorage.NewStorage(psql).Create(context.Background(), m))
+	w, err := s.Create(context.Background(), &wallet.CreateRequest{MemberID: m.ID, WalletName: "holds", Balance: 100})
+	require.NoError(t, err)
+
+	h, err := s.Authorize(context.Background(), w.ID, 60, 0, "")
+	require.NoError(t, err)
+	w, err = s.GetByID(context.Background(), w.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), w.Balance)
+	assert.Equal(t, int64(40), w.AvailableBalance)
+
+	// held funds can not be spent
+	_, err = s.Withdraw(context.Background(), w.ID, 50, "")
+	var e *serr.ServiceError
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrNotEnoughBalance, e.ErrorCode)
+
+	h, err = s.Capture(context.Background(), h.ID, 45, "")
+	require.NoError(t, err)
+	assert.Equal(t, "captured", h.Status)
+	assert.Equal(t, int64(45), h.CapturedAmount)
+	w, err = s.GetByID(context.Background(), w.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(55), w.Balance)
+	assert.Equal(t, int64(55), w.AvailableBalance)
+
+	_, err = s.Void(context.Background(), h.ID)
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrHoldNotActive, e.ErrorCode)
+
+	h, err = s.Authorize(context.Background(), w.ID, 10, 0, "")
+	require.NoError(t, err)
+	h, err = s.Void(context.Background(), h.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "voided", h.Status)
+
+	h, err = s.Authorize(context.Background(), w.ID, 10, time.Millisecond, "")
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	_, err = s.ExpireHolds(context.Background())
+	require.NoError(t, err)
+	h, err = s.GetHold(context.Background(), h.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "expired", h.Status)
+	w, err = s.GetByID(context.Background(), w.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(55), w.AvailableBalance)
+}
+
+func TestWalletService_Status(t *testing.T) {
+	_, err := wallet.New(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).Freeze(context.Background(), 1, " ", "admin")
+	var e *serr.ServiceError
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrInvalidReason, e.ErrorCode)
+
+	psql := testPostgres(t)
+	s := newTestService(psql)
+
+	m := &memberStorage.Member{FirstName: "a", LastName: "b", Phone: fmt.Sprintf("+98%d", time.Now().UnixNano()%1e10)}
+	require.NoError(t, memberStorage.NewStorage(psql).Create(context.Background(), m))
+	w, err := s.Create(context.Background(), &wallet.CreateRequest{MemberID: m.ID, WalletName: "status", Balance: 100})
+	require.NoError(t, err)
+	assert.Equal(t, "active", w.Status)
+	other, err := s.Create(context.Background(), &wallet.CreateRequest{MemberID: m.ID, WalletName: "other"})
+	require.NoError(t, err)
+
+	w, err = s.Freeze(context.Background(), w.ID, "card reported stolen", "admin:1")
+	require.NoError(t, err)
+	assert.Equal(t, "frozen", w.Status)
+
+	// frozen wallets are credited but not debited
+	_, err = s.Withdraw(context.Background(), w.ID, 10, "")
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrWalletFrozen, e.ErrorCode)
+	_, err = s.Transfer(context.Background(), w.ID, other.ID, 10, false, "")
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrWalletFrozen, e.ErrorCode)
+	_, err = s.Authorize(context.Background(), w.ID, 10, 0, "")
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrWalletFrozen, e.ErrorCode)
+	_, err = s.Recharge(context.Background(), w.ID, 10, "")
+	require.NoError(t, err)
+
+	_, err = s.Close(context.Background(), w.ID, "closed by the member", "admin:1")
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrInvalidStatusTransition, e.ErrorCode)
+
+	w, err = s.Unfreeze(context.Background(), w.ID, "card replaced", "admin:1")
+	require.NoError(t, err)
+	assert.Equal(t, "active", w.Status)
+
+	_, err = s.Close(context.Background(), w.ID, "closed by the member", "admin:1")
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrWalletNotEmpty, e.ErrorCode)
+
+	_, err = s.Transfer(context.Background(), w.ID, other.ID, 110, false, "")
+	require.NoError(t, err)
+	w, err = s.Close(context.Background(), w.ID, "closed by the member", "admin:1")
+	require.NoError(t, err)
+	assert.Equal(t, "closed", w.Status)
+
+	_, err = s.Transfer(context.Background(), other.ID, w.ID, 10, false, "")
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrWalletClosed, e.ErrorCode)
+
+	history, err := s.StatusHistory(context.Background(), w.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, "frozen", history[0].ToStatus)
+	assert.Equal(t, "card reported stolen", history[0].Reason)
+	assert.Equal(t, "admin:1", history[0].Actor)
+	assert.Equal(t, "closed", history[2].ToStatus)
+}
+
+func TestWalletService_DeleteAndArchive(t *testing.T) {
+	psql := testPostgres(t)
+	s := newTestService(psql)
+	transactions := transStorage.NewStorage(psql)
+
+	m := &memberStorage.Member{FirstName: "a", LastName: "b", Phone: fmt.Sprintf("+98%d", time.Now().UnixNano()%1e10)}
+	require.NoError(t, memberStorage.NewStorage(psql).Create(context.Background(), m))
+	w, err := s.Create(context.Background(), &wallet.CreateRequest{MemberID: m.ID, WalletName: "deleted", Balance: 100})
+	require.NoError(t, err)
+
+	err = s.Delete(context.Background(), w.ID)
+	var e *serr.ServiceError
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrWalletNotEmpty, e.ErrorCode)
+
+	_, err = s.Withdraw(context.Background(), w.ID, 100, "")
+	require.NoError(t, err)
+	require.NoError(t, s.Delete(context.Background(), w.ID))
+	_, err = s.GetByID(context.Background(), w.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// the history of a deleted wallet is kept until it is archived
+	ts, err := transactions.Find(context.Background(), &transStorage.Filter{WalletID: w.ID})
+	require.NoError(t, err)
+	assert.Len(t, ts, 2)
+
+	moved, err := transService.New(transactions, ledgerStorage.NewStorage(psql)).Archive(context.Background(), time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, moved, int64(2))
+	ts, err = transactions.Find(context.Background(), &transStorage.Filter{WalletID: w.ID})
+	require.NoError(t, err)
+	assert.Empty(t, ts)
+	var archived int
+	require.NoError(t, psql.QueryRow("SELECT count(*) FROM transaction_archive WHERE wallet_id = $1", w.ID).Scan(&archived))
+	assert.Equal(t, 2, archived)
+}
+
+func TestWalletService_Audit(t *testing.T) {
+	psql := testPostgres(t)
+	meta := audit.Meta{Actor: "admin:1", TraceID: fmt.Sprintf("audit-%d", time.Now().UnixNano()), ClientIP: "10.0.0.1"}
+	ctx := audit.WithMeta(context.Background(), meta)
+	s := newTestService(psql)
+
+	m := &memberStorage.Member{FirstName: "a", LastName: "b", Phone: fmt.Sprintf("+98%d", time.Now().UnixNano()%1e10)}
+	require.NoError(t, memberStorage.NewStorage(psql).Create(context.Background(), m))
+	w, err := s.Create(ctx, &wallet.CreateRequest{MemberID: m.ID, WalletName: "audited", Balance: 100})
+	require.NoError(t, err)
+	_, err = s.Withdraw(ctx, w.ID, 30, "")
+	require.NoError(t, err)
+
+	log := audit.New(auditStorage.NewStorage(psql))
+	entries, err := log.List(context.Background(), &audit.ListRequest{TraceID: meta.TraceID})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, audit.TransactionAction("withdraw"), entries[0].Action)
+	assert.Equal(t, audit.TransactionAction("recharge"), entries[1].Action)
+	assert.Equal(t, audit.WalletCreated, entries[2].Action)
+	assert.Nil(t, entries[2].Before)
+	assert.JSONEq(t, string(entries[1].After), string(entries[0].Before))
+	for _, e := range entries {
+		assert.Equal(t, "admin:1", e.Actor)
+		assert.Equal(t, "10.0.0.1", e.ClientIP)
+		assert.Equal(t, w.ID, e.EntityID)
+	}
+	assert.Equal(t, entries[1].Hash, entries[0].PrevHash)
+
+	v, err := log.Verify(context.Background())
+	require.NoError(t, err)
+	assert.True(t, v.Valid)
+
+	// entries can not be changed or removed
+	_, err = psql.Exec("UPDATE audit_log SET actor = 'member:1' WHERE id = $1", entries[0].ID)
+	assert.Error(t, err)
+	_, err = psql.Exec("DELETE FROM audit_log WHERE id = $1", entries[0].ID)
+	assert.Error(t, err)
+}
+
+func TestWalletService_Outbox(t *testing.T) {
+	psql := testPostgres(t)
+	s := newTestService(psql)
+	publisher := outboxService.NewMemoryPublisher()
+	relay := outboxService.NewRelay(outboxStorage.NewStorage(psql), publisher)
+	// events of earlier tests are published first
+	_, err := relay.Drain(context.Background())
+	require.NoError(t, err)
+
+	m := &memberStorage.Member{FirstName: "a", LastName: "b", Phone: fmt.Sprintf("+98%d", time.Now().UnixNano()%1e10)}
+	require.NoError(t, memberStorage.NewStorage(psql).Create(context.Background(), m))
+	w, err := s.Create(context.Background(), &wallet.CreateRequest{MemberID: m.ID, WalletName: "events", Balance: 100})
+	require.NoError(t, err)
+	other, err := s.Create(context.Background(), &wallet.CreateRequest{MemberID: m.ID, WalletName: "other"})
+	require.NoError(t, err)
+	_, err = s.Recharge(context.Background(), w.ID, 50, "")
+	require.NoError(t, err)
+	_, err = s.Withdraw(context.Background(), w.ID, 20, "")
+	require.NoError(t, err)
+	_, err = s.Transfer(context.Background(), w.ID, other.ID, 30, false, "")
+	require.NoError(t, err)
+	// a failed operation leaves no event behind
+	_, err = s.Withdraw(context.Background(), w.ID, 1000, "")
+	require.Error(t, err)
+
+	// a failing publisher leaves the events in the outbox
+	publisher.FailWith(errors.New("broker down"))
+	n, err := relay.Publish(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 0, n)
+	publisher.FailWith(nil)
+
+	_, err = relay.Drain(context.Background())
+	require.NoError(t, err)
+	var types []outboxService.EventType
+	for _, e := range publisher.Events() {
+		if e.WalletID == w.ID {
+			types = append(types, e.Type)
+		}
+	}
+	assert.Equal(t, []outboxService.EventType{
+		outboxService.WalletCreated,
+		outboxService.BalanceCredited,
+		outboxService.BalanceCredited,
+		outboxService.BalanceDebited,
+		outboxService.TransferCompleted,
+	}, types)
+
+	n, err = relay.Publish(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestWalletService_GiftRedemption(t *testing.T) {
+	psql := testPostgres(t)
+	ctx := context.Background()
+	discounts := discountMocks.NewClient(t)
+	rdb := dbMocks.NewRedisClient(t)
+	rdb.On("Del", mock.Anything, mock.Anything).Return(redis.NewIntCmd(ctx)).Maybe()
+	s := newGiftTestService(psql, discounts, rdb)
+
+	newWallet := func(name string) *wallet.DTO {
+		m := &memberStorage.Member{FirstName: "a", LastName: "b", Phone: fmt.Sprintf("+98%d", time.Now().UnixNano()%1e10)}
+		require.NoError(t, memberStorage.NewStorage(psql).Create(ctx, m))
+		w, err := s.Create(ctx, &wallet.CreateRequest{MemberID: m.ID, WalletName: name})
+		require.NoError(t, err)
+		return w
+	}
+	newGift := func() *discount.Gift {
+		g := &discount.Gift{
+			Code:           fmt.Sprintf("GIFT%d", time.Now().UnixNano()),
+			GiftAmount:     500,
+			UsageLimit:     10,
+			StartDateTime:  time.Now().Add(-time.Hour).Format(time.RFC3339),
+			ExpirationDate: time.Now().Add(time.Hour).Format(time.RFC3339),
+		}
+		discounts.On("GetGiftByCode", mock.Anything, g.Code).Return(g, nil)
+		return g
+	}
+	var reference string
+	keepReference := func(args mock.Arguments) { reference = args.String(3) }
+
+	t.Run("credits the wallet", func(t *testing.T) {
+		w, g := newWallet("gift"), newGift()
+		discounts.On("UseGift", mock.Anything, g.Code, mock.Anything, mock.Anything).Return(g, nil).Once()
+
+		w, err := s.AddGift(ctx, &wallet.AddGiftRequest{MemberID: w.MemberID, WalletID: w.ID, GiftCode: g.Code}, "")
+		require.NoError(t, err)
+		assert.Equal(t, int64(500), w.Balance)
+
+		_, err = s.AddGift(ctx, &wallet.AddGiftRequest{MemberID: w.MemberID, WalletID: w.ID, GiftCode: g.Code}, "")
+		var e *serr.ServiceError
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, serr.ErrDiscountCodeUsed, e.ErrorCode)
+	})
+
+	t.Run("releases the gift when the credit fails", func(t *testing.T) {
+		w, g := newWallet("closed"), newGift()
+		_, err := s.Close(ctx, w.ID, "test", "admin")
+		require.NoError(t, err)
+		discounts.On("UseGift", mock.Anything, g.Code, mock.Anything, mock.Anything).Return(g, nil).Run(keepReference).Once()
+		discounts.On("ReleaseGift", mock.Anything, g.Code, mock.Anything).Return(g, nil).Once()
+
+		_, err = s.AddGift(ctx, &wallet.AddGiftRequest{MemberID: w.MemberID, WalletID: w.ID, GiftCode: g.Code}, "")
+		var e *serr.ServiceError
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, serr.ErrWalletClosed, e.ErrorCode)
+		discounts.AssertCalled(t, "ReleaseGift", mock.Anything, g.Code, reference)
+
+		// a released gift can be redeemed again
+		other, err := s.Create(ctx, &wallet.CreateRequest{MemberID: w.MemberID, WalletName: "open"})
+		require.NoError(t, err)
+		discounts.On("UseGift", mock.Anything, g.Code, mock.Anything, mock.Anything).Return(g, nil).Once()
+		other, err = s.AddGift(ctx, &wallet.AddGiftRequest{MemberID: w.MemberID, WalletID: other.ID, GiftCode: g.Code}, "")
+		require.NoError(t, err)
+		assert.Equal(t, int64(500), other.Balance)
+	})
+
+	t.Run("recovers a redemption left pending", func(t *testing.T) {
+		w, g := newWallet("pending"), newGift()
+		unavailable := serr.UnavailableErr("useGift", "discount service is unavailable", serr.ErrDiscountUnavailable, nil)
+		discounts.On("UseGift", mock.Anything, g.Code, mock.Anything, mock.Anything).Return(nil, unavailable).Run(keepReference).Once()
+
+		_, err := s.AddGift(ctx, &wallet.AddGiftRequest{MemberID: w.MemberID, WalletID: w.ID, GiftCode: g.Code}, "")
+		require.Error(t, err)
+		// the use may have gone through, redeeming the gift again has to wait for the recovery
+		_, err = s.AddGift(ctx, &wallet.AddGiftRequest{MemberID: w.MemberID, WalletID: w.ID, GiftCode: g.Code}, "")
+		var e *serr.ServiceError
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, serr.ErrDiscountCodeUsed, e.ErrorCode)
+
+		used := reference
+		discounts.On("UseGift", mock.Anything, g.Code, mock.Anything, used).Return(g, nil).Once()
+		n, err := s.RecoverRedemptions(ctx, time.Now().Add(time.Second))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, n, 1)
+		w, err = s.GetByID(ctx, w.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(500), w.Balance)
+	})
+}
+
+func TestWalletService_LocalGiftEngine(t *testing.T) {
+	psql := testPostgres(t)
+	ctx := context.Background()
+	gifts := giftService.New(giftStorage.NewStorage(psql))
+	rdb := dbMocks.NewRedisClient(t)
+	rdb.On("Del", mock.Anything, mock.Anything).Return(redis.NewIntCmd(ctx)).Maybe()
+	s := newGiftTestService(psql, gifts, rdb)
+
+	m := &memberStorage.Member{FirstName: "a", LastName: "b", Phone: fmt.Sprintf("+98%d", time.Now().UnixNano()%1e10)}
+	require.NoError(t, memberStorage.NewStorage(psql).Create(ctx, m))
+	w, err := s.Create(ctx, &wallet.CreateRequest{MemberID: m.ID, WalletName: "local gift"})
+	require.NoError(t, err)
+	g, err := gifts.Create(ctx, &giftService.CreateRequest{
+		Code:  fmt.Sprintf("LOCAL%d", time.Now().UnixNano()),
+		Terms: giftService.Terms{Amount: 700, UsageLimit: 5, ExpiresAt: time.Now().Add(time.Hour)},
+	})
+	require.NoError(t, err)
+
+	w, err = s.AddGift(ctx, &wallet.AddGiftRequest{MemberID: m.ID, WalletID: w.ID, GiftCode: g.Code}, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(700), w.Balance)
+	g, err = gifts.GetByID(ctx, g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), g.UsedCount)
+
+	_, err = s.AddGift(ctx, &wallet.AddGiftRequest{MemberID: m.ID, WalletID: w.ID, GiftCode: "UNKNOWN"}, "")
+	var e *serr.ServiceError
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, serr.ErrGiftNotFound, e.ErrorCode)
+
+	t.Run("redeems a gift up to its member limit", func(t *testing.T) {
+		twice, err := gifts.Create(ctx, &giftService.CreateRequest{
+			Code:  fmt.Sprintf("TWICE%d", time.Now().UnixNano()),
+			Terms: giftService.Terms{Amount: 100, UsageLimit: 5, PerMemberLimit: 2, ExpiresAt: time.Now().Add(time.Hour)},
+		})
+		require.NoError(t, err)
+		r := &wallet.AddGiftRequest{MemberID: m.ID, WalletID: w.ID, GiftCode: twice.Code}
+
+		got, err := s.AddGift(ctx, r, "")
+		require.NoError(t, err)
+		assert.Equal(t, w.Balance+100, got.Balance)
+		got, err = s.AddGift(ctx, r, "")
+		require.NoError(t, err)
+		assert.Equal(t, w.Balance+200, got.Balance)
+
+		_, err = s.AddGift(ctx, r, "")
+		var e *serr.ServiceError
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, serr.ErrGiftMemberLimitReached, e.ErrorCode)
+		twice, err = gifts.GetByID(ctx, twice.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), twice.UsedCount)
+	})
+}
+
+func TestWalletService_RecoverRedemptions(t *testing.T) {
+	ctx := context.Background()
+	redemptions := redemptionMocks.NewRepository(t)
+	discounts := discountMocks.NewClient(t)
+	s := wallet.New(nil, nil, nil, nil, nil, nil, redemptions, nil, discounts, nil, nil)
+
+	rejected := &redemptionStorage.Redemption{ID: 1, GiftCode: "EXPIRED", Reference: "r1", Status: redemptionStorage.Pending}
+	unanswered := &redemptionStorage.Redemption{ID: 2, GiftCode: "GIFT", Reference: "r2", Status: redemptionStorage.Pending}
+	before := time.Now()
+	redemptions.On("GetStuck", mock.Anything, before, mock.Anything).
+		Return([]*redemptionStorage.Redemption{rejected, unanswered}, nil)
+	discounts.On("UseGift", mock.Anything, "EXPIRED", int64(0), "r1").
+		Return(nil, serr.ValidationErr("useGift", "gift expired", serr.ErrDiscountClient))
+	discounts.On("UseGift", mock.Anything, "GIFT", int64(0), "r2").
+		Return(nil, serr.UnavailableErr("useGift", "discount service is unavailable", serr.ErrDiscountUnavailable, nil))
+	redemptions.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil)
+
+	// the rejected use fails its redemption, the unanswered one is left for the next run
+	n, err := s.RecoverRedemptions(ctx, before)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, redemptionStorage.Failed, rejected.Status)
+	assert.Equal(t, redemptionStorage.Pending, unanswered.Status)
+	redemptions.AssertNumberOfCalls(t, "UpdateStatus", 1)
+}
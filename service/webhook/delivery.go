@@ -0,0 +1,187 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+	"wallet/internal/serr"
+	"wallet/service/outbox"
+	"wallet/storage/webhook"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	deliveryBatchSize = 50
+	maxAttempts       = 10
+	firstRetryDelay   = 30 * time.Second
+	maxRetryDelay     = 6 * time.Hour
+	// a claimed delivery is not sent again before its sender had time to give up on it
+	claimLease = 2 * sendTimeout
+
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Sign returns the signature of a payload sent at timestamp, the hex HMAC-SHA256 of
+// "<timestamp>.<payload>" keyed with the subscription secret.
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Publish queues a delivery of the event to each active subscription to its type. It is the
+// outbox publisher of webhooks, a redelivered event is queued once per subscription.
+func (s *Service) Publish(ctx context.Context, e *outbox.Event) error {
+	subs, err := s.webhook.GetSubscriptionsByEventType(ctx, string(e.Type))
+	if err != nil {
+		return err
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		err = s.webhook.CreateDelivery(ctx, &webhook.Delivery{
+			SubscriptionID: sub.ID,
+			EventID:        e.ID,
+			EventType:      string(e.Type),
+			Payload:        payload,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeliverDue sends the deliveries whose attempt is due and returns how many succeeded. A failed
+// attempt is retried with an exponential backoff until maxAttempts.
+func (s *Service) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := s.webhook.ClaimDue(ctx, deliveryBatchSize, claimLease)
+	if err != nil {
+		return 0, err
+	}
+	delivered := 0
+	for _, d := range deliveries {
+		if ctx.Err() != nil {
+			return delivered, ctx.Err()
+		}
+		sub, err := s.webhook.GetSubscription(ctx, d.SubscriptionID)
+		if err != nil {
+			return delivered, err
+		}
+		s.attempt(ctx, sub, d)
+		if err = s.webhook.UpdateDelivery(ctx, d); err != nil {
+			return delivered, err
+		}
+		if d.Status == webhook.Succeeded {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// attempt sends a delivery and records its outcome in d.
+func (s *Service) attempt(ctx context.Context, sub *webhook.Subscription, d *webhook.Delivery) {
+	d.Attempts++
+	d.LastStatusCode, d.LastError = 0, ""
+	if !sub.Active {
+		d.LastError = "subscription is paused"
+	} else if code, err := s.send(ctx, sub, d); err != nil {
+		d.LastStatusCode, d.LastError = code, err.Error()
+	} else {
+		now := time.Now()
+		d.Status, d.LastStatusCode, d.DeliveredAt = webhook.Succeeded, code, &now
+		return
+	}
+	if d.Attempts >= maxAttempts {
+		d.Status = webhook.Failed
+		log.Warn().Int64("delivery_id", d.ID).Int64("subscription_id", sub.ID).Str("error", d.LastError).
+			Msg("webhook delivery failed")
+		return
+	}
+	d.NextAttemptAt = time.Now().Add(backoff(d.Attempts))
+}
+
+// send posts the payload of a delivery to the subscription url, any status but 2xx fails it.
+func (s *Service) send(ctx context.Context, sub *webhook.Subscription, d *webhook.Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, d.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(d.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, ts, d.Payload))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay before the attempt following the given number of attempts.
+func backoff(attempts int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// Deliveries returns the delivery log of a subscription, newest first.
+func (s *Service) Deliveries(ctx context.Context, id int64, limit, offset int) ([]*DeliveryDTO, error) {
+	if _, err := s.webhook.GetSubscription(ctx, id); err != nil {
+		return nil, err
+	}
+	deliveries, err := s.webhook.GetDeliveries(ctx, id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*DeliveryDTO, 0, len(deliveries))
+	for _, d := range deliveries {
+		dtos = append(dtos, s.FromDeliveryModel(d))
+	}
+	return dtos, nil
+}
+
+// Replay queues a delivery of a subscription to be sent again now, with a fresh count of attempts.
+func (s *Service) Replay(ctx context.Context, id, deliveryID int64) (*DeliveryDTO, error) {
+	d, err := s.webhook.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if d.SubscriptionID != id {
+		return nil, serr.DBError("Replay", "webhook delivery", sql.ErrNoRows)
+	}
+	d.Status, d.Attempts, d.NextAttemptAt = webhook.Pending, 0, time.Now()
+	if err = s.webhook.UpdateDelivery(ctx, d); err != nil {
+		return nil, err
+	}
+	return s.FromDeliveryModel(d), nil
+}
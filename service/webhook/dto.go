@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+	"wallet/service/outbox"
+)
+
+// DTO is a webhook subscription, its secret is shown only when it is created.
+type DTO struct {
+	ID         int64              `json:"id"`
+	URL        string             `json:"url"`
+	EventTypes []outbox.EventType `json:"eventTypes"`
+	Active     bool               `json:"active"`
+	Secret     string             `json:"secret,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	UpdatedAt  time.Time          `json:"updatedAt"`
+}
+
+type CreateRequest struct {
+	URL        string             `json:"url"`
+	EventTypes []outbox.EventType `json:"eventTypes"`
+}
+
+// UpdateRequest replaces the url and event types of a subscription, it is paused or resumed when
+// Active is set.
+type UpdateRequest struct {
+	URL        string             `json:"url"`
+	EventTypes []outbox.EventType `json:"eventTypes"`
+	Active     *bool              `json:"active,omitempty"`
+}
+
+type DeliveryDTO struct {
+	ID             int64            `json:"id"`
+	SubscriptionID int64            `json:"subscriptionID"`
+	EventID        int64            `json:"eventID"`
+	EventType      outbox.EventType `json:"eventType"`
+	Payload        json.RawMessage  `json:"payload" swaggertype:"object"`
+	Status         string           `json:"status"`
+	Attempts       int              `json:"attempts"`
+	LastStatusCode int              `json:"lastStatusCode,omitempty"`
+	LastError      string           `json:"lastError,omitempty"`
+	NextAttemptAt  time.Time        `json:"nextAttemptAt"`
+	DeliveredAt    *time.Time       `json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time        `json:"createdAt"`
+}
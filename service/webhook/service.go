@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"time"
+	"wallet/service/outbox"
+	"wallet/storage/webhook"
+)
+
+const sendTimeout = 10 * time.Second
+
+type UseCase interface {
+	Create(ctx context.Context, r *CreateRequest) (*DTO, error)
+	GetByID(ctx context.Context, id int64) (*DTO, error)
+	List(ctx context.Context, limit, offset int) ([]*DTO, error)
+	Update(ctx context.Context, id int64, r *UpdateRequest) (*DTO, error)
+	Delete(ctx context.Context, id int64) error
+	Deliveries(ctx context.Context, id int64, limit, offset int) ([]*DeliveryDTO, error)
+	Replay(ctx context.Context, id, deliveryID int64) (*DeliveryDTO, error)
+	Publish(ctx context.Context, e *outbox.Event) error
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+type Service struct {
+	webhook webhook.Repository
+	client  *http.Client
+}
+
+func New(webhook webhook.Repository) *Service {
+	return &Service{webhook: webhook, client: &http.Client{Timeout: sendTimeout}}
+}
+
+func (s *Service) FromDBModel(sub *webhook.Subscription) *DTO {
+	dto := &DTO{
+		ID:        sub.ID,
+		URL:       sub.URL,
+		Active:    sub.Active,
+		CreatedAt: sub.CreatedAt,
+		UpdatedAt: sub.UpdatedAt,
+	}
+	for _, t := range sub.EventTypes {
+		dto.EventTypes = append(dto.EventTypes, outbox.EventType(t))
+	}
+	return dto
+}
+
+func (s *Service) FromDeliveryModel(d *webhook.Delivery) *DeliveryDTO {
+	return &DeliveryDTO{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      outbox.EventType(d.EventType),
+		Payload:        d.Payload,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		NextAttemptAt:  d.NextAttemptAt,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+	}
+}
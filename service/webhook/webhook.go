@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"wallet/internal/serr"
+	"wallet/service/outbox"
+	"wallet/storage/webhook"
+)
+
+// Create registers a url for events of the given types with a new signing secret.
+func (s *Service) Create(ctx context.Context, r *CreateRequest) (*DTO, error) {
+	eventTypes, err := validate(r.URL, r.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	sub := &webhook.Subscription{URL: r.URL, EventTypes: eventTypes, Secret: secret, Active: true}
+	if err = s.webhook.CreateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	dto := s.FromDBModel(sub)
+	dto.Secret = sub.Secret
+	return dto, nil
+}
+
+func (s *Service) GetByID(ctx context.Context, id int64) (*DTO, error) {
+	sub, err := s.webhook.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.FromDBModel(sub), nil
+}
+
+func (s *Service) List(ctx context.Context, limit, offset int) ([]*DTO, error) {
+	subs, err := s.webhook.GetSubscriptions(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*DTO, 0, len(subs))
+	for _, sub := range subs {
+		dtos = append(dtos, s.FromDBModel(sub))
+	}
+	return dtos, nil
+}
+
+func (s *Service) Update(ctx context.Context, id int64, r *UpdateRequest) (*DTO, error) {
+	eventTypes, err := validate(r.URL, r.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.webhook.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	sub.URL, sub.EventTypes = r.URL, eventTypes
+	if r.Active != nil {
+		sub.Active = *r.Active
+	}
+	if err = s.webhook.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return s.FromDBModel(sub), nil
+}
+
+// Delete removes a subscription with its delivery log.
+func (s *Service) Delete(ctx context.Context, id int64) error {
+	return s.webhook.DeleteSubscription(ctx, id)
+}
+
+// validate checks the url and event types of a subscription and returns the event types as stored.
+func validate(rawURL string, eventTypes []outbox.EventType) ([]string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || !u.IsAbs() || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, serr.ValidationErr("webhook", "invalid webhook url", serr.ErrInvalidWebhookURL)
+	}
+	if len(eventTypes) == 0 {
+		return nil, serr.ValidationErr("webhook", "at least one event type is required", serr.ErrInvalidEventType)
+	}
+	types := make([]string, 0, len(eventTypes))
+	seen := make(map[outbox.EventType]bool, len(eventTypes))
+	for _, t := range eventTypes {
+		if !t.Valid() {
+			return nil, serr.ValidationErr("webhook", "invalid event type", serr.ErrInvalidEventType)
+		}
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, string(t))
+		}
+	}
+	return types, nil
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
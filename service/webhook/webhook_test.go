@@ -0,0 +1,173 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+	"wallet/internal/serr"
+	repomocks "wallet/mocks/repomocks/webhook"
+	"wallet/service/outbox"
+	"wallet/service/webhook"
+	webhookStorage "wallet/storage/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{"id":1}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=3dd1b9aef568d75f6790a84bd2e5dfa1f44409eef3cbdbd3f10b837376100c11",
+		webhook.Sign("secret", 1700000000, []byte(`{"id":1}`)))
+}
+
+func TestCreate(t *testing.T) {
+	mockRepo := repomocks.NewRepository(t)
+	mockRepo.On("CreateSubscription", mock.Anything, mock.MatchedBy(func(s *webhookStorage.Subscription) bool {
+		return s.URL == "https://example.com/hook" && len(s.Secret) == 64 && s.Active &&
+			assert.ObjectsAreEqual([]string{"BalanceCredited", "BalanceDebited"}, s.EventTypes)
+	})).Return(nil)
+
+	dto, err := webhook.New(mockRepo).Create(context.Background(), &webhook.CreateRequest{
+		URL:        "https://example.com/hook",
+		EventTypes: []outbox.EventType{outbox.BalanceCredited, outbox.BalanceDebited, outbox.BalanceCredited},
+	})
+	require.NoError(t, err)
+	assert.Len(t, dto.Secret, 64)
+}
+
+func TestCreate_Invalid(t *testing.T) {
+	s := webhook.New(repomocks.NewRepository(t))
+	for _, tc := range []struct {
+		name string
+		req  webhook.CreateRequest
+		code serr.ErrorCode
+	}{
+		{"relative url", webhook.CreateRequest{URL: "/hook", EventTypes: []outbox.EventType{outbox.WalletCreated}},
+			serr.ErrInvalidWebhookURL},
+		{"unsupported scheme", webhook.CreateRequest{URL: "ftp://example.com", EventTypes: []outbox.EventType{outbox.WalletCreated}},
+			serr.ErrInvalidWebhookURL},
+		{"no event types", webhook.CreateRequest{URL: "https://example.com"}, serr.ErrInvalidEventType},
+		{"unknown event type", webhook.CreateRequest{URL: "https://example.com", EventTypes: []outbox.EventType{"Unknown"}},
+			serr.ErrInvalidEventType},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := s.Create(context.Background(), &tc.req)
+			var e *serr.ServiceError
+			require.True(t, errors.As(err, &e))
+			assert.Equal(t, tc.code, e.ErrorCode)
+		})
+	}
+}
+
+func TestPublish(t *testing.T) {
+	e := &outbox.Event{ID: 7, Type: outbox.BalanceCredited, WalletID: 1, Payload: json.RawMessage(`{}`)}
+	mockRepo := repomocks.NewRepository(t)
+	mockRepo.On("GetSubscriptionsByEventType", mock.Anything, "BalanceCredited").
+		Return([]*webhookStorage.Subscription{{ID: 1}, {ID: 2}}, nil)
+	for _, id := range []int64{1, 2} {
+		id := id
+		mockRepo.On("CreateDelivery", mock.Anything, mock.MatchedBy(func(d *webhookStorage.Delivery) bool {
+			return d.SubscriptionID == id && d.EventID == 7 && d.EventType == "BalanceCredited"
+		})).Return(nil).Once()
+	}
+
+	err := webhook.New(mockRepo).Publish(context.Background(), e)
+	assert.NoError(t, err)
+}
+
+func TestDeliverDue(t *testing.T) {
+	payload := []byte(`{"id":7}`)
+	var status = http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		assert.Equal(t, webhook.Sign("secret", ts, body), r.Header.Get(webhook.HeaderSignature))
+		assert.Equal(t, "BalanceCredited", r.Header.Get(webhook.HeaderEvent))
+		assert.Equal(t, "3", r.Header.Get(webhook.HeaderDelivery))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sub := &webhookStorage.Subscription{ID: 1, URL: srv.URL, Secret: "secret", Active: true}
+	newDelivery := func(attempts int) *webhookStorage.Delivery {
+		return &webhookStorage.Delivery{ID: 3, SubscriptionID: 1, EventType: "BalanceCredited", Payload: payload,
+			Status: webhookStorage.Pending, Attempts: attempts}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		status = http.StatusOK
+		mockRepo := repomocks.NewRepository(t)
+		mockRepo.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything).Return([]*webhookStorage.Delivery{newDelivery(0)}, nil)
+		mockRepo.On("GetSubscription", mock.Anything, int64(1)).Return(sub, nil)
+		mockRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d *webhookStorage.Delivery) bool {
+			return d.Status == webhookStorage.Succeeded && d.Attempts == 1 && d.LastStatusCode == 200 &&
+				d.DeliveredAt != nil
+		})).Return(nil)
+
+		n, err := webhook.New(mockRepo).DeliverDue(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		status = http.StatusServiceUnavailable
+		mockRepo := repomocks.NewRepository(t)
+		mockRepo.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything).Return([]*webhookStorage.Delivery{newDelivery(2)}, nil)
+		mockRepo.On("GetSubscription", mock.Anything, int64(1)).Return(sub, nil)
+		mockRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d *webhookStorage.Delivery) bool {
+			// the third attempt is retried 2 minutes later
+			delay := time.Until(d.NextAttemptAt)
+			return d.Status == webhookStorage.Pending && d.Attempts == 3 && d.LastStatusCode == 503 &&
+				delay > 119*time.Second && delay <= 120*time.Second
+		})).Return(nil)
+
+		n, err := webhook.New(mockRepo).DeliverDue(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("give up", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		mockRepo := repomocks.NewRepository(t)
+		mockRepo.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything).Return([]*webhookStorage.Delivery{newDelivery(9)}, nil)
+		mockRepo.On("GetSubscription", mock.Anything, int64(1)).Return(sub, nil)
+		mockRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d *webhookStorage.Delivery) bool {
+			return d.Status == webhookStorage.Failed && d.Attempts == 10
+		})).Return(nil)
+
+		_, err := webhook.New(mockRepo).DeliverDue(context.Background())
+		assert.NoError(t, err)
+	})
+}
+
+func TestReplay(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockRepo := repomocks.NewRepository(t)
+		mockRepo.On("GetDelivery", mock.Anything, int64(3)).Return(&webhookStorage.Delivery{ID: 3, SubscriptionID: 1,
+			Status: webhookStorage.Failed, Attempts: 10}, nil)
+		mockRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d *webhookStorage.Delivery) bool {
+			return d.Status == webhookStorage.Pending && d.Attempts == 0 && !d.NextAttemptAt.After(time.Now())
+		})).Return(nil)
+
+		d, err := webhook.New(mockRepo).Replay(context.Background(), 1, 3)
+		require.NoError(t, err)
+		assert.Equal(t, "pending", d.Status)
+	})
+
+	t.Run("other subscription", func(t *testing.T) {
+		mockRepo := repomocks.NewRepository(t)
+		mockRepo.On("GetDelivery", mock.Anything, int64(3)).Return(&webhookStorage.Delivery{ID: 3, SubscriptionID: 2}, nil)
+
+		_, err := webhook.New(mockRepo).Replay(context.Background(), 1, 3)
+		var e *serr.ServiceError
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, http.StatusNotFound, e.Code)
+	})
+}
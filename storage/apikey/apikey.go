@@ -0,0 +1,82 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"wallet/internal/serr"
+
+	"github.com/lib/pq"
+)
+
+const columns = "id,name,prefix,key_hash,scopes,expires_at,revoked_at,created_at,updated_at"
+
+func (s Storage) Create(ctx context.Context, k *APIKey) error {
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO api_key (name, prefix, key_hash, scopes)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`, k.Name, k.Prefix, k.KeyHash, pq.Array(k.Scopes)).Scan(&k.ID, &k.CreatedAt, &k.UpdatedAt)
+	if err != nil {
+		return serr.DBError("Create", "api key", err)
+	}
+	return nil
+}
+
+func (s Storage) GetByID(ctx context.Context, id int64) (*APIKey, error) {
+	k, err := s.Scan(s.db.QueryRowContext(ctx, "SELECT "+columns+" FROM api_key WHERE id = $1", id))
+	if err != nil {
+		return nil, serr.DBError("GetByID", "api key", err)
+	}
+	return k, nil
+}
+
+func (s Storage) GetByHash(ctx context.Context, keyHash string) (*APIKey, error) {
+	k, err := s.Scan(s.db.QueryRowContext(ctx, "SELECT "+columns+" FROM api_key WHERE key_hash = $1", keyHash))
+	if err != nil {
+		return nil, serr.DBError("GetByHash", "api key", err)
+	}
+	return k, nil
+}
+
+func (s Storage) GetAll(ctx context.Context, limit, offset int) ([]*APIKey, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT "+columns+" FROM api_key ORDER BY id LIMIT $1 OFFSET $2", limit, offset)
+	if err != nil {
+		return nil, serr.DBError("GetAll", "api key", err)
+	}
+	defer rows.Close()
+	keys := make([]*APIKey, 0)
+	for rows.Next() {
+		k, err := s.Scan(rows)
+		if err != nil {
+			return nil, serr.DBError("GetAll", "api key", err)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// Revoke stops a key from working, revoking a revoked key keeps the time it was first revoked.
+func (s Storage) Revoke(ctx context.Context, id int64) error {
+	res, err := s.db.ExecContext(ctx,
+		"UPDATE api_key SET revoked_at = COALESCE(revoked_at, now()), updated_at = now() WHERE id = $1", id)
+	if err != nil {
+		return serr.DBError("Revoke", "api key", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return serr.DBError("Revoke", "api key", sql.ErrNoRows)
+	}
+	return nil
+}
+
+// Expire makes a key stop working at the given time.
+func (s Storage) Expire(ctx context.Context, id int64, at time.Time) error {
+	res, err := s.db.ExecContext(ctx, "UPDATE api_key SET expires_at = $1, updated_at = now() WHERE id = $2", at, id)
+	if err != nil {
+		return serr.DBError("Expire", "api key", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return serr.DBError("Expire", "api key", sql.ErrNoRows)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package apikey_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"wallet/db/dbtest"
+	"wallet/storage/apikey"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// create stores a key with a hash no other test uses.
+func create(t *testing.T, s apikey.Storage, scopes ...string) *apikey.APIKey {
+	t.Helper()
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s-%d", t.Name(), time.Now().UnixNano())))
+	k := &apikey.APIKey{Name: "billing", Prefix: "wk_test", KeyHash: hex.EncodeToString(hash[:]), Scopes: scopes}
+	require.NoError(t, s.Create(context.Background(), k))
+	return k
+}
+
+func TestCreate(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := apikey.NewStorage(psql)
+	ctx := context.Background()
+	k := create(t, s, "wallet:read", "wallet:refund")
+	assert.NotZero(t, k.ID)
+
+	got, err := s.GetByHash(ctx, k.KeyHash)
+	require.NoError(t, err)
+	assert.Equal(t, k.ID, got.ID)
+	assert.Equal(t, []string{"wallet:read", "wallet:refund"}, got.Scopes)
+	assert.Nil(t, got.ExpiresAt)
+	assert.Nil(t, got.RevokedAt)
+
+	// hashes are unique
+	assert.Error(t, s.Create(ctx, &apikey.APIKey{Name: "copy", Prefix: "wk_test", KeyHash: k.KeyHash,
+		Scopes: []string{"wallet:read"}}))
+
+	_, err = s.GetByHash(ctx, "unknown")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestRevoke(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := apikey.NewStorage(psql)
+	ctx := context.Background()
+	k := create(t, s, "wallet:read")
+
+	require.NoError(t, s.Revoke(ctx, k.ID))
+	first, err := s.GetByID(ctx, k.ID)
+	require.NoError(t, err)
+	require.NotNil(t, first.RevokedAt)
+
+	// revoking again keeps the time it was first revoked
+	require.NoError(t, s.Revoke(ctx, k.ID))
+	again, err := s.GetByID(ctx, k.ID)
+	require.NoError(t, err)
+	assert.True(t, first.RevokedAt.Equal(*again.RevokedAt))
+
+	assert.ErrorIs(t, s.Revoke(ctx, 0), sql.ErrNoRows)
+}
+
+func TestExpire(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := apikey.NewStorage(psql)
+	ctx := context.Background()
+	k := create(t, s, "wallet:read")
+
+	at := time.Now().Add(time.Hour).Truncate(time.Microsecond)
+	require.NoError(t, s.Expire(ctx, k.ID, at))
+	got, err := s.GetByID(ctx, k.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got.ExpiresAt)
+	assert.True(t, at.Equal(*got.ExpiresAt))
+
+	assert.ErrorIs(t, s.Expire(ctx, 0, at), sql.ErrNoRows)
+}
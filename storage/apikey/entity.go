@@ -0,0 +1,17 @@
+package apikey
+
+import "time"
+
+// APIKey authenticates a service calling the api with the given scopes. Keys are looked up by their
+// hash, Prefix is the start of the key kept to tell keys apart.
+type APIKey struct {
+	ID        int64      `db:"id"`
+	Name      string     `db:"name"`
+	Prefix    string     `db:"prefix"`
+	KeyHash   string     `db:"key_hash"`
+	Scopes    []string   `db:"scopes"`
+	ExpiresAt *time.Time `db:"expires_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at"`
+}
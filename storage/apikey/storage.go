@@ -0,0 +1,44 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"wallet/db"
+
+	"github.com/lib/pq"
+)
+
+type Repository interface {
+	Create(ctx context.Context, k *APIKey) error
+	GetByID(ctx context.Context, id int64) (*APIKey, error)
+	GetByHash(ctx context.Context, keyHash string) (*APIKey, error)
+	GetAll(ctx context.Context, limit, offset int) ([]*APIKey, error)
+	Revoke(ctx context.Context, id int64) error
+	Expire(ctx context.Context, id int64, at time.Time) error
+}
+
+type Storage struct {
+	db db.SQLExt
+}
+
+func NewStorage(psql *sql.DB) Storage {
+	return Storage{db: db.NewConn(psql)}
+}
+
+func (s Storage) Scan(scanner db.Scanner) (*APIKey, error) {
+	k := &APIKey{}
+	var expiresAt, revokedAt sql.NullTime
+	err := scanner.Scan(&k.ID, &k.Name, &k.Prefix, &k.KeyHash, pq.Array(&k.Scopes), &expiresAt, &revokedAt,
+		&k.CreatedAt, &k.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		k.ExpiresAt = &expiresAt.Time
+	}
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.Time
+	}
+	return k, nil
+}
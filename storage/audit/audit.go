@@ -0,0 +1,113 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"wallet/internal/serr"
+)
+
+const (
+	entryColumns = "id,seq,actor,action,entity,entity_id,before,after,trace_id,client_ip,prev_hash,hash,created_at"
+	// sealLockKey is the advisory lock taken by the sealing job, so entries are chained by one
+	// sealer at a time. Writers of entries never take it.
+	sealLockKey = 7_210_413
+)
+
+// Lock takes the lock of the sealing job until the surrounding db transaction ends.
+func (s Storage) Lock(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", sealLockKey); err != nil {
+		return serr.DBError("Lock", "audit log", err)
+	}
+	return nil
+}
+
+// LastSealed returns the position and hash of the latest entry in the chain, zero and an empty string
+// when no entry was sealed yet.
+func (s Storage) LastSealed(ctx context.Context) (int64, string, error) {
+	var seq int64
+	var hash string
+	err := s.db.QueryRowContext(ctx,
+		"SELECT seq, hash FROM audit_log WHERE seq IS NOT NULL ORDER BY seq DESC LIMIT 1").Scan(&seq, &hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", serr.DBError("LastSealed", "audit log", err)
+	}
+	return seq, hash, nil
+}
+
+// Insert writes an entry outside the chain, the sealing job chains it later.
+func (s Storage) Insert(ctx context.Context, e *Entry) error {
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO audit_log (actor, action, entity, entity_id, before, after, trace_id, client_ip, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`, e.Actor, e.Action, e.Entity, e.EntityID, nullJSON(e.Before), nullJSON(e.After), e.TraceID, e.ClientIP,
+		e.CreatedAt).Scan(&e.ID)
+	if err != nil {
+		return serr.DBError("Insert", "audit log", err)
+	}
+	return nil
+}
+
+// GetUnsealed returns the committed entries not chained yet, oldest first.
+func (s Storage) GetUnsealed(ctx context.Context, limit int) ([]*Entry, error) {
+	sqlStmt := "SELECT " + entryColumns + " FROM audit_log WHERE seq IS NULL ORDER BY id LIMIT $1"
+	return s.queryEntries(ctx, "GetUnsealed", sqlStmt, limit)
+}
+
+// Seal stores the position of an entry in the chain and its hashes. An entry is sealed only once.
+func (s Storage) Seal(ctx context.Context, e *Entry) error {
+	res, err := s.db.ExecContext(ctx,
+		"UPDATE audit_log SET seq = $1, prev_hash = $2, hash = $3 WHERE id = $4 AND seq IS NULL",
+		e.Seq, e.PrevHash, e.Hash, e.ID)
+	if err != nil {
+		return serr.DBError("Seal", "audit log", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return serr.DBError("Seal", "audit log", sql.ErrNoRows)
+	}
+	return nil
+}
+
+// Find returns the entries matching the filter.
+func (s Storage) Find(ctx context.Context, f *Filter) ([]*Entry, error) {
+	sqlStmt, args := f.Query()
+	return s.queryEntries(ctx, "Find", sqlStmt, args...)
+}
+
+// GetAfter returns the sealed entries following position seq in chain order.
+func (s Storage) GetAfter(ctx context.Context, seq int64, limit int) ([]*Entry, error) {
+	sqlStmt := "SELECT " + entryColumns + " FROM audit_log WHERE seq > $1 ORDER BY seq LIMIT $2"
+	return s.queryEntries(ctx, "GetAfter", sqlStmt, seq, limit)
+}
+
+func (s Storage) queryEntries(ctx context.Context, method, sqlStmt string, args ...any) ([]*Entry, error) {
+	rows, err := s.db.QueryContext(ctx, sqlStmt, args...)
+	if err != nil {
+		return nil, serr.DBError(method, "audit log", err)
+	}
+	defer rows.Close()
+	entries := make([]*Entry, 0)
+	for rows.Next() {
+		e, err := s.ScanEntry(rows)
+		if err != nil {
+			return nil, serr.DBError(method, "audit log", err)
+		}
+		entries = append(entries, e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, serr.DBError(method, "audit log", err)
+	}
+	return entries, nil
+}
+
+// nullJSON stores a missing document as NULL.
+func nullJSON(doc []byte) any {
+	if doc == nil {
+		return nil
+	}
+	return string(doc)
+}
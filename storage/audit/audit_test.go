@@ -0,0 +1,108 @@
+package audit_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"wallet/db"
+	"wallet/db/dbtest"
+	"wallet/storage/audit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errRollback ends a test transaction without committing it, so the chain of the shared database
+// is left alone.
+var errRollback = errors.New("rollback")
+
+func TestSeal(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := audit.NewStorage(psql)
+
+	err := db.Transaction(context.Background(), func(ctx context.Context) error {
+		// holding the lock of the sealing job keeps it from sealing the entry first
+		require.NoError(t, s.Lock(ctx))
+		e := &audit.Entry{Actor: "admin:1", Action: "wallet.close", Entity: "wallet", EntityID: 1,
+			After: []byte(`{"status":"closed"}`), CreatedAt: time.Now().UTC().Truncate(time.Microsecond)}
+		require.NoError(t, s.Insert(ctx, e))
+
+		unsealed, err := s.GetUnsealed(ctx, 10000)
+		require.NoError(t, err)
+		var found *audit.Entry
+		for _, u := range unsealed {
+			if u.ID == e.ID {
+				found = u
+			}
+		}
+		require.NotNil(t, found)
+		assert.Zero(t, found.Seq)
+		assert.Empty(t, found.Hash)
+
+		seq, _, err := s.LastSealed(ctx)
+		require.NoError(t, err)
+		e.Seq, e.PrevHash, e.Hash = seq+1, "prev", "hash-"+t.Name()
+		require.NoError(t, s.Seal(ctx, e))
+		last, hash, err := s.LastSealed(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, e.Seq, last)
+		assert.Equal(t, e.Hash, hash)
+
+		sealed, err := s.GetAfter(ctx, seq, 10)
+		require.NoError(t, err)
+		require.Len(t, sealed, 1)
+		assert.Equal(t, e.ID, sealed[0].ID)
+		assert.JSONEq(t, `{"status":"closed"}`, string(sealed[0].After))
+
+		// an entry is sealed once
+		assert.ErrorIs(t, s.Seal(ctx, e), sql.ErrNoRows)
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+}
+
+func TestImmutable(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := audit.NewStorage(psql)
+	e := &audit.Entry{Actor: "admin:1", Action: "wallet.close", Entity: "wallet", EntityID: 1,
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond)}
+	require.NoError(t, s.Insert(context.Background(), e))
+
+	// sealing may not change what the entry records
+	_, err := psql.Exec("UPDATE audit_log SET seq = -id, prev_hash = 'prev', hash = 'hash', actor = 'system' WHERE id = $1", e.ID)
+	assert.ErrorContains(t, err, "audit log entries are immutable")
+	_, err = psql.Exec("DELETE FROM audit_log WHERE id = $1", e.ID)
+	assert.ErrorContains(t, err, "audit log entries are immutable")
+}
+
+func TestFilterQuery(t *testing.T) {
+	t.Run("no filter", func(t *testing.T) {
+		sqlStmt, args := (&audit.Filter{}).Query()
+		assert.True(t, strings.HasSuffix(sqlStmt, " FROM audit_log ORDER BY id DESC"))
+		assert.Empty(t, args)
+	})
+
+	t.Run("all filters", func(t *testing.T) {
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 1, 0)
+		f := &audit.Filter{
+			Actor:       "admin:1",
+			Action:      "wallet.freeze",
+			Entity:      "wallet",
+			EntityID:    7,
+			TraceID:     "trace",
+			CreatedFrom: from,
+			CreatedTo:   to,
+			Limit:       10,
+			Offset:      20,
+		}
+		sqlStmt, args := f.Query()
+		assert.True(t, strings.HasSuffix(sqlStmt, " FROM audit_log WHERE actor = $1 AND action = $2 AND entity = $3"+
+			" AND entity_id = $4 AND trace_id = $5 AND created_at >= $6 AND created_at < $7 ORDER BY id DESC"+
+			" LIMIT $8 OFFSET $9"))
+		assert.Equal(t, []any{"admin:1", "wallet.freeze", "wallet", int64(7), "trace", from, to, 10, 20}, args)
+	})
+}
@@ -0,0 +1,21 @@
+package audit
+
+import "time"
+
+// Entry is an action recorded in the audit log. Hash covers every other field but ID and Seq, and
+// PrevHash is the hash of the entry before it. Seq and the hashes are set when the entry is sealed.
+type Entry struct {
+	ID        int64     `db:"id"`
+	Seq       int64     `db:"seq"`
+	Actor     string    `db:"actor"`
+	Action    string    `db:"action"`
+	Entity    string    `db:"entity"`
+	EntityID  int64     `db:"entity_id"`
+	Before    []byte    `db:"before"`
+	After     []byte    `db:"after"`
+	TraceID   string    `db:"trace_id"`
+	ClientIP  string    `db:"client_ip"`
+	PrevHash  string    `db:"prev_hash"`
+	Hash      string    `db:"hash"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Filter selects entries for Find, zero fields match everything. Results are ordered newest first.
+type Filter struct {
+	Actor    string
+	Action   string
+	Entity   string
+	EntityID int64
+	TraceID  string
+	// CreatedFrom is inclusive and CreatedTo exclusive
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Limit       int
+	Offset      int
+}
+
+// Query returns the select statement of the filter and its arguments.
+func (f *Filter) Query() (string, []any) {
+	var conditions []string
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+	if f.Actor != "" {
+		conditions = append(conditions, "actor = "+arg(f.Actor))
+	}
+	if f.Action != "" {
+		conditions = append(conditions, "action = "+arg(f.Action))
+	}
+	if f.Entity != "" {
+		conditions = append(conditions, "entity = "+arg(f.Entity))
+	}
+	if f.EntityID != 0 {
+		conditions = append(conditions, "entity_id = "+arg(f.EntityID))
+	}
+	if f.TraceID != "" {
+		conditions = append(conditions, "trace_id = "+arg(f.TraceID))
+	}
+	if !f.CreatedFrom.IsZero() {
+		conditions = append(conditions, "created_at >= "+arg(f.CreatedFrom))
+	}
+	if !f.CreatedTo.IsZero() {
+		conditions = append(conditions, "created_at < "+arg(f.CreatedTo))
+	}
+	sqlStmt := "SELECT " + entryColumns + " FROM audit_log"
+	if len(conditions) > 0 {
+		sqlStmt += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	sqlStmt += " ORDER BY id DESC"
+	if f.Limit > 0 {
+		sqlStmt += " LIMIT " + arg(f.Limit)
+	}
+	if f.Offset > 0 {
+		sqlStmt += " OFFSET " + arg(f.Offset)
+	}
+	return sqlStmt, args
+}
@@ -0,0 +1,38 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"wallet/db"
+)
+
+type Repository interface {
+	Lock(ctx context.Context) error
+	LastSealed(ctx context.Context) (int64, string, error)
+	Insert(ctx context.Context, e *Entry) error
+	GetUnsealed(ctx context.Context, limit int) ([]*Entry, error)
+	Seal(ctx context.Context, e *Entry) error
+	Find(ctx context.Context, f *Filter) ([]*Entry, error)
+	GetAfter(ctx context.Context, seq int64, limit int) ([]*Entry, error)
+}
+
+type Storage struct {
+	db db.SQLExt
+}
+
+func NewStorage(psql *sql.DB) Storage {
+	return Storage{db: db.NewConn(psql)}
+}
+
+func (s Storage) ScanEntry(scanner db.Scanner) (*Entry, error) {
+	e := &Entry{}
+	var seq sql.NullInt64
+	var prevHash, hash sql.NullString
+	err := scanner.Scan(&e.ID, &seq, &e.Actor, &e.Action, &e.Entity, &e.EntityID, &e.Before, &e.After, &e.TraceID,
+		&e.ClientIP, &prevHash, &hash, &e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	e.Seq, e.PrevHash, e.Hash = seq.Int64, prevHash.String, hash.String
+	return e, nil
+}
@@ -0,0 +1,29 @@
+package gift
+
+import "time"
+
+// Gift is a code of the local discount engine worth Amount, usable UsageLimit times in all and
+// PerMemberLimit times by a member between StartsAt and ExpiresAt.
+type Gift struct {
+	ID             int64     `db:"id"`
+	Code           string    `db:"code"`
+	Amount         int64     `db:"amount"`
+	UsageLimit     int64     `db:"usage_limit"`
+	UsedCount      int64     `db:"used_count"`
+	PerMemberLimit int64     `db:"per_member_limit"`
+	StartsAt       time.Time `db:"starts_at"`
+	ExpiresAt      time.Time `db:"expires_at"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+// Use is a use of a gift by a member, made for the redemption of Reference. Released uses no longer
+// count towards the limits of the gift.
+type Use struct {
+	ID         int64      `db:"id"`
+	GiftID     int64      `db:"gift_id"`
+	MemberID   int64      `db:"member_id"`
+	Reference  string     `db:"reference"`
+	ReleasedAt *time.Time `db:"released_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
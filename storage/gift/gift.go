@@ -0,0 +1,163 @@
+package gift
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"wallet/internal/serr"
+)
+
+const (
+	giftColumns = "id,code,amount,usage_limit,used_count,per_member_limit,starts_at,expires_at,created_at,updated_at"
+	useColumns  = "id,gift_id,member_id,reference,released_at,created_at"
+)
+
+// Create stores a gift and returns ErrCodeExists if its code is taken.
+func (s Storage) Create(ctx context.Context, g *Gift) error {
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO gift (code, amount, usage_limit, per_member_limit, starts_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (code) DO NOTHING
+		RETURNING id, used_count, created_at, updated_at
+	`, g.Code, g.Amount, g.UsageLimit, g.PerMemberLimit, g.StartsAt, g.ExpiresAt).
+		Scan(&g.ID, &g.UsedCount, &g.CreatedAt, &g.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCodeExists
+	}
+	if err != nil {
+		return serr.DBError("Create", "gift", err)
+	}
+	return nil
+}
+
+func (s Storage) GetByID(ctx context.Context, id int64) (*Gift, error) {
+	sqlStmt := "SELECT " + giftColumns + " FROM gift WHERE id = $1"
+	g, err := s.ScanGift(s.db.QueryRowContext(ctx, sqlStmt, id))
+	if err != nil {
+		return nil, serr.DBError("GetByID", "gift", err)
+	}
+	return g, nil
+}
+
+func (s Storage) GetByCode(ctx context.Context, code string) (*Gift, error) {
+	sqlStmt := "SELECT " + giftColumns + " FROM gift WHERE code = $1"
+	g, err := s.ScanGift(s.db.QueryRowContext(ctx, sqlStmt, code))
+	if err != nil {
+		return nil, serr.DBError("GetByCode", "gift", err)
+	}
+	return g, nil
+}
+
+// GetByCodeForUpdate locks the gift row until the surrounding db transaction ends, uses of the gift
+// are made and released under this lock.
+func (s Storage) GetByCodeForUpdate(ctx context.Context, code string) (*Gift, error) {
+	sqlStmt := "SELECT " + giftColumns + " FROM gift WHERE code = $1 FOR UPDATE"
+	g, err := s.ScanGift(s.db.QueryRowContext(ctx, sqlStmt, code))
+	if err != nil {
+		return nil, serr.DBError("GetByCodeForUpdate", "gift", err)
+	}
+	return g, nil
+}
+
+func (s Storage) List(ctx context.Context, limit, offset int) ([]*Gift, error) {
+	sqlStmt := "SELECT " + giftColumns + " FROM gift ORDER BY id DESC LIMIT $1 OFFSET $2"
+	rows, err := s.db.QueryContext(ctx, sqlStmt, limit, offset)
+	if err != nil {
+		return nil, serr.DBError("List", "gift", err)
+	}
+	defer rows.Close()
+	gifts := make([]*Gift, 0)
+	for rows.Next() {
+		g, err := s.ScanGift(rows)
+		if err != nil {
+			return nil, serr.DBError("List", "gift", err)
+		}
+		gifts = append(gifts, g)
+	}
+	return gifts, nil
+}
+
+// Update stores the amount, limits and period of a gift, its code and used count are kept.
+func (s Storage) Update(ctx context.Context, g *Gift) error {
+	err := s.db.QueryRowContext(ctx, `
+		UPDATE gift SET amount = $1, usage_limit = $2, per_member_limit = $3, starts_at = $4, expires_at = $5,
+			updated_at = now()
+		WHERE id = $6
+		RETURNING used_count, updated_at
+	`, g.Amount, g.UsageLimit, g.PerMemberLimit, g.StartsAt, g.ExpiresAt, g.ID).Scan(&g.UsedCount, &g.UpdatedAt)
+	if err != nil {
+		return serr.DBError("Update", "gift", err)
+	}
+	return nil
+}
+
+func (s Storage) UpdateUsedCount(ctx context.Context, g *Gift) error {
+	err := s.db.QueryRowContext(ctx, `
+		UPDATE gift SET used_count = $1, updated_at = now() WHERE id = $2
+		RETURNING updated_at
+	`, g.UsedCount, g.ID).Scan(&g.UpdatedAt)
+	if err != nil {
+		return serr.DBError("UpdateUsedCount", "gift", err)
+	}
+	return nil
+}
+
+// Delete removes a gift with its uses.
+func (s Storage) Delete(ctx context.Context, id int64) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM gift WHERE id = $1", id)
+	if err != nil {
+		return serr.DBError("Delete", "gift", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return serr.DBError("Delete", "gift", sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (s Storage) CreateUse(ctx context.Context, u *Use) error {
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO gift_use (gift_id, member_id, reference)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`, u.GiftID, u.MemberID, u.Reference).Scan(&u.ID, &u.CreatedAt)
+	if err != nil {
+		return serr.DBError("CreateUse", "gift use", err)
+	}
+	return nil
+}
+
+// GetUseByReference returns nil without an error when no use was made with the reference.
+func (s Storage) GetUseByReference(ctx context.Context, reference string) (*Use, error) {
+	sqlStmt := "SELECT " + useColumns + " FROM gift_use WHERE reference = $1"
+	u, err := s.ScanUse(s.db.QueryRowContext(ctx, sqlStmt, reference))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, serr.DBError("GetUseByReference", "gift use", err)
+	}
+	return u, nil
+}
+
+// CountMemberUses counts the uses of a gift by a member that are not released.
+func (s Storage) CountMemberUses(ctx context.Context, giftID, memberID int64) (int64, error) {
+	var n int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT count(*) FROM gift_use WHERE gift_id = $1 AND member_id = $2 AND released_at IS NULL
+	`, giftID, memberID).Scan(&n)
+	if err != nil {
+		return 0, serr.DBError("CountMemberUses", "gift use", err)
+	}
+	return n, nil
+}
+
+func (s Storage) ReleaseUse(ctx context.Context, u *Use) error {
+	err := s.db.QueryRowContext(ctx, `
+		UPDATE gift_use SET released_at = now() WHERE id = $1
+		RETURNING released_at
+	`, u.ID).Scan(&u.ReleasedAt)
+	if err != nil {
+		return serr.DBError("ReleaseUse", "gift use", err)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package gift_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"wallet/db"
+	"wallet/db/dbtest"
+	"wallet/storage/gift"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGift returns a gift with a code of its own, usable for an hour.
+func newGift() *gift.Gift {
+	now := time.Now().Truncate(time.Microsecond)
+	return &gift.Gift{Code: "GIFT-" + uuid.NewString(), Amount: 100, UsageLimit: 10, PerMemberLimit: 1,
+		StartsAt: now, ExpiresAt: now.Add(time.Hour)}
+}
+
+func TestCreate(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := gift.NewStorage(psql)
+	ctx := context.Background()
+
+	g := newGift()
+	require.NoError(t, s.Create(ctx, g))
+	assert.NotZero(t, g.ID)
+	assert.Zero(t, g.UsedCount)
+
+	got, err := s.GetByCode(ctx, g.Code)
+	require.NoError(t, err)
+	assert.Equal(t, g.ID, got.ID)
+	assert.Equal(t, int64(100), got.Amount)
+	assert.Equal(t, int64(1), got.PerMemberLimit)
+	assert.True(t, g.StartsAt.Equal(got.StartsAt))
+	assert.True(t, g.ExpiresAt.Equal(got.ExpiresAt))
+
+	// codes are unique
+	taken := newGift()
+	taken.Code = g.Code
+	assert.ErrorIs(t, s.Create(ctx, taken), gift.ErrCodeExists)
+
+	// a gift needs positive limits and a period that ends after it starts
+	invalid := newGift()
+	invalid.PerMemberLimit = 0
+	assert.Error(t, s.Create(ctx, invalid))
+	invalid = newGift()
+	invalid.ExpiresAt = invalid.StartsAt
+	assert.Error(t, s.Create(ctx, invalid))
+
+	_, err = s.GetByCode(ctx, "GIFT-"+uuid.NewString())
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestList(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := gift.NewStorage(psql)
+	ctx := context.Background()
+	older, newer := newGift(), newGift()
+	require.NoError(t, s.Create(ctx, older))
+	require.NoError(t, s.Create(ctx, newer))
+
+	gifts, err := s.List(ctx, 10000, 0)
+	require.NoError(t, err)
+	// other tests share the database, only the gifts of this test are looked at
+	var ids []int64
+	for _, g := range gifts {
+		if g.ID == older.ID || g.ID == newer.ID {
+			ids = append(ids, g.ID)
+		}
+	}
+	assert.Equal(t, []int64{newer.ID, older.ID}, ids)
+}
+
+func TestUpdate(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := gift.NewStorage(psql)
+	ctx := context.Background()
+	g := newGift()
+	require.NoError(t, s.Create(ctx, g))
+
+	g.UsedCount = 2
+	require.NoError(t, s.UpdateUsedCount(ctx, g))
+	// the used count is kept by an update of the terms
+	g.Amount, g.UsageLimit, g.PerMemberLimit, g.UsedCount = 300, 5, 2, 0
+	require.NoError(t, s.Update(ctx, g))
+	assert.Equal(t, int64(2), g.UsedCount)
+
+	got, err := s.GetByID(ctx, g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(300), got.Amount)
+	assert.Equal(t, int64(5), got.UsageLimit)
+	assert.Equal(t, int64(2), got.PerMemberLimit)
+	assert.Equal(t, int64(2), got.UsedCount)
+
+	// the used count can not go past the usage limit
+	assert.Error(t, s.UpdateUsedCount(ctx, &gift.Gift{ID: g.ID, UsedCount: 6}))
+	g.UsageLimit = 1
+	assert.Error(t, s.Update(ctx, g))
+}
+
+func TestDelete(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := gift.NewStorage(psql)
+	ctx := context.Background()
+	g := newGift()
+	require.NoError(t, s.Create(ctx, g))
+	u := &gift.Use{GiftID: g.ID, MemberID: 1, Reference: uuid.NewString()}
+	require.NoError(t, s.CreateUse(ctx, u))
+
+	require.NoError(t, s.Delete(ctx, g.ID))
+	_, err := s.GetByID(ctx, g.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	// the uses of the gift go with it
+	got, err := s.GetUseByReference(ctx, u.Reference)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	assert.ErrorIs(t, s.Delete(ctx, g.ID), sql.ErrNoRows)
+}
+
+func TestGetByCodeForUpdate(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := gift.NewStorage(psql)
+	g := newGift()
+	require.NoError(t, s.Create(context.Background(), g))
+
+	updated := make(chan error, 1)
+	err := db.Transaction(context.Background(), func(ctx context.Context) error {
+		if _, err := s.GetByCodeForUpdate(ctx, g.Code); err != nil {
+			return err
+		}
+		go func() {
+			updated <- s.UpdateUsedCount(context.Background(), &gift.Gift{ID: g.ID, UsedCount: 1})
+		}()
+		select {
+		case <-updated:
+			t.Error("the gift changed while it was locked")
+		case <-time.After(100 * time.Millisecond):
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, <-updated)
+}
+
+func TestUses(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := gift.NewStorage(psql)
+	ctx := context.Background()
+	g := newGift()
+	require.NoError(t, s.Create(ctx, g))
+	const memberID = 7
+
+	first := &gift.Use{GiftID: g.ID, MemberID: memberID, Reference: uuid.NewString()}
+	require.NoError(t, s.CreateUse(ctx, first))
+	second := &gift.Use{GiftID: g.ID, MemberID: memberID, Reference: uuid.NewString()}
+	require.NoError(t, s.CreateUse(ctx, second))
+	require.NoError(t, s.CreateUse(ctx, &gift.Use{GiftID: g.ID, MemberID: memberID + 1, Reference: uuid.NewString()}))
+	// a reference is used once
+	assert.Error(t, s.CreateUse(ctx, &gift.Use{GiftID: g.ID, MemberID: memberID, Reference: first.Reference}))
+
+	got, err := s.GetUseByReference(ctx, first.Reference)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, first.ID, got.ID)
+	assert.Equal(t, g.ID, got.GiftID)
+	assert.Nil(t, got.ReleasedAt)
+
+	n, err := s.CountMemberUses(ctx, g.ID, memberID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	// released uses are not counted
+	require.NoError(t, s.ReleaseUse(ctx, first))
+	assert.NotNil(t, first.ReleasedAt)
+	n, err = s.CountMemberUses(ctx, g.ID, memberID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	got, err = s.GetUseByReference(ctx, first.Reference)
+	require.NoError(t, err)
+	assert.NotNil(t, got.ReleasedAt)
+
+	got, err = s.GetUseByReference(ctx, uuid.NewString())
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
@@ -0,0 +1,54 @@
+package gift
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"wallet/db"
+)
+
+var (
+	ErrCodeExists = errors.New("gift code already exists")
+)
+
+type Repository interface {
+	Create(ctx context.Context, g *Gift) error
+	GetByID(ctx context.Context, id int64) (*Gift, error)
+	GetByCode(ctx context.Context, code string) (*Gift, error)
+	GetByCodeForUpdate(ctx context.Context, code string) (*Gift, error)
+	List(ctx context.Context, limit, offset int) ([]*Gift, error)
+	Update(ctx context.Context, g *Gift) error
+	UpdateUsedCount(ctx context.Context, g *Gift) error
+	Delete(ctx context.Context, id int64) error
+	CreateUse(ctx context.Context, u *Use) error
+	GetUseByReference(ctx context.Context, reference string) (*Use, error)
+	CountMemberUses(ctx context.Context, giftID, memberID int64) (int64, error)
+	ReleaseUse(ctx context.Context, u *Use) error
+}
+
+type Storage struct {
+	db db.SQLExt
+}
+
+func NewStorage(psql *sql.DB) Storage {
+	return Storage{db: db.NewConn(psql)}
+}
+
+func (s Storage) ScanGift(scanner db.Scanner) (*Gift, error) {
+	g := &Gift{}
+	err := scanner.Scan(&g.ID, &g.Code, &g.Amount, &g.UsageLimit, &g.UsedCount, &g.PerMemberLimit, &g.StartsAt,
+		&g.ExpiresAt, &g.CreatedAt, &g.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func (s Storage) ScanUse(scanner db.Scanner) (*Use, error) {
+	u := &Use{}
+	err := scanner.Scan(&u.ID, &u.GiftID, &u.MemberID, &u.Reference, &u.ReleasedAt, &u.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
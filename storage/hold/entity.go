@@ -0,0 +1,25 @@
+package hold
+
+import "time"
+
+type Status string
+
+const (
+	Authorized Status = "authorized"
+	Captured   Status = "captured"
+	Voided     Status = "voided"
+	Expired    Status = "expired"
+)
+
+// Hold reserves Amount of a wallet balance until it is captured, voided or expires.
+type Hold struct {
+	ID             int64     `db:"id"`
+	WalletID       int64     `db:"wallet_id"`
+	Amount         int64     `db:"amount"`
+	CapturedAmount int64     `db:"captured_amount"`
+	Currency       string    `db:"currency"`
+	Status         Status    `db:"status"`
+	ExpiresAt      time.Time `db:"expires_at"`
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
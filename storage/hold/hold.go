@@ -0,0 +1,71 @@
+package hold
+
+import (
+	"context"
+	"wallet/internal/serr"
+)
+
+const holdColumns = "id,wallet_id,amount,captured_amount,currency,status,expires_at,created_at,updated_at"
+
+func (s Storage) Create(ctx context.Context, h *Hold) error {
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO hold (wallet_id, amount, currency, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`, h.WalletID, h.Amount, h.Currency, h.Status, h.ExpiresAt).Scan(&h.ID, &h.CreatedAt, &h.UpdatedAt)
+	if err != nil {
+		return serr.DBError("Create", "hold", err)
+	}
+	return nil
+}
+
+func (s Storage) GetByID(ctx context.Context, id int64) (*Hold, error) {
+	sqlStmt := "SELECT " + holdColumns + " FROM hold WHERE id = $1"
+	h, err := s.ScanHold(s.db.QueryRowContext(ctx, sqlStmt, id))
+	if err != nil {
+		return nil, serr.DBError("GetByID", "hold", err)
+	}
+	return h, nil
+}
+
+// GetByIDForUpdate locks the hold row until the surrounding db transaction ends. Lock the wallet of
+// the hold first so hold and balance changes always lock in the same order.
+func (s Storage) GetByIDForUpdate(ctx context.Context, id int64) (*Hold, error) {
+	sqlStmt := "SELECT " + holdColumns + " FROM hold WHERE id = $1 FOR UPDATE"
+	h, err := s.ScanHold(s.db.QueryRowContext(ctx, sqlStmt, id))
+	if err != nil {
+		return nil, serr.DBError("GetByIDForUpdate", "hold", err)
+	}
+	return h, nil
+}
+
+// UpdateStatus stores the status and captured amount of a hold.
+func (s Storage) UpdateStatus(ctx context.Context, h *Hold) error {
+	err := s.db.QueryRowContext(ctx, `
+		UPDATE hold SET status = $1, captured_amount = $2, updated_at = now() WHERE id = $3
+		RETURNING updated_at
+	`, h.Status, h.CapturedAmount, h.ID).Scan(&h.UpdatedAt)
+	if err != nil {
+		return serr.DBError("UpdateStatus", "hold", err)
+	}
+	return nil
+}
+
+// GetExpired returns authorized holds past their expiry, oldest first.
+func (s Storage) GetExpired(ctx context.Context, limit int) ([]*Hold, error) {
+	sqlStmt := "SELECT " + holdColumns + " FROM hold WHERE status = $1 AND expires_at < now() ORDER BY expires_at LIMIT $2"
+	rows, err := s.db.QueryContext(ctx, sqlStmt, Authorized, limit)
+	if err != nil {
+		return nil, serr.DBError("GetExpired", "hold", err)
+	}
+	defer rows.Close()
+	holds := make([]*Hold, 0)
+	for rows.Next() {
+		h, err := s.ScanHold(rows)
+		if err != nil {
+			return nil, serr.DBError("GetExpired", "hold", err)
+		}
+		holds = append(holds, h)
+	}
+	return holds, nil
+}
@@ -0,0 +1,115 @@
+package hold_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"wallet/db"
+	"wallet/db/dbtest"
+	"wallet/storage/hold"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreate(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := hold.NewStorage(psql)
+	ctx := context.Background()
+	walletID := dbtest.Wallet(t, psql, 1000)
+
+	h := &hold.Hold{WalletID: walletID, Amount: 100, Currency: "IRR", Status: hold.Authorized,
+		ExpiresAt: time.Now().Add(time.Hour).Truncate(time.Microsecond)}
+	require.NoError(t, s.Create(ctx, h))
+	assert.NotZero(t, h.ID)
+
+	got, err := s.GetByID(ctx, h.ID)
+	require.NoError(t, err)
+	assert.Equal(t, walletID, got.WalletID)
+	assert.Equal(t, int64(100), got.Amount)
+	assert.Zero(t, got.CapturedAmount)
+	assert.Equal(t, hold.Authorized, got.Status)
+	assert.True(t, h.ExpiresAt.Equal(got.ExpiresAt))
+
+	// a hold needs a positive amount and an existing wallet
+	assert.Error(t, s.Create(ctx, &hold.Hold{WalletID: walletID, Amount: 0, Currency: "IRR",
+		Status: hold.Authorized, ExpiresAt: time.Now()}))
+	assert.Error(t, s.Create(ctx, &hold.Hold{WalletID: 0, Amount: 100, Currency: "IRR",
+		Status: hold.Authorized, ExpiresAt: time.Now()}))
+}
+
+func TestUpdateStatus(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := hold.NewStorage(psql)
+	ctx := context.Background()
+	walletID := dbtest.Wallet(t, psql, 1000)
+	h := &hold.Hold{WalletID: walletID, Amount: 100, Currency: "IRR", Status: hold.Authorized,
+		ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, s.Create(ctx, h))
+
+	// more than the hold can not be captured
+	assert.Error(t, s.UpdateStatus(ctx, &hold.Hold{ID: h.ID, Status: hold.Captured, CapturedAmount: 101}))
+
+	h.Status, h.CapturedAmount = hold.Captured, 60
+	require.NoError(t, s.UpdateStatus(ctx, h))
+	got, err := s.GetByID(ctx, h.ID)
+	require.NoError(t, err)
+	assert.Equal(t, hold.Captured, got.Status)
+	assert.Equal(t, int64(60), got.CapturedAmount)
+	assert.False(t, got.UpdatedAt.Before(got.CreatedAt))
+}
+
+func TestGetByIDForUpdate(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := hold.NewStorage(psql)
+	walletID := dbtest.Wallet(t, psql, 1000)
+	h := &hold.Hold{WalletID: walletID, Amount: 100, Currency: "IRR", Status: hold.Authorized,
+		ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, s.Create(context.Background(), h))
+
+	updated := make(chan error, 1)
+	err := db.Transaction(context.Background(), func(ctx context.Context) error {
+		if _, err := s.GetByIDForUpdate(ctx, h.ID); err != nil {
+			return err
+		}
+		go func() {
+			updated <- s.UpdateStatus(context.Background(), &hold.Hold{ID: h.ID, Status: hold.Voided})
+		}()
+		select {
+		case <-updated:
+			t.Error("the hold changed while it was locked")
+		case <-time.After(100 * time.Millisecond):
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, <-updated)
+}
+
+func TestGetExpired(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := hold.NewStorage(psql)
+	ctx := context.Background()
+	walletID := dbtest.Wallet(t, psql, 1000)
+	create := func(expiresAt time.Time, status hold.Status) *hold.Hold {
+		h := &hold.Hold{WalletID: walletID, Amount: 10, Currency: "IRR", Status: status, ExpiresAt: expiresAt}
+		require.NoError(t, s.Create(ctx, h))
+		return h
+	}
+	older := create(time.Now().Add(-2*time.Hour), hold.Authorized)
+	newer := create(time.Now().Add(-time.Hour), hold.Authorized)
+	create(time.Now().Add(time.Hour), hold.Authorized)
+	create(time.Now().Add(-time.Hour), hold.Voided)
+
+	holds, err := s.GetExpired(ctx, 1000)
+	require.NoError(t, err)
+	// other tests share the database, only the holds of this wallet are looked at
+	var ids []int64
+	for _, h := range holds {
+		if h.WalletID == walletID {
+			ids = append(ids, h.ID)
+		}
+	}
+	assert.Equal(t, []int64{older.ID, newer.ID}, ids)
+}
@@ -0,0 +1,33 @@
+package hold
+
+import (
+	"context"
+	"database/sql"
+	"wallet/db"
+)
+
+type Repository interface {
+	Create(ctx context.Context, h *Hold) error
+	GetByID(ctx context.Context, id int64) (*Hold, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*Hold, error)
+	UpdateStatus(ctx context.Context, h *Hold) error
+	GetExpired(ctx context.Context, limit int) ([]*Hold, error)
+}
+
+type Storage struct {
+	db db.SQLExt
+}
+
+func NewStorage(psql *sql.DB) Storage {
+	return Storage{db: db.NewConn(psql)}
+}
+
+func (s Storage) ScanHold(scanner db.Scanner) (*Hold, error) {
+	h := &Hold{}
+	err := scanner.Scan(&h.ID, &h.WalletID, &h.Amount, &h.CapturedAmount, &h.Currency, &h.Status, &h.ExpiresAt,
+		&h.CreatedAt, &h.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
@@ -0,0 +1,10 @@
+package idempotency
+
+import "time"
+
+type Key struct {
+	Key         string    `db:"key"`
+	RequestHash string    `db:"request_hash"`
+	Response    []byte    `db:"response"`
+	CreatedAt   time.Time `db:"created_at"`
+}
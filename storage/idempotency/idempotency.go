@@ -0,0 +1,43 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"wallet/internal/serr"
+)
+
+const keyColumns = "key,request_hash,response,created_at"
+
+// Insert stores the key and returns ErrKeyExists if another request has already recorded it.
+func (s Storage) Insert(ctx context.Context, k *Key) error {
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO idempotency_key
+		    (key, request_hash, response)
+		VALUES 
+		    ($1, $2, $3)
+		ON CONFLICT (key) DO NOTHING
+		RETURNING created_at
+	`, k.Key, k.RequestHash, k.Response).Scan(&k.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrKeyExists
+	}
+	if err != nil {
+		return serr.DBError("Insert", "idempotency key", err)
+	}
+	return nil
+}
+
+// GetByKey returns nil without an error when the key has not been recorded yet.
+func (s Storage) GetByKey(ctx context.Context, key string) (*Key, error) {
+	sqlStmt := "SELECT " + keyColumns + " FROM idempotency_key WHERE key = $1"
+	k := &Key{}
+	err := s.db.QueryRowContext(ctx, sqlStmt, key).Scan(&k.Key, &k.RequestHash, &k.Response, &k.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, serr.DBError("GetByKey", "idempotency key", err)
+	}
+	return k, nil
+}
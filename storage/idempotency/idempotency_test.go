@@ -0,0 +1,70 @@
+package idempotency_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"wallet/db/dbtest"
+	"wallet/storage/idempotency"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsert(t *testing.T) {
+	s := idempotency.NewStorage(dbtest.Postgres(t))
+	ctx := context.Background()
+	key := fmt.Sprintf("key-%d", time.Now().UnixNano())
+
+	k := &idempotency.Key{Key: key, RequestHash: "hash", Response: []byte(`{"id":1}`)}
+	require.NoError(t, s.Insert(ctx, k))
+	assert.False(t, k.CreatedAt.IsZero())
+
+	// the second request with the key loses, the first response is kept
+	err := s.Insert(ctx, &idempotency.Key{Key: key, RequestHash: "other", Response: []byte(`{"id":2}`)})
+	assert.ErrorIs(t, err, idempotency.ErrKeyExists)
+	stored, err := s.GetByKey(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, "hash", stored.RequestHash)
+	assert.JSONEq(t, `{"id":1}`, string(stored.Response))
+}
+
+func TestInsert_Concurrent(t *testing.T) {
+	s := idempotency.NewStorage(dbtest.Postgres(t))
+	key := fmt.Sprintf("race-%d", time.Now().UnixNano())
+
+	var wg sync.WaitGroup
+	var won, lost atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := s.Insert(context.Background(), &idempotency.Key{
+				Key: key, RequestHash: fmt.Sprint(i), Response: []byte(`{}`),
+			})
+			switch {
+			case err == nil:
+				won.Add(1)
+			case errors.Is(err, idempotency.ErrKeyExists):
+				lost.Add(1)
+			default:
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), won.Load())
+	assert.Equal(t, int32(9), lost.Load())
+}
+
+func TestGetByKey_NotFound(t *testing.T) {
+	s := idempotency.NewStorage(dbtest.Postgres(t))
+
+	k, err := s.GetByKey(context.Background(), fmt.Sprintf("missing-%d", time.Now().UnixNano()))
+	require.NoError(t, err)
+	assert.Nil(t, k)
+}
@@ -0,0 +1,25 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"wallet/db"
+)
+
+var (
+	ErrKeyExists = errors.New("idempotency key already exists")
+)
+
+type Repository interface {
+	Insert(ctx context.Context, k *Key) error
+	GetByKey(ctx context.Context, key string) (*Key, error)
+}
+
+type Storage struct {
+	db db.SQLExt
+}
+
+func NewStorage(psql *sql.DB) Storage {
+	return Storage{db: db.NewConn(psql)}
+}
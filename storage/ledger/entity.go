@@ -0,0 +1,69 @@
+package ledger
+
+import "time"
+
+type Direction string
+
+const (
+	Debit  Direction = "debit"
+	Credit Direction = "credit"
+)
+
+// Account is a system account on the other side of wallet postings.
+type Account string
+
+const (
+	OpeningBalance     Account = "opening_balance"
+	CashIn             Account = "cash_in"
+	CashOut            Account = "cash_out"
+	GiftFunding        Account = "gift_funding"
+	MerchantSettlement Account = "merchant_settlement"
+	Reconciliation     Account = "reconciliation"
+	FXConversion       Account = "fx_conversion"
+)
+
+type JournalEntry struct {
+	ID          int64      `db:"id"`
+	Description string     `db:"description"`
+	CreatedAt   time.Time  `db:"created_at"`
+	Postings    []*Posting `db:"-"`
+}
+
+// Posting moves Amount on either a wallet or a system account, never both.
+type Posting struct {
+	ID             int64     `db:"id"`
+	JournalEntryID int64     `db:"journal_entry_id"`
+	WalletID       int64     `db:"wallet_id"`
+	Account        Account   `db:"account"`
+	Direction      Direction `db:"direction"`
+	Amount         int64     `db:"amount"`
+	Currency       string    `db:"currency"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+// Validate checks that the entry has valid postings whose debits equal its credits in every currency.
+func (e *JournalEntry) Validate() error {
+	if len(e.Postings) < 2 {
+		return ErrUnbalancedEntry
+	}
+	sums := make(map[string]int64)
+	for _, p := range e.Postings {
+		if p.Amount <= 0 || p.Currency == "" || (p.WalletID == 0) == (p.Account == "") {
+			return ErrInvalidPosting
+		}
+		switch p.Direction {
+		case Debit:
+			sums[p.Currency] += p.Amount
+		case Credit:
+			sums[p.Currency] -= p.Amount
+		default:
+			return ErrInvalidPosting
+		}
+	}
+	for _, sum := range sums {
+		if sum != 0 {
+			return ErrUnbalancedEntry
+		}
+	}
+	return nil
+}
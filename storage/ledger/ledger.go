@@ -0,0 +1,94 @@
+package ledger
+
+import (
+	"context"
+	"wallet/db"
+	"wallet/internal/serr"
+)
+
+const postingColumns = "id,journal_entry_id,wallet_id,account,direction,amount,currency,created_at"
+
+// InsertEntry validates and stores a journal entry with its postings. It must run in a tx, the
+// database checks that the entry is balanced when the tx commits.
+func (s Storage) InsertEntry(ctx context.Context, e *JournalEntry) error {
+	if !db.InTransaction(ctx) {
+		return db.ErrNotInTX
+	}
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO journal_entry (description) VALUES ($1) RETURNING id, created_at
+	`, e.Description).Scan(&e.ID, &e.CreatedAt)
+	if err != nil {
+		return serr.DBError("InsertEntry", "journal entry", err)
+	}
+	for _, p := range e.Postings {
+		p.JournalEntryID = e.ID
+		var walletID, account any
+		if p.WalletID != 0 {
+			walletID = p.WalletID
+		} else {
+			account = p.Account
+		}
+		err = s.db.QueryRowContext(ctx, `
+			INSERT INTO posting
+			    (journal_entry_id, wallet_id, account, direction, amount, currency)
+			VALUES 
+			    ($1, $2, $3, $4, $5, $6)
+			RETURNING id, created_at
+		`, p.JournalEntryID, walletID, account, p.Direction, p.Amount, p.Currency).Scan(&p.ID, &p.CreatedAt)
+		if err != nil {
+			return serr.DBError("InsertEntry", "posting", err)
+		}
+	}
+	return nil
+}
+
+func (s Storage) GetEntryByID(ctx context.Context, id int64) (*JournalEntry, error) {
+	e := &JournalEntry{}
+	err := s.db.QueryRowContext(ctx, "SELECT id,description,created_at FROM journal_entry WHERE id = $1", id).
+		Scan(&e.ID, &e.Description, &e.CreatedAt)
+	if err != nil {
+		return nil, serr.DBError("GetEntryByID", "journal entry", err)
+	}
+	rows, err := s.db.QueryContext(ctx, "SELECT "+postingColumns+" FROM posting WHERE journal_entry_id = $1 ORDER BY id", id)
+	if err != nil {
+		return nil, serr.DBError("GetEntryByID", "posting", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		p, err := s.ScanPosting(rows)
+		if err != nil {
+			return nil, serr.DBError("GetEntryByID", "posting", err)
+		}
+		e.Postings = append(e.Postings, p)
+	}
+	return e, nil
+}
+
+// GetWalletBalance returns credits minus debits posted to a wallet.
+func (s Storage) GetWalletBalance(ctx context.Context, walletID int64) (int64, error) {
+	sqlStmt := `
+	SELECT coalesce(sum(CASE WHEN direction = 'credit' THEN amount ELSE -amount END), 0)
+	FROM posting WHERE wallet_id = $1`
+	var balance int64
+	err := s.db.QueryRowContext(ctx, sqlStmt, walletID).Scan(&balance)
+	if err != nil {
+		return 0, serr.DBError("GetWalletBalance", "posting", err)
+	}
+	return balance, nil
+}
+
+// GetAccountBalance returns credits minus debits posted to a system account in a currency.
+func (s Storage) GetAccountBalance(ctx context.Context, account Account, currency string) (int64, error) {
+	sqlStmt := `
+	SELECT coalesce(sum(CASE WHEN direction = 'credit' THEN amount ELSE -amount END), 0)
+	FROM posting WHERE account = $1 AND currency = $2`
+	var balance int64
+	err := s.db.QueryRowContext(ctx, sqlStmt, account, currency).Scan(&balance)
+	if err != nil {
+		return 0, serr.DBError("GetAccountBalance", "posting", err)
+	}
+	return balance, nil
+}
@@ -0,0 +1,145 @@
+package ledger_test
+
+import (
+	"context"
+	"testing"
+
+	"wallet/db"
+	"wallet/db/dbtest"
+	"wallet/storage/ledger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournalEntryValidate(t *testing.T) {
+	t.Run("balanced", func(t *testing.T) {
+		e := &ledger.JournalEntry{Postings: []*ledger.Posting{
+			{Account: ledger.CashIn, Direction: ledger.Debit, Amount: 100, Currency: "IRR"},
+			{WalletID: 1, Direction: ledger.Credit, Amount: 100, Currency: "IRR"},
+		}}
+		assert.NoError(t, e.Validate())
+	})
+
+	t.Run("balanced between wallets", func(t *testing.T) {
+		e := &ledger.JournalEntry{Postings: []*ledger.Posting{
+			{WalletID: 1, Direction: ledger.Debit, Amount: 100, Currency: "IRR"},
+			{WalletID: 2, Direction: ledger.Credit, Amount: 60, Currency: "IRR"},
+			{WalletID: 3, Direction: ledger.Credit, Amount: 40, Currency: "IRR"},
+		}}
+		assert.NoError(t, e.Validate())
+	})
+
+	t.Run("unbalanced", func(t *testing.T) {
+		e := &ledger.JournalEntry{Postings: []*ledger.Posting{
+			{Account: ledger.CashIn, Direction: ledger.Debit, Amount: 100, Currency: "IRR"},
+			{WalletID: 1, Direction: ledger.Credit, Amount: 90, Currency: "IRR"},
+		}}
+		assert.ErrorIs(t, e.Validate(), ledger.ErrUnbalancedEntry)
+	})
+
+	t.Run("balanced per currency", func(t *testing.T) {
+		e := &ledger.JournalEntry{Postings: []*ledger.Posting{
+			{WalletID: 1, Direction: ledger.Debit, Amount: 100, Currency: "USD"},
+			{WalletID: 2, Direction: ledger.Credit, Amount: 100, Currency: "EUR"},
+		}}
+		assert.ErrorIs(t, e.Validate(), ledger.ErrUnbalancedEntry)
+	})
+
+	t.Run("single posting", func(t *testing.T) {
+		e := &ledger.JournalEntry{Postings: []*ledger.Posting{
+			{WalletID: 1, Direction: ledger.Credit, Amount: 100, Currency: "IRR"},
+		}}
+		assert.ErrorIs(t, e.Validate(), ledger.ErrUnbalancedEntry)
+	})
+
+	t.Run("invalid posting", func(t *testing.T) {
+		for _, p := range []*ledger.Posting{
+			{WalletID: 1, Account: ledger.CashIn, Direction: ledger.Debit, Amount: 100, Currency: "IRR"},
+			{Direction: ledger.Debit, Amount: 100, Currency: "IRR"},
+			{WalletID: 1, Direction: ledger.Debit, Amount: 0, Currency: "IRR"},
+			{WalletID: 1, Direction: "sideways", Amount: 100, Currency: "IRR"},
+			{WalletID: 1, Direction: ledger.Debit, Amount: 100},
+		} {
+			e := &ledger.JournalEntry{Postings: []*ledger.Posting{
+				p,
+				{WalletID: 2, Direction: ledger.Credit, Amount: 100, Currency: "IRR"},
+			}}
+			assert.ErrorIs(t, e.Validate(), ledger.ErrInvalidPosting)
+		}
+	})
+}
+
+func TestInsertEntry(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := ledger.NewStorage(psql)
+	walletID := dbtest.Wallet(t, psql, 0)
+
+	e := &ledger.JournalEntry{Description: "recharge", Postings: []*ledger.Posting{
+		{Account: ledger.CashIn, Direction: ledger.Debit, Amount: 100, Currency: "IRR"},
+		{WalletID: walletID, Direction: ledger.Credit, Amount: 100, Currency: "IRR"},
+	}}
+	require.NoError(t, db.Transaction(context.Background(), func(ctx context.Context) error {
+		return s.InsertEntry(ctx, e)
+	}))
+
+	stored, err := s.GetEntryByID(context.Background(), e.ID)
+	require.NoError(t, err)
+	require.Len(t, stored.Postings, 2)
+	assert.Equal(t, ledger.CashIn, stored.Postings[0].Account)
+	assert.Equal(t, walletID, stored.Postings[1].WalletID)
+	balance, err := s.GetWalletBalance(context.Background(), walletID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), balance)
+}
+
+func TestInsertEntry_NotInTransaction(t *testing.T) {
+	s := ledger.NewStorage(dbtest.Postgres(t))
+
+	err := s.InsertEntry(context.Background(), &ledger.JournalEntry{})
+	assert.ErrorIs(t, err, db.ErrNotInTX)
+}
+
+func TestPostings_Balanced(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	conn := db.NewConn(psql)
+	walletID := dbtest.Wallet(t, psql, 0)
+
+	// postings written past InsertEntry are checked by the deferred trigger when the tx commits
+	err := db.Transaction(context.Background(), func(ctx context.Context) error {
+		var entryID int64
+		if err := conn.QueryRowContext(ctx, "INSERT INTO journal_entry (description) VALUES ('unbalanced') RETURNING id").
+			Scan(&entryID); err != nil {
+			return err
+		}
+		_, err := conn.ExecContext(ctx, `
+			INSERT INTO posting (journal_entry_id, wallet_id, account, direction, amount, currency)
+			VALUES ($1, NULL, 'cash_in', 'debit', 100, 'IRR'), ($1, $2, NULL, 'credit', 90, 'IRR')
+		`, entryID, walletID)
+		return err
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not balanced")
+	balance, err := ledger.NewStorage(psql).GetWalletBalance(context.Background(), walletID)
+	require.NoError(t, err)
+	assert.Zero(t, balance)
+}
+
+func TestPostings_Immutable(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := ledger.NewStorage(psql)
+	walletID := dbtest.Wallet(t, psql, 0)
+	e := &ledger.JournalEntry{Description: "recharge", Postings: []*ledger.Posting{
+		{Account: ledger.CashIn, Direction: ledger.Debit, Amount: 100, Currency: "IRR"},
+		{WalletID: walletID, Direction: ledger.Credit, Amount: 100, Currency: "IRR"},
+	}}
+	require.NoError(t, db.Transaction(context.Background(), func(ctx context.Context) error {
+		return s.InsertEntry(ctx, e)
+	}))
+
+	_, err := psql.Exec("UPDATE posting SET amount = 1 WHERE journal_entry_id = $1", e.ID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "postings are immutable")
+	_, err = psql.Exec("DELETE FROM posting WHERE journal_entry_id = $1", e.ID)
+	require.Error(t, err)
+}
@@ -0,0 +1,41 @@
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"wallet/db"
+)
+
+var (
+	ErrUnbalancedEntry = errors.New("journal entry is not balanced")
+	ErrInvalidPosting  = errors.New("invalid posting")
+)
+
+type Repository interface {
+	InsertEntry(ctx context.Context, e *JournalEntry) error
+	GetEntryByID(ctx context.Context, id int64) (*JournalEntry, error)
+	GetWalletBalance(ctx context.Context, walletID int64) (int64, error)
+	GetAccountBalance(ctx context.Context, account Account, currency string) (int64, error)
+}
+
+type Storage struct {
+	db db.SQLExt
+}
+
+func NewStorage(psql *sql.DB) Storage {
+	return Storage{db: db.NewConn(psql)}
+}
+
+func (s Storage) ScanPosting(scanner db.Scanner) (*Posting, error) {
+	p := &Posting{}
+	var walletID sql.NullInt64
+	var account sql.NullString
+	err := scanner.Scan(&p.ID, &p.JournalEntryID, &walletID, &account, &p.Direction, &p.Amount, &p.Currency, &p.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	p.WalletID = walletID.Int64
+	p.Account = Account(account.String)
+	return p, nil
+}
@@ -0,0 +1,15 @@
+package limit
+
+import "time"
+
+// WalletLimit overrides the limits of the member tier of a wallet, a nil limit keeps the tier one.
+type WalletLimit struct {
+	WalletID           int64     `db:"wallet_id"`
+	MaxWithdrawal      *int64    `db:"max_withdrawal"`
+	DailyDebit         *int64    `db:"daily_debit"`
+	MonthlyDebit       *int64    `db:"monthly_debit"`
+	MaxBalance         *int64    `db:"max_balance"`
+	MaxTransfersPerDay *int64    `db:"max_transfers_per_day"`
+	CreatedAt          time.Time `db:"created_at"`
+	UpdatedAt          time.Time `db:"updated_at"`
+}
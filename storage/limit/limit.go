@@ -0,0 +1,42 @@
+package limit
+
+import (
+	"context"
+	"wallet/internal/serr"
+)
+
+const columns = "wallet_id,max_withdrawal,daily_debit,monthly_debit,max_balance,max_transfers_per_day,created_at,updated_at"
+
+func (s Storage) Get(ctx context.Context, walletID int64) (*WalletLimit, error) {
+	l, err := s.Scan(s.db.QueryRowContext(ctx, "SELECT "+columns+" FROM wallet_limit WHERE wallet_id = $1", walletID))
+	if err != nil {
+		return nil, serr.DBError("Get", "wallet limit", err)
+	}
+	return l, nil
+}
+
+// Upsert sets the limits of a wallet, replacing the ones it had.
+func (s Storage) Upsert(ctx context.Context, l *WalletLimit) error {
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO wallet_limit (wallet_id, max_withdrawal, daily_debit, monthly_debit, max_balance, max_transfers_per_day)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (wallet_id) DO UPDATE SET
+			max_withdrawal = excluded.max_withdrawal, daily_debit = excluded.daily_debit,
+			monthly_debit = excluded.monthly_debit, max_balance = excluded.max_balance,
+			max_transfers_per_day = excluded.max_transfers_per_day, updated_at = now()
+		RETURNING created_at, updated_at
+	`, l.WalletID, l.MaxWithdrawal, l.DailyDebit, l.MonthlyDebit, l.MaxBalance, l.MaxTransfersPerDay).
+		Scan(&l.CreatedAt, &l.UpdatedAt)
+	if err != nil {
+		return serr.DBError("Upsert", "wallet limit", err)
+	}
+	return nil
+}
+
+// Delete drops the limits of a wallet, its member tier limits apply again.
+func (s Storage) Delete(ctx context.Context, walletID int64) error {
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM wallet_limit WHERE wallet_id = $1", walletID); err != nil {
+		return serr.DBError("Delete", "wallet limit", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package limit_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"wallet/db/dbtest"
+	"wallet/storage/limit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr(v int64) *int64 {
+	return &v
+}
+
+func TestUpsert(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := limit.NewStorage(psql)
+	ctx := context.Background()
+	walletID := dbtest.Wallet(t, psql, 0)
+
+	l := &limit.WalletLimit{WalletID: walletID, DailyDebit: ptr(2000), MaxTransfersPerDay: ptr(0)}
+	require.NoError(t, s.Upsert(ctx, l))
+	got, err := s.Get(ctx, walletID)
+	require.NoError(t, err)
+	assert.Equal(t, ptr(2000), got.DailyDebit)
+	// a zero limit is kept apart from a limit left to the tier
+	assert.Equal(t, ptr(0), got.MaxTransfersPerDay)
+	assert.Nil(t, got.MaxWithdrawal)
+
+	// upserting replaces every limit of the wallet
+	l = &limit.WalletLimit{WalletID: walletID, MaxBalance: ptr(10000)}
+	require.NoError(t, s.Upsert(ctx, l))
+	got, err = s.Get(ctx, walletID)
+	require.NoError(t, err)
+	assert.Nil(t, got.DailyDebit)
+	assert.Nil(t, got.MaxTransfersPerDay)
+	assert.Equal(t, ptr(10000), got.MaxBalance)
+	assert.False(t, got.UpdatedAt.Before(got.CreatedAt))
+
+	// limits belong to existing wallets
+	assert.Error(t, s.Upsert(ctx, &limit.WalletLimit{WalletID: 0, MaxBalance: ptr(1)}))
+}
+
+func TestDelete(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := limit.NewStorage(psql)
+	ctx := context.Background()
+	walletID := dbtest.Wallet(t, psql, 0)
+	require.NoError(t, s.Upsert(ctx, &limit.WalletLimit{WalletID: walletID, DailyDebit: ptr(2000)}))
+
+	require.NoError(t, s.Delete(ctx, walletID))
+	_, err := s.Get(ctx, walletID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// deleting limits a wallet does not have is no error
+	assert.NoError(t, s.Delete(ctx, walletID))
+}
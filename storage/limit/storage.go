@@ -0,0 +1,44 @@
+package limit
+
+import (
+	"context"
+	"database/sql"
+	"wallet/db"
+)
+
+type Repository interface {
+	Get(ctx context.Context, walletID int64) (*WalletLimit, error)
+	Upsert(ctx context.Context, l *WalletLimit) error
+	Delete(ctx context.Context, walletID int64) error
+}
+
+type Storage struct {
+	db db.SQLExt
+}
+
+func NewStorage(psql *sql.DB) Storage {
+	return Storage{db: db.NewConn(psql)}
+}
+
+func (s Storage) Scan(scanner db.Scanner) (*WalletLimit, error) {
+	l := &WalletLimit{}
+	var maxWithdrawal, dailyDebit, monthlyDebit, maxBalance, maxTransfers sql.NullInt64
+	err := scanner.Scan(&l.WalletID, &maxWithdrawal, &dailyDebit, &monthlyDebit, &maxBalance, &maxTransfers,
+		&l.CreatedAt, &l.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	l.MaxWithdrawal = nullable(maxWithdrawal)
+	l.DailyDebit = nullable(dailyDebit)
+	l.MonthlyDebit = nullable(monthlyDebit)
+	l.MaxBalance = nullable(maxBalance)
+	l.MaxTransfersPerDay = nullable(maxTransfers)
+	return l, nil
+}
+
+func nullable(n sql.NullInt64) *int64 {
+	if !n.Valid {
+		return nil
+	}
+	return &n.Int64
+}
@@ -8,6 +8,7 @@ type Member struct {
 	LastName  string    `db:"last_name"`
 	Email     string    `db:"email"`
 	Phone     string    `db:"phone"`
+	Tier      string    `db:"tier"`
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
 }
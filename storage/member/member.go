@@ -1,45 +1,48 @@
 package member
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"wallet/internal/serr"
 )
 
-const memberColumns = "id,first_name,last_name,email,phone,created_at,updated_at"
+const memberColumns = "id,first_name,last_name,email,phone,tier,created_at,updated_at"
 
-func (s Storage) Create(u *Member) error {
+func (s Storage) Create(ctx context.Context, u *Member) error {
 	sqlStmt := `
-	INSERT INTO member (first_name, last_name, email, phone) VALUES ($1, $2, $3, $4) 
+	INSERT INTO member (first_name, last_name, email, phone, tier) VALUES ($1, $2, $3, $4, $5) 
 	                     RETURNING id, created_at, updated_at`
-	err := s.db.QueryRow(sqlStmt, u.FirstName, u.LastName, u.Email, u.Phone).Scan(&u.ID, &u.CreatedAt, &u.UpdatedAt)
+	err := s.db.QueryRowContext(ctx, sqlStmt, u.FirstName, u.LastName, u.Email, u.Phone, u.Tier).Scan(&u.ID, &u.CreatedAt, &u.UpdatedAt)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s Storage) Update(u *Member) error {
+func (s Storage) Update(ctx context.Context, u *Member) error {
 	sqlStmt := `
-	UPDATE member SET first_name = $1, last_name = $2, email = $3, phone = $4, updated_at = now() WHERE id = $5
-	                     RETURNING updated_at`
-	err := s.db.QueryRow(sqlStmt, u.FirstName, u.LastName, u.Email, u.Phone, u.ID).Scan(&u.UpdatedAt)
+	UPDATE member SET first_name = $1, last_name = $2, email = $3, phone = $4, updated_at = now()
+	                     WHERE id = $5 AND deleted_at IS NULL
+	                     RETURNING tier, updated_at`
+	err := s.db.QueryRowContext(ctx, sqlStmt, u.FirstName, u.LastName, u.Email, u.Phone, u.ID).Scan(&u.Tier, &u.UpdatedAt)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s Storage) GetAllByPage(limit, offset int, count bool) ([]*Member, int, error) {
+func (s Storage) GetAllByPage(ctx context.Context, limit, offset int, count bool) ([]*Member, int, error) {
 	var total int
 	if count {
-		err := s.db.QueryRow("SELECT count(*) FROM member").Scan(&total)
+		err := s.db.QueryRowContext(ctx, "SELECT count(*) FROM member WHERE deleted_at IS NULL").Scan(&total)
 		if err != nil {
 			return nil, 0, serr.DBError("List", "member", err)
 		}
 	}
 	pagination := fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
 	order := " ORDER BY created_at DESC"
-	rows, err := s.db.Query("SELECT " + memberColumns + " FROM member" + order + pagination)
+	rows, err := s.db.QueryContext(ctx, "SELECT "+memberColumns+" FROM member WHERE deleted_at IS NULL"+order+pagination)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -55,11 +58,11 @@ func (s Storage) GetAllByPage(limit, offset int, count bool) ([]*Member, int, er
 	return members, total, nil
 }
 
-func (s Storage) GetById(id int64) (*Member, error) {
+func (s Storage) GetById(ctx context.Context, id int64) (*Member, error) {
 	query := `
-	SELECT ` + memberColumns + ` FROM member WHERE id = $1`
+	SELECT ` + memberColumns + ` FROM member WHERE id = $1 AND deleted_at IS NULL`
 	u := &Member{}
-	row := s.db.QueryRow(query, id)
+	row := s.db.QueryRowContext(ctx, query, id)
 	u, err := s.ScanMember(row)
 	if err != nil {
 		return nil, err
@@ -68,14 +71,39 @@ func (s Storage) GetById(id int64) (*Member, error) {
 }
 
 // get member by phone
-func (s Storage) GetByPhone(phone string) (*Member, error) {
+func (s Storage) GetByPhone(ctx context.Context, phone string) (*Member, error) {
 	query := `
-	SELECT ` + memberColumns + ` FROM member WHERE phone = $1`
+	SELECT ` + memberColumns + ` FROM member WHERE phone = $1 AND deleted_at IS NULL`
 	u := &Member{}
-	row := s.db.QueryRow(query, phone)
+	row := s.db.QueryRowContext(ctx, query, phone)
 	u, err := s.ScanMember(row)
 	if err != nil {
 		return nil, err
 	}
 	return u, nil
 }
+
+// SetTier moves a member to the tier its spending limits come from.
+func (s Storage) SetTier(ctx context.Context, id int64, tier string) error {
+	res, err := s.db.ExecContext(ctx, "UPDATE member SET tier = $1, updated_at = now() WHERE id = $2 AND deleted_at IS NULL", tier, id)
+	if err != nil {
+		return serr.DBError("SetTier", "member", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return serr.DBError("SetTier", "member", sql.ErrNoRows)
+	}
+	return nil
+}
+
+// Delete marks a member deleted, deleted members are left out of every query.
+func (s Storage) Delete(ctx context.Context, id int64) error {
+	sqlStmt := "UPDATE member SET deleted_at = now(), updated_at = now() WHERE id = $1 AND deleted_at IS NULL"
+	res, err := s.db.ExecContext(ctx, sqlStmt, id)
+	if err != nil {
+		return serr.DBError("Delete", "member", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return serr.DBError("Delete", "member", sql.ErrNoRows)
+	}
+	return nil
+}
@@ -1,8 +1,10 @@
 package member_test
 
 import (
+	"context"
 	"errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"testing"
 	repomocks "wallet/mocks/repomocks/member"
 	"wallet/storage/member"
@@ -17,8 +19,8 @@ func TestCreate(t *testing.T) {
 			Phone:     "+989123456789",
 		}
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("Create", fakeMember).Return(nil)
-		err := mockRepo.Create(fakeMember)
+		mockRepo.On("Create", mock.Anything, fakeMember).Return(nil)
+		err := mockRepo.Create(context.Background(), fakeMember)
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
 	})
@@ -31,8 +33,8 @@ func TestCreate(t *testing.T) {
 			Phone:     "+989123456789",
 		}
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("Create", fakeMember).Return(errors.New("forced error"))
-		err := mockRepo.Create(fakeMember)
+		mockRepo.On("Create", mock.Anything, fakeMember).Return(errors.New("forced error"))
+		err := mockRepo.Create(context.Background(), fakeMember)
 		assert.Error(t, err)
 		assert.Equal(t, "forced error", err.Error())
 		mockRepo.AssertExpectations(t)
@@ -48,8 +50,8 @@ func TestGetAllByPage(t *testing.T) {
 			Phone:     "+989123456789",
 		}
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("GetAllByPage", 10, 0, false).Return([]*member.Member{fakeMember}, 1, nil)
-		members, count, err := mockRepo.GetAllByPage(10, 0, false)
+		mockRepo.On("GetAllByPage", mock.Anything, 10, 0, false).Return([]*member.Member{fakeMember}, 1, nil)
+		members, count, err := mockRepo.GetAllByPage(context.Background(), 10, 0, false)
 		assert.NoError(t, err)
 		assert.Equal(t, 1, count)
 		assert.Equal(t, fakeMember, members[0])
@@ -58,8 +60,8 @@ func TestGetAllByPage(t *testing.T) {
 
 	t.Run("error", func(t *testing.T) {
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("GetAllByPage", 10, 0, false).Return(nil, 0, errors.New("forced error"))
-		members, count, err := mockRepo.GetAllByPage(10, 0, false)
+		mockRepo.On("GetAllByPage", mock.Anything, 10, 0, false).Return(nil, 0, errors.New("forced error"))
+		members, count, err := mockRepo.GetAllByPage(context.Background(), 10, 0, false)
 		assert.Error(t, err)
 		assert.Equal(t, 0, count)
 		assert.Nil(t, members)
@@ -77,8 +79,8 @@ func TestGetByID(t *testing.T) {
 			Phone:     "+989123456789",
 		}
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("GetById", int64(1)).Return(fakeMember, nil)
-		member, err := mockRepo.GetById(1)
+		mockRepo.On("GetById", mock.Anything, int64(1)).Return(fakeMember, nil)
+		member, err := mockRepo.GetById(context.Background(), 1)
 		assert.NoError(t, err)
 		assert.Equal(t, fakeMember, member)
 		mockRepo.AssertExpectations(t)
@@ -86,8 +88,8 @@ func TestGetByID(t *testing.T) {
 
 	t.Run("error", func(t *testing.T) {
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("GetById", int64(1)).Return(nil, errors.New("forced error"))
-		member, err := mockRepo.GetById(1)
+		mockRepo.On("GetById", mock.Anything, int64(1)).Return(nil, errors.New("forced error"))
+		member, err := mockRepo.GetById(context.Background(), 1)
 		assert.Error(t, err)
 		assert.Nil(t, member)
 		assert.Equal(t, "forced error", err.Error())
@@ -104,8 +106,8 @@ func TestGetByPhone(t *testing.T) {
 			Phone:     "+989123456789",
 		}
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("GetByPhone", "+989123456789").Return(fakeMember, nil)
-		member, err := mockRepo.GetByPhone("+989123456789")
+		mockRepo.On("GetByPhone", mock.Anything, "+989123456789").Return(fakeMember, nil)
+		member, err := mockRepo.GetByPhone(context.Background(), "+989123456789")
 		assert.NoError(t, err)
 		assert.Equal(t, fakeMember, member)
 		mockRepo.AssertExpectations(t)
@@ -113,8 +115,8 @@ func TestGetByPhone(t *testing.T) {
 
 	t.Run("error", func(t *testing.T) {
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("GetByPhone", "+989123456789").Return(nil, errors.New("forced error"))
-		member, err := mockRepo.GetByPhone("+989123456789")
+		mockRepo.On("GetByPhone", mock.Anything, "+989123456789").Return(nil, errors.New("forced error"))
+		member, err := mockRepo.GetByPhone(context.Background(), "+989123456789")
 		assert.Error(t, err)
 		assert.Nil(t, member)
 		assert.Equal(t, "forced error", err.Error())
@@ -131,8 +133,8 @@ func TestUpdate(t *testing.T) {
 			Phone:     "+989123456789",
 		}
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("Update", fakeMember).Return(nil)
-		err := mockRepo.Update(fakeMember)
+		mockRepo.On("Update", mock.Anything, fakeMember).Return(nil)
+		err := mockRepo.Update(context.Background(), fakeMember)
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
 	})
@@ -145,8 +147,8 @@ func TestUpdate(t *testing.T) {
 			Phone:     "+989123456789",
 		}
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("Update", fakeMember).Return(errors.New("forced error"))
-		err := mockRepo.Update(fakeMember)
+		mockRepo.On("Update", mock.Anything, fakeMember).Return(errors.New("forced error"))
+		err := mockRepo.Update(context.Background(), fakeMember)
 		assert.Error(t, err)
 		assert.Equal(t, "forced error", err.Error())
 		mockRepo.AssertExpectations(t)
@@ -0,0 +1,15 @@
+package outbox
+
+import "time"
+
+// Event is a domain event waiting in the outbox until it is published.
+type Event struct {
+	ID          int64      `db:"id"`
+	Type        string     `db:"event_type"`
+	WalletID    int64      `db:"wallet_id"`
+	Payload     []byte     `db:"payload"`
+	Attempts    int        `db:"attempts"`
+	LastError   string     `db:"last_error"`
+	CreatedAt   time.Time  `db:"created_at"`
+	PublishedAt *time.Time `db:"published_at"`
+}
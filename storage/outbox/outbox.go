@@ -0,0 +1,58 @@
+package outbox
+
+import (
+	"context"
+	"wallet/internal/serr"
+)
+
+const eventColumns = "id,event_type,wallet_id,payload,attempts,last_error,created_at,published_at"
+
+func (s Storage) Insert(ctx context.Context, e *Event) error {
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO outbox_event (event_type, wallet_id, payload)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`, e.Type, e.WalletID, e.Payload).Scan(&e.ID, &e.CreatedAt)
+	if err != nil {
+		return serr.DBError("Insert", "outbox", err)
+	}
+	return nil
+}
+
+// GetUnpublished returns the oldest events not published yet and locks them until the surrounding db
+// transaction ends. Events locked by another relay are skipped.
+func (s Storage) GetUnpublished(ctx context.Context, limit int) ([]*Event, error) {
+	sqlStmt := "SELECT " + eventColumns + " FROM outbox_event WHERE published_at IS NULL ORDER BY id LIMIT $1 " +
+		"FOR UPDATE SKIP LOCKED"
+	rows, err := s.db.QueryContext(ctx, sqlStmt, limit)
+	if err != nil {
+		return nil, serr.DBError("GetUnpublished", "outbox", err)
+	}
+	defer rows.Close()
+	events := make([]*Event, 0)
+	for rows.Next() {
+		e, err := s.ScanEvent(rows)
+		if err != nil {
+			return nil, serr.DBError("GetUnpublished", "outbox", err)
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+func (s Storage) MarkPublished(ctx context.Context, id int64) error {
+	_, err := s.db.ExecContext(ctx, "UPDATE outbox_event SET published_at = now(), attempts = attempts + 1 WHERE id = $1", id)
+	if err != nil {
+		return serr.DBError("MarkPublished", "outbox", err)
+	}
+	return nil
+}
+
+// MarkFailed counts a failed publishing attempt, the event stays in the outbox to be retried.
+func (s Storage) MarkFailed(ctx context.Context, id int64, reason string) error {
+	_, err := s.db.ExecContext(ctx, "UPDATE outbox_event SET attempts = attempts + 1, last_error = $1 WHERE id = $2", reason, id)
+	if err != nil {
+		return serr.DBError("MarkFailed", "outbox", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package outbox_test
+
+import (
+	"context"
+	"testing"
+
+	"wallet/db"
+	"wallet/db/dbtest"
+	"wallet/storage/outbox"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// claimLimit is large enough for a relay to claim the events other tests left in the shared database.
+const claimLimit = 10000
+
+// find returns the events of walletID among events.
+func find(events []*outbox.Event, walletID int64) []*outbox.Event {
+	var found []*outbox.Event
+	for _, e := range events {
+		if e.WalletID == walletID {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+func TestInsert(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := outbox.NewStorage(psql)
+	walletID := dbtest.Wallet(t, psql, 0)
+
+	e := &outbox.Event{Type: "BalanceCredited", WalletID: walletID, Payload: []byte(`{"amount":100}`)}
+	require.NoError(t, s.Insert(context.Background(), e))
+	assert.NotZero(t, e.ID)
+	assert.False(t, e.CreatedAt.IsZero())
+
+	// the payload is json
+	assert.Error(t, s.Insert(context.Background(), &outbox.Event{Type: "BalanceCredited", WalletID: walletID,
+		Payload: []byte(`not json`)}))
+}
+
+func TestGetUnpublished_SkipsLockedEvents(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := outbox.NewStorage(psql)
+	walletID := dbtest.Wallet(t, psql, 0)
+	first := &outbox.Event{Type: "BalanceCredited", WalletID: walletID, Payload: []byte(`{"amount":100}`)}
+	second := &outbox.Event{Type: "BalanceDebited", WalletID: walletID, Payload: []byte(`{"amount":40}`)}
+	require.NoError(t, s.Insert(context.Background(), first))
+	require.NoError(t, s.Insert(context.Background(), second))
+
+	err := db.Transaction(context.Background(), func(ctx context.Context) error {
+		events, err := s.GetUnpublished(ctx, claimLimit)
+		if err != nil {
+			return err
+		}
+		claimed := find(events, walletID)
+		require.Len(t, claimed, 2)
+		assert.Equal(t, first.ID, claimed[0].ID)
+		assert.Equal(t, second.ID, claimed[1].ID)
+		assert.JSONEq(t, `{"amount":100}`, string(claimed[0].Payload))
+
+		// a second relay running at the same time skips the claimed events instead of waiting for them
+		return db.Transaction(context.Background(), func(ctx context.Context) error {
+			events, err := s.GetUnpublished(ctx, claimLimit)
+			if err != nil {
+				return err
+			}
+			assert.Empty(t, find(events, walletID))
+			return nil
+		})
+	})
+	require.NoError(t, err)
+
+	// the locks end with the transaction
+	events, err := s.GetUnpublished(context.Background(), claimLimit)
+	require.NoError(t, err)
+	assert.Len(t, find(events, walletID), 2)
+}
+
+func TestMarkPublished(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := outbox.NewStorage(psql)
+	ctx := context.Background()
+	walletID := dbtest.Wallet(t, psql, 0)
+	published := &outbox.Event{Type: "BalanceCredited", WalletID: walletID, Payload: []byte(`{}`)}
+	failed := &outbox.Event{Type: "BalanceDebited", WalletID: walletID, Payload: []byte(`{}`)}
+	require.NoError(t, s.Insert(ctx, published))
+	require.NoError(t, s.Insert(ctx, failed))
+
+	require.NoError(t, s.MarkPublished(ctx, published.ID))
+	require.NoError(t, s.MarkFailed(ctx, failed.ID, "broker down"))
+	require.NoError(t, s.MarkFailed(ctx, failed.ID, "broker down"))
+
+	// only the failed event is published again
+	events, err := s.GetUnpublished(ctx, claimLimit)
+	require.NoError(t, err)
+	left := find(events, walletID)
+	require.Len(t, left, 1)
+	assert.Equal(t, failed.ID, left[0].ID)
+	assert.Equal(t, 2, left[0].Attempts)
+	assert.Equal(t, "broker down", left[0].LastError)
+	assert.Nil(t, left[0].PublishedAt)
+}
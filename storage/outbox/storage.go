@@ -0,0 +1,37 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"wallet/db"
+)
+
+type Repository interface {
+	Insert(ctx context.Context, e *Event) error
+	GetUnpublished(ctx context.Context, limit int) ([]*Event, error)
+	MarkPublished(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, reason string) error
+}
+
+type Storage struct {
+	db db.SQLExt
+}
+
+func NewStorage(psql *sql.DB) Storage {
+	return Storage{db: db.NewConn(psql)}
+}
+
+func (s Storage) ScanEvent(scanner db.Scanner) (*Event, error) {
+	e := &Event{}
+	var lastError sql.NullString
+	var publishedAt sql.NullTime
+	err := scanner.Scan(&e.ID, &e.Type, &e.WalletID, &e.Payload, &e.Attempts, &lastError, &e.CreatedAt, &publishedAt)
+	if err != nil {
+		return nil, err
+	}
+	e.LastError = lastError.String
+	if publishedAt.Valid {
+		e.PublishedAt = &publishedAt.Time
+	}
+	return e, nil
+}
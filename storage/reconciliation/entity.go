@@ -0,0 +1,18 @@
+package reconciliation
+
+import "time"
+
+// Report records a wallet whose balance column or transaction history did not match its ledger.
+type Report struct {
+	ID                      int64     `db:"id"`
+	RunID                   string    `db:"run_id"`
+	WalletID                int64     `db:"wallet_id"`
+	WalletBalance           int64     `db:"wallet_balance"`
+	TransactionBalance      int64     `db:"transaction_balance"`
+	LedgerBalance           int64     `db:"ledger_balance"`
+	Difference              int64     `db:"difference"`
+	Repaired                bool      `db:"repaired"`
+	CorrectionEntryID       int64     `db:"correction_entry_id"`
+	CorrectionTransactionID int64     `db:"correction_transaction_id"`
+	CreatedAt               time.Time `db:"created_at"`
+}
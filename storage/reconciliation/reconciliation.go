@@ -0,0 +1,46 @@
+package reconciliation
+
+import (
+	"context"
+	"database/sql"
+	"wallet/internal/serr"
+)
+
+const reportColumns = "id,run_id,wallet_id,wallet_balance,transaction_balance,ledger_balance,difference,repaired," +
+	"correction_entry_id,correction_transaction_id,created_at"
+
+func (s Storage) Insert(ctx context.Context, r *Report) error {
+	correctionEntryID := sql.NullInt64{Int64: r.CorrectionEntryID, Valid: r.CorrectionEntryID != 0}
+	correctionTransactionID := sql.NullInt64{Int64: r.CorrectionTransactionID, Valid: r.CorrectionTransactionID != 0}
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO reconciliation_report
+		    (run_id, wallet_id, wallet_balance, transaction_balance, ledger_balance, difference, repaired,
+		     correction_entry_id, correction_transaction_id)
+		VALUES 
+		    ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`, r.RunID, r.WalletID, r.WalletBalance, r.TransactionBalance, r.LedgerBalance, r.Difference, r.Repaired,
+		correctionEntryID, correctionTransactionID).Scan(&r.ID, &r.CreatedAt)
+	if err != nil {
+		return serr.DBError("Insert", "reconciliation report", err)
+	}
+	return nil
+}
+
+func (s Storage) GetByRunID(ctx context.Context, runID string) ([]*Report, error) {
+	sqlStmt := "SELECT " + reportColumns + " FROM reconciliation_report WHERE run_id = $1 ORDER BY wallet_id"
+	rows, err := s.db.QueryContext(ctx, sqlStmt, runID)
+	if err != nil {
+		return nil, serr.DBError("GetByRunID", "reconciliation report", err)
+	}
+	defer rows.Close()
+	reports := make([]*Report, 0)
+	for rows.Next() {
+		r, err := s.ScanReport(rows)
+		if err != nil {
+			return nil, serr.DBError("GetByRunID", "reconciliation report", err)
+		}
+		reports = append(reports, r)
+	}
+	return reports, nil
+}
@@ -0,0 +1,53 @@
+package reconciliation_test
+
+import (
+	"context"
+	"testing"
+
+	"wallet/db/dbtest"
+	"wallet/storage/reconciliation"
+	"wallet/storage/transaction"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsert(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	ctx := context.Background()
+	s := reconciliation.NewStorage(psql)
+	walletID := dbtest.Wallet(t, psql, 100)
+	correction := &transaction.Transaction{WalletID: walletID, Amount: 50, Currency: "IRR",
+		TransactionType: transaction.Correction, Description: "reconciliation"}
+	require.NoError(t, transaction.NewStorage(psql).Insert(ctx, correction))
+
+	runID := uuid.NewString()
+	repaired := &reconciliation.Report{RunID: runID, WalletID: walletID, WalletBalance: 100, TransactionBalance: 100,
+		LedgerBalance: 150, Difference: 50, Repaired: true, CorrectionTransactionID: correction.ID}
+	require.NoError(t, s.Insert(ctx, repaired))
+	assert.NotZero(t, repaired.ID)
+	assert.False(t, repaired.CreatedAt.IsZero())
+
+	reported := &reconciliation.Report{RunID: runID, WalletID: walletID + 1, WalletBalance: 10, LedgerBalance: -5,
+		Difference: -15}
+	require.NoError(t, s.Insert(ctx, reported))
+
+	rs, err := s.GetByRunID(ctx, runID)
+	require.NoError(t, err)
+	require.Len(t, rs, 2)
+	assert.Equal(t, repaired.ID, rs[0].ID)
+	assert.Equal(t, correction.ID, rs[0].CorrectionTransactionID)
+	assert.Zero(t, rs[0].CorrectionEntryID)
+	assert.True(t, rs[0].Repaired)
+	assert.Equal(t, int64(-15), rs[1].Difference)
+	assert.Zero(t, rs[1].CorrectionTransactionID)
+	assert.False(t, rs[1].Repaired)
+}
+
+func TestGetByRunID_Empty(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	rs, err := reconciliation.NewStorage(psql).GetByRunID(context.Background(), uuid.NewString())
+	require.NoError(t, err)
+	assert.Empty(t, rs)
+}
@@ -0,0 +1,33 @@
+package reconciliation
+
+import (
+	"context"
+	"database/sql"
+	"wallet/db"
+)
+
+type Repository interface {
+	Insert(ctx context.Context, r *Report) error
+	GetByRunID(ctx context.Context, runID string) ([]*Report, error)
+}
+
+type Storage struct {
+	db db.SQLExt
+}
+
+func NewStorage(psql *sql.DB) Storage {
+	return Storage{db: db.NewConn(psql)}
+}
+
+func (s Storage) ScanReport(scanner db.Scanner) (*Report, error) {
+	r := &Report{}
+	var correctionEntryID, correctionTransactionID sql.NullInt64
+	err := scanner.Scan(&r.ID, &r.RunID, &r.WalletID, &r.WalletBalance, &r.TransactionBalance, &r.LedgerBalance,
+		&r.Difference, &r.Repaired, &correctionEntryID, &correctionTransactionID, &r.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	r.CorrectionEntryID = correctionEntryID.Int64
+	r.CorrectionTransactionID = correctionTransactionID.Int64
+	return r, nil
+}
@@ -0,0 +1,34 @@
+package redemption
+
+import "time"
+
+type Status string
+
+const (
+	// Pending redemptions are written before their gift is used.
+	Pending Status = "pending"
+	// Used redemptions have their gift used and their wallet not credited yet.
+	Used      Status = "used"
+	Completed Status = "completed"
+	// Released redemptions gave the use of their gift back after the credit failed.
+	Released Status = "released"
+	// Failed redemptions had their gift turned down by the discount service.
+	Failed Status = "failed"
+)
+
+// Redemption is a gift redeemed into a wallet, from before the gift is used until the wallet is
+// credited with Amount or the use is released. Reference is sent with the use and the release of the
+// gift so repeating them is safe. A member holds one active redemption of a gift at a time when Once
+// is set, gifts redeemed more than once are limited by the discount service.
+type Redemption struct {
+	ID        int64     `db:"id"`
+	MemberID  int64     `db:"member_id"`
+	WalletID  int64     `db:"wallet_id"`
+	GiftCode  string    `db:"gift_code"`
+	Reference string    `db:"reference"`
+	Amount    int64     `db:"amount"`
+	Status    Status    `db:"status"`
+	Once      bool      `db:"once"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
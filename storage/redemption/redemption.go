@@ -0,0 +1,71 @@
+package redemption
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"wallet/internal/serr"
+)
+
+const redemptionColumns = "id,member_id,wallet_id,gift_code,reference,amount,status,once,created_at,updated_at"
+
+// Create stores a redemption and returns ErrActive if it is redeemed once and the member already has
+// one of the gift that is not released or failed.
+func (s Storage) Create(ctx context.Context, r *Redemption) error {
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO gift_redemption (member_id, wallet_id, gift_code, reference, amount, status, once)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT DO NOTHING
+		RETURNING id, created_at, updated_at
+	`, r.MemberID, r.WalletID, r.GiftCode, r.Reference, r.Amount, r.Status, r.Once).Scan(&r.ID, &r.CreatedAt, &r.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrActive
+	}
+	if err != nil {
+		return serr.DBError("Create", "gift redemption", err)
+	}
+	return nil
+}
+
+// GetByIDForUpdate locks the redemption row until the surrounding db transaction ends.
+func (s Storage) GetByIDForUpdate(ctx context.Context, id int64) (*Redemption, error) {
+	sqlStmt := "SELECT " + redemptionColumns + " FROM gift_redemption WHERE id = $1 FOR UPDATE"
+	r, err := s.ScanRedemption(s.db.QueryRowContext(ctx, sqlStmt, id))
+	if err != nil {
+		return nil, serr.DBError("GetByIDForUpdate", "gift redemption", err)
+	}
+	return r, nil
+}
+
+// UpdateStatus stores the status and amount of a redemption.
+func (s Storage) UpdateStatus(ctx context.Context, r *Redemption) error {
+	err := s.db.QueryRowContext(ctx, `
+		UPDATE gift_redemption SET status = $1, amount = $2, updated_at = now() WHERE id = $3
+		RETURNING updated_at
+	`, r.Status, r.Amount, r.ID).Scan(&r.UpdatedAt)
+	if err != nil {
+		return serr.DBError("UpdateStatus", "gift redemption", err)
+	}
+	return nil
+}
+
+// GetStuck returns pending and used redemptions not changed since before, oldest first.
+func (s Storage) GetStuck(ctx context.Context, before time.Time, limit int) ([]*Redemption, error) {
+	sqlStmt := "SELECT " + redemptionColumns + ` FROM gift_redemption
+		WHERE status IN ($1, $2) AND updated_at < $3 ORDER BY updated_at LIMIT $4`
+	rows, err := s.db.QueryContext(ctx, sqlStmt, Pending, Used, before, limit)
+	if err != nil {
+		return nil, serr.DBError("GetStuck", "gift redemption", err)
+	}
+	defer rows.Close()
+	redemptions := make([]*Redemption, 0)
+	for rows.Next() {
+		r, err := s.ScanRedemption(rows)
+		if err != nil {
+			return nil, serr.DBError("GetStuck", "gift redemption", err)
+		}
+		redemptions = append(redemptions, r)
+	}
+	return redemptions, nil
+}
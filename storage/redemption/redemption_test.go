@@ -0,0 +1,151 @@
+package redemption_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"wallet/db"
+	"wallet/db/dbtest"
+	"wallet/storage/redemption"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRedemption returns a pending redemption of code into a new wallet.
+func newRedemption(t *testing.T, psql *sql.DB, code string) *redemption.Redemption {
+	t.Helper()
+	r := &redemption.Redemption{WalletID: dbtest.Wallet(t, psql, 0), GiftCode: code,
+		Reference: uuid.NewString(), Status: redemption.Pending, Once: true}
+	require.NoError(t, psql.QueryRow("SELECT member_id FROM wallet WHERE id = $1", r.WalletID).Scan(&r.MemberID))
+	return r
+}
+
+func TestCreate(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := redemption.NewStorage(psql)
+	ctx := context.Background()
+
+	r := newRedemption(t, psql, "GIFT")
+	require.NoError(t, s.Create(ctx, r))
+	assert.NotZero(t, r.ID)
+	assert.False(t, r.CreatedAt.IsZero())
+
+	// the member can not redeem the gift again while the redemption is active
+	again := *r
+	again.ID, again.Reference = 0, uuid.NewString()
+	assert.ErrorIs(t, s.Create(ctx, &again), redemption.ErrActive)
+
+	// the same reference can not be used twice
+	other := newRedemption(t, psql, "GIFT")
+	other.Reference = r.Reference
+	err := s.Create(ctx, other)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, redemption.ErrActive)
+
+	// a released redemption lets the gift be redeemed again
+	r.Status = redemption.Released
+	require.NoError(t, s.UpdateStatus(ctx, r))
+	require.NoError(t, s.Create(ctx, &again))
+	assert.NotEqual(t, r.ID, again.ID)
+
+	// gifts not redeemed once can have many active redemptions of a member
+	many := newRedemption(t, psql, "GIFT")
+	many.Once = false
+	require.NoError(t, s.Create(ctx, many))
+	more := *many
+	more.ID, more.Reference = 0, uuid.NewString()
+	require.NoError(t, s.Create(ctx, &more))
+	got, err := s.GetByIDForUpdate(ctx, more.ID)
+	require.NoError(t, err)
+	assert.False(t, got.Once)
+}
+
+func TestUpdateStatus(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := redemption.NewStorage(psql)
+	ctx := context.Background()
+	r := newRedemption(t, psql, "GIFT")
+	require.NoError(t, s.Create(ctx, r))
+
+	r.Status, r.Amount = redemption.Used, 500
+	require.NoError(t, s.UpdateStatus(ctx, r))
+	got, err := s.GetByIDForUpdate(ctx, r.ID)
+	require.NoError(t, err)
+	assert.Equal(t, redemption.Used, got.Status)
+	assert.Equal(t, int64(500), got.Amount)
+	assert.Equal(t, r.Reference, got.Reference)
+	assert.False(t, got.UpdatedAt.Before(got.CreatedAt))
+
+	assert.Error(t, s.UpdateStatus(ctx, &redemption.Redemption{ID: -1, Status: redemption.Used}))
+}
+
+func TestGetByIDForUpdate(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := redemption.NewStorage(psql)
+	r := newRedemption(t, psql, "GIFT")
+	require.NoError(t, s.Create(context.Background(), r))
+
+	updated := make(chan error, 1)
+	err := db.Transaction(context.Background(), func(ctx context.Context) error {
+		if _, err := s.GetByIDForUpdate(ctx, r.ID); err != nil {
+			return err
+		}
+		go func() {
+			updated <- s.UpdateStatus(context.Background(), &redemption.Redemption{ID: r.ID, Status: redemption.Failed})
+		}()
+		select {
+		case <-updated:
+			t.Error("the redemption changed while it was locked")
+		case <-time.After(100 * time.Millisecond):
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, <-updated)
+
+	_, err = s.GetByIDForUpdate(context.Background(), -1)
+	assert.Error(t, err)
+}
+
+func TestGetStuck(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := redemption.NewStorage(psql)
+	ctx := context.Background()
+	create := func(code string, status redemption.Status) *redemption.Redemption {
+		r := newRedemption(t, psql, code)
+		require.NoError(t, s.Create(ctx, r))
+		if status != redemption.Pending {
+			r.Status = status
+			require.NoError(t, s.UpdateStatus(ctx, r))
+		}
+		return r
+	}
+	pending := create("PENDING", redemption.Pending)
+	used := create("USED", redemption.Used)
+	completed := create("COMPLETED", redemption.Completed)
+	released := create("RELEASED", redemption.Released)
+	before := time.Now().Add(time.Minute)
+
+	redemptions, err := s.GetStuck(ctx, before, 10000)
+	require.NoError(t, err)
+	// other tests share the database, only the redemptions of this test are looked at
+	ours := map[int64]bool{pending.ID: true, used.ID: true, completed.ID: true, released.ID: true}
+	var ids []int64
+	for _, r := range redemptions {
+		if ours[r.ID] {
+			ids = append(ids, r.ID)
+		}
+	}
+	assert.Equal(t, []int64{pending.ID, used.ID}, ids)
+
+	// redemptions changed after before are not stuck yet
+	redemptions, err = s.GetStuck(ctx, pending.UpdatedAt, 10000)
+	require.NoError(t, err)
+	for _, r := range redemptions {
+		assert.False(t, ours[r.ID])
+	}
+}
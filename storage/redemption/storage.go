@@ -0,0 +1,40 @@
+package redemption
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"wallet/db"
+)
+
+var (
+	// ErrActive is returned creating a redemption of a gift redeemed once that the member has already
+	// redeemed or is redeeming.
+	ErrActive = errors.New("gift redemption already exists")
+)
+
+type Repository interface {
+	Create(ctx context.Context, r *Redemption) error
+	GetByIDForUpdate(ctx context.Context, id int64) (*Redemption, error)
+	UpdateStatus(ctx context.Context, r *Redemption) error
+	GetStuck(ctx context.Context, before time.Time, limit int) ([]*Redemption, error)
+}
+
+type Storage struct {
+	db db.SQLExt
+}
+
+func NewStorage(psql *sql.DB) Storage {
+	return Storage{db: db.NewConn(psql)}
+}
+
+func (s Storage) ScanRedemption(scanner db.Scanner) (*Redemption, error) {
+	r := &Redemption{}
+	err := scanner.Scan(&r.ID, &r.MemberID, &r.WalletID, &r.GiftCode, &r.Reference, &r.Amount, &r.Status, &r.Once,
+		&r.CreatedAt, &r.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
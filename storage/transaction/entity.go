@@ -11,14 +11,32 @@ const (
 	Payment  Type = "payment"
 	Refund   Type = "refund"
 	Transfer Type = "transfer"
+	// Correction is written by reconciliation to bring the history in line with the ledger.
+	Correction Type = "correction"
 )
 
 type Transaction struct {
-	ID              int64     `db:"id"`
-	WalletID        int64     `db:"wallet_id"`
-	Amount          int64     `db:"amount"`
-	TransactionType Type      `db:"transaction_type"`
-	Description     string    `db:"description"`
-	DiscountCode    string    `db:"discount_code"`
-	CreatedAt       time.Time `db:"created_at"`
+	ID                int64     `db:"id"`
+	WalletID          int64     `db:"wallet_id"`
+	Amount            int64     `db:"amount"`
+	Currency          string    `db:"currency"`
+	TransactionType   Type      `db:"transaction_type"`
+	Description       string    `db:"description"`
+	DiscountCode      string    `db:"discount_code"`
+	JournalEntryID    int64     `db:"journal_entry_id"`
+	FXRate            float64   `db:"fx_rate"`
+	FXSpread          float64   `db:"fx_spread"`
+	CounterAmount     int64     `db:"counter_amount"`
+	CounterCurrency   string    `db:"counter_currency"`
+	MerchantReference string    `db:"merchant_reference"`
+	OrderID           string    `db:"order_id"`
+	ParentID          int64     `db:"parent_transaction_id"`
+	CreatedAt         time.Time `db:"created_at"`
+}
+
+// DebitTotals are the debits of a wallet since the start of the current day and month.
+type DebitTotals struct {
+	Day            int64
+	Month          int64
+	TransfersToday int
 }
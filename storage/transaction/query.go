@@ -0,0 +1,104 @@
+package transaction
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+	"wallet/internal/serr"
+)
+
+// Filter selects transactions for Find, zero fields match everything. Results are ordered newest
+// first by (created_at, id).
+type Filter struct {
+	WalletID     int64
+	Types        []Type
+	DiscountCode string
+	// CreatedFrom is inclusive and CreatedTo exclusive
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	MinAmount   *int64
+	MaxAmount   *int64
+	// After continues a listing after the last transaction of the previous page
+	After  *Cursor
+	Limit  int
+	Offset int
+}
+
+// Cursor is the (created_at, id) position of a transaction in a listing.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+// Query returns the select statement of the filter and its arguments.
+func (f *Filter) Query() (string, []any) {
+	var conditions []string
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+	if f.WalletID != 0 {
+		conditions = append(conditions, "wallet_id = "+arg(f.WalletID))
+	}
+	if len(f.Types) > 0 {
+		placeholders := make([]string, len(f.Types))
+		for i, t := range f.Types {
+			placeholders[i] = arg(t)
+		}
+		conditions = append(conditions, "transaction_type IN ("+strings.Join(placeholders, ",")+")")
+	}
+	if f.DiscountCode != "" {
+		conditions = append(conditions, "discount_code = "+arg(f.DiscountCode))
+	}
+	if !f.CreatedFrom.IsZero() {
+		conditions = append(conditions, "created_at >= "+arg(f.CreatedFrom))
+	}
+	if !f.CreatedTo.IsZero() {
+		conditions = append(conditions, "created_at < "+arg(f.CreatedTo))
+	}
+	if f.MinAmount != nil {
+		conditions = append(conditions, "amount >= "+arg(*f.MinAmount))
+	}
+	if f.MaxAmount != nil {
+		conditions = append(conditions, "amount <= "+arg(*f.MaxAmount))
+	}
+	if f.After != nil {
+		conditions = append(conditions, "(created_at, id) < ("+arg(f.After.CreatedAt)+", "+arg(f.After.ID)+")")
+	}
+	sqlStmt := "SELECT " + transactionColumns + " FROM transaction"
+	if len(conditions) > 0 {
+		sqlStmt += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	sqlStmt += " ORDER BY created_at DESC, id DESC"
+	if f.Limit > 0 {
+		sqlStmt += " LIMIT " + arg(f.Limit)
+	}
+	if f.Offset > 0 {
+		sqlStmt += " OFFSET " + arg(f.Offset)
+	}
+	return sqlStmt, args
+}
+
+// Find returns the transactions matching the filter.
+func (s Storage) Find(ctx context.Context, f *Filter) ([]*Transaction, error) {
+	sqlStmt, args := f.Query()
+	rows, err := s.db.QueryContext(ctx, sqlStmt, args...)
+	if err != nil {
+		return nil, serr.DBError("Find", "transaction", err)
+	}
+	defer rows.Close()
+	transactions := make([]*Transaction, 0)
+	for rows.Next() {
+		t, err := s.ScanTransaction(rows)
+		if err != nil {
+			return nil, serr.DBError("Find", "transaction", err)
+		}
+		transactions = append(transactions, t)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, serr.DBError("Find", "transaction", err)
+	}
+	return transactions, nil
+}
@@ -1,45 +1,47 @@
 package transaction
 
 import (
+	"context"
 	"database/sql"
+	"time"
 	"wallet/db"
 )
 
 type Repository interface {
-	Insert(t *Transaction) error
-	GetByID(id int64) (*Transaction, error)
-	GetByWalletID(walletID int64) ([]*Transaction, error)
-	GetByWalletIDWithPagination(walletID int64, limit, offset int) ([]*Transaction, error)
-	GetByWalletIDAndType(walletID int64, transactionType Type) ([]*Transaction, error)
-	GetByWalletIDAndDiscountCode(walletID int64, discountCode string) ([]*Transaction, error)
-	GetByWalletIDAndTypeAndDiscountCode(walletID int64, transactionType Type, discountCode string) ([]*Transaction, error)
-	GetByDiscountCodeWithPagination(discountCode string, limit, offset int) ([]*Transaction, error)
-	DeleteByWalletID(walletID int64) error
-	DeleteByWalletIDAndType(walletID int64, transactionType Type) error
-	DeleteByWalletIDAndDiscountCode(walletID int64, discountCode string) error
-	DeleteByID(id int64) error
-	GetBalance(walletID int64) (int64, error)
-	WithTX(tx *sql.Tx) (Repository, error)
+	Insert(ctx context.Context, t *Transaction) error
+	GetByID(ctx context.Context, id int64) (*Transaction, error)
+	Find(ctx context.Context, f *Filter) ([]*Transaction, error)
+	GetBalance(ctx context.Context, walletID int64) (int64, error)
+	GetBalanceBefore(ctx context.Context, walletID int64, before time.Time) (int64, error)
+	GetRefundedAmount(ctx context.Context, parentID int64) (int64, error)
+	GetDebitTotals(ctx context.Context, walletID int64, dayStart, monthStart time.Time) (*DebitTotals, error)
+	GetArchivableWalletIDs(ctx context.Context, before time.Time, limit int) ([]int64, error)
+	Archive(ctx context.Context, walletID int64) (int64, error)
 }
 
 type Storage struct {
 	db db.SQLExt
 }
 
-func NewStorage(db *sql.DB) Storage {
-	return Storage{db: db}
+func NewStorage(psql *sql.DB) Storage {
+	return Storage{db: db.NewConn(psql)}
 }
 
-// WithTX returns a new storage with the given transaction replacing the db.
-func (s Storage) WithTX(tx *sql.Tx) (Repository, error) {
-	if tx == nil {
-		return nil, db.ErrNoTXProvided
+func (s Storage) ScanTransaction(scanner db.Scanner) (*Transaction, error) {
+	t := &Transaction{}
+	var journalEntryID, counterAmount, parentID sql.NullInt64
+	var fxRate, fxSpread sql.NullFloat64
+	var counterCurrency, merchantReference, orderID sql.NullString
+	err := scanner.Scan(&t.ID, &t.WalletID, &t.Amount, &t.Currency, &t.TransactionType, &t.Description, &t.DiscountCode,
+		&journalEntryID, &fxRate, &fxSpread, &counterAmount, &counterCurrency, &merchantReference, &orderID,
+		&parentID, &t.CreatedAt)
+	if err != nil {
+		return nil, err
 	}
-	switch s.db.(type) {
-	case *sql.Tx:
-		return nil, db.ErrAlreadyInTX
-	case *sql.DB:
-		return Storage{db: tx}, nil
-	}
-	return s, nil
+	t.JournalEntryID = journalEntryID.Int64
+	t.FXRate, t.FXSpread = fxRate.Float64, fxSpread.Float64
+	t.CounterAmount, t.CounterCurrency = counterAmount.Int64, counterCurrency.String
+	t.MerchantReference, t.OrderID = merchantReference.String, orderID.String
+	t.ParentID = parentID.Int64
+	return t, nil
 }
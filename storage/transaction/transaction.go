@@ -1,197 +1,147 @@
 package transaction
 
 import (
+	"context"
+	"database/sql"
+	"time"
+	"wallet/db"
 	"wallet/internal/serr"
 )
 
-const transactionColumns = "id,wallet_id,amount,transaction_type,description,discount_code,created_at"
+const transactionColumns = "id,wallet_id,amount,currency,transaction_type,description,discount_code,journal_entry_id," +
+	"fx_rate,fx_spread,counter_amount,counter_currency,merchant_reference,order_id," +
+	"parent_transaction_id,created_at"
 
-func (s Storage) Insert(t *Transaction) error {
-	err := s.db.QueryRow(`
+func (s Storage) Insert(ctx context.Context, t *Transaction) error {
+	journalEntryID := sql.NullInt64{Int64: t.JournalEntryID, Valid: t.JournalEntryID != 0}
+	converted := t.CounterCurrency != ""
+	err := s.db.QueryRowContext(ctx, `
 		INSERT INTO transaction
-		    (wallet_id, amount, transaction_type, description, discount_code)
+		    (wallet_id, amount, currency, transaction_type, description, discount_code, journal_entry_id,
+		     fx_rate, fx_spread, counter_amount, counter_currency, merchant_reference, order_id,
+		     parent_transaction_id)
 		VALUES 
-		    ($1, $2, $3, $4, $5)
+		    ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING id, created_at
-	`, t.WalletID, t.Amount, t.TransactionType, t.Description, t.DiscountCode).Scan(&t.ID, &t.CreatedAt)
+	`, t.WalletID, t.Amount, t.Currency, t.TransactionType, t.Description, t.DiscountCode, journalEntryID,
+		sql.NullFloat64{Float64: t.FXRate, Valid: converted}, sql.NullFloat64{Float64: t.FXSpread, Valid: converted},
+		sql.NullInt64{Int64: t.CounterAmount, Valid: converted}, sql.NullString{String: t.CounterCurrency, Valid: converted},
+		sql.NullString{String: t.MerchantReference, Valid: t.MerchantReference != ""},
+		sql.NullString{String: t.OrderID, Valid: t.OrderID != ""},
+		sql.NullInt64{Int64: t.ParentID, Valid: t.ParentID != 0},
+	).Scan(&t.ID, &t.CreatedAt)
 	if err != nil {
 		return serr.DBError("Insert", "transaction", err)
 	}
 	return nil
 }
 
-func (s Storage) GetByID(id int64) (*Transaction, error) {
+func (s Storage) GetByID(ctx context.Context, id int64) (*Transaction, error) {
 	sqlStmt := "SELECT " + transactionColumns + " FROM transaction WHERE id = $1"
-	t := &Transaction{}
-	err := s.db.QueryRow(sqlStmt, id).Scan(&t.ID, &t.WalletID, &t.Amount, &t.TransactionType, &t.Description, &t.DiscountCode, &t.CreatedAt)
+	t, err := s.ScanTransaction(s.db.QueryRowContext(ctx, sqlStmt, id))
 	if err != nil {
 		return nil, serr.DBError("GetByID", "transaction", err)
 	}
 	return t, nil
 }
 
-func (s Storage) GetByWalletID(walletID int64) ([]*Transaction, error) {
-	sqlStmt := "SELECT " + transactionColumns + " FROM transaction WHERE wallet_id = $1 ORDER BY created_at DESC"
-	rows, err := s.db.Query(sqlStmt, walletID)
-	if err != nil {
-		return nil, serr.DBError("GetByWalletID", "transaction", err)
-	}
-	defer rows.Close()
-	transactions := make([]*Transaction, 0)
-	for rows.Next() {
-		t := &Transaction{}
-		err := rows.Scan(&t.ID, &t.WalletID, &t.Amount, &t.TransactionType, &t.Description, &t.DiscountCode, &t.CreatedAt)
-		if err != nil {
-			return nil, serr.DBError("GetByWalletID", "transaction", err)
-		}
-		transactions = append(transactions, t)
-	}
-	return transactions, nil
-}
-
-func (s Storage) GetByWalletIDWithPagination(walletID int64, limit, offset int) ([]*Transaction, error) {
-	sqlStmt := "SELECT " + transactionColumns + " FROM transaction WHERE wallet_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3"
-	rows, err := s.db.Query(sqlStmt, walletID, limit, offset)
+// calculate amount of a wallet
+func (s Storage) GetBalance(ctx context.Context, walletID int64) (int64, error) {
+	sqlStmt := "SELECT coalesce(sum(amount), 0) FROM transaction WHERE wallet_id = $1"
+	var balance int64
+	err := s.db.QueryRowContext(ctx, sqlStmt, walletID).Scan(&balance)
 	if err != nil {
-		return nil, serr.DBError("GetByWalletIDWithPagination", "transaction", err)
-	}
-	defer rows.Close()
-	transactions := make([]*Transaction, 0)
-	for rows.Next() {
-		t := &Transaction{}
-		err := rows.Scan(&t.ID, &t.WalletID, &t.Amount, &t.TransactionType, &t.Description, &t.DiscountCode, &t.CreatedAt)
-		if err != nil {
-			return nil, serr.DBError("GetByWalletIDWithPagination", "transaction", err)
-		}
-		transactions = append(transactions, t)
+		return 0, serr.DBError("GetBalance", "transaction", err)
 	}
-	return transactions, nil
+	return balance, nil
 }
 
-func (s Storage) GetByWalletIDAndType(walletID int64, transactionType Type) ([]*Transaction, error) {
-	sqlStmt := "SELECT " + transactionColumns + " FROM transaction WHERE wallet_id = $1 AND transaction_type = $2"
-	rows, err := s.db.Query(sqlStmt, walletID, transactionType)
+// GetBalanceBefore returns the balance of a wallet made of the transactions created before the given time.
+func (s Storage) GetBalanceBefore(ctx context.Context, walletID int64, before time.Time) (int64, error) {
+	sqlStmt := "SELECT coalesce(sum(amount), 0) FROM transaction WHERE wallet_id = $1 AND created_at < $2"
+	var balance int64
+	err := s.db.QueryRowContext(ctx, sqlStmt, walletID, before).Scan(&balance)
 	if err != nil {
-		return nil, serr.DBError("GetByWalletIDAndType", "transaction", err)
-	}
-	defer rows.Close()
-	transactions := make([]*Transaction, 0)
-	for rows.Next() {
-		t := &Transaction{}
-		err := rows.Scan(&t.ID, &t.WalletID, &t.Amount, &t.TransactionType, &t.Description, &t.DiscountCode, &t.CreatedAt)
-		if err != nil {
-			return nil, serr.DBError("GetByWalletIDAndType", "transaction", err)
-		}
-		transactions = append(transactions, t)
+		return 0, serr.DBError("GetBalanceBefore", "transaction", err)
 	}
-	return transactions, nil
+	return balance, nil
 }
 
-func (s Storage) GetByWalletIDAndDiscountCode(walletID int64, discountCode string) ([]*Transaction, error) {
-	sqlStmt := "SELECT " + transactionColumns + " FROM transaction WHERE wallet_id = $1 AND discount_code = $2"
-	rows, err := s.db.Query(sqlStmt, walletID, discountCode)
+// GetRefundedAmount returns the sum of refunds already made against a transaction.
+func (s Storage) GetRefundedAmount(ctx context.Context, parentID int64) (int64, error) {
+	sqlStmt := "SELECT coalesce(sum(amount), 0) FROM transaction WHERE parent_transaction_id = $1 AND transaction_type = $2"
+	var amount int64
+	err := s.db.QueryRowContext(ctx, sqlStmt, parentID, Refund).Scan(&amount)
 	if err != nil {
-		return nil, serr.DBError("GetByWalletIDAndDiscountCode", "transaction", err)
-	}
-	defer rows.Close()
-	transactions := make([]*Transaction, 0)
-	for rows.Next() {
-		t := &Transaction{}
-		err := rows.Scan(&t.ID, &t.WalletID, &t.Amount, &t.TransactionType, &t.Description, &t.DiscountCode, &t.CreatedAt)
-		if err != nil {
-			return nil, serr.DBError("GetByWalletIDAndDiscountCode", "transaction", err)
-		}
-		transactions = append(transactions, t)
+		return 0, serr.DBError("GetRefundedAmount", "transaction", err)
 	}
-	return transactions, nil
+	return amount, nil
 }
 
-func (s Storage) GetByWalletIDAndTypeAndDiscountCode(walletID int64, transactionType Type, discountCode string) ([]*Transaction, error) {
-	sqlStmt := "SELECT " + transactionColumns + " FROM transaction WHERE wallet_id = $1 AND transaction_type = $2 AND discount_code = $3"
-	rows, err := s.db.Query(sqlStmt, walletID, transactionType, discountCode)
+// GetDebitTotals sums the withdrawals, payments and outgoing transfers of a wallet since dayStart and
+// monthStart and counts its outgoing transfers since dayStart. Refunds do not lower the totals.
+func (s Storage) GetDebitTotals(ctx context.Context, walletID int64, dayStart, monthStart time.Time) (*DebitTotals, error) {
+	sqlStmt := `
+		SELECT coalesce(sum(-amount) FILTER (WHERE created_at >= $2), 0),
+		       coalesce(sum(-amount), 0),
+		       count(*) FILTER (WHERE created_at >= $2 AND transaction_type = $4)
+		FROM transaction
+		WHERE wallet_id = $1 AND amount < 0 AND created_at >= $3 AND transaction_type IN ($4, $5, $6)`
+	t := &DebitTotals{}
+	err := s.db.QueryRowContext(ctx, sqlStmt, walletID, dayStart, monthStart, Transfer, Withdraw, Payment).
+		Scan(&t.Day, &t.Month, &t.TransfersToday)
 	if err != nil {
-		return nil, serr.DBError("GetByWalletIDAndTypeAndDiscountCode", "transaction", err)
+		return nil, serr.DBError("GetDebitTotals", "transaction", err)
 	}
-	defer rows.Close()
-	transactions := make([]*Transaction, 0)
-	for rows.Next() {
-		t := &Transaction{}
-		err := rows.Scan(&t.ID, &t.WalletID, &t.Amount, &t.TransactionType, &t.Description, &t.DiscountCode, &t.CreatedAt)
-		if err != nil {
-			return nil, serr.DBError("GetByWalletIDAndTypeAndDiscountCode", "transaction", err)
-		}
-		transactions = append(transactions, t)
-	}
-	return transactions, nil
+	return t, nil
 }
 
-// get by discount code and pagination sorted by created_at
-func (s Storage) GetByDiscountCodeWithPagination(discountCode string, limit, offset int) ([]*Transaction, error) {
-	sqlStmt := "SELECT " + transactionColumns + " FROM transaction WHERE discount_code = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3"
-	rows, err := s.db.Query(sqlStmt, discountCode, limit, offset)
+// GetArchivableWalletIDs returns wallets closed or deleted before the given time that still have
+// transactions to archive.
+func (s Storage) GetArchivableWalletIDs(ctx context.Context, before time.Time, limit int) ([]int64, error) {
+	sqlStmt := `
+		SELECT w.id FROM wallet w
+		WHERE (w.deleted_at < $1 OR (w.status = 'closed' AND (
+		          SELECT max(c.created_at) FROM wallet_status_change c
+		          WHERE c.wallet_id = w.id AND c.to_status = 'closed') < $1))
+		  AND EXISTS (SELECT 1 FROM transaction t WHERE t.wallet_id = w.id)
+		ORDER BY w.id LIMIT $2`
+	rows, err := s.db.QueryContext(ctx, sqlStmt, before, limit)
 	if err != nil {
-		return nil, serr.DBError("GetByDiscountCodeWithPagination", "transaction", err)
+		return nil, serr.DBError("GetArchivableWalletIDs", "transaction", err)
 	}
 	defer rows.Close()
-	transactions := make([]*Transaction, 0)
+	ids := make([]int64, 0)
 	for rows.Next() {
-		t := &Transaction{}
-		err := rows.Scan(&t.ID, &t.WalletID, &t.Amount, &t.TransactionType, &t.Description, &t.DiscountCode, &t.CreatedAt)
-		if err != nil {
-			return nil, serr.DBError("GetByDiscountCodeWithPagination", "transaction", err)
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			return nil, serr.DBError("GetArchivableWalletIDs", "transaction", err)
 		}
-		transactions = append(transactions, t)
-	}
-	return transactions, nil
-}
-
-// delete all transactions of a wallet
-func (s Storage) DeleteByWalletID(walletID int64) error {
-	sqlStmt := "DELETE FROM transaction WHERE wallet_id = $1"
-	_, err := s.db.Exec(sqlStmt, walletID)
-	if err != nil {
-		return serr.DBError("DeleteByWalletID", "transaction", err)
-	}
-	return nil
-}
-
-// delete all transactions of a wallet with a specific type
-func (s Storage) DeleteByWalletIDAndType(walletID int64, transactionType Type) error {
-	sqlStmt := "DELETE FROM transaction WHERE wallet_id = $1 AND transaction_type = $2"
-	_, err := s.db.Exec(sqlStmt, walletID, transactionType)
-	if err != nil {
-		return serr.DBError("DeleteByWalletIDAndType", "transaction", err)
+		ids = append(ids, id)
 	}
-	return nil
+	return ids, nil
 }
 
-// delete all transactions of a wallet with a specific discount code
-func (s Storage) DeleteByWalletIDAndDiscountCode(walletID int64, discountCode string) error {
-	sqlStmt := "DELETE FROM transaction WHERE wallet_id = $1 AND discount_code = $2"
-	_, err := s.db.Exec(sqlStmt, walletID, discountCode)
+// Archive moves the transactions of a wallet into transaction_archive and returns how many it moved.
+// Transactions are immutable but for this move, so it has to run on a db transaction.
+func (s Storage) Archive(ctx context.Context, walletID int64) (int64, error) {
+	if !db.InTransaction(ctx) {
+		return 0, db.ErrNotInTX
+	}
+	// lets the immutability trigger through until the db transaction ends
+	if _, err := s.db.ExecContext(ctx, "SELECT set_config('wallet.archiving', 'on', true)"); err != nil {
+		return 0, serr.DBError("Archive", "transaction", err)
+	}
+	sqlStmt := `
+		WITH moved AS (DELETE FROM transaction WHERE wallet_id = $1 RETURNING ` + transactionColumns + `)
+		INSERT INTO transaction_archive (` + transactionColumns + `) SELECT * FROM moved`
+	res, err := s.db.ExecContext(ctx, sqlStmt, walletID)
 	if err != nil {
-		return serr.DBError("DeleteByWalletIDAndDiscountCode", "transaction", err)
+		return 0, serr.DBError("Archive", "transaction", err)
 	}
-	return nil
-}
-
-// delete a transaction with transaction id
-func (s Storage) DeleteByID(id int64) error {
-	sqlStmt := "DELETE FROM transaction WHERE id = $1"
-	_, err := s.db.Exec(sqlStmt, id)
+	n, err := res.RowsAffected()
 	if err != nil {
-		return serr.DBError("DeleteByID", "transaction", err)
+		return 0, serr.DBError("Archive", "transaction", err)
 	}
-	return nil
-}
-
-// calculate amount of a wallet
-func (s Storage) GetBalance(walletID int64) (int64, error) {
-	sqlStmt := "SELECT sum(amount) FROM transaction WHERE wallet_id = $1"
-	var balance int64
-	err := s.db.QueryRow(sqlStmt, walletID).Scan(&balance)
-	if err != nil {
-		return 0, serr.DBError("GetBalance", "transaction", err)
-	}
-	return balance, nil
+	return n, nil
 }
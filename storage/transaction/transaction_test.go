@@ -1,13 +1,19 @@
 package transaction_test
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"wallet/db/dbtest"
 	repomocks "wallet/mocks/repomocks/transaction"
 	"wallet/storage/transaction"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCreate(t *testing.T) {
@@ -19,8 +25,8 @@ func TestCreate(t *testing.T) {
 		}
 		mockRepo := repomocks.NewRepository(t)
 		// Change "Create" to "Insert" here
-		mockRepo.On("Insert", fakeTr).Return(nil)
-		err := mockRepo.Insert(fakeTr)
+		mockRepo.On("Insert", mock.Anything, fakeTr).Return(nil)
+		err := mockRepo.Insert(context.Background(), fakeTr)
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
 	})
@@ -33,96 +39,52 @@ func TestCreate(t *testing.T) {
 		}
 		mockRepo := repomocks.NewRepository(t)
 		// Change "Create" to "Insert" here
-		mockRepo.On("Insert", fakeTr).Return(errors.New("forced error"))
-		err := mockRepo.Insert(fakeTr)
+		mockRepo.On("Insert", mock.Anything, fakeTr).Return(errors.New("forced error"))
+		err := mockRepo.Insert(context.Background(), fakeTr)
 		assert.Error(t, err)
 		assert.Equal(t, "forced error", err.Error())
 		mockRepo.AssertExpectations(t)
 	})
 }
 
-func TestDeleteByID(t *testing.T) {
+func TestGetArchivableWalletIDs(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
-		fakeTrID := int64(1)
+		before := time.Now()
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("DeleteByID", fakeTrID).Return(nil)
-		err := mockRepo.DeleteByID(fakeTrID)
+		mockRepo.On("GetArchivableWalletIDs", mock.Anything, before, 100).Return([]int64{1, 2}, nil)
+		ids, err := mockRepo.GetArchivableWalletIDs(context.Background(), before, 100)
 		assert.NoError(t, err)
+		assert.Equal(t, []int64{1, 2}, ids)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("error", func(t *testing.T) {
-		fakeTrID := int64(1)
+		before := time.Now()
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("DeleteByID", fakeTrID).Return(errors.New("forced error"))
-		err := mockRepo.DeleteByID(fakeTrID)
+		mockRepo.On("GetArchivableWalletIDs", mock.Anything, before, 100).Return(nil, errors.New("forced error"))
+		ids, err := mockRepo.GetArchivableWalletIDs(context.Background(), before, 100)
 		assert.Error(t, err)
-		assert.Equal(t, "forced error", err.Error())
+		assert.Nil(t, ids)
 		mockRepo.AssertExpectations(t)
 	})
 }
 
-func TestDeleteByWalletID(t *testing.T) {
+func TestArchive(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		fakeWalletID := int64(1)
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("DeleteByWalletID", fakeWalletID).Return(nil)
-		err := mockRepo.DeleteByWalletID(fakeWalletID)
+		mockRepo.On("Archive", mock.Anything, fakeWalletID).Return(int64(3), nil)
+		n, err := mockRepo.Archive(context.Background(), fakeWalletID)
 		assert.NoError(t, err)
+		assert.Equal(t, int64(3), n)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("error", func(t *testing.T) {
 		fakeWalletID := int64(1)
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("DeleteByWalletID", fakeWalletID).Return(errors.New("forced error"))
-		err := mockRepo.DeleteByWalletID(fakeWalletID)
-		assert.Error(t, err)
-		assert.Equal(t, "forced error", err.Error())
-		mockRepo.AssertExpectations(t)
-	})
-}
-
-func TestDeleteByWalletIDAndDiscountCode(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
-		fakeWalletID := int64(1)
-		fakeDiscountCode := "TEST"
-		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("DeleteByWalletIDAndDiscountCode", fakeWalletID, fakeDiscountCode).Return(nil)
-		err := mockRepo.DeleteByWalletIDAndDiscountCode(fakeWalletID, fakeDiscountCode)
-		assert.NoError(t, err)
-		mockRepo.AssertExpectations(t)
-	})
-
-	t.Run("error", func(t *testing.T) {
-		fakeWalletID := int64(1)
-		fakeDiscountCode := "TEST"
-		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("DeleteByWalletIDAndDiscountCode", fakeWalletID, fakeDiscountCode).Return(errors.New("forced error"))
-		err := mockRepo.DeleteByWalletIDAndDiscountCode(fakeWalletID, fakeDiscountCode)
-		assert.Error(t, err)
-		assert.Equal(t, "forced error", err.Error())
-		mockRepo.AssertExpectations(t)
-	})
-}
-
-func TestDeleteByWalletIDAndType(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
-		fakeWalletID := int64(1)
-		fakeType := transaction.Recharge
-		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("DeleteByWalletIDAndType", fakeWalletID, fakeType).Return(nil)
-		err := mockRepo.DeleteByWalletIDAndType(fakeWalletID, fakeType)
-		assert.NoError(t, err)
-		mockRepo.AssertExpectations(t)
-	})
-
-	t.Run("error", func(t *testing.T) {
-		fakeWalletID := int64(1)
-		fakeType := transaction.Recharge
-		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("DeleteByWalletIDAndType", fakeWalletID, fakeType).Return(errors.New("forced error"))
-		err := mockRepo.DeleteByWalletIDAndType(fakeWalletID, fakeType)
+		mockRepo.On("Archive", mock.Anything, fakeWalletID).Return(int64(0), errors.New("forced error"))
+		_, err := mockRepo.Archive(context.Background(), fakeWalletID)
 		assert.Error(t, err)
 		assert.Equal(t, "forced error", err.Error())
 		mockRepo.AssertExpectations(t)
@@ -135,8 +97,8 @@ func TestGetBalance(t *testing.T) {
 		fakeWalletID := int64(1)
 		mockRepo := repomocks.NewRepository(t)
 		// Include mock balance (like 5000) along with nil
-		mockRepo.On("GetBalance", fakeWalletID).Return(int64(5000), nil)
-		balance, err := mockRepo.GetBalance(fakeWalletID)
+		mockRepo.On("GetBalance", mock.Anything, fakeWalletID).Return(int64(5000), nil)
+		balance, err := mockRepo.GetBalance(context.Background(), fakeWalletID)
 		assert.NoError(t, err)
 		assert.Equal(t, int64(5000), balance)
 		mockRepo.AssertExpectations(t)
@@ -146,8 +108,8 @@ func TestGetBalance(t *testing.T) {
 		fakeWalletID := int64(1)
 		mockRepo := repomocks.NewRepository(t)
 		// Include zero balance with error
-		mockRepo.On("GetBalance", fakeWalletID).Return(int64(0), errors.New("forced error"))
-		balance, err := mockRepo.GetBalance(fakeWalletID)
+		mockRepo.On("GetBalance", mock.Anything, fakeWalletID).Return(int64(0), errors.New("forced error"))
+		balance, err := mockRepo.GetBalance(context.Background(), fakeWalletID)
 		assert.Error(t, err)
 		assert.Equal(t, int64(0), balance)
 		assert.Equal(t, "forced error", err.Error())
@@ -155,194 +117,171 @@ func TestGetBalance(t *testing.T) {
 	})
 }
 
-func TestGetByDiscountCodeWithPagination(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
-		fakeDiscountCode := "TEST"
-		fakePage := 1
-		fakeLimit := 10
-		mockRepo := repomocks.NewRepository(t)
-		// Include mock balance (like 5000) along with nil
-		mockRepo.On("GetByDiscountCodeWithPagination", fakeDiscountCode, fakePage, fakeLimit).Return([]*transaction.Transaction{}, nil)
-		transactions, err := mockRepo.GetByDiscountCodeWithPagination(fakeDiscountCode, fakePage, fakeLimit)
-		assert.NoError(t, err)
-		assert.Equal(t, []*transaction.Transaction{}, transactions)
-		mockRepo.AssertExpectations(t)
-	})
+func TestGetRefundedAmount(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	ctx := context.Background()
+	s := transaction.NewStorage(psql)
+	walletID := dbtest.Wallet(t, psql, 0)
+	insert := func(amount int64, transactionType transaction.Type, parentID int64) *transaction.Transaction {
+		tr := &transaction.Transaction{WalletID: walletID, Amount: amount, Currency: "IRR",
+			TransactionType: transactionType, ParentID: parentID}
+		require.NoError(t, s.Insert(ctx, tr))
+		return tr
+	}
+	payment := insert(-500, transaction.Payment, 0)
+	other := insert(-200, transaction.Withdraw, 0)
 
-	t.Run("error", func(t *testing.T) {
-		fakeDiscountCode := "TEST"
-		fakePage := 1
-		fakeLimit := 10
-		mockRepo := repomocks.NewRepository(t)
-		// Include zero balance with error
-		mockRepo.On("GetByDiscountCodeWithPagination", fakeDiscountCode, fakePage, fakeLimit).Return([]*transaction.Transaction{}, errors.New("forced error"))
-		transactions, err := mockRepo.GetByDiscountCodeWithPagination(fakeDiscountCode, fakePage, fakeLimit)
-		assert.Error(t, err)
-		assert.Equal(t, []*transaction.Transaction{}, transactions)
-		assert.Equal(t, "forced error", err.Error())
-		mockRepo.AssertExpectations(t)
-	})
-}
+	amount, err := s.GetRefundedAmount(ctx, payment.ID)
+	require.NoError(t, err)
+	assert.Zero(t, amount)
 
-func TestGetByID(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
-		fakeTrID := int64(1)
-		mockRepo := repomocks.NewRepository(t)
-		// Include mock balance (like 5000) along with nil
-		mockRepo.On("GetByID", fakeTrID).Return(&transaction.Transaction{}, nil)
-		tr, err := mockRepo.GetByID(fakeTrID)
-		assert.NoError(t, err)
-		assert.Equal(t, &transaction.Transaction{}, tr)
-		mockRepo.AssertExpectations(t)
-	})
+	insert(100, transaction.Refund, payment.ID)
+	insert(150, transaction.Refund, payment.ID)
+	insert(200, transaction.Refund, other.ID)
 
-	t.Run("error", func(t *testing.T) {
-		fakeTrID := int64(1)
-		mockRepo := repomocks.NewRepository(t)
-		// Include zero balance with error
-		mockRepo.On("GetByID", fakeTrID).Return(&transaction.Transaction{}, errors.New("forced error"))
-		tr, err := mockRepo.GetByID(fakeTrID)
-		assert.Error(t, err)
-		assert.Equal(t, &transaction.Transaction{}, tr)
-		assert.Equal(t, "forced error", err.Error())
-		mockRepo.AssertExpectations(t)
-	})
+	amount, err = s.GetRefundedAmount(ctx, payment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(250), amount)
+	amount, err = s.GetRefundedAmount(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(200), amount)
 }
 
-func TestGetByWalletID(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
-		fakeWalletID := int64(1)
-		mockRepo := repomocks.NewRepository(t)
-		// Include mock balance (like 5000) along with nil
-		mockRepo.On("GetByWalletID", fakeWalletID).Return([]*transaction.Transaction{}, nil)
-		tr, err := mockRepo.GetByWalletID(fakeWalletID)
-		assert.NoError(t, err)
-		assert.Equal(t, []*transaction.Transaction{}, tr)
-		mockRepo.AssertExpectations(t)
-	})
+func TestGetDebitTotals(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	ctx := context.Background()
+	s := transaction.NewStorage(psql)
+	walletID := dbtest.Wallet(t, psql, 0)
+	now := time.Now()
+	dayStart, monthStart := now.Add(-time.Hour), now.Add(-48*time.Hour)
+	insert := func(amount int64, transactionType transaction.Type, createdAt time.Time) {
+		_, err := psql.Exec(`INSERT INTO transaction (wallet_id, amount, currency, transaction_type, description,
+			discount_code, created_at) VALUES ($1, $2, 'IRR', $3, '', '', $4)`, walletID, amount, transactionType, createdAt)
+		require.NoError(t, err)
+	}
+	insert(-100, transaction.Withdraw, now)
+	insert(-200, transaction.Transfer, now)
+	insert(-300, transaction.Payment, now.Add(-24*time.Hour))
+	insert(-400, transaction.Withdraw, now.Add(-72*time.Hour))
+	// credits and corrections are no debits
+	insert(1000, transaction.Recharge, now)
+	insert(50, transaction.Refund, now)
+	insert(-500, transaction.Correction, now)
 
-	t.Run("error", func(t *testing.T) {
-		fakeWalletID := int64(1)
-		mockRepo := repomocks.NewRepository(t)
-		// Include zero balance with error
-		mockRepo.On("GetByWalletID", fakeWalletID).Return([]*transaction.Transaction{}, errors.New("forced error"))
-		tr, err := mockRepo.GetByWalletID(fakeWalletID)
-		assert.Error(t, err)
-		assert.Equal(t, []*transaction.Transaction{}, tr)
-		assert.Equal(t, "forced error", err.Error())
-		mockRepo.AssertExpectations(t)
-	})
+	totals, err := s.GetDebitTotals(ctx, walletID, dayStart, monthStart)
+	require.NoError(t, err)
+	assert.Equal(t, int64(300), totals.Day)
+	assert.Equal(t, int64(600), totals.Month)
+	assert.Equal(t, 1, totals.TransfersToday)
 }
 
-func TestGetByWalletIDAndDiscountCode(t *testing.T) {
+func TestGetBalanceBefore(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		fakeWalletID := int64(1)
-		fakeDiscountCode := "TEST"
+		before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 		mockRepo := repomocks.NewRepository(t)
-		// Include mock balance (like 5000) along with nil
-		mockRepo.On("GetByWalletIDAndDiscountCode", fakeWalletID, fakeDiscountCode).Return([]*transaction.Transaction{}, nil)
-		tr, err := mockRepo.GetByWalletIDAndDiscountCode(fakeWalletID, fakeDiscountCode)
+		mockRepo.On("GetBalanceBefore", mock.Anything, fakeWalletID, before).Return(int64(5000), nil)
+		balance, err := mockRepo.GetBalanceBefore(context.Background(), fakeWalletID, before)
 		assert.NoError(t, err)
-		assert.Equal(t, []*transaction.Transaction{}, tr)
+		assert.Equal(t, int64(5000), balance)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("error", func(t *testing.T) {
 		fakeWalletID := int64(1)
-		fakeDiscountCode := "TEST"
+		before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 		mockRepo := repomocks.NewRepository(t)
-		// Include zero balance with error
-		mockRepo.On("GetByWalletIDAndDiscountCode", fakeWalletID, fakeDiscountCode).Return([]*transaction.Transaction{}, errors.New("forced error"))
-		tr, err := mockRepo.GetByWalletIDAndDiscountCode(fakeWalletID, fakeDiscountCode)
+		mockRepo.On("GetBalanceBefore", mock.Anything, fakeWalletID, before).Return(int64(0), errors.New("forced error"))
+		balance, err := mockRepo.GetBalanceBefore(context.Background(), fakeWalletID, before)
 		assert.Error(t, err)
-		assert.Equal(t, []*transaction.Transaction{}, tr)
-		assert.Equal(t, "forced error", err.Error())
+		assert.Equal(t, int64(0), balance)
 		mockRepo.AssertExpectations(t)
 	})
 }
 
-func TestGetByWalletIDAndType(t *testing.T) {
+func TestGetByID(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
-		fakeWalletID := int64(1)
-		fakeType := transaction.Recharge
+		fakeTrID := int64(1)
 		mockRepo := repomocks.NewRepository(t)
 		// Include mock balance (like 5000) along with nil
-		mockRepo.On("GetByWalletIDAndType", fakeWalletID, fakeType).Return([]*transaction.Transaction{}, nil)
-		tr, err := mockRepo.GetByWalletIDAndType(fakeWalletID, fakeType)
+		mockRepo.On("GetByID", mock.Anything, fakeTrID).Return(&transaction.Transaction{}, nil)
+		tr, err := mockRepo.GetByID(context.Background(), fakeTrID)
 		assert.NoError(t, err)
-		assert.Equal(t, []*transaction.Transaction{}, tr)
+		assert.Equal(t, &transaction.Transaction{}, tr)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("error", func(t *testing.T) {
-		fakeWalletID := int64(1)
-		fakeType := transaction.Recharge
+		fakeTrID := int64(1)
 		mockRepo := repomocks.NewRepository(t)
 		// Include zero balance with error
-		mockRepo.On("GetByWalletIDAndType", fakeWalletID, fakeType).Return([]*transaction.Transaction{}, errors.New("forced error"))
-		tr, err := mockRepo.GetByWalletIDAndType(fakeWalletID, fakeType)
+		mockRepo.On("GetByID", mock.Anything, fakeTrID).Return(&transaction.Transaction{}, errors.New("forced error"))
+		tr, err := mockRepo.GetByID(context.Background(), fakeTrID)
 		assert.Error(t, err)
-		assert.Equal(t, []*transaction.Transaction{}, tr)
+		assert.Equal(t, &transaction.Transaction{}, tr)
 		assert.Equal(t, "forced error", err.Error())
 		mockRepo.AssertExpectations(t)
 	})
 }
 
-func TestGetByWalletIDAndTypeAndDiscountCode(t *testing.T) {
+func TestFind(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
-		fakeWalletID := int64(1)
-		fakeType := transaction.Recharge
-		fakeDiscountCode := "TEST"
+		fakeFilter := &transaction.Filter{WalletID: 1, Types: []transaction.Type{transaction.Gift}, DiscountCode: "TEST"}
+		fakeTransactions := []*transaction.Transaction{{ID: 1, WalletID: 1, DiscountCode: "TEST"}}
 		mockRepo := repomocks.NewRepository(t)
-		// Include mock balance (like 5000) along with nil
-		mockRepo.On("GetByWalletIDAndTypeAndDiscountCode", fakeWalletID, fakeType, fakeDiscountCode).Return([]*transaction.Transaction{}, nil)
-		tr, err := mockRepo.GetByWalletIDAndTypeAndDiscountCode(fakeWalletID, fakeType, fakeDiscountCode)
+		mockRepo.On("Find", mock.Anything, fakeFilter).Return(fakeTransactions, nil)
+		transactions, err := mockRepo.Find(context.Background(), fakeFilter)
 		assert.NoError(t, err)
-		assert.Equal(t, []*transaction.Transaction{}, tr)
+		assert.Equal(t, fakeTransactions, transactions)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("error", func(t *testing.T) {
-		fakeWalletID := int64(1)
-		fakeType := transaction.Recharge
-		fakeDiscountCode := "TEST"
+		fakeFilter := &transaction.Filter{WalletID: 1}
 		mockRepo := repomocks.NewRepository(t)
-		// Include zero balance with error
-		mockRepo.On("GetByWalletIDAndTypeAndDiscountCode", fakeWalletID, fakeType, fakeDiscountCode).Return([]*transaction.Transaction{}, errors.New("forced error"))
-		tr, err := mockRepo.GetByWalletIDAndTypeAndDiscountCode(fakeWalletID, fakeType, fakeDiscountCode)
+		mockRepo.On("Find", mock.Anything, fakeFilter).Return(nil, errors.New("forced error"))
+		transactions, err := mockRepo.Find(context.Background(), fakeFilter)
 		assert.Error(t, err)
-		assert.Equal(t, []*transaction.Transaction{}, tr)
+		assert.Nil(t, transactions)
 		assert.Equal(t, "forced error", err.Error())
 		mockRepo.AssertExpectations(t)
 	})
 }
 
-func TestGetByWalletIDWithPagination(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
-		fakeWalletID := int64(1)
-		fakePage := 1
-		fakeLimit := 10
-		mockRepo := repomocks.NewRepository(t)
-		// Include mock balance (like 5000) along with nil
-		mockRepo.On("GetByWalletIDWithPagination", fakeWalletID, fakePage, fakeLimit).Return([]*transaction.Transaction{}, nil)
-		tr, err := mockRepo.GetByWalletIDWithPagination(fakeWalletID, fakePage, fakeLimit)
-		assert.NoError(t, err)
-		assert.Equal(t, []*transaction.Transaction{}, tr)
-		mockRepo.AssertExpectations(t)
+func TestFilterQuery(t *testing.T) {
+	t.Run("no filter", func(t *testing.T) {
+		sqlStmt, args := (&transaction.Filter{}).Query()
+		assert.True(t, strings.HasSuffix(sqlStmt, " FROM transaction ORDER BY created_at DESC, id DESC"))
+		assert.Empty(t, args)
 	})
 
-	t.Run("error", func(t *testing.T) {
-		fakeWalletID := int64(1)
-		fakePage := 1
-		fakeLimit := 10
-		mockRepo := repomocks.NewRepository(t)
-		// Include zero balance with error
-		mockRepo.On("GetByWalletIDWithPagination", fakeWalletID, fakePage, fakeLimit).Return([]*transaction.Transaction{}, errors.New("forced error"))
-		tr, err := mockRepo.GetByWalletIDWithPagination(fakeWalletID, fakePage, fakeLimit)
-		assert.Error(t, err)
-		assert.Equal(t, []*transaction.Transaction{}, tr)
-		assert.Equal(t, "forced error", err.Error())
-		mockRepo.AssertExpectations(t)
+	t.Run("all filters", func(t *testing.T) {
+		from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 1, 0)
+		minAmount, maxAmount := int64(-500), int64(500)
+		f := &transaction.Filter{
+			WalletID:     1,
+			Types:        []transaction.Type{transaction.Recharge, transaction.Gift},
+			DiscountCode: "TEST",
+			CreatedFrom:  from,
+			CreatedTo:    to,
+			MinAmount:    &minAmount,
+			MaxAmount:    &maxAmount,
+			After:        &transaction.Cursor{CreatedAt: to, ID: 42},
+			Limit:        21,
+		}
+		sqlStmt, args := f.Query()
+		assert.True(t, strings.HasSuffix(sqlStmt, " FROM transaction WHERE wallet_id = $1 AND transaction_type IN ($2,$3)"+
+			" AND discount_code = $4 AND created_at >= $5 AND created_at < $6 AND amount >= $7 AND amount <= $8"+
+			" AND (created_at, id) < ($9, $10) ORDER BY created_at DESC, id DESC LIMIT $11"))
+		assert.Equal(t, []any{int64(1), transaction.Recharge, transaction.Gift, "TEST", from, to, minAmount, maxAmount,
+			to, int64(42), 21}, args)
+	})
+
+	t.Run("offset", func(t *testing.T) {
+		sqlStmt, args := (&transaction.Filter{DiscountCode: "TEST", Limit: 10, Offset: 20}).Query()
+		assert.True(t, strings.HasSuffix(sqlStmt, " WHERE discount_code = $1 ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3"))
+		assert.Equal(t, []any{"TEST", 10, 20}, args)
 	})
 }
 
@@ -355,8 +294,8 @@ func TestWithTX(t *testing.T) {
 		}
 		mockRepo := repomocks.NewRepository(t)
 		// Change "Create" to "Insert" here
-		mockRepo.On("Insert", fakeTx).Return(nil)
-		err := mockRepo.Insert(fakeTx)
+		mockRepo.On("Insert", mock.Anything, fakeTx).Return(nil)
+		err := mockRepo.Insert(context.Background(), fakeTx)
 		assert.NoError(t, err)
 		mockRepo.AssertExpectations(t)
 	})
@@ -369,8 +308,8 @@ func TestWithTX(t *testing.T) {
 		}
 		mockRepo := repomocks.NewRepository(t)
 		// Change "Create" to "Insert" here
-		mockRepo.On("Insert", fakeTx).Return(errors.New("forced error"))
-		err := mockRepo.Insert(fakeTx)
+		mockRepo.On("Insert", mock.Anything, fakeTx).Return(errors.New("forced error"))
+		err := mockRepo.Insert(context.Background(), fakeTx)
 		assert.Error(t, err)
 		assert.Equal(t, "forced error", err.Error())
 		mockRepo.AssertExpectations(t)
@@ -2,11 +2,33 @@ package wallet
 
 import "time"
 
+type Status string
+
+const (
+	Active Status = "active"
+	Frozen Status = "frozen"
+	Closed Status = "closed"
+)
+
 type Wallet struct {
+	ID          int64     `db:"id"`
+	MemberID    int64     `db:"member_id"`
+	WalletName  string    `db:"wallet_name"`
+	Balance     int64     `db:"balance"`
+	HeldBalance int64     `db:"held_balance"`
+	Currency    string    `db:"currency"`
+	Status      Status    `db:"status"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// StatusChange is a move of a wallet from one status to another, Actor is who made it.
+type StatusChange struct {
 	ID         int64     `db:"id"`
-	MemberID   int64     `db:"member_id"`
-	WalletName string    `db:"wallet_name"`
-	Balance    int64     `db:"balance"`
+	WalletID   int64     `db:"wallet_id"`
+	FromStatus Status    `db:"from_status"`
+	ToStatus   Status    `db:"to_status"`
+	Reason     string    `db:"reason"`
+	Actor      string    `db:"actor"`
 	CreatedAt  time.Time `db:"created_at"`
-	UpdatedAt  time.Time `db:"updated_at"`
 }
@@ -1,42 +1,55 @@
 package wallet
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"wallet/db"
 )
 
 var (
-	ErrNoRowToUpdate = errors.New("no row to update")
+	ErrNoRowToUpdate   = errors.New("no row to update")
+	ErrNegativeBalance = errors.New("balance can not be negative")
 )
 
 type Repository interface {
-	Create(w *Wallet) error
-	UpdateBalance(id int64, balance int64) error
-	GetByID(id int64) (*Wallet, error)
-	GetByMemberID(memberID int64) ([]*Wallet, error)
-	Delete(id int64) error
-	DeleteByMemberID(memberID int64) error
-	WithTX(tx *sql.Tx) (Repository, error)
+	Create(ctx context.Context, w *Wallet) error
+	AdjustBalance(ctx context.Context, id int64, delta int64) (int64, error)
+	AdjustHeld(ctx context.Context, id int64, delta int64) (int64, error)
+	GetByID(ctx context.Context, id int64) (*Wallet, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*Wallet, error)
+	GetByMemberID(ctx context.Context, memberID int64) ([]*Wallet, error)
+	GetAllByPage(ctx context.Context, limit, offset int) ([]*Wallet, error)
+	Delete(ctx context.Context, id int64) error
+	DeleteByMemberID(ctx context.Context, memberID int64) error
+	SetStatus(ctx context.Context, id int64, status Status) error
+	CreateStatusChange(ctx context.Context, c *StatusChange) error
+	GetStatusChanges(ctx context.Context, walletID int64) ([]*StatusChange, error)
 }
 
 type Storage struct {
 	db db.SQLExt
 }
 
-func NewStorage(db *sql.DB) Storage {
-	return Storage{db: db}
+func NewStorage(psql *sql.DB) Storage {
+	return Storage{db: db.NewConn(psql)}
 }
 
-func (s Storage) WithTX(tx *sql.Tx) (Repository, error) {
-	if tx == nil {
-		return nil, db.ErrNoTXProvided
+func (s Storage) ScanWallet(scanner db.Scanner) (*Wallet, error) {
+	w := &Wallet{}
+	err := scanner.Scan(&w.ID, &w.MemberID, &w.WalletName, &w.Balance, &w.HeldBalance, &w.Currency, &w.Status,
+		&w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return nil, err
 	}
-	switch s.db.(type) {
-	case *sql.Tx:
-		return nil, db.ErrAlreadyInTX
-	case *sql.DB:
-		return Storage{db: tx}, nil
+	return w, nil
+}
+
+func (s Storage) ScanStatusChange(scanner db.Scanner) (*StatusChange, error) {
+	c := &StatusChange{}
+	err := scanner.Scan(&c.ID, &c.WalletID, &c.FromStatus, &c.ToStatus, &c.Reason, &c.Actor, &c.CreatedAt)
+	if err != nil {
+		return nil, err
 	}
-	return s, nil
+	return c, nil
 }
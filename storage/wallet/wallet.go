@@ -1,55 +1,96 @@
 package wallet
 
-const walletColumns = "id" + ",member_id,wallet_name,balance,created_at,updated_at"
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"wallet/internal/serr"
+)
 
-func (s Storage) Create(w *Wallet) error {
+const (
+	walletColumns       = "id" + ",member_id,wallet_name,balance,held_balance,currency,status,created_at,updated_at"
+	statusChangeColumns = "id,wallet_id,from_status,to_status,reason,actor,created_at"
+)
+
+func (s Storage) Create(ctx context.Context, w *Wallet) error {
 	sqlStmt := `
-	INSERT INTO wallet (member_id, wallet_name, balance) VALUES ($1, $2, $3) 
-	                     RETURNING id, wallet_name, created_at, updated_at`
-	err := s.db.QueryRow(sqlStmt, w.MemberID, w.WalletName, w.Balance).Scan(&w.ID, &w.WalletName,
-		&w.CreatedAt, &w.UpdatedAt)
+	INSERT INTO wallet (member_id, wallet_name, balance, currency) VALUES ($1, $2, $3, $4) 
+	                     RETURNING id, wallet_name, status, created_at, updated_at`
+	err := s.db.QueryRowContext(ctx, sqlStmt, w.MemberID, w.WalletName, w.Balance, w.Currency).Scan(&w.ID, &w.WalletName,
+		&w.Status, &w.CreatedAt, &w.UpdatedAt)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s Storage) UpdateBalance(id int64, balance int64) error {
+// AdjustBalance atomically adds delta to the wallet balance and returns the new balance.
+// It returns ErrNegativeBalance if the wallet does not exist or the balance would drop below zero
+// or below the held balance.
+func (s Storage) AdjustBalance(ctx context.Context, id int64, delta int64) (int64, error) {
 	sqlStmt := `
-	UPDATE wallet SET balance = $1, updated_at = now() WHERE id = $2
-	                     RETURNING updated_at`
-	row, err := s.db.Exec(sqlStmt, balance, id)
+	UPDATE wallet SET balance = balance + $1, updated_at = now() WHERE id = $2 AND balance + $1 >= held_balance
+	                     RETURNING balance`
+	var balance int64
+	err := s.db.QueryRowContext(ctx, sqlStmt, delta, id).Scan(&balance)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrNegativeBalance
+	}
 	if err != nil {
-		return err
+		return 0, serr.DBError("AdjustBalance", "wallet", err)
 	}
-	if count, err := row.RowsAffected(); err != nil || count == 0 {
-		return ErrNoRowToUpdate
+	return balance, nil
+}
+
+// AdjustHeld atomically adds delta to the held balance and returns the new held balance.
+// It returns ErrNegativeBalance if the wallet does not exist or the held balance would leave the
+// range between zero and the balance.
+func (s Storage) AdjustHeld(ctx context.Context, id int64, delta int64) (int64, error) {
+	sqlStmt := `
+	UPDATE wallet SET held_balance = held_balance + $1, updated_at = now()
+	              WHERE id = $2 AND held_balance + $1 >= 0 AND held_balance + $1 <= balance
+	                     RETURNING held_balance`
+	var held int64
+	err := s.db.QueryRowContext(ctx, sqlStmt, delta, id).Scan(&held)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrNegativeBalance
 	}
-	return nil
+	if err != nil {
+		return 0, serr.DBError("AdjustHeld", "wallet", err)
+	}
+	return held, nil
 }
 
-func (s Storage) GetByID(id int64) (*Wallet, error) {
-	sqlStmt := "SELECT " + walletColumns + " FROM wallet WHERE id = $1"
-	w := &Wallet{}
-	err := s.db.QueryRow(sqlStmt, id).Scan(&w.ID, &w.MemberID, &w.WalletName, &w.Balance, &w.CreatedAt, &w.UpdatedAt)
+func (s Storage) GetByID(ctx context.Context, id int64) (*Wallet, error) {
+	sqlStmt := "SELECT " + walletColumns + " FROM wallet WHERE id = $1 AND deleted_at IS NULL"
+	w, err := s.ScanWallet(s.db.QueryRowContext(ctx, sqlStmt, id))
 	if err != nil {
-		return nil, err
+		return nil, serr.DBError("GetByID", "wallet", err)
 	}
 	return w, nil
 }
 
-func (s Storage) GetByMemberID(memberID int64) ([]*Wallet, error) {
+// GetByIDForUpdate locks the wallet row until the surrounding db transaction ends.
+func (s Storage) GetByIDForUpdate(ctx context.Context, id int64) (*Wallet, error) {
+	sqlStmt := "SELECT " + walletColumns + " FROM wallet WHERE id = $1 AND deleted_at IS NULL FOR UPDATE"
+	w, err := s.ScanWallet(s.db.QueryRowContext(ctx, sqlStmt, id))
+	if err != nil {
+		return nil, serr.DBError("GetByIDForUpdate", "wallet", err)
+	}
+	return w, nil
+}
+
+func (s Storage) GetByMemberID(ctx context.Context, memberID int64) ([]*Wallet, error) {
 	// one member can have multi wallet
-	sqlStmt := "SELECT " + walletColumns + " FROM wallet WHERE member_id = $1"
-	rows, err := s.db.Query(sqlStmt, memberID)
+	sqlStmt := "SELECT " + walletColumns + " FROM wallet WHERE member_id = $1 AND deleted_at IS NULL"
+	rows, err := s.db.QueryContext(ctx, sqlStmt, memberID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	wallets := make([]*Wallet, 0)
 	for rows.Next() {
-		w := &Wallet{}
-		err := rows.Scan(&w.ID, &w.MemberID, &w.WalletName, &w.Balance, &w.CreatedAt, &w.UpdatedAt)
+		w, err := s.ScanWallet(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -58,9 +99,29 @@ func (s Storage) GetByMemberID(memberID int64) ([]*Wallet, error) {
 	return wallets, nil
 }
 
-func (s Storage) Delete(id int64) error {
-	sqlStmt := "DELETE FROM wallet WHERE id = $1"
-	row, err := s.db.Exec(sqlStmt, id)
+func (s Storage) GetAllByPage(ctx context.Context, limit, offset int) ([]*Wallet, error) {
+	sqlStmt := "SELECT " + walletColumns + " FROM wallet WHERE deleted_at IS NULL ORDER BY id LIMIT $1 OFFSET $2"
+	rows, err := s.db.QueryContext(ctx, sqlStmt, limit, offset)
+	if err != nil {
+		return nil, serr.DBError("GetAllByPage", "wallet", err)
+	}
+	defer rows.Close()
+	wallets := make([]*Wallet, 0)
+	for rows.Next() {
+		w, err := s.ScanWallet(rows)
+		if err != nil {
+			return nil, serr.DBError("GetAllByPage", "wallet", err)
+		}
+		wallets = append(wallets, w)
+	}
+	return wallets, nil
+}
+
+// Delete marks a wallet deleted, deleted wallets are left out of every query and their transactions are
+// kept until they are archived.
+func (s Storage) Delete(ctx context.Context, id int64) error {
+	sqlStmt := "UPDATE wallet SET deleted_at = now(), updated_at = now() WHERE id = $1 AND deleted_at IS NULL"
+	row, err := s.db.ExecContext(ctx, sqlStmt, id)
 	if err != nil {
 		return err
 	}
@@ -70,9 +131,10 @@ func (s Storage) Delete(id int64) error {
 	return nil
 }
 
-func (s Storage) DeleteByMemberID(memberID int64) error {
-	sqlStmt := "DELETE FROM wallet WHERE member_id = $1"
-	row, err := s.db.Exec(sqlStmt, memberID)
+// DeleteByMemberID marks the wallets of a member deleted.
+func (s Storage) DeleteByMemberID(ctx context.Context, memberID int64) error {
+	sqlStmt := "UPDATE wallet SET deleted_at = now(), updated_at = now() WHERE member_id = $1 AND deleted_at IS NULL"
+	row, err := s.db.ExecContext(ctx, sqlStmt, memberID)
 	if err != nil {
 		return err
 	}
@@ -81,3 +143,46 @@ func (s Storage) DeleteByMemberID(memberID int64) error {
 	}
 	return nil
 }
+
+func (s Storage) SetStatus(ctx context.Context, id int64, status Status) error {
+	sqlStmt := "UPDATE wallet SET status = $1, updated_at = now() WHERE id = $2 AND deleted_at IS NULL"
+	res, err := s.db.ExecContext(ctx, sqlStmt, status, id)
+	if err != nil {
+		return serr.DBError("SetStatus", "wallet", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return serr.DBError("SetStatus", "wallet", sql.ErrNoRows)
+	}
+	return nil
+}
+
+func (s Storage) CreateStatusChange(ctx context.Context, c *StatusChange) error {
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO wallet_status_change (wallet_id, from_status, to_status, reason, actor)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, c.WalletID, c.FromStatus, c.ToStatus, c.Reason, c.Actor).Scan(&c.ID, &c.CreatedAt)
+	if err != nil {
+		return serr.DBError("CreateStatusChange", "wallet status change", err)
+	}
+	return nil
+}
+
+// GetStatusChanges returns the status changes of a wallet, oldest first.
+func (s Storage) GetStatusChanges(ctx context.Context, walletID int64) ([]*StatusChange, error) {
+	sqlStmt := "SELECT " + statusChangeColumns + " FROM wallet_status_change WHERE wallet_id = $1 ORDER BY id"
+	rows, err := s.db.QueryContext(ctx, sqlStmt, walletID)
+	if err != nil {
+		return nil, serr.DBError("GetStatusChanges", "wallet status change", err)
+	}
+	defer rows.Close()
+	changes := make([]*StatusChange, 0)
+	for rows.Next() {
+		c, err := s.ScanStatusChange(rows)
+		if err != nil {
+			return nil, serr.DBError("GetStatusChanges", "wallet status change", err)
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
@@ -1,11 +1,20 @@
 package wallet_test
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"net/http"
 	"testing"
+	"time"
+	"wallet/db"
+	"wallet/db/dbtest"
+	"wallet/internal/serr"
 	repomocks "wallet/mocks/repomocks/wallet"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	wallet "wallet/storage/wallet"
 )
@@ -16,10 +25,10 @@ func TestCreate(t *testing.T) {
 		fakeWallet := &wallet.Wallet{ID: 1, Balance: 1000} // adjust this per your Wallet struct
 
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("Create", fakeWallet).Return(nil) // you expect Create function to return nil error, which indicates success
+		mockRepo.On("Create", mock.Anything, fakeWallet).Return(nil) // you expect Create function to return nil error, which indicates success
 
 		// Act
-		err := mockRepo.Create(fakeWallet)
+		err := mockRepo.Create(context.Background(), fakeWallet)
 
 		// Assert
 		assert.NoError(t, err)
@@ -31,10 +40,10 @@ func TestCreate(t *testing.T) {
 		fakeWallet := &wallet.Wallet{ID: 2, Balance: 2000} // adjust this per your Wallet struct
 
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("Create", fakeWallet).Return(errors.New("forced error")) // you expect Create function to return an error
+		mockRepo.On("Create", mock.Anything, fakeWallet).Return(errors.New("forced error")) // you expect Create function to return an error
 
 		// Act
-		err := mockRepo.Create(fakeWallet)
+		err := mockRepo.Create(context.Background(), fakeWallet)
 
 		// Assert
 		assert.Error(t, err)
@@ -49,10 +58,10 @@ func TestDelete(t *testing.T) {
 		fakeWalletID := int64(1) // adjust this per your Wallet struct
 
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("Delete", fakeWalletID).Return(nil) // you expect Create function to return nil error, which indicates success
+		mockRepo.On("Delete", mock.Anything, fakeWalletID).Return(nil) // you expect Create function to return nil error, which indicates success
 
 		// Act
-		err := mockRepo.Delete(fakeWalletID)
+		err := mockRepo.Delete(context.Background(), fakeWalletID)
 
 		// Assert
 		assert.NoError(t, err)
@@ -64,10 +73,10 @@ func TestDelete(t *testing.T) {
 		fakeWalletID := int64(2) // adjust this per your Wallet struct
 
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("Delete", fakeWalletID).Return(errors.New("forced error")) // you expect Create function to return an error
+		mockRepo.On("Delete", mock.Anything, fakeWalletID).Return(errors.New("forced error")) // you expect Create function to return an error
 
 		// Act
-		err := mockRepo.Delete(fakeWalletID)
+		err := mockRepo.Delete(context.Background(), fakeWalletID)
 
 		// Assert
 		assert.Error(t, err)
@@ -82,10 +91,10 @@ func TestDeleteByMemberID(t *testing.T) {
 		fakeMemberID := int64(1) // adjust this per your Wallet struct
 
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("DeleteByMemberID", fakeMemberID).Return(nil) // you expect Create function to return nil error, which indicates success
+		mockRepo.On("DeleteByMemberID", mock.Anything, fakeMemberID).Return(nil) // you expect Create function to return nil error, which indicates success
 
 		// Act
-		err := mockRepo.DeleteByMemberID(fakeMemberID)
+		err := mockRepo.DeleteByMemberID(context.Background(), fakeMemberID)
 
 		// Assert
 		assert.NoError(t, err)
@@ -97,10 +106,10 @@ func TestDeleteByMemberID(t *testing.T) {
 		fakeMemberID := int64(2) // adjust this per your Wallet struct
 
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("DeleteByMemberID", fakeMemberID).Return(errors.New("forced error")) // you expect Create function to return an error
+		mockRepo.On("DeleteByMemberID", mock.Anything, fakeMemberID).Return(errors.New("forced error")) // you expect Create function to return an error
 
 		// Act
-		err := mockRepo.DeleteByMemberID(fakeMemberID)
+		err := mockRepo.DeleteByMemberID(context.Background(), fakeMemberID)
 
 		// Assert
 		assert.Error(t, err)
@@ -116,10 +125,10 @@ func TestGetByID(t *testing.T) {
 		fakeWallet := &wallet.Wallet{ID: 1, Balance: 1000}
 
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("GetByID", fakeWalletID).Return(fakeWallet, nil) // you expect Create function to return nil error, which indicates success
+		mockRepo.On("GetByID", mock.Anything, fakeWalletID).Return(fakeWallet, nil) // you expect Create function to return nil error, which indicates success
 
 		// Act
-		result, err := mockRepo.GetByID(fakeWalletID)
+		result, err := mockRepo.GetByID(context.Background(), fakeWalletID)
 
 		// Assert
 		assert.NoError(t, err)
@@ -132,10 +141,10 @@ func TestGetByID(t *testing.T) {
 		fakeWalletID := int64(2) // adjust this per your Wallet struct
 
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("GetByID", fakeWalletID).Return(nil, errors.New("forced error")) // you expect Create function to return an error
+		mockRepo.On("GetByID", mock.Anything, fakeWalletID).Return(nil, errors.New("forced error")) // you expect Create function to return an error
 
 		// Act
-		result, err := mockRepo.GetByID(fakeWalletID)
+		result, err := mockRepo.GetByID(context.Background(), fakeWalletID)
 
 		// Assert
 		assert.Error(t, err)
@@ -152,10 +161,10 @@ func TestGetByMemberID(t *testing.T) {
 		fakeWallet := []*wallet.Wallet{{ID: 1, Balance: 1000}} // make this a slice
 
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("GetByMemberID", fakeMemberID).Return(fakeWallet, nil) // you expect Create function to return nil error, which indicates success
+		mockRepo.On("GetByMemberID", mock.Anything, fakeMemberID).Return(fakeWallet, nil) // you expect Create function to return nil error, which indicates success
 
 		// Act
-		result, err := mockRepo.GetByMemberID(fakeMemberID)
+		result, err := mockRepo.GetByMemberID(context.Background(), fakeMemberID)
 
 		// Assert
 		assert.NoError(t, err)
@@ -168,10 +177,10 @@ func TestGetByMemberID(t *testing.T) {
 		fakeMemberID := int64(2) // adjust this per your Wallet struct
 
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("GetByMemberID", fakeMemberID).Return(nil, errors.New("forced error")) // you expect Create function to return an error
+		mockRepo.On("GetByMemberID", mock.Anything, fakeMemberID).Return(nil, errors.New("forced error")) // you expect Create function to return an error
 
 		// Act
-		result, err := mockRepo.GetByMemberID(fakeMemberID)
+		result, err := mockRepo.GetByMemberID(context.Background(), fakeMemberID)
 
 		// Assert
 		assert.Error(t, err)
@@ -181,37 +190,172 @@ func TestGetByMemberID(t *testing.T) {
 	})
 }
 
-func TestUpdateBalance(t *testing.T) {
+func TestAdjustHeld(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := wallet.NewStorage(psql)
+	ctx := context.Background()
+	id := dbtest.Wallet(t, psql, 1000)
+
+	held, err := s.AdjustHeld(ctx, id, 300)
+	require.NoError(t, err)
+	assert.Equal(t, int64(300), held)
+
+	// the held balance stays between zero and the balance
+	_, err = s.AdjustHeld(ctx, id, 701)
+	assert.ErrorIs(t, err, wallet.ErrNegativeBalance)
+	_, err = s.AdjustHeld(ctx, id, -301)
+	assert.ErrorIs(t, err, wallet.ErrNegativeBalance)
+
+	// and the held part of the balance can not be debited
+	_, err = s.AdjustBalance(ctx, id, -701)
+	assert.ErrorIs(t, err, wallet.ErrNegativeBalance)
+	balance, err := s.AdjustBalance(ctx, id, -700)
+	require.NoError(t, err)
+	assert.Equal(t, int64(300), balance)
+
+	held, err = s.AdjustHeld(ctx, id, -300)
+	require.NoError(t, err)
+	assert.Zero(t, held)
+	w, err := s.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(300), w.Balance)
+	assert.Zero(t, w.HeldBalance)
+}
+
+func TestAdjustBalance(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := wallet.NewStorage(psql)
+	ctx := context.Background()
+	id := dbtest.Wallet(t, psql, 1000)
+
+	balance, err := s.AdjustBalance(ctx, id, -400)
+	require.NoError(t, err)
+	assert.Equal(t, int64(600), balance)
+
+	// a debit below zero is turned down and leaves the balance alone
+	_, err = s.AdjustBalance(ctx, id, -601)
+	assert.ErrorIs(t, err, wallet.ErrNegativeBalance)
+	w, err := s.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(600), w.Balance)
+
+	_, err = s.AdjustBalance(ctx, 0, 100)
+	assert.ErrorIs(t, err, wallet.ErrNegativeBalance)
+}
+
+func TestGetByID_NotFound(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := wallet.NewStorage(psql)
+
+	// a missing wallet is reported as not found rather than failing
+	_, err := s.GetByID(context.Background(), -1)
+	var e *serr.ServiceError
+	require.True(t, errors.As(err, &e))
+	assert.Equal(t, http.StatusNotFound, e.Code)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestGetByIDForUpdate(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := wallet.NewStorage(psql)
+	id := dbtest.Wallet(t, psql, 1000)
+
+	adjusted := make(chan error, 1)
+	err := db.Transaction(context.Background(), func(ctx context.Context) error {
+		w, err := s.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, int64(1000), w.Balance)
+		go func() {
+			_, err := s.AdjustBalance(context.Background(), id, 1)
+			adjusted <- err
+		}()
+		select {
+		case <-adjusted:
+			t.Error("the balance changed while the wallet was locked")
+		case <-time.After(100 * time.Millisecond):
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, <-adjusted)
+}
+
+func TestGetAllByPage(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		// Prepare
-		fakeWalletID := int64(1) // adjust this per your Wallet struct
-		fakeBalance := int64(1000)
+		fakeWallets := []*wallet.Wallet{{ID: 1, Balance: 1000}, {ID: 2, Balance: 0}}
 
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("UpdateBalance", fakeWalletID, fakeBalance).Return(nil) // you expect Create function to return nil error, which indicates success
+		mockRepo.On("GetAllByPage", mock.Anything, 100, 0).Return(fakeWallets, nil)
 
 		// Act
-		err := mockRepo.UpdateBalance(fakeWalletID, fakeBalance)
+		result, err := mockRepo.GetAllByPage(context.Background(), 100, 0)
 
 		// Assert
 		assert.NoError(t, err)
-		mockRepo.AssertExpectations(t) // This checks if the function has been called with correct parameters
+		assert.Equal(t, fakeWallets, result)
+		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("error", func(t *testing.T) {
 		// Prepare
-		fakeWalletID := int64(2) // adjust this per your Wallet struct
-		fakeBalance := int64(2000)
+		mockRepo := repomocks.NewRepository(t)
+		mockRepo.On("GetAllByPage", mock.Anything, 100, 0).Return(nil, errors.New("forced error"))
+
+		// Act
+		result, err := mockRepo.GetAllByPage(context.Background(), 100, 0)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, "forced error", err.Error())
+		assert.Nil(t, result)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestSetStatus(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// Prepare
+		mockRepo := repomocks.NewRepository(t)
+		mockRepo.On("SetStatus", mock.Anything, int64(1), wallet.Frozen).Return(nil)
+
+		// Act
+		err := mockRepo.SetStatus(context.Background(), 1, wallet.Frozen)
+
+		// Assert
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
 
+	t.Run("error", func(t *testing.T) {
+		// Prepare
 		mockRepo := repomocks.NewRepository(t)
-		mockRepo.On("UpdateBalance", fakeWalletID, fakeBalance).Return(errors.New("forced error")) // you expect Create function to return an error
+		mockRepo.On("SetStatus", mock.Anything, int64(2), wallet.Closed).Return(errors.New("forced error"))
 
 		// Act
-		err := mockRepo.UpdateBalance(fakeWalletID, fakeBalance)
+		err := mockRepo.SetStatus(context.Background(), 2, wallet.Closed)
 
 		// Assert
 		assert.Error(t, err)
 		assert.Equal(t, "forced error", err.Error())
-		mockRepo.AssertExpectations(t) // This checks if the function has been called with correct parameters
+		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestGetStatusChanges(t *testing.T) {
+	// Prepare
+	changes := []*wallet.StatusChange{{ID: 1, WalletID: 1, FromStatus: wallet.Active, ToStatus: wallet.Frozen,
+		Reason: "card reported stolen", Actor: "admin:1"}}
+	mockRepo := repomocks.NewRepository(t)
+	mockRepo.On("GetStatusChanges", mock.Anything, int64(1)).Return(changes, nil)
+
+	// Act
+	result, err := mockRepo.GetStatusChanges(context.Background(), 1)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, changes, result)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,39 @@
+package webhook
+
+import "time"
+
+type DeliveryStatus string
+
+const (
+	Pending   DeliveryStatus = "pending"
+	Succeeded DeliveryStatus = "succeeded"
+	Failed    DeliveryStatus = "failed"
+)
+
+// Subscription registers a url to be called with events of the given types.
+type Subscription struct {
+	ID         int64     `db:"id"`
+	URL        string    `db:"url"`
+	EventTypes []string  `db:"event_types"`
+	Secret     string    `db:"secret"`
+	Active     bool      `db:"active"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+}
+
+// Delivery is an event sent, or to be sent, to a subscription with the outcome of its last attempt.
+type Delivery struct {
+	ID             int64          `db:"id"`
+	SubscriptionID int64          `db:"subscription_id"`
+	EventID        int64          `db:"event_id"`
+	EventType      string         `db:"event_type"`
+	Payload        []byte         `db:"payload"`
+	Status         DeliveryStatus `db:"status"`
+	Attempts       int            `db:"attempts"`
+	LastStatusCode int            `db:"last_status_code"`
+	LastError      string         `db:"last_error"`
+	NextAttemptAt  time.Time      `db:"next_attempt_at"`
+	DeliveredAt    *time.Time     `db:"delivered_at"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+}
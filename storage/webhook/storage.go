@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"wallet/db"
+
+	"github.com/lib/pq"
+)
+
+type Repository interface {
+	CreateSubscription(ctx context.Context, sub *Subscription) error
+	GetSubscription(ctx context.Context, id int64) (*Subscription, error)
+	GetSubscriptions(ctx context.Context, limit, offset int) ([]*Subscription, error)
+	GetSubscriptionsByEventType(ctx context.Context, eventType string) ([]*Subscription, error)
+	UpdateSubscription(ctx context.Context, sub *Subscription) error
+	DeleteSubscription(ctx context.Context, id int64) error
+	CreateDelivery(ctx context.Context, d *Delivery) error
+	GetDelivery(ctx context.Context, id int64) (*Delivery, error)
+	GetDeliveries(ctx context.Context, subscriptionID int64, limit, offset int) ([]*Delivery, error)
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*Delivery, error)
+	UpdateDelivery(ctx context.Context, d *Delivery) error
+}
+
+type Storage struct {
+	db db.SQLExt
+}
+
+func NewStorage(psql *sql.DB) Storage {
+	return Storage{db: db.NewConn(psql)}
+}
+
+func (s Storage) ScanSubscription(scanner db.Scanner) (*Subscription, error) {
+	sub := &Subscription{}
+	err := scanner.Scan(&sub.ID, &sub.URL, pq.Array(&sub.EventTypes), &sub.Secret, &sub.Active, &sub.CreatedAt,
+		&sub.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s Storage) ScanDelivery(scanner db.Scanner) (*Delivery, error) {
+	d := &Delivery{}
+	var statusCode sql.NullInt64
+	var lastError sql.NullString
+	var deliveredAt sql.NullTime
+	err := scanner.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&statusCode, &lastError, &d.NextAttemptAt, &deliveredAt, &d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	d.LastStatusCode = int(statusCode.Int64)
+	d.LastError = lastError.String
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	return d, nil
+}
@@ -0,0 +1,181 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+	"wallet/internal/serr"
+
+	"github.com/lib/pq"
+)
+
+const (
+	subscriptionColumns = "id,url,event_types,secret,active,created_at,updated_at"
+	deliveryColumns     = "id,subscription_id,event_id,event_type,payload,status,attempts,last_status_code," +
+		"last_error,next_attempt_at,delivered_at,created_at,updated_at"
+)
+
+func (s Storage) CreateSubscription(ctx context.Context, sub *Subscription) error {
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO webhook_subscription (url, event_types, secret, active)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`, sub.URL, pq.Array(sub.EventTypes), sub.Secret, sub.Active).Scan(&sub.ID, &sub.CreatedAt, &sub.UpdatedAt)
+	if err != nil {
+		return serr.DBError("CreateSubscription", "webhook", err)
+	}
+	return nil
+}
+
+func (s Storage) GetSubscription(ctx context.Context, id int64) (*Subscription, error) {
+	sqlStmt := "SELECT " + subscriptionColumns + " FROM webhook_subscription WHERE id = $1"
+	sub, err := s.ScanSubscription(s.db.QueryRowContext(ctx, sqlStmt, id))
+	if err != nil {
+		return nil, serr.DBError("GetSubscription", "webhook", err)
+	}
+	return sub, nil
+}
+
+func (s Storage) GetSubscriptions(ctx context.Context, limit, offset int) ([]*Subscription, error) {
+	sqlStmt := "SELECT " + subscriptionColumns + " FROM webhook_subscription ORDER BY id LIMIT $1 OFFSET $2"
+	return s.querySubscriptions(ctx, "GetSubscriptions", sqlStmt, limit, offset)
+}
+
+// GetSubscriptionsByEventType returns the active subscriptions to an event type.
+func (s Storage) GetSubscriptionsByEventType(ctx context.Context, eventType string) ([]*Subscription, error) {
+	sqlStmt := "SELECT " + subscriptionColumns + " FROM webhook_subscription WHERE active AND $1 = ANY(event_types) ORDER BY id"
+	return s.querySubscriptions(ctx, "GetSubscriptionsByEventType", sqlStmt, eventType)
+}
+
+func (s Storage) querySubscriptions(ctx context.Context, method, sqlStmt string, args ...any) ([]*Subscription, error) {
+	rows, err := s.db.QueryContext(ctx, sqlStmt, args...)
+	if err != nil {
+		return nil, serr.DBError(method, "webhook", err)
+	}
+	defer rows.Close()
+	subs := make([]*Subscription, 0)
+	for rows.Next() {
+		sub, err := s.ScanSubscription(rows)
+		if err != nil {
+			return nil, serr.DBError(method, "webhook", err)
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+func (s Storage) UpdateSubscription(ctx context.Context, sub *Subscription) error {
+	err := s.db.QueryRowContext(ctx, `
+		UPDATE webhook_subscription SET url = $1, event_types = $2, active = $3, updated_at = now() WHERE id = $4
+		RETURNING updated_at
+	`, sub.URL, pq.Array(sub.EventTypes), sub.Active, sub.ID).Scan(&sub.UpdatedAt)
+	if err != nil {
+		return serr.DBError("UpdateSubscription", "webhook", err)
+	}
+	return nil
+}
+
+func (s Storage) DeleteSubscription(ctx context.Context, id int64) error {
+	res, err := s.db.ExecContext(ctx, "DELETE FROM webhook_subscription WHERE id = $1", id)
+	if err != nil {
+		return serr.DBError("DeleteSubscription", "webhook", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return serr.DBError("DeleteSubscription", "webhook", sql.ErrNoRows)
+	}
+	return nil
+}
+
+// CreateDelivery queues a delivery, a delivery of the same event to the same subscription is kept
+// instead and d.ID is left zero.
+func (s Storage) CreateDelivery(ctx context.Context, d *Delivery) error {
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO webhook_delivery (subscription_id, event_id, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+		RETURNING id, status, next_attempt_at, created_at, updated_at
+	`, d.SubscriptionID, d.EventID, d.EventType, d.Payload).
+		Scan(&d.ID, &d.Status, &d.NextAttemptAt, &d.CreatedAt, &d.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return serr.DBError("CreateDelivery", "webhook delivery", err)
+	}
+	return nil
+}
+
+func (s Storage) GetDelivery(ctx context.Context, id int64) (*Delivery, error) {
+	sqlStmt := "SELECT " + deliveryColumns + " FROM webhook_delivery WHERE id = $1"
+	d, err := s.ScanDelivery(s.db.QueryRowContext(ctx, sqlStmt, id))
+	if err != nil {
+		return nil, serr.DBError("GetDelivery", "webhook delivery", err)
+	}
+	return d, nil
+}
+
+// GetDeliveries returns the deliveries of a subscription, newest first.
+func (s Storage) GetDeliveries(ctx context.Context, subscriptionID int64, limit, offset int) ([]*Delivery, error) {
+	sqlStmt := "SELECT " + deliveryColumns + " FROM webhook_delivery WHERE subscription_id = $1 " +
+		"ORDER BY id DESC LIMIT $2 OFFSET $3"
+	return s.queryDeliveries(ctx, "GetDeliveries", sqlStmt, subscriptionID, limit, offset)
+}
+
+// ClaimDue returns pending deliveries whose attempt is due and postpones their next attempt by lease,
+// so other workers skip them while they are sent. A delivery whose sender dies is retried once the
+// lease is over.
+func (s Storage) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*Delivery, error) {
+	sqlStmt := `
+		UPDATE webhook_delivery SET next_attempt_at = now() + $1 * interval '1 millisecond', updated_at = now()
+		WHERE id IN (
+			SELECT id FROM webhook_delivery WHERE status = $2 AND next_attempt_at <= now()
+			ORDER BY next_attempt_at LIMIT $3 FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns
+	return s.queryDeliveries(ctx, "ClaimDue", sqlStmt, lease.Milliseconds(), Pending, limit)
+}
+
+func (s Storage) queryDeliveries(ctx context.Context, method, sqlStmt string, args ...any) ([]*Delivery, error) {
+	rows, err := s.db.QueryContext(ctx, sqlStmt, args...)
+	if err != nil {
+		return nil, serr.DBError(method, "webhook delivery", err)
+	}
+	defer rows.Close()
+	deliveries := make([]*Delivery, 0)
+	for rows.Next() {
+		d, err := s.ScanDelivery(rows)
+		if err != nil {
+			return nil, serr.DBError(method, "webhook delivery", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery stores the outcome of an attempt and when the next one is due.
+func (s Storage) UpdateDelivery(ctx context.Context, d *Delivery) error {
+	var statusCode sql.NullInt64
+	if d.LastStatusCode != 0 {
+		statusCode = sql.NullInt64{Int64: int64(d.LastStatusCode), Valid: true}
+	}
+	var lastError sql.NullString
+	if d.LastError != "" {
+		lastError = sql.NullString{String: d.LastError, Valid: true}
+	}
+	var deliveredAt sql.NullTime
+	if d.DeliveredAt != nil {
+		deliveredAt = sql.NullTime{Time: *d.DeliveredAt, Valid: true}
+	}
+	err := s.db.QueryRowContext(ctx, `
+		UPDATE webhook_delivery
+		SET status = $1, attempts = $2, last_status_code = $3, last_error = $4, next_attempt_at = $5,
+			delivered_at = $6, updated_at = now()
+		WHERE id = $7
+		RETURNING updated_at
+	`, d.Status, d.Attempts, statusCode, lastError, d.NextAttemptAt, deliveredAt, d.ID).Scan(&d.UpdatedAt)
+	if err != nil {
+		return serr.DBError("UpdateDelivery", "webhook delivery", err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package webhook_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"wallet/db"
+	"wallet/db/dbtest"
+	"wallet/storage/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// claimLimit is large enough for a worker to claim the deliveries other tests left in the shared database.
+const claimLimit = 10000
+
+// subscription creates a subscription to an event type no other test uses.
+func subscription(t *testing.T, psql *sql.DB) *webhook.Subscription {
+	t.Helper()
+	sub := &webhook.Subscription{URL: "https://example.com/hook", Secret: "secret", Active: true,
+		EventTypes: []string{fmt.Sprintf("Test%d", time.Now().UnixNano())}}
+	require.NoError(t, webhook.NewStorage(psql).CreateSubscription(context.Background(), sub))
+	return sub
+}
+
+// find returns the deliveries of subscriptionID among deliveries.
+func find(deliveries []*webhook.Delivery, subscriptionID int64) []*webhook.Delivery {
+	var found []*webhook.Delivery
+	for _, d := range deliveries {
+		if d.SubscriptionID == subscriptionID {
+			found = append(found, d)
+		}
+	}
+	return found
+}
+
+func TestSubscriptions(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := webhook.NewStorage(psql)
+	ctx := context.Background()
+	sub := subscription(t, psql)
+	assert.NotZero(t, sub.ID)
+
+	got, err := s.GetSubscription(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sub.EventTypes, got.EventTypes)
+	assert.True(t, got.Active)
+
+	subs, err := s.GetSubscriptionsByEventType(ctx, sub.EventTypes[0])
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+	assert.Equal(t, sub.ID, subs[0].ID)
+
+	// inactive subscriptions get no events
+	sub.Active = false
+	require.NoError(t, s.UpdateSubscription(ctx, sub))
+	subs, err = s.GetSubscriptionsByEventType(ctx, sub.EventTypes[0])
+	require.NoError(t, err)
+	assert.Empty(t, subs)
+
+	require.NoError(t, s.DeleteSubscription(ctx, sub.ID))
+	_, err = s.GetSubscription(ctx, sub.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.ErrorIs(t, s.DeleteSubscription(ctx, sub.ID), sql.ErrNoRows)
+}
+
+func TestCreateDelivery_Redelivered(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := webhook.NewStorage(psql)
+	ctx := context.Background()
+	sub := subscription(t, psql)
+
+	d := &webhook.Delivery{SubscriptionID: sub.ID, EventID: 1, EventType: sub.EventTypes[0], Payload: []byte(`{"n":1}`)}
+	require.NoError(t, s.CreateDelivery(ctx, d))
+	assert.NotZero(t, d.ID)
+	assert.Equal(t, webhook.Pending, d.Status)
+
+	// the relay published the event again, the delivery queued first is kept
+	again := &webhook.Delivery{SubscriptionID: sub.ID, EventID: 1, EventType: sub.EventTypes[0], Payload: []byte(`{"n":2}`)}
+	require.NoError(t, s.CreateDelivery(ctx, again))
+	assert.Zero(t, again.ID)
+
+	deliveries, err := s.GetDeliveries(ctx, sub.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, d.ID, deliveries[0].ID)
+	assert.JSONEq(t, `{"n":1}`, string(deliveries[0].Payload))
+}
+
+func TestClaimDue(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := webhook.NewStorage(psql)
+	ctx := context.Background()
+	sub := subscription(t, psql)
+	for eventID := int64(1); eventID <= 2; eventID++ {
+		require.NoError(t, s.CreateDelivery(ctx, &webhook.Delivery{SubscriptionID: sub.ID, EventID: eventID,
+			EventType: sub.EventTypes[0], Payload: []byte(`{}`)}))
+	}
+
+	claimed, err := s.ClaimDue(ctx, claimLimit, time.Minute)
+	require.NoError(t, err)
+	mine := find(claimed, sub.ID)
+	require.Len(t, mine, 2)
+	assert.True(t, mine[0].NextAttemptAt.After(time.Now().Add(50*time.Second)))
+
+	// leased deliveries are not claimed again until the lease is over
+	claimed, err = s.ClaimDue(ctx, claimLimit, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, find(claimed, sub.ID))
+}
+
+func TestClaimDue_SkipsLockedDeliveries(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := webhook.NewStorage(psql)
+	sub := subscription(t, psql)
+	require.NoError(t, s.CreateDelivery(context.Background(), &webhook.Delivery{SubscriptionID: sub.ID, EventID: 1,
+		EventType: sub.EventTypes[0], Payload: []byte(`{}`)}))
+
+	err := db.Transaction(context.Background(), func(ctx context.Context) error {
+		claimed, err := s.ClaimDue(ctx, claimLimit, time.Minute)
+		if err != nil {
+			return err
+		}
+		require.Len(t, find(claimed, sub.ID), 1)
+		// another worker neither waits for the uncommitted claim nor takes the delivery
+		claimed, err = s.ClaimDue(context.Background(), claimLimit, time.Minute)
+		if err != nil {
+			return err
+		}
+		assert.Empty(t, find(claimed, sub.ID))
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestUpdateDelivery(t *testing.T) {
+	psql := dbtest.Postgres(t)
+	s := webhook.NewStorage(psql)
+	ctx := context.Background()
+	sub := subscription(t, psql)
+	d := &webhook.Delivery{SubscriptionID: sub.ID, EventID: 1, EventType: sub.EventTypes[0], Payload: []byte(`{}`)}
+	require.NoError(t, s.CreateDelivery(ctx, d))
+
+	deliveredAt := time.Now().Truncate(time.Microsecond)
+	d.Status, d.Attempts, d.LastStatusCode, d.DeliveredAt = webhook.Succeeded, 2, 200, &deliveredAt
+	require.NoError(t, s.UpdateDelivery(ctx, d))
+
+	got, err := s.GetDelivery(ctx, d.ID)
+	require.NoError(t, err)
+	assert.Equal(t, webhook.Succeeded, got.Status)
+	assert.Equal(t, 2, got.Attempts)
+	assert.Equal(t, 200, got.LastStatusCode)
+	assert.Empty(t, got.LastError)
+	require.NotNil(t, got.DeliveredAt)
+	assert.True(t, deliveredAt.Equal(*got.DeliveredAt))
+
+	// delivered deliveries are not claimed
+	claimed, err := s.ClaimDue(ctx, claimLimit, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, find(claimed, sub.ID))
+}